@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -10,10 +11,14 @@ import (
 	"github.com/tonitomc/healthcare-crm-api/internal/adapters"
 	"github.com/tonitomc/healthcare-crm-api/internal/database"
 	"github.com/tonitomc/healthcare-crm-api/pkg/config"
+	"github.com/tonitomc/healthcare-crm-api/pkg/password"
+	"github.com/tonitomc/healthcare-crm-api/pkg/validator"
 
 	middlewarePkg "github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
 	"github.com/tonitomc/healthcare-crm-api/internal/api/routes"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointmenttype"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/audit"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/auth"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/consultation"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/exam"
@@ -25,6 +30,7 @@ import (
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/role"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/schedule"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/user"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist"
 )
 
 func main() {
@@ -51,13 +57,27 @@ func main() {
 
 	// Initialize Echo instance
 	e := echo.New()
+	// Every c.Bind also enforces the DTO's `validate` tags
+	e.Validator = validator.Default()
+	e.Binder = &middlewarePkg.Binder{}
+	// Every error is answered with the same body (see middlewarePkg.ErrorResponse)
+	e.HTTPErrorHandler = middlewarePkg.ErrorHandler
+	// Client addresses count failed logins and go to the audit log: X-Forwarded-For is
+	// only believed from the configured proxies
+	ipExtractor, err := middlewarePkg.IPExtractor(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	e.IPExtractor = ipExtractor
 
 	// Middleware
-	e.Use(middleware.Logger())
+	e.Use(middleware.RequestID())
+	e.Use(middlewarePkg.Logger()) // echo's logger, without the calendar feed ?token=
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORS())
 
-	e.Use(middlewarePkg.JWTMiddleware(cfg.JWTSecret))
+	e.Use(middlewarePkg.JWTMiddleware())
+	e.Use(middlewarePkg.ActiveSession())
 
 	// Root test route
 	e.GET("/", func(c echo.Context) error {
@@ -66,32 +86,93 @@ func main() {
 
 	// ===== Dependency Injection Setup =====
 
+	// Unit of work for operations spanning several repositories
+	uow := database.NewUnitOfWork(db)
+
+	// Cached permissions are dropped when user or role services change roles
+	permissionCacheAdapter := &adapters.PermissionCacheAdapter{}
+
 	// Role dependencies
 	roleRepo := role.NewRepository(db)
-	roleService := role.NewService(roleRepo)
+	roleService := role.NewService(roleRepo, permissionCacheAdapter)
 	roleHandler := role.NewHandler(roleService)
 
 	// User dependencies
 	userRepo := user.NewRepository(db)
-	userService := user.NewService(userRepo, roleService)
+	userService := user.NewService(userRepo, roleService, permissionCacheAdapter)
 	userHandler := user.NewHandler(userService)
 
 	userPermAdapter := adapters.NewUserPermissionAdapter(userService)
-	middlewarePkg.InjectPermissionProvider(userPermAdapter)
+	var permissionProvider middlewarePkg.PermissionProvider = userPermAdapter
+	if cfg.PermissionCacheTTL > 0 {
+		permissionCacheAdapter.Cache = middlewarePkg.NewPermissionCache(userPermAdapter, cfg.PermissionCacheTTL)
+		permissionProvider = permissionCacheAdapter.Cache
+	}
+	middlewarePkg.InjectPermissionProvider(permissionProvider)
+	middlewarePkg.InjectPermissionSource(middlewarePkg.PermissionSource(cfg.PermissionSource))
 
 	rbacService := rbac.NewService(userService, roleService)
 
+	// Audit log: every successful change through the API is recorded by middleware.Audit
+	// and every read of a patient's chart by middleware.AccessLog
+	auditRepo := audit.NewRepository(db)
+	auditService := audit.NewService(auditRepo)
+	auditHandler := audit.NewHandler(auditService)
+	auditRecorder := adapters.NewAuditRecorderAdapter(auditService)
+	middlewarePkg.InjectAuditRecorder(auditRecorder)
+	middlewarePkg.InjectAccessRecorder(auditRecorder)
+
 	// Auth Config
 	authCfg := auth.Config{
-		JWTSecret: cfg.JWTSecret,
-		AccessTTL: cfg.JWTTTL,
-		Issuer:    cfg.JWTIssuer,
+		JWTSecret:  cfg.JWTSecret,
+		AccessTTL:  cfg.JWTTTL,
+		RefreshTTL: cfg.RefreshTTL,
+		Issuer:     cfg.JWTIssuer,
+
+		SigningAlgorithm: cfg.JWTAlgorithm,
+		KeyRotation:      cfg.JWTKeyRotation,
+
+		LoginMaxAttempts: cfg.LoginMaxAttempts,
+		LoginLockout:     cfg.LoginLockout,
+
+		PasswordMinLength: cfg.PasswordMinLength,
+		PasswordHistory:   cfg.PasswordHistory,
+		ResetTTL:          cfg.PasswordResetTTL,
+		InvitationTTL:     cfg.InvitationTTL,
+		AppURL:            cfg.AppURL,
+	}
+	if cfg.PasswordBreachList != "" {
+		breached, err := password.LoadList(cfg.PasswordBreachList)
+		if err != nil {
+			log.Fatalf("Failed to load breached password list: %v", err)
+		}
+		authCfg.BreachedPasswords = breached
 	}
 
-	// Auth dependencies
-	authService := auth.NewService(userService, rbacService, authCfg)
+	// Auth dependencies; reset and invitation links are only logged until a real
+	// notifier (email) is wired in
+	authRepo := auth.NewRepository(db)
+	notifier := adapters.NewLogNotifier(cfg.NotificationsFile)
+	authService := auth.NewService(authRepo, userService, rbacService, notifier, uow, authCfg)
 	authHandler := auth.NewHandler(authService)
 
+	// Revoked sessions lose access right away, not when their token expires
+	middlewarePkg.InjectSessionValidator(authService)
+	middlewarePkg.InjectTokenKeys(authService)
+
+	// Signing keys: load them (creating the first one if needed) and check hourly
+	// whether the next one is due
+	if err := authService.RotateKeys(false); err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+	go func() {
+		for range time.Tick(time.Hour) {
+			if err := authService.RotateKeys(false); err != nil {
+				e.Logger.Errorf("JWT key rotation failed: %v", err)
+			}
+		}
+	}()
+
 	ensureSuperuser(cfg, userService, authService, e.Logger)
 	ensureSecretary(cfg, userService, authService, e.Logger)
 
@@ -101,13 +182,20 @@ func main() {
 	scheduleHandler := schedule.NewHandler(scheduleService)
 
 	// Patient dependencies, handler declared further down
-	// as it works as an orchestration layer for response enrichment
+	// as it works as an orchestration layer for response enrichment.
+	// Deleting or merging a patient also updates these repositories, in the same transaction
 	patientRepo := patient.NewRepository(db)
-	patientService := patient.NewService(patientRepo)
+	appointmentRepo := appointment.NewRepository(db)
+	consultationRepo := consultation.NewRepository(db)
+	examRepo := exam.NewRepository(db)
+	recordRepo := medicalrecord.NewRepository(db)
+	waitlistRepo := waitlist.NewRepository(db)
+	patientCleanup := adapters.NewPatientCleanupAdapter(appointmentRepo, consultationRepo, examRepo, recordRepo)
+	patientMerge := adapters.NewPatientMergeAdapter(appointmentRepo, waitlistRepo, consultationRepo, examRepo, recordRepo)
+	patientService := patient.NewService(patientRepo, uow, patientCleanup, patientMerge, patient.Config{RetentionYears: cfg.PatientRetentionYears})
 
 	patientProvider := &adapters.PatientAdapter{Service: patientService}
 	// MedicalRecord dependencies
-	recordRepo := medicalrecord.NewRepository(db)
 	recordService := medicalrecord.NewService(recordRepo)
 	recordHandler := medicalrecord.NewHandler(recordService)
 
@@ -118,33 +206,53 @@ func main() {
 
 	questionnaireValidator := &adapters.QuestionnaireAdapter{Service: questionnaireService}
 
-	// Consultation dependencies
-	consultationRepo := consultation.NewRepository(db)
-	consultationService := consultation.NewService(consultationRepo, questionnaireValidator)
+	// Consultation dependencies. Completing a consultation closes its appointment; the
+	// closer gets the appointment service once it is built below
+	appointmentCloser := &adapters.AppointmentCloserAdapter{}
+	consultationService := consultation.NewService(consultationRepo, questionnaireValidator, questionnaireValidator, appointmentCloser, uow)
 	consultationHandler := consultation.NewHandler(consultationService)
 
 	// Exam dependencies
-	examRepo := exam.NewRepository(db)
 	examService := exam.NewService(examRepo, patientProvider, s3Adapter)
 	examHandler := exam.NewHandler(examService)
 
+	// Reminder dependencies
+	reminderRepo := reminder.NewRepository(db)
+	reminderService := reminder.NewService(reminderRepo)
+	reminderHandler := reminder.NewHandler(reminderService)
+
+	// Appointment type and resource catalog
+	appointmentTypeRepo := appointmenttype.NewRepository(db)
+	appointmentTypeService := appointmenttype.NewService(appointmentTypeRepo)
+	appointmentTypeHandler := appointmenttype.NewHandler(appointmentTypeService)
+
 	// Adapters para appointments
 	patientAdapter := adapters.NewPatientAdapter(patientService)
 	scheduleAdapter := adapters.NewScheduleAdapter(scheduleService)
+	doctorAdapter := adapters.NewDoctorAdapter(userService)
+	appointmentTypeAdapter := adapters.NewAppointmentTypeAdapter(appointmentTypeService)
+	consultationStarter := adapters.NewConsultationStarterAdapter(consultationService)
+	// Waitlist and appointments depend on each other: the listener gets its
+	// service once the waitlist is built below
+	waitlistAdapter := &adapters.WaitlistAdapter{}
 
 	// Appointment dependencies
-	appointmentRepo := appointment.NewRepository(db)
-	appointmentService := appointment.NewService(appointmentRepo, patientAdapter, scheduleAdapter)
+	appointmentCfg := appointment.Config{
+		BufferMinutes: cfg.AppointmentBufferMinutes,
+	}
+	appointmentService := appointment.NewService(appointmentRepo, patientAdapter, scheduleAdapter, doctorAdapter, appointmentTypeAdapter, consultationStarter, waitlistAdapter, uow, appointmentCfg)
 	appointmentHandler := appointment.NewHandler(appointmentService)
+	appointmentCloser.Service = appointmentService
 	patientHandler := patient.NewHandler(patientService, examService, consultationService, recordService)
 
-	// Reminder dependencies
-	reminderRepo := reminder.NewRepository(db)
-	reminderService := reminder.NewService(reminderRepo)
-	reminderHandler := reminder.NewHandler(reminderService)
+	// Waitlist dependencies
+	waitlistService := waitlist.NewService(waitlistRepo, adapters.NewAppointmentBookerAdapter(appointmentService), adapters.NewReminderAdapter(reminderService), uow)
+	waitlistHandler := waitlist.NewHandler(waitlistService)
+	waitlistAdapter.Service = waitlistService
 
 	// ===== Route Registration =====
-	routes.RegisterRoutes(e, recordHandler, reminderHandler, authHandler, scheduleHandler, userHandler, roleHandler, patientHandler, consultationHandler, examHandler, appointmentHandler, questionnaireHandler)
+	authHandler.RegisterWellKnownRoutes(e)
+	routes.RegisterRoutes(e, recordHandler, reminderHandler, authHandler, scheduleHandler, userHandler, roleHandler, patientHandler, consultationHandler, examHandler, appointmentHandler, questionnaireHandler, waitlistHandler, appointmentTypeHandler, auditHandler)
 
 	// ===== Server Start =====
 	e.Logger.Fatal(e.Start(":8080"))
@@ -0,0 +1,33 @@
+package adapters
+
+import (
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointmenttype"
+)
+
+// AppointmentTypeAdapter implements appointment.TypeProvider on top of appointmenttype.Service
+type AppointmentTypeAdapter struct {
+	Service appointmenttype.Service
+}
+
+func NewAppointmentTypeAdapter(service appointmenttype.Service) *AppointmentTypeAdapter {
+	return &AppointmentTypeAdapter{Service: service}
+}
+
+func (a *AppointmentTypeAdapter) GetType(id int) (*models.AppointmentType, error) {
+	t, err := a.Service.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]models.Resource, 0, len(t.Recursos))
+	for _, r := range t.Recursos {
+		resources = append(resources, models.Resource{ID: r.ID, Nombre: r.Nombre, Activo: r.Activo})
+	}
+	return &models.AppointmentType{
+		ID:       t.ID,
+		Duracion: t.Duracion,
+		Activo:   t.Activo,
+		Recursos: resources,
+	}, nil
+}
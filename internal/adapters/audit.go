@@ -0,0 +1,44 @@
+package adapters
+
+import (
+	middlewarePkg "github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/audit"
+	auditModels "github.com/tonitomc/healthcare-crm-api/internal/domain/audit/models"
+)
+
+// AuditRecorderAdapter adapts audit.Service to the middleware's AuditRecorder and
+// AccessRecorder interfaces.
+type AuditRecorderAdapter struct {
+	Service audit.Service
+}
+
+func NewAuditRecorderAdapter(service audit.Service) *AuditRecorderAdapter {
+	return &AuditRecorderAdapter{Service: service}
+}
+
+// Implements middleware.AuditRecorder
+func (a *AuditRecorderAdapter) Record(rec middlewarePkg.AuditRecord) error {
+	return a.Service.Record(&auditModels.Record{
+		UsuarioID: rec.UsuarioID,
+		Accion:    rec.Accion,
+		Entidad:   rec.Entidad,
+		EntidadID: rec.EntidadID,
+		Ruta:      rec.Ruta,
+		Antes:     rec.Antes,
+		Despues:   rec.Despues,
+		IP:        rec.IP,
+	})
+}
+
+// Implements middleware.AccessRecorder
+func (a *AuditRecorderAdapter) RecordAccess(rec middlewarePkg.AccessRecord) error {
+	return a.Service.RecordAccess(&auditModels.AccessRecord{
+		UsuarioID:  rec.UsuarioID,
+		PacienteID: rec.PacienteID,
+		Recurso:    rec.Recurso,
+		RecursoID:  rec.RecursoID,
+		Motivo:     rec.Motivo,
+		Ruta:       rec.Ruta,
+		IP:         rec.IP,
+	})
+}
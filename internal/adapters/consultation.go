@@ -0,0 +1,55 @@
+package adapters
+
+import (
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment"
+	appointmentModels "github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/consultation"
+	consultationModels "github.com/tonitomc/healthcare-crm-api/internal/domain/consultation/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// ConsultationStarterAdapter implements appointment.ConsultationStarter on top of consultation.Service
+type ConsultationStarterAdapter struct {
+	Service consultation.Service
+}
+
+func NewConsultationStarterAdapter(service consultation.Service) *ConsultationStarterAdapter {
+	return &ConsultationStarterAdapter{Service: service}
+}
+
+func (a *ConsultationStarterAdapter) StartConsultation(start appointmentModels.ConsultationStart) (int, error) {
+	return a.Service.CreateForAppointment(&consultationModels.AppointmentConsultationDTO{
+		CitaID:         start.CitaID,
+		PacienteID:     start.PacienteID,
+		CuestionarioID: start.CuestionarioID,
+		Motivo:         start.Motivo,
+	})
+}
+
+func (a *ConsultationStarterAdapter) WithTx(tx database.DBTX) appointment.ConsultationStarter {
+	return &ConsultationStarterAdapter{Service: a.Service.WithTx(tx)}
+}
+
+// AppointmentCloserAdapter implements consultation.AppointmentCloser: completing a
+// consultation closes the appointment it was checked in from.
+//
+// Service is set after construction because the appointment service itself opens
+// consultations through the consultation service (see cmd/server/main.go).
+type AppointmentCloserAdapter struct {
+	Service appointment.Service
+}
+
+func (a *AppointmentCloserAdapter) CompleteAppointment(citaID, userID int) error {
+	if a.Service == nil {
+		return appErr.Wrap("AppointmentCloserAdapter.CompleteAppointment(no service)", appErr.ErrInternal, nil)
+	}
+	return a.Service.Complete(citaID, userID)
+}
+
+func (a *AppointmentCloserAdapter) WithTx(tx database.DBTX) consultation.AppointmentCloser {
+	if a.Service == nil {
+		return a
+	}
+	return &AppointmentCloserAdapter{Service: a.Service.WithTx(tx)}
+}
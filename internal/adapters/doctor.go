@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	userDomain "github.com/tonitomc/healthcare-crm-api/internal/domain/user"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// DoctorRoleName is the role that marks a user as a provider who can receive appointments.
+const DoctorRoleName = "doctor"
+
+// DoctorAdapter implements appointment.DoctorProvider on top of user.Service
+type DoctorAdapter struct {
+	Service userDomain.Service
+}
+
+func NewDoctorAdapter(service userDomain.Service) *DoctorAdapter {
+	return &DoctorAdapter{Service: service}
+}
+
+func (d *DoctorAdapter) IsDoctor(userID int) (bool, error) {
+	roles, err := d.Service.GetUserRoles(userID)
+	if errors.Is(err, appErr.ErrNotFound) {
+		return false, nil // un usuario inexistente no es doctor
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, r := range roles {
+		if strings.EqualFold(r.Name, DoctorRoleName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *DoctorAdapter) ListDoctors() ([]models.Doctor, error) {
+	users, err := d.Service.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	doctors := []models.Doctor{}
+	for _, u := range users {
+		ok, err := d.IsDoctor(u.ID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			doctors = append(doctors, models.Doctor{ID: u.ID, Nombre: u.Username})
+		}
+	}
+	return doctors, nil
+}
@@ -0,0 +1,50 @@
+package adapters
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	authModels "github.com/tonitomc/healthcare-crm-api/internal/domain/auth/models"
+)
+
+// LogNotifier implements auth.Notifier for development: instead of sending the
+// message it appends it, as a JSON line, to a file, or logs it when there is none.
+// The links it writes give access to accounts, so it must not be used in production.
+type LogNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewLogNotifier(path string) *LogNotifier {
+	return &LogNotifier{path: path}
+}
+
+// Notify implements auth.Notifier
+func (n *LogNotifier) Notify(msg authModels.Notification) error {
+	if n.path == "" {
+		log.Printf("[Notifier] %s para %s <%s>: %s (expira %s)",
+			msg.Tipo, msg.Usuario, msg.Para, msg.Enlace, msg.ExpiraEn.Format("2006-01-02 15:04"))
+		return nil
+	}
+
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(n.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
@@ -1,8 +1,11 @@
 package adapters
 
 import (
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/patient"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/patient/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
 )
 
 type PatientAdapter struct {
@@ -25,14 +28,22 @@ func (p *PatientAdapter) GetByID(id int) (*models.Patient, error) {
 	return p.Service.GetByID(id)
 }
 
+// Exists rejects archived patients, so no new appointments are booked for them
 func (p *PatientAdapter) Exists(id int) (bool, error) {
-	_, err := p.Service.GetByID(id)
+	patient, err := p.Service.GetByID(id)
 	if err != nil {
 		return false, err
 	}
+	if patient.ArchivadoEn != nil {
+		return false, appErr.NewDomainError(appErr.ErrConflict, "El paciente está archivado")
+	}
 	return true, nil
 }
 
 func (p *PatientAdapter) Create(dto *models.PatientCreateDTO) (int, error) {
 	return p.Service.Create(dto)
 }
+
+func (p *PatientAdapter) WithTx(tx database.DBTX) appointment.PatientProvider {
+	return &PatientAdapter{Service: p.Service.WithTx(tx)}
+}
@@ -0,0 +1,43 @@
+package adapters
+
+import (
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/consultation"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/exam"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/medicalrecord"
+)
+
+// PatientCleanupAdapter implements patient.DependentCleaner.
+// It removes everything that references a patient, binding each repository
+// to the transaction the patient deletion runs in.
+type PatientCleanupAdapter struct {
+	Appointments  appointment.Repository
+	Consultations consultation.Repository
+	Exams         exam.Repository
+	Records       medicalrecord.Repository
+}
+
+func NewPatientCleanupAdapter(appointments appointment.Repository, consultations consultation.Repository, exams exam.Repository, records medicalrecord.Repository) *PatientCleanupAdapter {
+	return &PatientCleanupAdapter{
+		Appointments:  appointments,
+		Consultations: consultations,
+		Exams:         exams,
+		Records:       records,
+	}
+}
+
+func (a *PatientCleanupAdapter) CleanupPatient(tx database.DBTX, patientID int) error {
+	// Appointments keep the patient's name so the agenda history survives
+	if err := a.Appointments.WithTx(tx).DetachPatient(patientID); err != nil {
+		return err
+	}
+	// Exams may point at a consultation, so they go first
+	if err := a.Exams.WithTx(tx).DeleteByPatient(patientID); err != nil {
+		return err
+	}
+	if err := a.Consultations.WithTx(tx).DeleteByPatient(patientID); err != nil {
+		return err
+	}
+	return a.Records.WithTx(tx).DeleteByPatient(patientID)
+}
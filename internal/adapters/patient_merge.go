@@ -0,0 +1,48 @@
+package adapters
+
+import (
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/consultation"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/exam"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/medicalrecord"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist"
+)
+
+// PatientMergeAdapter implements patient.DependentMerger.
+// It re-points everything that references the merged patient to the survivor,
+// binding each repository to the transaction the merge runs in.
+type PatientMergeAdapter struct {
+	Appointments  appointment.Repository
+	Waitlist      waitlist.Repository
+	Consultations consultation.Repository
+	Exams         exam.Repository
+	Records       medicalrecord.Repository
+}
+
+func NewPatientMergeAdapter(appointments appointment.Repository, waitlist waitlist.Repository, consultations consultation.Repository, exams exam.Repository, records medicalrecord.Repository) *PatientMergeAdapter {
+	return &PatientMergeAdapter{
+		Appointments:  appointments,
+		Waitlist:      waitlist,
+		Consultations: consultations,
+		Exams:         exams,
+		Records:       records,
+	}
+}
+
+func (a *PatientMergeAdapter) MergePatient(tx database.DBTX, fromID, toID int) error {
+	if err := a.Appointments.WithTx(tx).ReassignPatient(fromID, toID); err != nil {
+		return err
+	}
+	if err := a.Waitlist.WithTx(tx).ReassignPatient(fromID, toID); err != nil {
+		return err
+	}
+	// Questionnaire answers, diagnostics and treatments hang off the consultation
+	if err := a.Consultations.WithTx(tx).ReassignPatient(fromID, toID); err != nil {
+		return err
+	}
+	if err := a.Exams.WithTx(tx).ReassignPatient(fromID, toID); err != nil {
+		return err
+	}
+	return a.Records.WithTx(tx).MergeInto(fromID, toID)
+}
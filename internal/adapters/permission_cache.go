@@ -0,0 +1,26 @@
+package adapters
+
+import (
+	middlewarePkg "github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
+)
+
+// PermissionCacheAdapter drops cached permissions when user.Service or role.Service
+// change roles. Implements user.AccessListener and role.AccessListener. Cache is
+// set once built, since the cache itself depends on user.Service; it may stay nil
+// when caching is disabled.
+type PermissionCacheAdapter struct {
+	Cache *middlewarePkg.PermissionCache
+}
+
+func (a *PermissionCacheAdapter) UserAccessChanged(userID int) {
+	if a.Cache != nil {
+		a.Cache.InvalidateUser(userID)
+	}
+}
+
+// A role may be held by many users, so every entry is dropped.
+func (a *PermissionCacheAdapter) RoleAccessChanged(roleID int) {
+	if a.Cache != nil {
+		a.Cache.InvalidateAll()
+	}
+}
@@ -2,8 +2,11 @@ package adapters
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/questionnaire"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
 )
 
 type QuestionnaireAdapter struct {
@@ -18,3 +21,22 @@ func (q *QuestionnaireAdapter) Validate(questionnaireID int, answers json.RawMes
 	return q.Service.Validate(questionnaireID, answers)
 }
 
+// ActiveVersion returns the active version of the questionnaire family questionnaireID belongs to
+func (q *QuestionnaireAdapter) ActiveVersion(questionnaireID int) (int, error) {
+	questionnaire, err := q.Service.GetByID(questionnaireID)
+	if err != nil {
+		return 0, err
+	}
+	if questionnaire.Activo {
+		return questionnaire.ID, nil
+	}
+	active, err := q.Service.GetActiveByName(questionnaire.Nombre)
+	if errors.Is(err, appErr.ErrNotFound) {
+		return 0, appErr.NewDomainError(appErr.ErrInvalidInput,
+			fmt.Sprintf("El cuestionario '%s' no tiene una versión activa", questionnaire.Nombre))
+	}
+	if err != nil {
+		return 0, err
+	}
+	return active.ID, nil
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/schedule"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/schedule/models"
 )
 
 // ScheduleAdapter implements BusinessHoursValidator for appointment service
@@ -15,14 +16,10 @@ func NewScheduleAdapter(service schedule.Service) *ScheduleAdapter {
 	return &ScheduleAdapter{Service: service}
 }
 
-func (s *ScheduleAdapter) IsWithinBusinessHours(date, start, end time.Time) (bool, error) {
-	return s.Service.IsTimeRangeWithinWorkingHours(date, start, end)
+func (s *ScheduleAdapter) IsWithinBusinessHours(date, start, end time.Time, doctorID *int) (bool, error) {
+	return s.Service.IsTimeRangeWithinWorkingHours(date, start, end, doctorID)
 }
 
-func (s *ScheduleAdapter) GetEffectiveDay(date time.Time) (bool, error) {
-	effectiveDay, err := s.Service.GetEffectiveDay(date)
-	if err != nil {
-		return false, err
-	}
-	return effectiveDay.Active, nil
+func (s *ScheduleAdapter) GetEffectiveDay(date time.Time, doctorID *int) (*models.EffectiveDay, error) {
+	return s.Service.GetEffectiveDay(date, doctorID)
 }
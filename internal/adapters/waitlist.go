@@ -0,0 +1,74 @@
+package adapters
+
+import (
+	"log"
+	"time"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment"
+	appointmentModels "github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/reminder"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist"
+	waitlistModels "github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist/models"
+)
+
+// WaitlistAdapter implements appointment.SlotListener: every slot freed by a
+// cancellation or a move is offered to the waitlist.
+//
+// Service is set after construction because the waitlist service itself books
+// through the appointment service (see cmd/server/main.go).
+type WaitlistAdapter struct {
+	Service waitlist.Service
+}
+
+func (w *WaitlistAdapter) SlotReleased(slot appointmentModels.ReleasedSlot) {
+	if w.Service == nil {
+		return
+	}
+	// Offering is best effort: a failure here must not undo the cancellation
+	if _, err := w.Service.OfferSlot(waitlistModels.Slot{
+		Fecha:    slot.Fecha,
+		Duracion: slot.Duracion,
+		DoctorID: slot.DoctorID,
+	}); err != nil {
+		log.Printf("[Waitlist] could not offer released slot %s: %v", slot.Fecha.Format(time.RFC3339), err)
+	}
+}
+
+// AppointmentBookerAdapter implements waitlist.AppointmentBooker on top of appointment.Service
+type AppointmentBookerAdapter struct {
+	Service appointment.Service
+}
+
+func NewAppointmentBookerAdapter(service appointment.Service) *AppointmentBookerAdapter {
+	return &AppointmentBookerAdapter{Service: service}
+}
+
+func (a *AppointmentBookerAdapter) Book(pacienteID *int, nombre *string, fecha time.Time, duracion int64, doctorID *int) (int, error) {
+	return a.Service.Create(&appointmentModels.AppointmentCreateDTO{
+		PacienteID: pacienteID,
+		Nombre:     nombre,
+		Fecha:      fecha,
+		Duracion:   duracion,
+		DoctorID:   doctorID,
+	})
+}
+
+func (a *AppointmentBookerAdapter) WithTx(tx database.DBTX) waitlist.AppointmentBooker {
+	return &AppointmentBookerAdapter{Service: a.Service.WithTx(tx)}
+}
+
+// ReminderAdapter implements waitlist.ReminderCreator on top of reminder.Service
+type ReminderAdapter struct {
+	Service reminder.Service
+}
+
+func NewReminderAdapter(service reminder.Service) *ReminderAdapter {
+	return &ReminderAdapter{Service: service}
+}
+
+// CreateGlobal creates a reminder visible to every user (the front desk)
+func (r *ReminderAdapter) CreateGlobal(description string) error {
+	_, err := r.Service.Create(0, description, true)
+	return err
+}
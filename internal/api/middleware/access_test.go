@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type fakeAccessRecorder struct {
+	records []AccessRecord
+}
+
+func (f *fakeAccessRecorder) RecordAccess(rec AccessRecord) error {
+	f.records = append(f.records, rec)
+	return nil
+}
+
+func TestAccessLog(t *testing.T) {
+	recorder := &fakeAccessRecorder{}
+	InjectAccessRecorder(recorder)
+	t.Cleanup(func() { InjectAccessRecorder(nil) })
+
+	examPatient := func(id int) (int, error) { return 12, nil }
+	e := echo.New()
+	e.GET("/api/exams/:id/file", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, AccessLog("archivo_examen", "id", examPatient))
+	e.GET("/api/exams/:id/missing", func(c echo.Context) error {
+		return echo.ErrNotFound
+	}, AccessLog("archivo_examen", "id", examPatient))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/exams/40/file?motivo=control", nil)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/exams/40/missing", nil))
+
+	if len(recorder.records) != 1 {
+		t.Fatalf("got %d records, want only the successful read", len(recorder.records))
+	}
+	rec := recorder.records[0]
+	if rec.PacienteID != 12 || *rec.RecursoID != 40 || rec.Motivo != "control" || rec.Ruta != "GET /api/exams/:id/file" {
+		t.Fatalf("unexpected record %+v", rec)
+	}
+}
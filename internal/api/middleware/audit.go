@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// ─────────────────────────────────────────────────────────────
+// AuditRecorder Interface (decouples from the audit domain)
+// ─────────────────────────────────────────────────────────────
+
+// Audit actions
+const (
+	AuditCreate = "crear"
+	AuditUpdate = "actualizar"
+	AuditDelete = "eliminar"
+)
+
+// AuditRecord is one change made through the API.
+type AuditRecord struct {
+	UsuarioID *int
+	Accion    string // AuditCreate, AuditUpdate or AuditDelete
+	Entidad   string
+	EntidadID *int
+	Ruta      string      // method and route template, e.g. "PUT /api/consultations/:id"
+	Antes     interface{} // state before the change; nil when unknown or on create
+	Despues   interface{} // state after the change; nil when unknown or on delete
+	IP        string
+}
+
+type AuditRecorder interface {
+	Record(rec AuditRecord) error
+}
+
+var auditRecorder AuditRecorder
+
+func InjectAuditRecorder(recorder AuditRecorder) {
+	auditRecorder = recorder
+}
+
+// AuditLoader returns the current state of the entity with the given ID. It is
+// called before and after the handler; ErrNotFound means there is no state.
+type AuditLoader func(id int) (interface{}, error)
+
+// ─────────────────────────────────────────────────────────────
+// Middleware
+// ─────────────────────────────────────────────────────────────
+
+const (
+	auditStateKey      = "auditState"
+	maxAuditedResponse = 64 << 10
+)
+
+// auditState is shared by nested Audit middlewares of a request: the innermost one
+// (route level) decides the entity, the outermost one records.
+type auditState struct {
+	entity string
+	id     *int
+	load   AuditLoader
+	before interface{}
+}
+
+// Audit records every successful POST, PUT, PATCH or DELETE of the routes it wraps
+// as a change to entity. The entity ID comes from the route parameter param or, on
+// creation, from the "id" of the JSON response; load (optional) provides the state
+// before and after. A route-level Audit takes precedence over the group's.
+//
+// The change is already committed when it is recorded, so a recording failure is
+// logged instead of failing the request.
+func Audit(entity, param string, load AuditLoader) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if auditRecorder == nil || !isMutation(c.Request().Method) {
+				return next(c)
+			}
+
+			state, nested := c.Get(auditStateKey).(*auditState)
+			if !nested {
+				state = &auditState{}
+				c.Set(auditStateKey, state)
+			}
+			state.entity, state.load, state.id, state.before = entity, load, nil, nil
+			if id, err := strconv.Atoi(c.Param(param)); err == nil && param != "" {
+				state.id = &id
+				state.before = loadAuditState(c, load, id)
+			}
+
+			if nested {
+				return next(c)
+			}
+
+			// Keep the response to read the ID of created entities
+			capture := &captureWriter{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = capture
+			err := next(c)
+			c.Response().Writer = capture.ResponseWriter
+
+			if err != nil || c.Response().Status >= http.StatusBadRequest || state.entity == "" {
+				return err
+			}
+			recordAudit(c, state, capture.body.Bytes())
+			return nil
+		}
+	}
+}
+
+// NoAudit excludes a route of an audited group, e.g. a POST that only validates.
+func NoAudit() echo.MiddlewareFunc {
+	return Audit("", "", nil)
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func recordAudit(c echo.Context, state *auditState, response []byte) {
+	req := c.Request()
+	rec := AuditRecord{
+		Entidad:   state.entity,
+		EntidadID: state.id,
+		Ruta:      req.Method + " " + c.Path(),
+		Antes:     state.before,
+		IP:        c.RealIP(),
+	}
+	if claims := GetClaims(c); claims != nil {
+		userID := int(claims.UserID)
+		rec.UsuarioID = &userID
+	}
+
+	switch {
+	case req.Method == http.MethodDelete:
+		rec.Accion = AuditDelete
+	case req.Method == http.MethodPost && state.id == nil:
+		rec.Accion = AuditCreate
+		var created struct {
+			ID *int `json:"id"`
+		}
+		if json.Unmarshal(response, &created) == nil {
+			rec.EntidadID = created.ID
+		}
+	default:
+		rec.Accion = AuditUpdate
+	}
+	if rec.EntidadID != nil {
+		rec.Despues = loadAuditState(c, state.load, *rec.EntidadID)
+	}
+
+	if err := auditRecorder.Record(rec); err != nil {
+		c.Logger().Errorf("[Audit] could not record %s of %s (%s): %v", rec.Accion, rec.Entidad, rec.Ruta, err)
+	}
+}
+
+func loadAuditState(c echo.Context, load AuditLoader, id int) interface{} {
+	if load == nil {
+		return nil
+	}
+	state, err := load(id)
+	if err != nil {
+		if !errors.Is(err, appErr.ErrNotFound) {
+			c.Logger().Warnf("[Audit] could not load state of %d: %v", id, err)
+		}
+		return nil
+	}
+	return state
+}
+
+// captureWriter keeps the first bytes of the response while writing it through
+type captureWriter struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	if room := maxAuditedResponse - w.body.Len(); room > 0 {
+		w.body.Write(b[:min(room, len(b))])
+	}
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+type fakeRecorder struct {
+	records []AuditRecord
+}
+
+func (f *fakeRecorder) Record(rec AuditRecord) error {
+	f.records = append(f.records, rec)
+	return nil
+}
+
+// serveAudited runs one request through an audited group; states holds the
+// entity states by ID and is what the handler changes.
+func serveAudited(t *testing.T, method, target string, states map[int]string, handler echo.HandlerFunc, routeMiddleware ...echo.MiddlewareFunc) []AuditRecord {
+	t.Helper()
+	recorder := &fakeRecorder{}
+	InjectAuditRecorder(recorder)
+	t.Cleanup(func() { InjectAuditRecorder(nil) })
+
+	load := func(id int) (interface{}, error) {
+		state, ok := states[id]
+		if !ok {
+			return nil, appErr.Wrap("load", appErr.ErrNotFound, nil)
+		}
+		return state, nil
+	}
+
+	e := echo.New()
+	g := e.Group("/api/items", Audit("item", "id", load))
+	g.Add(method, "", handler, routeMiddleware...)
+	g.Add(method, "/:id", handler, routeMiddleware...)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(""))
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	return recorder.records
+}
+
+func TestAudit_Update(t *testing.T) {
+	states := map[int]string{4: "antes"}
+	records := serveAudited(t, http.MethodPut, "/api/items/4", states, func(c echo.Context) error {
+		states[4] = "después"
+		return c.NoContent(http.StatusOK)
+	})
+
+	if len(records) != 1 {
+		t.Fatalf("got %d records", len(records))
+	}
+	rec := records[0]
+	if rec.Accion != AuditUpdate || rec.Entidad != "item" || *rec.EntidadID != 4 || rec.Ruta != "PUT /api/items/:id" {
+		t.Fatalf("unexpected record %+v", rec)
+	}
+	if rec.Antes != "antes" || rec.Despues != "después" {
+		t.Fatalf("states not captured: %+v", rec)
+	}
+}
+
+func TestAudit_CreateReadsIDFromResponse(t *testing.T) {
+	states := map[int]string{}
+	records := serveAudited(t, http.MethodPost, "/api/items", states, func(c echo.Context) error {
+		states[9] = "nuevo"
+		return c.JSON(http.StatusCreated, echo.Map{"id": 9})
+	})
+
+	if len(records) != 1 || records[0].Accion != AuditCreate || records[0].EntidadID == nil || *records[0].EntidadID != 9 {
+		t.Fatalf("unexpected records %+v", records)
+	}
+	if records[0].Antes != nil || records[0].Despues != "nuevo" {
+		t.Fatalf("states not captured: %+v", records[0])
+	}
+}
+
+func TestAudit_DeleteHasNoStateAfter(t *testing.T) {
+	states := map[int]string{2: "viejo"}
+	records := serveAudited(t, http.MethodDelete, "/api/items/2", states, func(c echo.Context) error {
+		delete(states, 2)
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	if len(records) != 1 || records[0].Accion != AuditDelete || records[0].Antes != "viejo" || records[0].Despues != nil {
+		t.Fatalf("unexpected records %+v", records)
+	}
+}
+
+func TestAudit_SkipsFailuresAndExcludedRoutes(t *testing.T) {
+	failing := func(c echo.Context) error {
+		return appErr.NewDomainError(appErr.ErrConflict, "ocupado")
+	}
+	if records := serveAudited(t, http.MethodPut, "/api/items/1", map[int]string{}, failing); len(records) != 0 {
+		t.Fatalf("failed request recorded: %+v", records)
+	}
+
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	if records := serveAudited(t, http.MethodPost, "/api/items/1", map[int]string{}, ok, NoAudit()); len(records) != 0 {
+		t.Fatalf("excluded route recorded: %+v", records)
+	}
+}
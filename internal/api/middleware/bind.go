@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// Binder binds requests like echo.DefaultBinder and then runs the registered
+// Validator, so every c.Bind enforces the DTO's `validate` tags.
+type Binder struct {
+	echo.DefaultBinder
+}
+
+// Bind decodes the request into i and validates the result.
+func (b *Binder) Bind(i interface{}, c echo.Context) error {
+	if err := b.DefaultBinder.Bind(i, c); err != nil {
+		return err
+	}
+	if c.Echo().Validator == nil {
+		return nil
+	}
+	return c.Validate(i)
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// ErrorResponse is the body of every error response of the API.
+type ErrorResponse struct {
+	Error     string              `json:"error"`                // human-readable message (Spanish)
+	Codigo    string              `json:"codigo"`               // stable machine-readable code, e.g. "not_found"
+	Detalles  []appErr.FieldError `json:"detalles,omitempty"`   // invalid fields, for validation errors
+	RequestID string              `json:"request_id,omitempty"` // same value as the X-Request-ID header
+}
+
+// errorClass is how a sentinel is presented over HTTP
+type errorClass struct {
+	sentinel error
+	status   int
+	code     string
+	message  string
+}
+
+// errorClasses in matching order: an error that wraps several sentinels is presented
+// as the first one listed.
+var errorClasses = []errorClass{
+	{appErr.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials", "Credenciales inválidas."},
+	{appErr.ErrInvalidToken, http.StatusUnauthorized, "invalid_token", "Token inválido o expirado."},
+	{appErr.ErrUnauthorized, http.StatusUnauthorized, "unauthorized", "No autorizado."},
+	{appErr.ErrForbidden, http.StatusForbidden, "forbidden", "Acceso denegado."},
+	{appErr.ErrTooManyRequests, http.StatusTooManyRequests, "too_many_requests", "Demasiados intentos. Intente más tarde."},
+	{appErr.ErrInvalidInput, http.StatusBadRequest, "invalid_input", "Datos inválidos o incompletos."},
+	{appErr.ErrInvalidRequest, http.StatusBadRequest, "invalid_request", "Solicitud inválida."},
+	{appErr.ErrIncompleteData, http.StatusBadRequest, "incomplete_data", "Datos incompletos o incorrectos."},
+	{appErr.ErrNotFound, http.StatusNotFound, "not_found", "Recurso no encontrado."},
+	{appErr.ErrAlreadyExists, http.StatusConflict, "already_exists", "El recurso ya existe."},
+	{appErr.ErrConflict, http.StatusConflict, "conflict", "Conflicto de datos."},
+	{appErr.ErrTooLarge, http.StatusRequestEntityTooLarge, "too_large", "El contenido supera el tamaño máximo."},
+	{appErr.ErrOperationNotAllowed, http.StatusUnprocessableEntity, "operation_not_allowed", "Operación no permitida."},
+}
+
+var internalClass = errorClass{appErr.ErrInternal, http.StatusInternalServerError, "internal_error", "Error interno del servidor."}
+
+func classify(err error) errorClass {
+	for _, class := range errorClasses {
+		if errors.Is(err, class.sentinel) {
+			return class
+		}
+	}
+	return internalClass
+}
+
+// statusSentinels presents errors raised by Echo itself (unknown route, malformed
+// JWT...) like the sentinel of the same status.
+var statusSentinels = map[int]error{
+	http.StatusBadRequest:            appErr.ErrInvalidRequest,
+	http.StatusUnauthorized:          appErr.ErrUnauthorized,
+	http.StatusForbidden:             appErr.ErrForbidden,
+	http.StatusNotFound:              appErr.ErrNotFound,
+	http.StatusConflict:              appErr.ErrConflict,
+	http.StatusRequestEntityTooLarge: appErr.ErrTooLarge,
+	http.StatusUnprocessableEntity:   appErr.ErrOperationNotAllowed,
+	http.StatusTooManyRequests:       appErr.ErrTooManyRequests,
+}
+
+func classifyStatus(status int) errorClass {
+	if sentinel, ok := statusSentinels[status]; ok {
+		return classify(sentinel)
+	}
+	if status >= http.StatusInternalServerError {
+		return internalClass
+	}
+	return errorClass{status: status, code: "http_error", message: http.StatusText(status)}
+}
+
+const errorMessagesKey = "errorMessages"
+
+// ErrorMessages registers the messages a route group uses for some sentinels, e.g.
+// "Paciente no encontrado." for ErrNotFound. Everything else keeps the general
+// message of ErrorHandler.
+func ErrorMessages(messages map[error]string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(errorMessagesKey, messages)
+			return next(c)
+		}
+	}
+}
+
+// ErrorHandler is the echo.HTTPErrorHandler of the API. It presents every error with
+// the same ErrorResponse body:
+//   - validation errors answer 400 with their field details;
+//   - domain errors use the status of their Code and their own message;
+//   - sentinels use their status and the group's message (see ErrorMessages);
+//   - anything else is a 500 whose detail only reaches the log.
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	var (
+		class   errorClass
+		message string
+		details []appErr.FieldError
+		domain  *appErr.DomainError
+		httpErr *echo.HTTPError
+	)
+
+	switch {
+	case errors.As(err, new(*appErr.ValidationError)):
+		class = classify(appErr.ErrInvalidInput)
+		class.code = "validation_error"
+		message = class.message
+		details, _ = appErr.ValidationDetails(err)
+
+	case errors.As(err, &domain):
+		class = classify(domain.Code)
+		if class.sentinel == appErr.ErrInternal {
+			// A business rule broken without a more specific code
+			class = classify(appErr.ErrConflict)
+		}
+		message = domain.Message
+
+	case errors.As(err, &httpErr):
+		class = classifyStatus(httpErr.Code)
+		message = class.message
+		if msg, ok := httpErr.Message.(string); ok && class.code == "http_error" {
+			message = msg
+		}
+
+	default:
+		class = classify(err)
+		message = class.message
+		messages, _ := c.Get(errorMessagesKey).(map[error]string)
+		if custom, ok := messages[class.sentinel]; ok {
+			message = custom
+		}
+	}
+	if message == "" {
+		message = class.message
+	}
+
+	req := c.Request()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+	if class.status >= http.StatusInternalServerError {
+		c.Logger().Errorf("%s %s [%s]: %v", req.Method, req.URL.Path, requestID, err)
+	} else {
+		c.Logger().Warnf("%s %s [%s]: %v", req.Method, req.URL.Path, requestID, err)
+	}
+
+	if req.Method == http.MethodHead {
+		err = c.NoContent(class.status)
+	} else {
+		err = c.JSON(class.status, ErrorResponse{
+			Error:     message,
+			Codigo:    class.code,
+			Detalles:  details,
+			RequestID: requestID,
+		})
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+func handle(t *testing.T, err error, messages map[error]string) (int, ErrorResponse) {
+	t.Helper()
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/patients/1", nil), rec)
+	c.Response().Header().Set(echo.HeaderXRequestID, "req-1")
+
+	h := func(echo.Context) error { return err }
+	if messages != nil {
+		h = ErrorMessages(messages)(h)
+	}
+	ErrorHandler(h(c), c)
+
+	var body ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid body %q: %v", rec.Body.String(), err)
+	}
+	if body.RequestID != "req-1" {
+		t.Errorf("request id not echoed: %+v", body)
+	}
+	return rec.Code, body
+}
+
+func TestErrorHandler(t *testing.T) {
+	groupMessages := map[error]string{appErr.ErrNotFound: "Paciente no encontrado."}
+
+	cases := []struct {
+		name     string
+		err      error
+		messages map[error]string
+		status   int
+		code     string
+		message  string
+	}{
+		{"domain invalid input is a 400", appErr.NewDomainError(appErr.ErrInvalidInput, "La fecha es inválida"), nil,
+			http.StatusBadRequest, "invalid_input", "La fecha es inválida"},
+		{"domain conflict", appErr.NewDomainError(appErr.ErrConflict, "El horario está ocupado"), nil,
+			http.StatusConflict, "conflict", "El horario está ocupado"},
+		{"group message for not found", appErr.Wrap("Repo.GetByID", appErr.ErrNotFound, nil), groupMessages,
+			http.StatusNotFound, "not_found", "Paciente no encontrado."},
+		{"general message without group", appErr.Wrap("Repo.GetByID", appErr.ErrNotFound, nil), nil,
+			http.StatusNotFound, "not_found", "Recurso no encontrado."},
+		{"forbidden", appErr.Wrap("RequirePermission", appErr.ErrForbidden, nil), groupMessages,
+			http.StatusForbidden, "forbidden", "Acceso denegado."},
+		{"too many requests", appErr.Wrap("AuthService.Login", appErr.ErrTooManyRequests, nil), nil,
+			http.StatusTooManyRequests, "too_many_requests", "Demasiados intentos. Intente más tarde."},
+		{"domain too large is a 413", appErr.NewDomainError(appErr.ErrTooLarge, "El archivo supera el tamaño máximo de 1MB"), nil,
+			http.StatusRequestEntityTooLarge, "too_large", "El archivo supera el tamaño máximo de 1MB"},
+		{"echo body limit uses the same body", echo.ErrStatusRequestEntityTooLarge, nil,
+			http.StatusRequestEntityTooLarge, "too_large", "El contenido supera el tamaño máximo."},
+		{"echo errors use the same body", echo.ErrNotFound, nil,
+			http.StatusNotFound, "not_found", "Recurso no encontrado."},
+		{"unknown errors hide their detail", appErr.Wrap("Repo.Create", appErr.ErrInternal, http.ErrHandlerTimeout), nil,
+			http.StatusInternalServerError, "internal_error", "Error interno del servidor."},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, body := handle(t, tc.err, tc.messages)
+			if status != tc.status || body.Codigo != tc.code || body.Error != tc.message {
+				t.Fatalf("got %d %+v, want %d %s %q", status, body, tc.status, tc.code, tc.message)
+			}
+		})
+	}
+}
+
+func TestErrorHandler_ValidationDetails(t *testing.T) {
+	err := appErr.Wrap("Handler.Create.Bind", appErr.ErrInvalidInput,
+		appErr.NewValidationError("sexo", "oneof", "El campo 'sexo' debe ser uno de: M, F, I, X"))
+
+	status, body := handle(t, err, nil)
+	if status != http.StatusBadRequest || body.Codigo != "validation_error" {
+		t.Fatalf("got %d %+v", status, body)
+	}
+	if len(body.Detalles) != 1 || body.Detalles[0].Campo != "sexo" {
+		t.Fatalf("missing field details: %+v", body.Detalles)
+	}
+}
@@ -1,30 +1,100 @@
 package middleware
 
 import (
-	"net/http"
+	"errors"
 
 	"github.com/golang-jwt/jwt/v5"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 	authModels "github.com/tonitomc/healthcare-crm-api/internal/domain/auth/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
 )
 
+// ─────────────────────────────────────────────────────────────
+// TokenKeys Interface (decouples from the auth domain)
+// ─────────────────────────────────────────────────────────────
+
+// TokenKeys returns the key that verifies an access token, by its "kid" and algorithm.
+type TokenKeys interface {
+	Keyfunc(t *jwt.Token) (any, error)
+}
+
+var tokenKeys TokenKeys
+
+func InjectTokenKeys(keys TokenKeys) {
+	tokenKeys = keys
+}
+
+var errNoTokenKeys = errors.New("token keys not injected")
+
 // JWTMiddleware validates JWT tokens and injects *jwt.Token into context (key "user").
-// Claims type is your custom struct via NewClaimsFunc.
-func JWTMiddleware(secret string) echo.MiddlewareFunc {
+// Claims type is your custom struct via NewClaimsFunc. The keys come from InjectTokenKeys.
+func JWTMiddleware() echo.MiddlewareFunc {
 	return echojwt.WithConfig(echojwt.Config{
-		SigningKey:    []byte(secret),
-		SigningMethod: echojwt.AlgorithmHS256, // "HS256"
-		ContextKey:    "user",                 // c.Get("user") -> *jwt.Token
+		KeyFunc: func(t *jwt.Token) (any, error) {
+			if tokenKeys == nil {
+				return nil, errNoTokenKeys
+			}
+			return tokenKeys.Keyfunc(t)
+		},
+		ContextKey: "user", // c.Get("user") -> *jwt.Token
 		NewClaimsFunc: func(c echo.Context) jwt.Claims {
 			return new(authModels.Claims)
 		},
 		Skipper: func(c echo.Context) bool {
-			return c.Request().URL.Path == "/api/auth/login"
+			switch c.Request().URL.Path {
+			case "/.well-known/jwks.json",
+				"/api/auth/login",
+				"/api/auth/refresh", "/api/auth/logout", // authenticated by the refresh token
+				"/api/auth/mfa/verify", "/api/auth/mfa/pending/enroll", // authenticated by the MFA token of the login
+				"/api/auth/password/forgot", "/api/auth/password/reset", "/api/auth/invitations/accept",
+				"/api/appointments/calendar.ics": // calendar apps authenticate with a feed token
+				return true
+			}
+			return false
 		},
 	})
 }
 
+// ─────────────────────────────────────────────────────────────
+// SessionValidator Interface (decouples from the auth domain)
+// ─────────────────────────────────────────────────────────────
+
+type SessionValidator interface {
+	IsSessionActive(sessionID string) (bool, error)
+}
+
+var sessionValidator SessionValidator
+
+func InjectSessionValidator(validator SessionValidator) {
+	sessionValidator = validator
+}
+
+// ActiveSession rejects access tokens whose session was closed or revoked, so a
+// logout or an admin revocation takes effect before the token expires. Tokens
+// issued without a session are rejected as well. Requests without a token pass
+// through: RequireAuth and RequirePermission decide about those.
+func ActiveSession() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := GetClaims(c)
+			if claims == nil || sessionValidator == nil {
+				return next(c)
+			}
+
+			active, err := sessionValidator.IsSessionActive(claims.SessionID)
+			if err != nil {
+				return appErr.Wrap("ActiveSession", appErr.ErrInternal, err)
+			}
+			if !active {
+				return appErr.NewDomainError(appErr.ErrInvalidToken, "La sesión fue cerrada o expiró.")
+			}
+
+			return next(c)
+		}
+	}
+}
+
 // RequireAuth ensures a valid JWT exists in context.
 // It does NOT check permissions — only authentication.
 func RequireAuth() echo.MiddlewareFunc {
@@ -32,23 +102,17 @@ func RequireAuth() echo.MiddlewareFunc {
 		return func(c echo.Context) error {
 			token, ok := c.Get("user").(*jwt.Token)
 			if !ok || token == nil {
-				return c.JSON(http.StatusUnauthorized, echo.Map{
-					"error": "Token no válido o ausente.",
-				})
+				return appErr.NewDomainError(appErr.ErrUnauthorized, "Token no válido o ausente.")
 			}
 
 			claims, ok := token.Claims.(*authModels.Claims)
 			if !ok || claims == nil {
-				return c.JSON(http.StatusUnauthorized, echo.Map{
-					"error": "Token inválido.",
-				})
+				return appErr.NewDomainError(appErr.ErrUnauthorized, "Token inválido.")
 			}
 
 			// Optional safety check
 			if claims.UserID <= 0 {
-				return c.JSON(http.StatusUnauthorized, echo.Map{
-					"error": "Token sin ID de usuario válido.",
-				})
+				return appErr.NewDomainError(appErr.ErrUnauthorized, "Token sin ID de usuario válido.")
 			}
 
 			return next(c)
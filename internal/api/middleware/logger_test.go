@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestLogger_RedactsCredentials(t *testing.T) {
+	e := echo.New()
+	var out bytes.Buffer
+	e.Logger.SetOutput(&out)
+	e.Use(Logger())
+	e.GET("/api/appointments/calendar.ics", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	cases := map[string]string{
+		"/api/appointments/calendar.ics?token=s3cret&x=1": "/api/appointments/calendar.ics?token=REDACTED&x=1",
+		"/api/appointments/calendar.ics?x=%22a%22":        `/api/appointments/calendar.ics?x=%22a%22`,
+	}
+	for target, want := range cases {
+		out.Reset()
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+
+		var line struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+			t.Fatalf("%s: invalid log line %q: %v", target, out.String(), err)
+		}
+		if line.URI != want || strings.Contains(out.String(), "s3cret") {
+			t.Errorf("%s: logged %q, want %q", target, line.URI, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// PermissionCache is a PermissionProvider that keeps each user's roles and
+// permissions in memory for a TTL, so RequirePermission does not query the database
+// on every request. InvalidateUser and InvalidateAll drop entries when roles or
+// their permissions change; other instances of the API see the change once their
+// own entries expire.
+type PermissionCache struct {
+	provider PermissionProvider
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[int]permissionEntry
+	// Bumped by every invalidation, so a lookup that raced with one is not cached
+	generation uint64
+}
+
+type permissionEntry struct {
+	roles     []any
+	perms     []PermissionLike
+	expiresAt time.Time
+}
+
+// NewPermissionCache caches the lookups of provider for ttl.
+func NewPermissionCache(provider PermissionProvider, ttl time.Duration) *PermissionCache {
+	return &PermissionCache{
+		provider: provider,
+		ttl:      ttl,
+		entries:  map[int]permissionEntry{},
+	}
+}
+
+// Implements PermissionProvider. Errors are not cached.
+func (c *PermissionCache) GetRolesAndPermissions(userID int) ([]any, []PermissionLike, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	if e, ok := c.entries[userID]; ok && now.Before(e.expiresAt) {
+		c.mu.Unlock()
+		return e.roles, e.perms, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	roles, perms, err := c.provider.GetRolesAndPermissions(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.entries[userID] = permissionEntry{roles: roles, perms: perms, expiresAt: now.Add(c.ttl)}
+	}
+	c.mu.Unlock()
+
+	return roles, perms, nil
+}
+
+// InvalidateUser drops the entry of a user whose roles changed.
+func (c *PermissionCache) InvalidateUser(userID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+	c.generation++
+}
+
+// InvalidateAll drops every entry, e.g. when the permissions of a role change.
+func (c *PermissionCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[int]permissionEntry{}
+	c.generation++
+}
@@ -1,7 +1,8 @@
 package middleware
 
 import (
-	"net/http"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -28,6 +29,29 @@ func InjectPermissionProvider(provider PermissionProvider) {
 	permissionProvider = provider
 }
 
+// ─────────────────────────────────────────────────────────────
+// Permission source
+// ─────────────────────────────────────────────────────────────
+
+// PermissionSource is where RequirePermission takes the user's permissions from.
+// Only one source is trusted.
+type PermissionSource string
+
+const (
+	// PermissionsFromDatabase looks them up through the PermissionProvider (usually a
+	// PermissionCache): a removed permission stops working once the cache drops it.
+	PermissionsFromDatabase PermissionSource = "database"
+	// PermissionsFromToken trusts the permissions signed into the access token: no
+	// lookups, but a removed permission keeps working until the token expires.
+	PermissionsFromToken PermissionSource = "token"
+)
+
+var permissionSource = PermissionsFromDatabase
+
+func InjectPermissionSource(source PermissionSource) {
+	permissionSource = source
+}
+
 // ─────────────────────────────────────────────────────────────
 // Helpers
 // ─────────────────────────────────────────────────────────────
@@ -47,6 +71,26 @@ func hasPermission(perms []string, required string) bool {
 	return false
 }
 
+// UserHasPermission checks the user's current permissions through the
+// PermissionProvider, whatever the PermissionSource. Used directly by routes that
+// authenticate without a JWT (e.g. calendar feed tokens).
+func UserHasPermission(userID int, required string) (bool, error) {
+	if permissionProvider == nil {
+		return false, appErr.Wrap("UserHasPermission(no permission provider injected)", appErr.ErrInternal, nil)
+	}
+
+	_, dbPerms, err := permissionProvider.GetRolesAndPermissions(userID)
+	if err != nil {
+		return false, err
+	}
+
+	var perms []string
+	for _, p := range dbPerms {
+		perms = append(perms, p.GetName())
+	}
+	return hasPermission(perms, required), nil
+}
+
 // ─────────────────────────────────────────────────────────────
 // Middleware
 // ─────────────────────────────────────────────────────────────
@@ -56,53 +100,39 @@ func RequirePermission(required string) echo.MiddlewareFunc {
 		return func(c echo.Context) error {
 			token, ok := c.Get("user").(*jwt.Token)
 			if !ok || token == nil {
-				return c.JSON(http.StatusUnauthorized, echo.Map{
-					"error": "Token no válido o ausente.",
-				})
+				return appErr.NewDomainError(appErr.ErrUnauthorized, "Token no válido o ausente.")
 			}
 
 			claims, ok := token.Claims.(*authModels.Claims)
 			if !ok || claims == nil {
-				return c.JSON(http.StatusUnauthorized, echo.Map{
-					"error": "Estructura de token no válida.",
-				})
+				return appErr.NewDomainError(appErr.ErrUnauthorized, "Estructura de token no válida.")
 			}
 
 			userID := int(claims.UserID)
 			if userID <= 0 {
-				return c.JSON(http.StatusUnauthorized, echo.Map{
-					"error": "Token sin ID de usuario válido.",
-				})
-			}
-
-			if permissionProvider == nil {
-				c.Logger().Error("[RequirePermission] No permission provider injected")
-				return c.JSON(http.StatusInternalServerError, echo.Map{
-					"error": "No se pudo validar permisos — configuración incompleta.",
-				})
-			}
-
-			_, dbPerms, err := permissionProvider.GetRolesAndPermissions(userID)
-			if err != nil {
-				c.Logger().Errorf("[RequirePermission] DB lookup failed: %v", err)
-				return c.JSON(http.StatusInternalServerError, echo.Map{
-					"error": "No se pudieron verificar los permisos del usuario.",
-				})
+				return appErr.NewDomainError(appErr.ErrUnauthorized, "Token sin ID de usuario válido.")
 			}
 
-			var perms []string
-			for _, p := range dbPerms {
-				perms = append(perms, p.GetName())
+			var allowed bool
+			if permissionSource == PermissionsFromToken {
+				allowed = hasPermission(claims.Permissions, required)
+			} else {
+				if permissionProvider == nil {
+					return appErr.Wrap("RequirePermission", appErr.ErrInternal, errors.New("no permission provider injected"))
+				}
+
+				var err error
+				allowed, err = UserHasPermission(userID, required)
+				if err != nil {
+					return appErr.Wrap("RequirePermission(lookup)", appErr.ErrInternal, err)
+				}
 			}
 
-			if hasPermission(perms, required) || hasPermission(claims.Permissions, required) {
+			if allowed {
 				return next(c)
 			}
 
-			c.Logger().Warnf("[RequirePermission] Permission '%s' denied for user %d", required, userID)
-			return c.JSON(http.StatusForbidden, echo.Map{
-				"error": appErr.ErrForbidden.Error(),
-			})
+			return appErr.Wrap(fmt.Sprintf("RequirePermission(%s, user %d)", required, userID), appErr.ErrForbidden, nil)
 		}
 	}
 }
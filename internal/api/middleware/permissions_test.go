@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	authModels "github.com/tonitomc/healthcare-crm-api/internal/domain/auth/models"
+)
+
+type fakePermission string
+
+func (p fakePermission) GetName() string { return string(p) }
+
+type fakePermissionProvider struct {
+	perms map[int][]string
+	calls int
+	err   error
+}
+
+func (f *fakePermissionProvider) GetRolesAndPermissions(userID int) ([]any, []PermissionLike, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	out := []PermissionLike{}
+	for _, p := range f.perms[userID] {
+		out = append(out, fakePermission(p))
+	}
+	return nil, out, nil
+}
+
+func TestPermissionCache(t *testing.T) {
+	provider := &fakePermissionProvider{perms: map[int][]string{1: {"ver-pacientes"}}}
+	cache := NewPermissionCache(provider, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		_, perms, err := cache.GetRolesAndPermissions(1)
+		if err != nil || len(perms) != 1 {
+			t.Fatalf("got %v, %v", perms, err)
+		}
+	}
+	if provider.calls != 1 {
+		t.Fatalf("got %d lookups, want 1", provider.calls)
+	}
+
+	provider.perms[1] = nil
+	cache.InvalidateUser(1)
+	if _, perms, _ := cache.GetRolesAndPermissions(1); len(perms) != 0 {
+		t.Fatalf("got %v after InvalidateUser, want none", perms)
+	}
+
+	cache.GetRolesAndPermissions(2)
+	cache.InvalidateAll()
+	cache.GetRolesAndPermissions(2)
+	if provider.calls != 4 {
+		t.Fatalf("got %d lookups, want 4", provider.calls)
+	}
+
+	// Errors are not cached
+	provider.err = errors.New("db down")
+	if _, _, err := cache.GetRolesAndPermissions(3); err == nil {
+		t.Fatal("want error")
+	}
+	provider.err = nil
+	if _, _, err := cache.GetRolesAndPermissions(3); err != nil {
+		t.Fatalf("got %v after the database came back", err)
+	}
+}
+
+func TestPermissionCache_Expires(t *testing.T) {
+	provider := &fakePermissionProvider{}
+	cache := NewPermissionCache(provider, time.Millisecond)
+
+	cache.GetRolesAndPermissions(1)
+	time.Sleep(5 * time.Millisecond)
+	cache.GetRolesAndPermissions(1)
+	if provider.calls != 2 {
+		t.Fatalf("got %d lookups, want 2", provider.calls)
+	}
+}
+
+func TestRequirePermission_Source(t *testing.T) {
+	provider := &fakePermissionProvider{perms: map[int][]string{1: {"ver-pacientes"}}}
+	InjectPermissionProvider(provider)
+	t.Cleanup(func() {
+		InjectPermissionProvider(nil)
+		InjectPermissionSource(PermissionsFromDatabase)
+	})
+
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// The token still carries a permission the user no longer has
+			c.Set("user", &jwt.Token{Claims: &authModels.Claims{UserID: 1, Permissions: []string{"manejar-usuarios"}}})
+			return next(c)
+		}
+	})
+	e.GET("/pacientes", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, RequirePermission("ver-pacientes"))
+	e.GET("/usuarios", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, RequirePermission("manejar-usuarios"))
+
+	serve := func(path string) int {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	cases := []struct {
+		source PermissionSource
+		path   string
+		want   int
+	}{
+		{PermissionsFromDatabase, "/pacientes", http.StatusOK},
+		{PermissionsFromDatabase, "/usuarios", http.StatusForbidden},
+		{PermissionsFromToken, "/pacientes", http.StatusForbidden},
+		{PermissionsFromToken, "/usuarios", http.StatusOK},
+	}
+	for _, tc := range cases {
+		InjectPermissionSource(tc.source)
+		if got := serve(tc.path); got != tc.want {
+			t.Errorf("%s %s: got %d, want %d", tc.source, tc.path, got, tc.want)
+		}
+	}
+}
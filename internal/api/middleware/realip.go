@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/labstack/echo/v4"
+)
+
+// IPExtractor decides where c.RealIP() takes the client address from. The address
+// counts failed logins and is recorded in the audit and access logs, so headers are
+// only believed when the request comes through one of trustedProxies (CIDR ranges).
+// Without proxies the connection's address is used and X-Forwarded-For is ignored.
+func IPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// Only the configured ranges: echo trusts loopback and private networks by default
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range trustedProxies {
+		_, ipRange, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy range %q: %w", cidr, err)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// serveFrom sends a request from remoteAddr with the given X-Forwarded-For through an
+// audited route and returns the IP the handler saw (the one failed logins count) and
+// the one recorded in the audit log.
+func serveFrom(t *testing.T, trustedProxies []string, remoteAddr, forwardedFor string) (string, string) {
+	t.Helper()
+	recorder := &fakeRecorder{}
+	InjectAuditRecorder(recorder)
+	t.Cleanup(func() { InjectAuditRecorder(nil) })
+
+	extractor, err := IPExtractor(trustedProxies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := echo.New()
+	e.IPExtractor = extractor
+
+	var seen string
+	e.POST("/api/items", func(c echo.Context) error {
+		seen = c.RealIP()
+		return c.JSON(http.StatusCreated, echo.Map{"id": 1})
+	}, Audit("item", "", nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/items", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+	req.Header.Set(echo.HeaderXRealIP, forwardedFor)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(recorder.records) != 1 {
+		t.Fatalf("got %d records", len(recorder.records))
+	}
+	return seen, recorder.records[0].IP
+}
+
+func TestIPExtractor_IgnoresForgedHeadersWithoutProxies(t *testing.T) {
+	seen, recorded := serveFrom(t, nil, "203.0.113.7:52000", "198.51.100.1")
+	if seen != "203.0.113.7" || recorded != "203.0.113.7" {
+		t.Fatalf("forged header was used: counted %q, recorded %q", seen, recorded)
+	}
+}
+
+func TestIPExtractor_UntrustedClientCannotForgeHeaders(t *testing.T) {
+	// A private address is not trusted unless configured
+	seen, recorded := serveFrom(t, []string{"10.0.0.0/24"}, "192.168.1.20:52000", "198.51.100.1")
+	if seen != "192.168.1.20" || recorded != "192.168.1.20" {
+		t.Fatalf("forged header was used: counted %q, recorded %q", seen, recorded)
+	}
+}
+
+func TestIPExtractor_TrustedProxy(t *testing.T) {
+	// The proxy appends the address it saw after whatever the client sent
+	seen, recorded := serveFrom(t, []string{"10.0.0.0/24"}, "10.0.0.2:52000", "198.51.100.1, 203.0.113.7")
+	if seen != "203.0.113.7" || recorded != "203.0.113.7" {
+		t.Fatalf("expected the address seen by the proxy: counted %q, recorded %q", seen, recorded)
+	}
+}
+
+func TestIPExtractor_InvalidRange(t *testing.T) {
+	if _, err := IPExtractor([]string{"10.0.0.0"}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,326 @@
+// Package query holds the list options shared by the list endpoints: pagination
+// (page or keyset cursor), sorting and typed filters. Handlers parse them from the
+// query string with Parse and wrap the result in a Page envelope; repositories turn
+// them into SQL and return the page together with the total row count.
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/timeutil"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+
+	dateLayout = "2006-01-02"
+)
+
+// FilterType determina cómo se interpreta y compara el valor de un filtro
+type FilterType int
+
+const (
+	Text     FilterType = iota // contiene, sin distinguir mayúsculas
+	Exact                      // texto exacto
+	Int                        // entero exacto
+	Bool                       // true / false
+	DateFrom                   // fecha >= AAAA-MM-DD
+	DateTo                     // fecha <= AAAA-MM-DD (día completo)
+)
+
+// Spec declara lo que acepta un listado. Los nombres son los de la API; cada
+// repositorio los traduce a columnas con Columns.
+type Spec struct {
+	Sortable    []string
+	Filters     map[string]FilterType
+	DefaultSort []Sort
+}
+
+// Sort es un criterio de orden; ?sort=-fecha,nombre
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Filter es un filtro ya validado y convertido a su tipo
+type Filter struct {
+	Field string
+	Type  FilterType
+	Value interface{}
+}
+
+// Options son las opciones de un listado. Page es 0 cuando se pagina por cursor.
+type Options struct {
+	Limit   int
+	Offset  int
+	Page    int
+	Sort    []Sort
+	Filters []Filter
+	// After son los valores de orden de la última fila de la página anterior, uno por
+	// campo de Sort y al final el id; solo al paginar por cursor (ver Seek)
+	After []interface{}
+}
+
+// SortKey devuelve el valor de un campo de orden de un elemento, incluido "id"; con él
+// se arma el cursor de la página siguiente.
+type SortKey[T any] func(item T, field string) interface{}
+
+// Page es el sobre común de las respuestas paginadas
+type Page[T any] struct {
+	Data       []T     `json:"data"`
+	Total      int     `json:"total"`
+	Limit      int     `json:"limit"`
+	Page       int     `json:"page,omitempty"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// NewPage arma el sobre para los elementos de una página y el total de filas que
+// cumplen los filtros. El cursor siguiente apunta después del último elemento, así
+// que las filas agregadas o borradas entre páginas no repiten ni saltan elementos.
+func NewPage[T any](items []T, total int, opts Options, key SortKey[T]) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	p := &Page[T]{Data: items, Total: total, Limit: opts.Limit, Page: opts.Page}
+
+	// Por página se sabe si quedan filas; por cursor, solo si la página vino llena
+	more := opts.Offset+len(items) < total
+	if opts.Page == 0 {
+		more = len(items) == opts.Limit
+	}
+	if len(items) > 0 && more {
+		cursor := encodeCursor(opts.Sort, items[len(items)-1], key)
+		p.NextCursor = &cursor
+	}
+	return p
+}
+
+// Parse lee ?limit=, ?page= o ?cursor=, ?sort= y los filtros declarados en spec.
+// Los errores son de dominio (ErrInvalidInput) con un mensaje apto para el cliente.
+func Parse(c echo.Context, spec Spec) (Options, error) {
+	opts, err := parseOptions(c, spec)
+	if err != nil {
+		return Options{}, appErr.NewDomainError(appErr.ErrInvalidInput, err.Error())
+	}
+	return opts, nil
+}
+
+func parseOptions(c echo.Context, spec Spec) (Options, error) {
+	opts := Options{Limit: DefaultLimit, Sort: spec.DefaultSort}
+
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return Options{}, fmt.Errorf("El parámetro 'limit' debe estar entre 1 y %d", MaxLimit)
+		}
+		opts.Limit = limit
+	}
+
+	if raw := c.QueryParam("sort"); raw != "" {
+		sorts, err := parseSort(raw, spec.Sortable)
+		if err != nil {
+			return Options{}, err
+		}
+		opts.Sort = sorts
+	}
+
+	page, cursor := c.QueryParam("page"), c.QueryParam("cursor")
+	switch {
+	case page != "" && cursor != "":
+		return Options{}, errors.New("Use 'page' o 'cursor', no ambos")
+	case cursor != "":
+		after, err := decodeCursor(cursor, opts.Sort)
+		if err != nil {
+			return Options{}, errors.New("El parámetro 'cursor' es inválido o no corresponde al orden indicado")
+		}
+		opts.After = after
+	default:
+		opts.Page = 1
+		if page != "" {
+			n, err := strconv.Atoi(page)
+			if err != nil || n < 1 {
+				return Options{}, errors.New("El parámetro 'page' debe ser un entero positivo")
+			}
+			if n > math.MaxInt/opts.Limit {
+				return Options{}, errors.New("El parámetro 'page' es demasiado grande")
+			}
+			opts.Page = n
+		}
+		opts.Offset = (opts.Page - 1) * opts.Limit
+	}
+
+	names := make([]string, 0, len(spec.Filters))
+	for name := range spec.Filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		typ := spec.Filters[name]
+		raw := strings.TrimSpace(c.QueryParam(name))
+		if raw == "" {
+			continue
+		}
+		value, err := parseFilter(typ, raw)
+		if err != nil {
+			return Options{}, fmt.Errorf("El filtro '%s' es inválido: %v", name, err)
+		}
+		opts.Filters = append(opts.Filters, Filter{Field: name, Type: typ, Value: value})
+	}
+	return opts, nil
+}
+
+func parseSort(raw string, sortable []string) ([]Sort, error) {
+	var sorts []Sort
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		s := Sort{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !contains(sortable, s.Field) {
+			return nil, fmt.Errorf("No se puede ordenar por '%s'", s.Field)
+		}
+		sorts = append(sorts, s)
+	}
+	return sorts, nil
+}
+
+func parseFilter(typ FilterType, raw string) (interface{}, error) {
+	switch typ {
+	case Int:
+		return strconv.Atoi(raw)
+	case Bool:
+		return strconv.ParseBool(raw)
+	case DateFrom, DateTo:
+		d, err := time.ParseInLocation(dateLayout, raw, timeutil.ClinicLocation())
+		if err != nil {
+			return nil, errors.New("use AAAA-MM-DD")
+		}
+		return d, nil
+	default:
+		return raw, nil
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// El cursor es opaco para el cliente: guarda el orden con el que se armó y los
+// valores de orden de la última fila entregada. Cada valor lleva su tipo para
+// volver a compararlo en SQL tal como vino de la base.
+type cursor struct {
+	Sort string      `json:"s"`
+	Keys []cursorKey `json:"k"`
+}
+
+type cursorKey struct {
+	Type  string `json:"t"` // "s" texto, "i" entero, "d" fecha/hora, "n" nulo
+	Value string `json:"v,omitempty"`
+}
+
+func sortString(sorts []Sort) string {
+	parts := make([]string, len(sorts))
+	for i, s := range sorts {
+		parts[i] = s.Field
+		if s.Desc {
+			parts[i] = "-" + s.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func encodeCursor[T any](sorts []Sort, last T, key SortKey[T]) string {
+	c := cursor{Sort: sortString(sorts)}
+	for _, s := range sorts {
+		c.Keys = append(c.Keys, encodeKey(key(last, s.Field)))
+	}
+	c.Keys = append(c.Keys, encodeKey(key(last, "id")))
+
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func encodeKey(v interface{}) cursorKey {
+	// Los campos opcionales llegan como punteros
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return cursorKey{Type: "n"}
+		}
+		v = rv.Elem().Interface()
+	}
+	switch v := v.(type) {
+	case nil:
+		return cursorKey{Type: "n"}
+	case string:
+		return cursorKey{Type: "s", Value: v}
+	case time.Time:
+		return cursorKey{Type: "d", Value: v.Format(time.RFC3339Nano)}
+	case int:
+		return cursorKey{Type: "i", Value: strconv.FormatInt(int64(v), 10)}
+	case int64:
+		return cursorKey{Type: "i", Value: strconv.FormatInt(v, 10)}
+	default:
+		return cursorKey{Type: "s", Value: fmt.Sprint(v)}
+	}
+}
+
+// decodeCursor devuelve los valores de Options.After; el cursor debe ser del mismo orden
+func decodeCursor(raw string, sorts []Sort) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, err
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if c.Sort != sortString(sorts) || len(c.Keys) != len(sorts)+1 {
+		return nil, errors.New("cursor for another sort")
+	}
+
+	after := make([]interface{}, len(c.Keys))
+	for i, k := range c.Keys {
+		switch k.Type {
+		case "n":
+			after[i] = nil
+		case "s":
+			after[i] = k.Value
+		case "i":
+			n, err := strconv.ParseInt(k.Value, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			after[i] = n
+		case "d":
+			t, err := time.Parse(time.RFC3339Nano, k.Value)
+			if err != nil {
+				return nil, err
+			}
+			after[i] = t
+		default:
+			return nil, errors.New("invalid cursor key")
+		}
+	}
+	if after[len(after)-1] == nil {
+		return nil, errors.New("cursor without id")
+	}
+	return after, nil
+}
@@ -0,0 +1,195 @@
+package query
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+var testSpec = Spec{
+	Sortable: []string{"nombre", "fecha"},
+	Filters: map[string]FilterType{
+		"nombre":      Text,
+		"paciente_id": Int,
+		"completada":  Bool,
+		"desde":       DateFrom,
+		"hasta":       DateTo,
+	},
+	DefaultSort: []Sort{{Field: "nombre"}},
+}
+
+var testColumns = Columns{
+	"nombre":      "p.nombre",
+	"fecha":       "c.fecha",
+	"paciente_id": "c.paciente_id",
+	"completada":  "c.completada",
+	"desde":       "c.fecha",
+	"hasta":       "c.fecha",
+}
+
+func parse(t *testing.T, rawQuery string) (Options, error) {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/?"+rawQuery, nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	return Parse(c, testSpec)
+}
+
+func TestParse_Defaults(t *testing.T) {
+	opts, err := parse(t, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Limit != DefaultLimit || opts.Page != 1 || opts.Offset != 0 {
+		t.Fatalf("unexpected defaults %+v", opts)
+	}
+	if len(opts.Sort) != 1 || opts.Sort[0].Field != "nombre" {
+		t.Fatalf("default sort not applied: %+v", opts.Sort)
+	}
+}
+
+func TestParse_PageAndSort(t *testing.T) {
+	opts, err := parse(t, "limit=20&page=3&sort=-fecha,nombre")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Offset != 40 {
+		t.Fatalf("expected offset 40, got %d", opts.Offset)
+	}
+	if len(opts.Sort) != 2 || !opts.Sort[0].Desc || opts.Sort[1].Desc {
+		t.Fatalf("unexpected sort %+v", opts.Sort)
+	}
+}
+
+func TestParse_Rejects(t *testing.T) {
+	for _, q := range []string{
+		"limit=0",
+		"limit=1000",
+		"page=0",
+		"page=9223372036854775807",
+		"limit=200&page=46116860184273880",
+		"page=1&cursor=abc",
+		"cursor=not-a-cursor",
+		"sort=password",
+		"paciente_id=abc",
+		"completada=quizas",
+		"desde=03-03-2025",
+	} {
+		if _, err := parse(t, q); err == nil {
+			t.Errorf("expected error for %q", q)
+		}
+	}
+}
+
+type row struct {
+	id     int
+	nombre *string
+	fecha  time.Time
+}
+
+func rowKey(r row, field string) interface{} {
+	switch field {
+	case "nombre":
+		return r.nombre
+	case "fecha":
+		return r.fecha
+	}
+	return r.id
+}
+
+func TestNewPage_CursorRoundTrip(t *testing.T) {
+	ana := "Ana"
+	fecha := time.Date(2025, 3, 3, 9, 30, 0, 0, time.UTC)
+	opts, _ := parse(t, "limit=2&sort=-fecha,nombre")
+	page := NewPage([]row{{id: 1}, {id: 7, nombre: &ana, fecha: fecha}}, 5, opts, rowKey)
+	if page.NextCursor == nil || page.Total != 5 {
+		t.Fatalf("expected next cursor, got %+v", page)
+	}
+
+	next, err := parse(t, "limit=2&sort=-fecha,nombre&cursor="+*page.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Offset != 0 || next.Page != 0 || len(next.After) != 3 {
+		t.Fatalf("cursor did not resume after the last row: %+v", next)
+	}
+	if !next.After[0].(time.Time).Equal(fecha) || next.After[1] != "Ana" || next.After[2] != int64(7) {
+		t.Fatalf("unexpected cursor values %v", next.After)
+	}
+
+	// The cursor belongs to the sort it was made for
+	if _, err := parse(t, "limit=2&sort=nombre&cursor="+*page.NextCursor); err == nil {
+		t.Fatal("expected an error for a cursor of another sort")
+	}
+
+	if last := NewPage([]row{{id: 9}}, 5, next, rowKey); last.NextCursor != nil {
+		t.Fatal("a page that is not full should not have a next cursor")
+	}
+	if last := NewPage([]row{{id: 9}}, 5, Options{Limit: 2, Offset: 4, Page: 3}, rowKey); last.NextCursor != nil {
+		t.Fatal("last page should not have a next cursor")
+	}
+	if empty := NewPage[row](nil, 0, opts, rowKey); empty.Data == nil {
+		t.Fatal("data should be an empty list, not null")
+	}
+}
+
+func TestOptions_Seek(t *testing.T) {
+	fecha := time.Date(2025, 3, 3, 9, 30, 0, 0, time.UTC)
+	opts := Options{
+		Sort:  []Sort{{Field: "fecha", Desc: true}, {Field: "nombre"}},
+		After: []interface{}{fecha, "Ana", int64(7)},
+	}
+	conds, args := opts.Seek(testColumns, "c.id", []string{"c.paciente_id = $1"}, []interface{}{3})
+	want := "((c.fecha < $2 OR c.fecha IS NULL)) OR " +
+		"(c.fecha = $2 AND (p.nombre > $3 OR p.nombre IS NULL)) OR " +
+		"(c.fecha = $2 AND p.nombre = $3 AND c.id > $4)"
+	if len(conds) != 2 || conds[1] != "("+want+")" {
+		t.Fatalf("unexpected seek %q", conds)
+	}
+	if len(args) != 4 || args[1] != fecha || args[2] != "Ana" || args[3] != int64(7) {
+		t.Fatalf("unexpected args %v", args)
+	}
+
+	// After a null only other nulls follow
+	opts.After = []interface{}{nil, "Ana", int64(7)}
+	conds, _ = opts.Seek(testColumns, "c.id", nil, nil)
+	want = "(c.fecha IS NULL AND (p.nombre > $1 OR p.nombre IS NULL)) OR " +
+		"(c.fecha IS NULL AND p.nombre = $1 AND c.id > $2)"
+	if conds[0] != "("+want+")" {
+		t.Fatalf("unexpected seek %q", conds)
+	}
+
+	if conds, _ := (Options{Sort: opts.Sort}).Seek(testColumns, "c.id", nil, nil); len(conds) != 0 {
+		t.Fatalf("no cursor should add no condition: %q", conds)
+	}
+}
+
+func TestOptions_SQL(t *testing.T) {
+	opts, err := parse(t, "nombre=50%25_ana&paciente_id=7&hasta=2025-03-03&sort=-fecha&limit=10&page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conds, args := opts.Conditions(testColumns, []interface{}{"base"})
+	where := Where(conds)
+	if where != " WHERE c.fecha < $2 AND p.nombre ILIKE $3 AND c.paciente_id = $4" {
+		t.Fatalf("unexpected where %q", where)
+	}
+	if args[2] != `%50\%\_ana%` {
+		t.Fatalf("LIKE value not escaped: %q", args[2])
+	}
+	if day := args[1]; !strings.HasPrefix(day.(interface{ String() string }).String(), "2025-03-04") {
+		t.Fatalf("hasta should include the whole day, got %v", day)
+	}
+
+	if order := opts.OrderBy(testColumns, "c.id"); order != " ORDER BY c.fecha DESC NULLS LAST, c.id" {
+		t.Fatalf("unexpected order %q", order)
+	}
+
+	page, args := opts.LimitOffset(args)
+	if page != " LIMIT $5 OFFSET $6" || args[4] != 10 || args[5] != 10 {
+		t.Fatalf("unexpected page %q %v", page, args)
+	}
+}
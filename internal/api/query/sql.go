@@ -0,0 +1,116 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Columns traduce los nombres de campo de la API (orden y filtros) a expresiones SQL
+type Columns map[string]string
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Conditions devuelve las condiciones SQL de los filtros. Los placeholders continúan
+// después de los args existentes; se devuelven los args extendidos.
+func (o Options) Conditions(cols Columns, args []interface{}) ([]string, []interface{}) {
+	var conds []string
+	for _, f := range o.Filters {
+		col, ok := cols[f.Field]
+		if !ok {
+			continue
+		}
+		value := f.Value
+		var op string
+		switch f.Type {
+		case Text:
+			op = "ILIKE"
+			value = "%" + likeEscaper.Replace(f.Value.(string)) + "%"
+		case DateFrom:
+			op = ">="
+		case DateTo:
+			// Incluye el día completo
+			op = "<"
+			value = f.Value.(time.Time).AddDate(0, 0, 1)
+		default:
+			op = "="
+		}
+		args = append(args, value)
+		conds = append(conds, col+" "+op+" $"+strconv.Itoa(len(args)))
+	}
+	return conds, args
+}
+
+// Where une condiciones en una cláusula WHERE; vacía si no hay condiciones
+func Where(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+// OrderBy arma el ORDER BY. tiebreak (normalmente la llave primaria) se agrega al
+// final para que el orden sea estable entre páginas.
+func (o Options) OrderBy(cols Columns, tiebreak string) string {
+	parts := make([]string, 0, len(o.Sort)+1)
+	for _, s := range o.Sort {
+		col, ok := cols[s.Field]
+		if !ok {
+			continue
+		}
+		dir := " ASC"
+		if s.Desc {
+			dir = " DESC"
+		}
+		parts = append(parts, col+dir+" NULLS LAST")
+	}
+	parts = append(parts, tiebreak)
+	return " ORDER BY " + strings.Join(parts, ", ")
+}
+
+// Seek agrega a conds la condición del cursor: solo las filas que van después de la
+// última de la página anterior en el orden de OrderBy (con los nulos al final).
+// Sin cursor no agrega nada. Se usa después de contar el total, que no la incluye.
+func (o Options) Seek(cols Columns, tiebreak string, conds []string, args []interface{}) ([]string, []interface{}) {
+	if len(o.After) != len(o.Sort)+1 {
+		return conds, args
+	}
+
+	// (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ... OR (c1 = v1 AND ... AND id > vid)
+	var alternatives, equal []string
+	for i, s := range o.Sort {
+		col, ok := cols[s.Field]
+		if !ok {
+			continue
+		}
+		value := o.After[i]
+		if value == nil {
+			// Después de un nulo solo siguen otros nulos, desempatados por el id
+			equal = append(equal, col+" IS NULL")
+			continue
+		}
+		args = append(args, value)
+		param := "$" + strconv.Itoa(len(args))
+		op := " > "
+		if s.Desc {
+			op = " < "
+		}
+		alternatives = append(alternatives, and(append(equal, "("+col+op+param+" OR "+col+" IS NULL)")))
+		equal = append(equal, col+" = "+param)
+	}
+	args = append(args, o.After[len(o.After)-1])
+	alternatives = append(alternatives, and(append(equal, tiebreak+" > $"+strconv.Itoa(len(args)))))
+
+	return append(conds, "("+strings.Join(alternatives, " OR ")+")"), args
+}
+
+func and(conds []string) string {
+	return "(" + strings.Join(conds, " AND ") + ")"
+}
+
+// LimitOffset agrega LIMIT y OFFSET como parámetros
+func (o Options) LimitOffset(args []interface{}) (string, []interface{}) {
+	args = append(args, o.Limit, o.Offset)
+	n := len(args)
+	return " LIMIT $" + strconv.Itoa(n-1) + " OFFSET $" + strconv.Itoa(n), args
+}
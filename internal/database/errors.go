@@ -20,6 +20,7 @@ const (
 	CodeForeignKeyViolation = "23503"
 	CodeNotNullViolation    = "23502"
 	CodeCheckViolation      = "23514"
+	CodeExclusionViolation  = "23P01"
 	CodeInvalidTextRep      = "22P02"
 	CodeSerializationFail   = "40001"
 )
@@ -32,6 +33,7 @@ var errorMap = map[string]error{
 	CodeForeignKeyViolation: appErr.ErrInvalidInput,   // invalid FK reference
 	CodeNotNullViolation:    appErr.ErrIncompleteData, // missing required value
 	CodeCheckViolation:      appErr.ErrInvalidInput,   // constraint validation failed
+	CodeExclusionViolation:  appErr.ErrConflict,       // overlapping rows (EXCLUDE constraint)
 	CodeInvalidTextRep:      appErr.ErrInvalidRequest, // malformed literal or bad type
 	CodeSerializationFail:   appErr.ErrConflict,       // concurrent write conflict
 }
@@ -72,6 +74,17 @@ func MapSQLError(err error, context string) error {
 	return appErr.Wrap(context, appErr.ErrInternal, err)
 }
 
+// -----------------------------------------------------------------------------
+// HasSQLState
+// -----------------------------------------------------------------------------
+
+// HasSQLState reports whether err comes from PostgreSQL with the given SQLSTATE code.
+// Useful when a repository wants a domain-specific message for a known constraint.
+func HasSQLState(err error, code string) bool {
+	var pqe pqError
+	return errors.As(err, &pqe) && pqe.SQLState() == code
+}
+
 // -----------------------------------------------------------------------------
 // MapTxError
 // -----------------------------------------------------------------------------
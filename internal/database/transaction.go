@@ -0,0 +1,83 @@
+package database
+
+import (
+	"database/sql"
+)
+
+// DBTX is the query surface shared by *sql.DB and *sql.Tx.
+//
+// Repositories hold a DBTX instead of a *sql.DB so the same code runs either
+// directly against the pool or inside a transaction opened by a UnitOfWork.
+type DBTX interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// -----------------------------------------------------------------------------
+// UnitOfWork
+// -----------------------------------------------------------------------------
+
+// UnitOfWork runs a multi-repository operation atomically.
+//
+// Services bind the repositories they need to the transaction handed to fn
+// (via each repository's WithTx). Returning an error from fn rolls everything
+// back; returning nil commits.
+//
+//	err := uow.Do(func(tx database.DBTX) error {
+//	    id, err := patientRepo.WithTx(tx).Create(dto)
+//	    ...
+//	})
+type UnitOfWork interface {
+	Do(fn func(tx DBTX) error) error
+}
+
+type unitOfWork struct {
+	db DBTX
+}
+
+func NewUnitOfWork(db *sql.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+// JoinUnitOfWork returns a UnitOfWork whose Do runs inside the already open
+// transaction tx instead of starting a new one.
+func JoinUnitOfWork(tx DBTX) UnitOfWork {
+	return &unitOfWork{db: tx}
+}
+
+func (u *unitOfWork) Do(fn func(tx DBTX) error) error {
+	return RunInTx(u.db, "UnitOfWork.Do", fn)
+}
+
+// -----------------------------------------------------------------------------
+// RunInTx
+// -----------------------------------------------------------------------------
+
+// RunInTx runs fn inside a transaction on q.
+//
+// Only a *sql.DB starts a new transaction. Anything else is treated as an open
+// transaction: fn joins it and whoever opened it keeps control of commit and
+// rollback. This lets repository methods that need their own transaction take
+// part in a larger UnitOfWork unchanged.
+func RunInTx(q DBTX, context string, fn func(tx DBTX) error) error {
+	db, ok := q.(*sql.DB)
+	if !ok {
+		return fn(q)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return MapSQLError(err, context+"(begin)")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return MapTxError(err, context+"(commit)")
+	}
+	return nil
+}
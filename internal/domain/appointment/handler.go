@@ -1,15 +1,23 @@
 package appointment
 
 import (
+	"bytes"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/ical"
 )
 
+// maxImportBytes limita el tamaño del archivo .ics importado
+const maxImportBytes = 1 << 20
+
 type Handler struct {
 	service Service
 }
@@ -19,23 +27,50 @@ func NewHandler(service Service) *Handler {
 }
 
 func (h *Handler) RegisterRoutes(e *echo.Group) {
-	appointments := e.Group("/appointments", ErrorMiddleware())
+	appointments := e.Group("/appointments", ErrorMiddleware(), middleware.Audit("cita", "id", h.auditedAppointment))
 
 	appointments.GET("", h.GetBetween, middleware.RequirePermission("ver-citas"))
 	appointments.GET("/:id", h.GetByID, middleware.RequirePermission("ver-citas"))
 	appointments.GET("/today", h.GetToday, middleware.RequirePermission("ver-citas"))
 	appointments.GET("/date/:date", h.GetByDate, middleware.RequirePermission("ver-citas"))
+	appointments.GET("/date/:date/providers", h.GetDayByProvider, middleware.RequirePermission("ver-citas"))
+	appointments.GET("/doctors", h.ListDoctors, middleware.RequirePermission("ver-citas"))
 	appointments.GET("/available-slots/:date", h.GetAvailableSlots, middleware.RequirePermission("ver-citas"))
+	appointments.GET("/next-available", h.GetNextAvailableSlots, middleware.RequirePermission("ver-citas"))
 	appointments.POST("", h.Create, middleware.RequirePermission("manejar-citas"))
 	appointments.POST("/with-new-patient", h.CreateWithNewPatient, middleware.RequirePermission("manejar-citas"))
 	appointments.PUT("/:id", h.Update, middleware.RequirePermission("manejar-citas"))
-	appointments.DELETE("/:id", h.Delete, middleware.RequirePermission("manejar-citas"))
+	appointments.DELETE("/:id", h.Cancel, middleware.RequirePermission("manejar-citas"))
+
+	// Ciclo de vida
+	appointments.PUT("/:id/status", h.ChangeStatus, middleware.RequirePermission("manejar-citas"))
+	appointments.GET("/:id/history", h.GetStatusHistory, middleware.RequirePermission("ver-citas"))
+	appointments.POST("/:id/check-in", h.CheckIn, middleware.RequirePermission("manejar-citas"))
+
+	// Series recurrentes
+	appointments.GET("/series/:serieId", h.GetBySeries, middleware.RequirePermission("ver-citas"))
+	appointments.POST("/series", h.CreateSeries, middleware.RequirePermission("manejar-citas"), middleware.Audit("serie", "", nil))
+	appointments.PUT("/:id/series", h.UpdateSeries, middleware.RequirePermission("manejar-citas"))
+	appointments.DELETE("/:id/series", h.CancelSeries, middleware.RequirePermission("manejar-citas"))
+
+	// Calendario iCalendar. El feed se autentica con el token de la URL (los clientes de
+	// calendario no envían el JWT), por eso queda fuera del middleware JWT; middleware.Logger
+	// no escribe el token en el log.
+	appointments.GET("/calendar.ics", h.GetCalendarFeed)
+	appointments.POST("/calendar/token", h.CreateCalendarToken, middleware.RequirePermission("ver-citas"), middleware.Audit("token_calendario", "", nil))
+	appointments.DELETE("/calendar/token", h.RevokeCalendarToken, middleware.RequirePermission("ver-citas"), middleware.Audit("token_calendario", "", nil))
+	appointments.POST("/import", h.ImportCalendar, middleware.RequirePermission("manejar-citas"), middleware.Audit("importacion", "", nil))
+}
+
+// auditedAppointment is the state middleware.Audit records before and after a change
+func (h *Handler) auditedAppointment(id int) (interface{}, error) {
+	return h.service.GetByID(id)
 }
 
 func (h *Handler) GetByID(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "ID inválido"})
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
 	}
 	appt, err := h.service.GetByID(id)
 	if err != nil {
@@ -49,7 +84,11 @@ func (h *Handler) GetToday(c echo.Context) error {
 	clinicLoc, _ := time.LoadLocation("America/Guatemala")
 	// time.Now() podría venir en otro TZ según el servidor; normalizamos
 	today := time.Now().In(clinicLoc)
-	appts, err := h.service.GetByDate(today)
+	filter, err := listFilter(c)
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "El parámetro 'doctor_id' debe ser numérico")
+	}
+	appts, err := h.service.GetByDate(today, filter)
 	if err != nil {
 		return err
 	}
@@ -60,40 +99,71 @@ func (h *Handler) GetByDate(c echo.Context) error {
 	dateStr := c.Param("date")
 	date, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Formato de fecha inválido, use AAAA-MM-DD"})
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "Formato de fecha inválido, use AAAA-MM-DD")
 	}
 	clinicLoc, _ := time.LoadLocation("America/Guatemala")
 	localized := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, clinicLoc)
-	appts, err := h.service.GetByDate(localized)
+	filter, err := listFilter(c)
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "El parámetro 'doctor_id' debe ser numérico")
+	}
+	appts, err := h.service.GetByDate(localized, filter)
 	if err != nil {
 		return err
 	}
 	return c.JSON(http.StatusOK, appts)
 }
 
+// GetDayByProvider devuelve las citas del día agrupadas en columnas por doctor
+func (h *Handler) GetDayByProvider(c echo.Context) error {
+	date, err := time.Parse("2006-01-02", c.Param("date"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "Formato de fecha inválido, use AAAA-MM-DD")
+	}
+	clinicLoc, _ := time.LoadLocation("America/Guatemala")
+	localized := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, clinicLoc)
+	columns, err := h.service.GetDayByProvider(localized, statusFilter(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, columns)
+}
+
+func (h *Handler) ListDoctors(c echo.Context) error {
+	doctors, err := h.service.ListDoctors()
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, doctors)
+}
+
 func (h *Handler) GetBetween(c echo.Context) error {
 	startStr := c.QueryParam("start")
 	endStr := c.QueryParam("end")
 
 	if startStr == "" || endStr == "" {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Se requieren los parámetros 'start' y 'end' en formato AAAA-MM-DD"})
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "Se requieren los parámetros 'start' y 'end' en formato AAAA-MM-DD")
 	}
 
 	startDate, err := time.Parse("2006-01-02", startStr)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Formato de fecha inicial inválido, use AAAA-MM-DD"})
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "Formato de fecha inicial inválido, use AAAA-MM-DD")
 	}
 
 	endDate, err := time.Parse("2006-01-02", endStr)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Formato de fecha final inválido, use AAAA-MM-DD"})
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "Formato de fecha final inválido, use AAAA-MM-DD")
 	}
 
 	clinicLoc, _ := time.LoadLocation("America/Guatemala")
 	localizedStart := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, clinicLoc)
 	localizedEnd := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 999999999, clinicLoc)
 
-	appts, err := h.service.GetBetween(localizedStart, localizedEnd)
+	filter, err := listFilter(c)
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "El parámetro 'doctor_id' debe ser numérico")
+	}
+	appts, err := h.service.GetBetween(localizedStart, localizedEnd, filter)
 	if err != nil {
 		return err
 	}
@@ -103,7 +173,7 @@ func (h *Handler) GetBetween(c echo.Context) error {
 func (h *Handler) Create(c echo.Context) error {
 	var req models.AppointmentCreateDTO
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Cuerpo de solicitud inválido"})
+		return appErr.Wrap("Appointment.Create.Bind", appErr.ErrInvalidInput, err)
 	}
 	id, err := h.service.Create(&req)
 	if err != nil {
@@ -115,11 +185,11 @@ func (h *Handler) Create(c echo.Context) error {
 func (h *Handler) Update(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "ID inválido"})
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
 	}
 	var req models.AppointmentUpdateDTO
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Cuerpo de solicitud inválido"})
+		return appErr.Wrap("Appointment.Update.Bind", appErr.ErrInvalidInput, err)
 	}
 	if err := h.service.Update(id, &req); err != nil {
 		return err
@@ -127,21 +197,78 @@ func (h *Handler) Update(c echo.Context) error {
 	return c.JSON(http.StatusOK, echo.Map{"message": "Cita actualizada exitosamente"})
 }
 
-func (h *Handler) Delete(c echo.Context) error {
+// Cancel cancela la cita (no se elimina); ?motivo= opcional
+func (h *Handler) Cancel(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "ID inválido"})
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
 	}
-	if err := h.service.Delete(id); err != nil {
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return appErr.Wrap("Appointment.Cancel.GetClaims", appErr.ErrUnauthorized, nil)
+	}
+	if err := h.service.Cancel(id, claims.UserID, optionalQuery(c, "motivo")); err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, echo.Map{"message": "Cita eliminada exitosamente"})
+	return c.JSON(http.StatusOK, echo.Map{"message": "Cita cancelada exitosamente"})
+}
+
+func (h *Handler) ChangeStatus(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	var req models.StatusChangeDTO
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Appointment.ChangeStatus.Bind", appErr.ErrInvalidInput, err)
+	}
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return appErr.Wrap("Appointment.ChangeStatus.GetClaims", appErr.ErrUnauthorized, nil)
+	}
+	if err := h.service.ChangeStatus(id, claims.UserID, &req); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": "Estado de la cita actualizado exitosamente"})
+}
+
+// CheckIn registra la llegada del paciente y abre su consulta
+func (h *Handler) CheckIn(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	var req models.CheckInDTO
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Appointment.CheckIn.Bind", appErr.ErrInvalidInput, err)
+	}
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return appErr.Wrap("Appointment.CheckIn.GetClaims", appErr.ErrUnauthorized, nil)
+	}
+	result, err := h.service.CheckIn(id, claims.UserID, &req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, result)
+}
+
+func (h *Handler) GetStatusHistory(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	history, err := h.service.GetStatusHistory(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, history)
 }
 
 func (h *Handler) CreateWithNewPatient(c echo.Context) error {
 	var req models.AppointmentWithNewPatientDTO
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Cuerpo de solicitud inválido"})
+		return appErr.Wrap("Appointment.CreateWithNewPatient.Bind", appErr.ErrInvalidInput, err)
 	}
 	id, err := h.service.CreateWithNewPatient(&req)
 	if err != nil {
@@ -154,21 +281,292 @@ func (h *Handler) GetAvailableSlots(c echo.Context) error {
 	dateStr := c.Param("date")
 	date, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Formato de fecha inválido, use AAAA-MM-DD"})
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "Formato de fecha inválido, use AAAA-MM-DD")
 	}
 	clinicLoc, _ := time.LoadLocation("America/Guatemala")
 	localized := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, clinicLoc)
 
-	slotDuration := int64(900) // 15 min default
+	opts, err := slotOptions(c)
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "Los parámetros 'buffer', 'doctor_id' y 'tipo_id' deben ser numéricos")
+	}
+
+	slots, err := h.service.GetAvailableSlots(localized, opts)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, slots)
+}
+
+// GetNextAvailableSlots devuelve los próximos horarios libres;
+// ?from=AAAA-MM-DD (default: ahora), ?count=N (default: 5), ?duration=segundos, ?buffer=minutos, ?tipo_id=N
+func (h *Handler) GetNextAvailableSlots(c echo.Context) error {
+	clinicLoc, _ := time.LoadLocation("America/Guatemala")
+	from := time.Now().In(clinicLoc)
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		date, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return appErr.NewDomainError(appErr.ErrInvalidInput, "Formato de fecha inválido, use AAAA-MM-DD")
+		}
+		if localized := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, clinicLoc); localized.After(from) {
+			from = localized
+		}
+	}
+
+	count := 0
+	if countStr := c.QueryParam("count"); countStr != "" {
+		parsed, err := strconv.Atoi(countStr)
+		if err != nil {
+			return appErr.NewDomainError(appErr.ErrInvalidInput, "El parámetro 'count' debe ser numérico")
+		}
+		count = parsed
+	}
+
+	opts, err := slotOptions(c)
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "Los parámetros 'buffer', 'doctor_id' y 'tipo_id' deben ser numéricos")
+	}
+
+	slots, err := h.service.GetNextAvailableSlots(from, count, opts)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, slots)
+}
+
+// slotOptions lee ?duration (segundos), ?buffer (minutos), ?doctor_id y ?tipo_id de la consulta.
+// Sin duration se usa la duración del tipo, o 15 min.
+func slotOptions(c echo.Context) (models.SlotOptions, error) {
+	opts := models.SlotOptions{}
 	if dur := c.QueryParam("duration"); dur != "" {
 		if parsed, err := strconv.ParseInt(dur, 10, 64); err == nil {
-			slotDuration = parsed
+			opts.Duracion = parsed
+		}
+	}
+	if buf := c.QueryParam("buffer"); buf != "" {
+		parsed, err := strconv.Atoi(buf)
+		if err != nil {
+			return opts, err
 		}
+		opts.BufferMinutes = &parsed
+	}
+	doctorID, err := doctorParam(c)
+	if err != nil {
+		return opts, err
 	}
+	opts.DoctorID = doctorID
+	if raw := c.QueryParam("tipo_id"); raw != "" {
+		tipoID, err := strconv.Atoi(raw)
+		if err != nil {
+			return opts, err
+		}
+		opts.TipoID = &tipoID
+	}
+	return opts, nil
+}
 
-	slots, err := h.service.GetAvailableSlots(localized, slotDuration)
+func (h *Handler) GetBySeries(c echo.Context) error {
+	serieID, err := strconv.Atoi(c.Param("serieId"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID de serie inválido")
+	}
+	appts, err := h.service.GetBySeries(serieID)
 	if err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, slots)
+	return c.JSON(http.StatusOK, appts)
+}
+
+func (h *Handler) CreateSeries(c echo.Context) error {
+	var req models.SeriesCreateDTO
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Appointment.CreateSeries.Bind", appErr.ErrInvalidInput, err)
+	}
+	result, err := h.service.CreateSeries(&req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, result)
+}
+
+// UpdateSeries modifica una cita de una serie; ?scope=this|following|all (default: this)
+func (h *Handler) UpdateSeries(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	var req models.AppointmentUpdateDTO
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Appointment.UpdateSeries.Bind", appErr.ErrInvalidInput, err)
+	}
+	result, err := h.service.UpdateSeries(id, seriesScope(c), &req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// CancelSeries cancela citas de una serie; ?scope=this|following|all (default: this), ?motivo= opcional
+func (h *Handler) CancelSeries(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return appErr.Wrap("Appointment.CancelSeries.GetClaims", appErr.ErrUnauthorized, nil)
+	}
+	result, err := h.service.CancelSeries(id, seriesScope(c), claims.UserID, optionalQuery(c, "motivo"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetCalendarFeed publica el calendario del dueño de ?token=. El token debe seguir
+// vigente y el usuario conservar el permiso de ver citas.
+func (h *Handler) GetCalendarFeed(c echo.Context) error {
+	userID, err := h.service.ResolveCalendarToken(c.QueryParam("token"))
+	if err != nil {
+		return err
+	}
+	allowed, err := middleware.UserHasPermission(userID, "ver-citas")
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return appErr.Wrap("Appointment.GetCalendarFeed(ver-citas)", appErr.ErrForbidden, nil)
+	}
+
+	cal, err := h.service.GetCalendarFeed(userID)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(echo.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `inline; filename="citas.ics"`)
+	c.Response().WriteHeader(http.StatusOK)
+	return ical.Encode(c.Response(), cal)
+}
+
+// CreateCalendarToken genera (o regenera) el token del feed del usuario autenticado.
+// El token solo se muestra en esta respuesta.
+func (h *Handler) CreateCalendarToken(c echo.Context) error {
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return appErr.Wrap("Appointment.CreateCalendarToken.GetClaims", appErr.ErrUnauthorized, nil)
+	}
+	token, err := h.service.CreateCalendarToken(claims.UserID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, models.CalendarToken{
+		Token: token,
+		URL:   "/api/appointments/calendar.ics?token=" + token,
+	})
+}
+
+func (h *Handler) RevokeCalendarToken(c echo.Context) error {
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return appErr.Wrap("Appointment.RevokeCalendarToken.GetClaims", appErr.ErrUnauthorized, nil)
+	}
+	if err := h.service.RevokeCalendarToken(claims.UserID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ImportCalendar importa un archivo .ics enviado como multipart ("file") o como cuerpo
+// crudo. ?doctor_id= y ?tipo_id= se aplican a todas las citas importadas.
+func (h *Handler) ImportCalendar(c echo.Context) error {
+	var opts models.ImportOptions
+	doctorID, err := doctorParam(c)
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "El parámetro 'doctor_id' debe ser numérico")
+	}
+	opts.DoctorID = doctorID
+	if raw := c.QueryParam("tipo_id"); raw != "" {
+		tipoID, err := strconv.Atoi(raw)
+		if err != nil {
+			return appErr.NewDomainError(appErr.ErrInvalidInput, "El parámetro 'tipo_id' debe ser numérico")
+		}
+		opts.TipoID = &tipoID
+	}
+
+	body := io.Reader(c.Request().Body)
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return appErr.NewDomainError(appErr.ErrInvalidInput, "Falta el archivo 'file'")
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return appErr.NewDomainError(appErr.ErrInvalidInput, "No se pudo leer el archivo")
+		}
+		defer f.Close()
+		body = f
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxImportBytes+1))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "No se pudo leer el archivo")
+	}
+	if len(data) > maxImportBytes {
+		return appErr.NewDomainError(appErr.ErrTooLarge, "El archivo supera el tamaño máximo de 1MB")
+	}
+
+	result, err := h.service.ImportCalendar(bytes.NewReader(data), opts)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func seriesScope(c echo.Context) string {
+	if scope := c.QueryParam("scope"); scope != "" {
+		return scope
+	}
+	return models.ScopeThis
+}
+
+// listFilter lee ?estado=scheduled,confirmed y ?doctor_id=N como filtros de los listados
+func listFilter(c echo.Context) (models.AppointmentFilter, error) {
+	doctorID, err := doctorParam(c)
+	if err != nil {
+		return models.AppointmentFilter{}, err
+	}
+	return models.AppointmentFilter{Estados: statusFilter(c), DoctorID: doctorID}, nil
+}
+
+func doctorParam(c echo.Context) (*int, error) {
+	raw := c.QueryParam("doctor_id")
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// statusFilter lee ?estado=scheduled,confirmed como filtro de estados
+func statusFilter(c echo.Context) []string {
+	raw := c.QueryParam("estado")
+	if raw == "" {
+		return nil
+	}
+	var estados []string
+	for _, e := range strings.Split(raw, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			estados = append(estados, e)
+		}
+	}
+	return estados
+}
+
+func optionalQuery(c echo.Context, name string) *string {
+	if v := strings.TrimSpace(c.QueryParam(name)); v != "" {
+		return &v
+	}
+	return nil
 }
@@ -0,0 +1,356 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	database "github.com/tonitomc/healthcare-crm-api/internal/database"
+	appointment "github.com/tonitomc/healthcare-crm-api/internal/domain/appointment"
+	models "github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(appt *models.AppointmentCreateDTO) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", appt)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(appt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), appt)
+}
+
+// CreateSeries mocks base method.
+func (m *MockRepository) CreateSeries(series *models.Series, occurrences []models.AppointmentCreateDTO) (int, []int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSeries", series, occurrences)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].([]int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateSeries indicates an expected call of CreateSeries.
+func (mr *MockRepositoryMockRecorder) CreateSeries(series, occurrences interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSeries", reflect.TypeOf((*MockRepository)(nil).CreateSeries), series, occurrences)
+}
+
+// DeleteCalendarToken mocks base method.
+func (m *MockRepository) DeleteCalendarToken(userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCalendarToken", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCalendarToken indicates an expected call of DeleteCalendarToken.
+func (mr *MockRepositoryMockRecorder) DeleteCalendarToken(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCalendarToken", reflect.TypeOf((*MockRepository)(nil).DeleteCalendarToken), userID)
+}
+
+// DetachPatient mocks base method.
+func (m *MockRepository) DetachPatient(patientID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachPatient", patientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachPatient indicates an expected call of DetachPatient.
+func (mr *MockRepositoryMockRecorder) DetachPatient(patientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachPatient", reflect.TypeOf((*MockRepository)(nil).DetachPatient), patientID)
+}
+
+// ExistsExternalUID mocks base method.
+func (m *MockRepository) ExistsExternalUID(uid string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsExternalUID", uid)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsExternalUID indicates an expected call of ExistsExternalUID.
+func (mr *MockRepositoryMockRecorder) ExistsExternalUID(uid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsExternalUID", reflect.TypeOf((*MockRepository)(nil).ExistsExternalUID), uid)
+}
+
+// GetBetween mocks base method.
+func (m *MockRepository) GetBetween(start, end time.Time, filter models.AppointmentFilter) ([]models.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBetween", start, end, filter)
+	ret0, _ := ret[0].([]models.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBetween indicates an expected call of GetBetween.
+func (mr *MockRepositoryMockRecorder) GetBetween(start, end, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBetween", reflect.TypeOf((*MockRepository)(nil).GetBetween), start, end, filter)
+}
+
+// GetByDate mocks base method.
+func (m *MockRepository) GetByDate(date time.Time, filter models.AppointmentFilter) ([]models.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByDate", date, filter)
+	ret0, _ := ret[0].([]models.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByDate indicates an expected call of GetByDate.
+func (mr *MockRepositoryMockRecorder) GetByDate(date, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDate", reflect.TypeOf((*MockRepository)(nil).GetByDate), date, filter)
+}
+
+// GetByID mocks base method.
+func (m *MockRepository) GetByID(id int) (*models.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRepository)(nil).GetByID), id)
+}
+
+// GetBySeries mocks base method.
+func (m *MockRepository) GetBySeries(serieID int) ([]models.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySeries", serieID)
+	ret0, _ := ret[0].([]models.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySeries indicates an expected call of GetBySeries.
+func (mr *MockRepositoryMockRecorder) GetBySeries(serieID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySeries", reflect.TypeOf((*MockRepository)(nil).GetBySeries), serieID)
+}
+
+// GetCalendarTokenUser mocks base method.
+func (m *MockRepository) GetCalendarTokenUser(tokenHash string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarTokenUser", tokenHash)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarTokenUser indicates an expected call of GetCalendarTokenUser.
+func (mr *MockRepositoryMockRecorder) GetCalendarTokenUser(tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarTokenUser", reflect.TypeOf((*MockRepository)(nil).GetCalendarTokenUser), tokenHash)
+}
+
+// GetStatusHistory mocks base method.
+func (m *MockRepository) GetStatusHistory(id int) ([]models.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", id)
+	ret0, _ := ret[0].([]models.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockRepositoryMockRecorder) GetStatusHistory(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*MockRepository)(nil).GetStatusHistory), id)
+}
+
+// GetToday mocks base method.
+func (m *MockRepository) GetToday(filter models.AppointmentFilter) ([]models.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetToday", filter)
+	ret0, _ := ret[0].([]models.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetToday indicates an expected call of GetToday.
+func (mr *MockRepositoryMockRecorder) GetToday(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToday", reflect.TypeOf((*MockRepository)(nil).GetToday), filter)
+}
+
+// LockDay mocks base method.
+func (m *MockRepository) LockDay(day time.Time, doctorID *int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockDay", day, doctorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockDay indicates an expected call of LockDay.
+func (mr *MockRepositoryMockRecorder) LockDay(day, doctorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockDay", reflect.TypeOf((*MockRepository)(nil).LockDay), day, doctorID)
+}
+
+// LockResources mocks base method.
+func (m *MockRepository) LockResources(day time.Time, recursoIDs []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockResources", day, recursoIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockResources indicates an expected call of LockResources.
+func (mr *MockRepositoryMockRecorder) LockResources(day, recursoIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockResources", reflect.TypeOf((*MockRepository)(nil).LockResources), day, recursoIDs)
+}
+
+// ReassignPatient mocks base method.
+func (m *MockRepository) ReassignPatient(fromID, toID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignPatient", fromID, toID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReassignPatient indicates an expected call of ReassignPatient.
+func (mr *MockRepositoryMockRecorder) ReassignPatient(fromID, toID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignPatient", reflect.TypeOf((*MockRepository)(nil).ReassignPatient), fromID, toID)
+}
+
+// SaveCalendarToken mocks base method.
+func (m *MockRepository) SaveCalendarToken(userID int, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCalendarToken", userID, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCalendarToken indicates an expected call of SaveCalendarToken.
+func (mr *MockRepositoryMockRecorder) SaveCalendarToken(userID, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCalendarToken", reflect.TypeOf((*MockRepository)(nil).SaveCalendarToken), userID, tokenHash)
+}
+
+// Update mocks base method.
+func (m *MockRepository) Update(id int, appt *models.AppointmentUpdateDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", id, appt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRepositoryMockRecorder) Update(id, appt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), id, appt)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockRepository) UpdateStatus(changes []models.StatusChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", changes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockRepositoryMockRecorder) UpdateStatus(changes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockRepository)(nil).UpdateStatus), changes)
+}
+
+// WithTx mocks base method.
+func (m *MockRepository) WithTx(tx database.DBTX) appointment.Repository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(appointment.Repository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRepositoryMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepository)(nil).WithTx), tx)
+}
+
+// Mockscanner is a mock of scanner interface.
+type Mockscanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockscannerMockRecorder
+}
+
+// MockscannerMockRecorder is the mock recorder for Mockscanner.
+type MockscannerMockRecorder struct {
+	mock *Mockscanner
+}
+
+// NewMockscanner creates a new mock instance.
+func NewMockscanner(ctrl *gomock.Controller) *Mockscanner {
+	mock := &Mockscanner{ctrl: ctrl}
+	mock.recorder = &MockscannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockscanner) EXPECT() *MockscannerMockRecorder {
+	return m.recorder
+}
+
+// Scan mocks base method.
+func (m *Mockscanner) Scan(dest ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range dest {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Scan", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockscannerMockRecorder) Scan(dest ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*Mockscanner)(nil).Scan), dest...)
+}
@@ -0,0 +1,739 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	database "github.com/tonitomc/healthcare-crm-api/internal/database"
+	appointment "github.com/tonitomc/healthcare-crm-api/internal/domain/appointment"
+	models "github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	models0 "github.com/tonitomc/healthcare-crm-api/internal/domain/patient/models"
+	models1 "github.com/tonitomc/healthcare-crm-api/internal/domain/schedule/models"
+	ical "github.com/tonitomc/healthcare-crm-api/pkg/ical"
+)
+
+// MockPatientProvider is a mock of PatientProvider interface.
+type MockPatientProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockPatientProviderMockRecorder
+}
+
+// MockPatientProviderMockRecorder is the mock recorder for MockPatientProvider.
+type MockPatientProviderMockRecorder struct {
+	mock *MockPatientProvider
+}
+
+// NewMockPatientProvider creates a new mock instance.
+func NewMockPatientProvider(ctrl *gomock.Controller) *MockPatientProvider {
+	mock := &MockPatientProvider{ctrl: ctrl}
+	mock.recorder = &MockPatientProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPatientProvider) EXPECT() *MockPatientProviderMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPatientProvider) Create(dto *models0.PatientCreateDTO) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", dto)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPatientProviderMockRecorder) Create(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPatientProvider)(nil).Create), dto)
+}
+
+// Exists mocks base method.
+func (m *MockPatientProvider) Exists(id int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockPatientProviderMockRecorder) Exists(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockPatientProvider)(nil).Exists), id)
+}
+
+// GetByID mocks base method.
+func (m *MockPatientProvider) GetByID(id int) (*models0.Patient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models0.Patient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPatientProviderMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPatientProvider)(nil).GetByID), id)
+}
+
+// WithTx mocks base method.
+func (m *MockPatientProvider) WithTx(tx database.DBTX) appointment.PatientProvider {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(appointment.PatientProvider)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockPatientProviderMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockPatientProvider)(nil).WithTx), tx)
+}
+
+// MockScheduleValidator is a mock of ScheduleValidator interface.
+type MockScheduleValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduleValidatorMockRecorder
+}
+
+// MockScheduleValidatorMockRecorder is the mock recorder for MockScheduleValidator.
+type MockScheduleValidatorMockRecorder struct {
+	mock *MockScheduleValidator
+}
+
+// NewMockScheduleValidator creates a new mock instance.
+func NewMockScheduleValidator(ctrl *gomock.Controller) *MockScheduleValidator {
+	mock := &MockScheduleValidator{ctrl: ctrl}
+	mock.recorder = &MockScheduleValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduleValidator) EXPECT() *MockScheduleValidatorMockRecorder {
+	return m.recorder
+}
+
+// GetEffectiveDay mocks base method.
+func (m *MockScheduleValidator) GetEffectiveDay(date time.Time, doctorID *int) (*models1.EffectiveDay, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEffectiveDay", date, doctorID)
+	ret0, _ := ret[0].(*models1.EffectiveDay)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEffectiveDay indicates an expected call of GetEffectiveDay.
+func (mr *MockScheduleValidatorMockRecorder) GetEffectiveDay(date, doctorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEffectiveDay", reflect.TypeOf((*MockScheduleValidator)(nil).GetEffectiveDay), date, doctorID)
+}
+
+// IsWithinBusinessHours mocks base method.
+func (m *MockScheduleValidator) IsWithinBusinessHours(date, start, end time.Time, doctorID *int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsWithinBusinessHours", date, start, end, doctorID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsWithinBusinessHours indicates an expected call of IsWithinBusinessHours.
+func (mr *MockScheduleValidatorMockRecorder) IsWithinBusinessHours(date, start, end, doctorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsWithinBusinessHours", reflect.TypeOf((*MockScheduleValidator)(nil).IsWithinBusinessHours), date, start, end, doctorID)
+}
+
+// MockDoctorProvider is a mock of DoctorProvider interface.
+type MockDoctorProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockDoctorProviderMockRecorder
+}
+
+// MockDoctorProviderMockRecorder is the mock recorder for MockDoctorProvider.
+type MockDoctorProviderMockRecorder struct {
+	mock *MockDoctorProvider
+}
+
+// NewMockDoctorProvider creates a new mock instance.
+func NewMockDoctorProvider(ctrl *gomock.Controller) *MockDoctorProvider {
+	mock := &MockDoctorProvider{ctrl: ctrl}
+	mock.recorder = &MockDoctorProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDoctorProvider) EXPECT() *MockDoctorProviderMockRecorder {
+	return m.recorder
+}
+
+// IsDoctor mocks base method.
+func (m *MockDoctorProvider) IsDoctor(userID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDoctor", userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDoctor indicates an expected call of IsDoctor.
+func (mr *MockDoctorProviderMockRecorder) IsDoctor(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDoctor", reflect.TypeOf((*MockDoctorProvider)(nil).IsDoctor), userID)
+}
+
+// ListDoctors mocks base method.
+func (m *MockDoctorProvider) ListDoctors() ([]models.Doctor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDoctors")
+	ret0, _ := ret[0].([]models.Doctor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDoctors indicates an expected call of ListDoctors.
+func (mr *MockDoctorProviderMockRecorder) ListDoctors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDoctors", reflect.TypeOf((*MockDoctorProvider)(nil).ListDoctors))
+}
+
+// MockTypeProvider is a mock of TypeProvider interface.
+type MockTypeProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockTypeProviderMockRecorder
+}
+
+// MockTypeProviderMockRecorder is the mock recorder for MockTypeProvider.
+type MockTypeProviderMockRecorder struct {
+	mock *MockTypeProvider
+}
+
+// NewMockTypeProvider creates a new mock instance.
+func NewMockTypeProvider(ctrl *gomock.Controller) *MockTypeProvider {
+	mock := &MockTypeProvider{ctrl: ctrl}
+	mock.recorder = &MockTypeProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTypeProvider) EXPECT() *MockTypeProviderMockRecorder {
+	return m.recorder
+}
+
+// GetType mocks base method.
+func (m *MockTypeProvider) GetType(id int) (*models.AppointmentType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetType", id)
+	ret0, _ := ret[0].(*models.AppointmentType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetType indicates an expected call of GetType.
+func (mr *MockTypeProviderMockRecorder) GetType(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetType", reflect.TypeOf((*MockTypeProvider)(nil).GetType), id)
+}
+
+// MockConsultationStarter is a mock of ConsultationStarter interface.
+type MockConsultationStarter struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsultationStarterMockRecorder
+}
+
+// MockConsultationStarterMockRecorder is the mock recorder for MockConsultationStarter.
+type MockConsultationStarterMockRecorder struct {
+	mock *MockConsultationStarter
+}
+
+// NewMockConsultationStarter creates a new mock instance.
+func NewMockConsultationStarter(ctrl *gomock.Controller) *MockConsultationStarter {
+	mock := &MockConsultationStarter{ctrl: ctrl}
+	mock.recorder = &MockConsultationStarterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsultationStarter) EXPECT() *MockConsultationStarterMockRecorder {
+	return m.recorder
+}
+
+// StartConsultation mocks base method.
+func (m *MockConsultationStarter) StartConsultation(start models.ConsultationStart) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartConsultation", start)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartConsultation indicates an expected call of StartConsultation.
+func (mr *MockConsultationStarterMockRecorder) StartConsultation(start interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartConsultation", reflect.TypeOf((*MockConsultationStarter)(nil).StartConsultation), start)
+}
+
+// WithTx mocks base method.
+func (m *MockConsultationStarter) WithTx(tx database.DBTX) appointment.ConsultationStarter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(appointment.ConsultationStarter)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockConsultationStarterMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockConsultationStarter)(nil).WithTx), tx)
+}
+
+// MockSlotListener is a mock of SlotListener interface.
+type MockSlotListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockSlotListenerMockRecorder
+}
+
+// MockSlotListenerMockRecorder is the mock recorder for MockSlotListener.
+type MockSlotListenerMockRecorder struct {
+	mock *MockSlotListener
+}
+
+// NewMockSlotListener creates a new mock instance.
+func NewMockSlotListener(ctrl *gomock.Controller) *MockSlotListener {
+	mock := &MockSlotListener{ctrl: ctrl}
+	mock.recorder = &MockSlotListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSlotListener) EXPECT() *MockSlotListenerMockRecorder {
+	return m.recorder
+}
+
+// SlotReleased mocks base method.
+func (m *MockSlotListener) SlotReleased(slot models.ReleasedSlot) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SlotReleased", slot)
+}
+
+// SlotReleased indicates an expected call of SlotReleased.
+func (mr *MockSlotListenerMockRecorder) SlotReleased(slot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SlotReleased", reflect.TypeOf((*MockSlotListener)(nil).SlotReleased), slot)
+}
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockService) Cancel(id, userID int, motivo *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", id, userID, motivo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockServiceMockRecorder) Cancel(id, userID, motivo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockService)(nil).Cancel), id, userID, motivo)
+}
+
+// CancelSeries mocks base method.
+func (m *MockService) CancelSeries(id int, scope string, userID int, motivo *string) (*models.SeriesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSeries", id, scope, userID, motivo)
+	ret0, _ := ret[0].(*models.SeriesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelSeries indicates an expected call of CancelSeries.
+func (mr *MockServiceMockRecorder) CancelSeries(id, scope, userID, motivo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSeries", reflect.TypeOf((*MockService)(nil).CancelSeries), id, scope, userID, motivo)
+}
+
+// ChangeStatus mocks base method.
+func (m *MockService) ChangeStatus(id, userID int, dto *models.StatusChangeDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeStatus", id, userID, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeStatus indicates an expected call of ChangeStatus.
+func (mr *MockServiceMockRecorder) ChangeStatus(id, userID, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeStatus", reflect.TypeOf((*MockService)(nil).ChangeStatus), id, userID, dto)
+}
+
+// CheckIn mocks base method.
+func (m *MockService) CheckIn(id, userID int, dto *models.CheckInDTO) (*models.CheckInResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIn", id, userID, dto)
+	ret0, _ := ret[0].(*models.CheckInResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIn indicates an expected call of CheckIn.
+func (mr *MockServiceMockRecorder) CheckIn(id, userID, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIn", reflect.TypeOf((*MockService)(nil).CheckIn), id, userID, dto)
+}
+
+// Complete mocks base method.
+func (m *MockService) Complete(id, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockServiceMockRecorder) Complete(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockService)(nil).Complete), id, userID)
+}
+
+// Create mocks base method.
+func (m *MockService) Create(appt *models.AppointmentCreateDTO) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", appt)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockServiceMockRecorder) Create(appt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), appt)
+}
+
+// CreateCalendarToken mocks base method.
+func (m *MockService) CreateCalendarToken(userID int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCalendarToken", userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCalendarToken indicates an expected call of CreateCalendarToken.
+func (mr *MockServiceMockRecorder) CreateCalendarToken(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCalendarToken", reflect.TypeOf((*MockService)(nil).CreateCalendarToken), userID)
+}
+
+// CreateSeries mocks base method.
+func (m *MockService) CreateSeries(dto *models.SeriesCreateDTO) (*models.SeriesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSeries", dto)
+	ret0, _ := ret[0].(*models.SeriesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSeries indicates an expected call of CreateSeries.
+func (mr *MockServiceMockRecorder) CreateSeries(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSeries", reflect.TypeOf((*MockService)(nil).CreateSeries), dto)
+}
+
+// CreateWithNewPatient mocks base method.
+func (m *MockService) CreateWithNewPatient(dto *models.AppointmentWithNewPatientDTO) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithNewPatient", dto)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithNewPatient indicates an expected call of CreateWithNewPatient.
+func (mr *MockServiceMockRecorder) CreateWithNewPatient(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithNewPatient", reflect.TypeOf((*MockService)(nil).CreateWithNewPatient), dto)
+}
+
+// GetAvailableSlots mocks base method.
+func (m *MockService) GetAvailableSlots(date time.Time, opts models.SlotOptions) ([]models.AvailabilitySlot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailableSlots", date, opts)
+	ret0, _ := ret[0].([]models.AvailabilitySlot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailableSlots indicates an expected call of GetAvailableSlots.
+func (mr *MockServiceMockRecorder) GetAvailableSlots(date, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableSlots", reflect.TypeOf((*MockService)(nil).GetAvailableSlots), date, opts)
+}
+
+// GetBetween mocks base method.
+func (m *MockService) GetBetween(start, end time.Time, filter models.AppointmentFilter) ([]models.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBetween", start, end, filter)
+	ret0, _ := ret[0].([]models.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBetween indicates an expected call of GetBetween.
+func (mr *MockServiceMockRecorder) GetBetween(start, end, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBetween", reflect.TypeOf((*MockService)(nil).GetBetween), start, end, filter)
+}
+
+// GetByDate mocks base method.
+func (m *MockService) GetByDate(date time.Time, filter models.AppointmentFilter) ([]models.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByDate", date, filter)
+	ret0, _ := ret[0].([]models.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByDate indicates an expected call of GetByDate.
+func (mr *MockServiceMockRecorder) GetByDate(date, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDate", reflect.TypeOf((*MockService)(nil).GetByDate), date, filter)
+}
+
+// GetByID mocks base method.
+func (m *MockService) GetByID(id int) (*models.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockServiceMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockService)(nil).GetByID), id)
+}
+
+// GetBySeries mocks base method.
+func (m *MockService) GetBySeries(serieID int) ([]models.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySeries", serieID)
+	ret0, _ := ret[0].([]models.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySeries indicates an expected call of GetBySeries.
+func (mr *MockServiceMockRecorder) GetBySeries(serieID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySeries", reflect.TypeOf((*MockService)(nil).GetBySeries), serieID)
+}
+
+// GetCalendarFeed mocks base method.
+func (m *MockService) GetCalendarFeed(userID int) (*ical.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarFeed", userID)
+	ret0, _ := ret[0].(*ical.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarFeed indicates an expected call of GetCalendarFeed.
+func (mr *MockServiceMockRecorder) GetCalendarFeed(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarFeed", reflect.TypeOf((*MockService)(nil).GetCalendarFeed), userID)
+}
+
+// GetDayByProvider mocks base method.
+func (m *MockService) GetDayByProvider(date time.Time, estados []string) ([]models.ProviderColumn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDayByProvider", date, estados)
+	ret0, _ := ret[0].([]models.ProviderColumn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDayByProvider indicates an expected call of GetDayByProvider.
+func (mr *MockServiceMockRecorder) GetDayByProvider(date, estados interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDayByProvider", reflect.TypeOf((*MockService)(nil).GetDayByProvider), date, estados)
+}
+
+// GetNextAvailableSlots mocks base method.
+func (m *MockService) GetNextAvailableSlots(from time.Time, count int, opts models.SlotOptions) ([]models.AvailabilitySlot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextAvailableSlots", from, count, opts)
+	ret0, _ := ret[0].([]models.AvailabilitySlot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextAvailableSlots indicates an expected call of GetNextAvailableSlots.
+func (mr *MockServiceMockRecorder) GetNextAvailableSlots(from, count, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextAvailableSlots", reflect.TypeOf((*MockService)(nil).GetNextAvailableSlots), from, count, opts)
+}
+
+// GetStatusHistory mocks base method.
+func (m *MockService) GetStatusHistory(id int) ([]models.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", id)
+	ret0, _ := ret[0].([]models.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockServiceMockRecorder) GetStatusHistory(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*MockService)(nil).GetStatusHistory), id)
+}
+
+// GetToday mocks base method.
+func (m *MockService) GetToday(filter models.AppointmentFilter) ([]models.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetToday", filter)
+	ret0, _ := ret[0].([]models.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetToday indicates an expected call of GetToday.
+func (mr *MockServiceMockRecorder) GetToday(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToday", reflect.TypeOf((*MockService)(nil).GetToday), filter)
+}
+
+// ImportCalendar mocks base method.
+func (m *MockService) ImportCalendar(r io.Reader, opts models.ImportOptions) (*models.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCalendar", r, opts)
+	ret0, _ := ret[0].(*models.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCalendar indicates an expected call of ImportCalendar.
+func (mr *MockServiceMockRecorder) ImportCalendar(r, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCalendar", reflect.TypeOf((*MockService)(nil).ImportCalendar), r, opts)
+}
+
+// ListDoctors mocks base method.
+func (m *MockService) ListDoctors() ([]models.Doctor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDoctors")
+	ret0, _ := ret[0].([]models.Doctor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDoctors indicates an expected call of ListDoctors.
+func (mr *MockServiceMockRecorder) ListDoctors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDoctors", reflect.TypeOf((*MockService)(nil).ListDoctors))
+}
+
+// ResolveCalendarToken mocks base method.
+func (m *MockService) ResolveCalendarToken(token string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveCalendarToken", token)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveCalendarToken indicates an expected call of ResolveCalendarToken.
+func (mr *MockServiceMockRecorder) ResolveCalendarToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveCalendarToken", reflect.TypeOf((*MockService)(nil).ResolveCalendarToken), token)
+}
+
+// RevokeCalendarToken mocks base method.
+func (m *MockService) RevokeCalendarToken(userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeCalendarToken", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeCalendarToken indicates an expected call of RevokeCalendarToken.
+func (mr *MockServiceMockRecorder) RevokeCalendarToken(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeCalendarToken", reflect.TypeOf((*MockService)(nil).RevokeCalendarToken), userID)
+}
+
+// Update mocks base method.
+func (m *MockService) Update(id int, appt *models.AppointmentUpdateDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", id, appt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockServiceMockRecorder) Update(id, appt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), id, appt)
+}
+
+// UpdateSeries mocks base method.
+func (m *MockService) UpdateSeries(id int, scope string, appt *models.AppointmentUpdateDTO) (*models.SeriesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSeries", id, scope, appt)
+	ret0, _ := ret[0].(*models.SeriesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSeries indicates an expected call of UpdateSeries.
+func (mr *MockServiceMockRecorder) UpdateSeries(id, scope, appt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSeries", reflect.TypeOf((*MockService)(nil).UpdateSeries), id, scope, appt)
+}
+
+// WithTx mocks base method.
+func (m *MockService) WithTx(tx database.DBTX) appointment.Service {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(appointment.Service)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockServiceMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockService)(nil).WithTx), tx)
+}
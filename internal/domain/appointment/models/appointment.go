@@ -11,23 +11,45 @@ type Appointment struct {
 	PacienteID *int      `json:"paciente_id,omitempty"`
 	Nombre     *string   `json:"nombre,omitempty"` // Para citas sin paciente
 	Fecha      time.Time `json:"fecha"`
-	Duracion   int64     `json:"duracion"` // segundos
+	Duracion   int64     `json:"duracion"`           // segundos
+	SerieID    *int      `json:"serie_id,omitempty"` // Cita perteneciente a una serie recurrente
+	Estado     string    `json:"estado"`
+	DoctorID   *int      `json:"doctor_id,omitempty"` // nil = agenda general de la clínica
+	TipoID     *int      `json:"tipo_id,omitempty"`   // tipo de cita del catálogo
+	Recursos   []int     `json:"recursos,omitempty"`  // cuartos y equipos que ocupa la cita
 	// Datos enriquecidos del join con paciente
 	NombrePaciente   *string    `json:"nombre_paciente,omitempty"`
 	TelefonoPaciente *string    `json:"telefono_paciente,omitempty"`
 	FechaNacimiento  *time.Time `json:"fecha_nacimiento,omitempty"`
+	// Datos enriquecidos del join con el doctor
+	NombreDoctor *string `json:"nombre_doctor,omitempty"`
+	// Datos enriquecidos del join con el tipo de cita
+	NombreTipo     *string `json:"nombre_tipo,omitempty"`
+	ColorTipo      *string `json:"color_tipo,omitempty"`
+	CuestionarioID *int    `json:"cuestionario_id,omitempty"` // cuestionario a adjuntar en la consulta
+	// Control de versiones para el feed de calendario (SEQUENCE / LAST-MODIFIED)
+	Secuencia         int       `json:"-"`
+	FechaModificacion time.Time `json:"-"`
 }
 
 type AppointmentCreateDTO struct {
 	PacienteID *int      `json:"paciente_id,omitempty"`
 	Nombre     *string   `json:"nombre,omitempty"`
 	Fecha      time.Time `json:"fecha" validate:"required"`
-	Duracion   int64     `json:"duracion" validate:"required"`
+	Duracion   int64     `json:"duracion"` // 0 usa la duración por defecto del tipo
+	DoctorID   *int      `json:"doctor_id,omitempty"`
+	TipoID     *int      `json:"tipo_id,omitempty"`
+	SerieID    *int      `json:"-"` // Asignado internamente al crear una serie
+	Recursos   []int     `json:"-"` // Copiados del tipo de cita al agendar
+	UIDExterno *string   `json:"-"` // UID del evento iCalendar importado
 }
 
 type AppointmentUpdateDTO struct {
 	Fecha    *time.Time `json:"fecha,omitempty"`
 	Duracion *int64     `json:"duracion,omitempty"`
+	DoctorID *int       `json:"doctor_id,omitempty"` // reasignar a otro doctor
+	TipoID   *int       `json:"tipo_id,omitempty"`   // cambiar el tipo reemplaza los recursos de la cita
+	Recursos []int      `json:"-"`                   // Asignado internamente; nil conserva los actuales
 }
 
 // AppointmentWithNewPatientDTO - Para crear paciente y cita en una transacción
@@ -35,12 +57,64 @@ type AppointmentWithNewPatientDTO struct {
 	PatientData     patientModels.PatientCreateDTO `json:"patient_data" validate:"required"`
 	AppointmentData struct {
 		Fecha    time.Time `json:"fecha" validate:"required"`
-		Duracion int64     `json:"duracion" validate:"required"`
+		Duracion int64     `json:"duracion"`
+		DoctorID *int      `json:"doctor_id,omitempty"`
+		TipoID   *int      `json:"tipo_id,omitempty"`
 	} `json:"appointment_data" validate:"required"`
 }
 
+// ReleasedSlot es un horario que quedó libre al cancelar o mover una cita
+type ReleasedSlot struct {
+	Fecha    time.Time
+	Duracion int64
+	DoctorID *int
+}
+
 type AvailabilitySlot struct {
 	Start     time.Time `json:"start"`
 	End       time.Time `json:"end"`
 	Available bool      `json:"available"`
 }
+
+// SlotOptions parámetros para generar horarios disponibles
+type SlotOptions struct {
+	Duracion      int64 // segundos; 0 usa la duración del tipo, o 15 min
+	BufferMinutes *int  // nil usa el buffer configurado en el servicio
+	DoctorID      *int  // nil = agenda general de la clínica
+	TipoID        *int  // los horarios también deben tener libres los recursos del tipo
+	Recursos      []int // resuelto a partir de TipoID
+}
+
+// AppointmentFilter filtros opcionales para los listados de citas
+type AppointmentFilter struct {
+	Estados  []string // vacío = todos los estados
+	DoctorID *int     // nil = todos los doctores
+}
+
+// Doctor es un usuario con rol de doctor al que se le pueden asignar citas
+type Doctor struct {
+	ID     int    `json:"id"`
+	Nombre string `json:"nombre"`
+}
+
+// AppointmentType es lo que la agenda necesita de un tipo de cita del catálogo
+type AppointmentType struct {
+	ID       int
+	Duracion int64
+	Activo   bool
+	Recursos []Resource
+}
+
+// Resource es un cuarto o equipo requerido por un tipo de cita
+type Resource struct {
+	ID     int
+	Nombre string
+	Activo bool
+}
+
+// ProviderColumn agrupa las citas de un día por doctor (vista lado a lado).
+// Doctor nil corresponde a las citas sin doctor asignado.
+type ProviderColumn struct {
+	Doctor *Doctor       `json:"doctor"`
+	Citas  []Appointment `json:"citas"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// CalendarToken se devuelve una sola vez al generar el token del feed
+type CalendarToken struct {
+	Token string `json:"token"`
+	URL   string `json:"url"` // ruta del feed lista para suscribirse
+}
+
+// ImportOptions se aplican a todas las citas importadas de un archivo .ics
+type ImportOptions struct {
+	DoctorID *int
+	TipoID   *int
+}
+
+// ImportConflict describe un evento del archivo que no se importó
+type ImportConflict struct {
+	UID     string    `json:"uid,omitempty"`
+	Resumen string    `json:"resumen,omitempty"`
+	Fecha   time.Time `json:"fecha"`
+	Motivo  string    `json:"motivo"`
+}
+
+// ImportResult resume una importación de eventos iCalendar
+type ImportResult struct {
+	Citas      []int            `json:"citas"`
+	Conflictos []ImportConflict `json:"conflictos"`
+}
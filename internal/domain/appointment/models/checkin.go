@@ -0,0 +1,22 @@
+package models
+
+// CheckInDTO registra la llegada del paciente a su cita.
+// CuestionarioID solo se usa si el tipo de cita no define un cuestionario.
+type CheckInDTO struct {
+	Motivo         *string `json:"motivo,omitempty"`
+	CuestionarioID *int    `json:"cuestionario_id,omitempty"`
+}
+
+// CheckInResult devuelve la consulta creada para la cita
+type CheckInResult struct {
+	CitaID     int `json:"cita_id"`
+	ConsultaID int `json:"consulta_id"`
+}
+
+// ConsultationStart son los datos con los que se abre la consulta de una cita
+type ConsultationStart struct {
+	CitaID         int
+	PacienteID     int
+	CuestionarioID int
+	Motivo         string
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Frecuencias soportadas para una serie de citas
+const (
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+)
+
+// Alcance de una edición o cancelación sobre una serie
+const (
+	ScopeThis      = "this"      // solo la cita indicada
+	ScopeFollowing = "following" // la cita indicada y las siguientes de la serie
+	ScopeAll       = "all"       // todas las citas de la serie
+)
+
+// RecurrenceRule describe cómo se repite una serie.
+// Debe terminar en una fecha (Hasta) o después de N ocurrencias (Ocurrencias).
+type RecurrenceRule struct {
+	Frecuencia  string     `json:"frecuencia" validate:"required,oneof=weekly monthly"`
+	Intervalo   int        `json:"intervalo"` // cada N semanas / meses, default 1
+	Hasta       *time.Time `json:"hasta,omitempty"`
+	Ocurrencias *int       `json:"ocurrencias,omitempty"`
+}
+
+// Series representa la definición persistida de una serie de citas
+type Series struct {
+	ID          int        `json:"id"`
+	PacienteID  *int       `json:"paciente_id,omitempty"`
+	Nombre      *string    `json:"nombre,omitempty"`
+	DoctorID    *int       `json:"doctor_id,omitempty"`
+	TipoID      *int       `json:"tipo_id,omitempty"`
+	Frecuencia  string     `json:"frecuencia"`
+	Intervalo   int        `json:"intervalo"`
+	FechaInicio time.Time  `json:"fecha_inicio"`
+	Duracion    int64      `json:"duracion"`
+	Hasta       *time.Time `json:"hasta,omitempty"`
+	Ocurrencias *int       `json:"ocurrencias,omitempty"`
+}
+
+// SeriesCreateDTO para crear una serie de citas recurrentes
+type SeriesCreateDTO struct {
+	PacienteID *int           `json:"paciente_id,omitempty"`
+	Nombre     *string        `json:"nombre,omitempty"`
+	Fecha      time.Time      `json:"fecha" validate:"required"` // primera ocurrencia
+	Duracion   int64          `json:"duracion"`                  // 0 usa la duración por defecto del tipo
+	DoctorID   *int           `json:"doctor_id,omitempty"`
+	TipoID     *int           `json:"tipo_id,omitempty"`
+	Regla      RecurrenceRule `json:"regla" validate:"required"`
+}
+
+// SeriesConflict describe una ocurrencia que no pudo agendarse o modificarse
+type SeriesConflict struct {
+	Fecha  time.Time `json:"fecha"`
+	CitaID *int      `json:"cita_id,omitempty"`
+	Motivo string    `json:"motivo"`
+}
+
+// SeriesResult resume el resultado de una operación sobre una serie
+type SeriesResult struct {
+	SerieID    int              `json:"serie_id"`
+	Citas      []int            `json:"citas"`
+	Conflictos []SeriesConflict `json:"conflictos"`
+}
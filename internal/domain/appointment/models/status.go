@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// Estados del ciclo de vida de una cita
+const (
+	StatusScheduled  = "scheduled"
+	StatusConfirmed  = "confirmed"
+	StatusCheckedIn  = "checked-in"
+	StatusInProgress = "in-progress"
+	StatusCompleted  = "completed"
+	StatusCancelled  = "cancelled"
+	StatusNoShow     = "no-show"
+)
+
+// statusTransitions define a qué estados puede pasar una cita desde cada estado.
+// completed, cancelled y no-show son estados finales.
+var statusTransitions = map[string][]string{
+	StatusScheduled:  {StatusConfirmed, StatusCheckedIn, StatusCancelled, StatusNoShow},
+	StatusConfirmed:  {StatusScheduled, StatusCheckedIn, StatusCancelled, StatusNoShow},
+	StatusCheckedIn:  {StatusInProgress, StatusCancelled},
+	StatusInProgress: {StatusCompleted},
+}
+
+// IsValidStatus indica si el estado es uno de los estados conocidos
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusScheduled, StatusConfirmed, StatusCheckedIn, StatusInProgress,
+		StatusCompleted, StatusCancelled, StatusNoShow:
+		return true
+	}
+	return false
+}
+
+// CanTransition indica si una cita puede pasar del estado from al estado to
+func CanTransition(from, to string) bool {
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// BlocksSlot indica si una cita en este estado ocupa su horario.
+// Las citas canceladas o marcadas como no-show liberan el horario.
+func BlocksSlot(status string) bool {
+	return status != StatusCancelled && status != StatusNoShow
+}
+
+// IsReschedulable indica si una cita en este estado puede cambiar de fecha o duración
+func IsReschedulable(status string) bool {
+	return status == StatusScheduled || status == StatusConfirmed
+}
+
+// StatusChange es una entrada del historial de estados de una cita
+type StatusChange struct {
+	ID             int       `json:"id"`
+	CitaID         int       `json:"cita_id"`
+	EstadoAnterior string    `json:"estado_anterior"`
+	EstadoNuevo    string    `json:"estado_nuevo"`
+	UsuarioID      *int      `json:"usuario_id,omitempty"`
+	Motivo         *string   `json:"motivo,omitempty"`
+	Fecha          time.Time `json:"fecha"`
+}
+
+// StatusChangeDTO para cambiar el estado de una cita
+type StatusChangeDTO struct {
+	Estado string  `json:"estado" validate:"required"`
+	Motivo *string `json:"motivo,omitempty"`
+}
@@ -1,9 +1,12 @@
-//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mockspackage appointment
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
 
 package appointment
 
 import (
 	"database/sql"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tonitomc/healthcare-crm-api/internal/database"
@@ -14,139 +17,458 @@ import (
 
 type Repository interface {
 	GetByID(id int) (*models.Appointment, error)
-	GetByDate(date time.Time) ([]models.Appointment, error)
-	GetToday() ([]models.Appointment, error)
-	GetBetween(start, end time.Time) ([]models.Appointment, error)
+	// Los listados aceptan filtros opcionales de estado y doctor; un filtro vacío devuelve todas las citas
+	GetByDate(date time.Time, filter models.AppointmentFilter) ([]models.Appointment, error)
+	GetToday(filter models.AppointmentFilter) ([]models.Appointment, error)
+	GetBetween(start, end time.Time, filter models.AppointmentFilter) ([]models.Appointment, error)
 	Create(appt *models.AppointmentCreateDTO) (int, error)
 	Update(id int, appt *models.AppointmentUpdateDTO) error
-	Delete(id int) error
+
+	// LockDay serializa las reservas de un día y doctor hasta el fin de la transacción actual.
+	// Solo tiene efecto dentro de una transacción (ver WithTx)
+	LockDay(day time.Time, doctorID *int) error
+	// LockResources serializa del mismo modo las reservas que ocupan los recursos indicados
+	LockResources(day time.Time, recursoIDs []int) error
+
+	// Ciclo de vida
+	UpdateStatus(changes []models.StatusChange) error
+	GetStatusHistory(id int) ([]models.StatusChange, error)
+
+	// Series recurrentes
+	CreateSeries(series *models.Series, occurrences []models.AppointmentCreateDTO) (int, []int, error)
+	GetBySeries(serieID int) ([]models.Appointment, error)
+
+	// DetachPatient desvincula las citas y series de un paciente que se va a eliminar,
+	// conservando su nombre para no perder el historial de la agenda
+	DetachPatient(patientID int) error
+	// ReassignPatient pasa las citas y series de un paciente a otro (fusión de duplicados)
+	ReassignPatient(fromID, toID int) error
+
+	// Calendario: tokens de feed por usuario (se guarda solo el hash) e importación
+	SaveCalendarToken(userID int, tokenHash string) error
+	GetCalendarTokenUser(tokenHash string) (int, error)
+	DeleteCalendarToken(userID int) error
+	ExistsExternalUID(uid string) (bool, error)
+
+	// WithTx devuelve una copia del repositorio que ejecuta sus consultas en tx
+	WithTx(tx database.DBTX) Repository
 }
 
 type repository struct {
-	db *sql.DB
+	db database.DBTX
 }
 
 func NewRepository(db *sql.DB) Repository {
 	return &repository{db: db}
 }
 
+func (r *repository) WithTx(tx database.DBTX) Repository {
+	return &repository{db: tx}
+}
+
 func (r *repository) GetByID(id int) (*models.Appointment, error) {
-	var a models.Appointment
-	err := r.db.QueryRow(`
-		SELECT c.id, c.paciente_id, c.nombre, c.fecha, c.duracion,
-			   p.nombre, p.telefono, p.fecha_nacimiento
-		FROM citas c
-		LEFT JOIN pacientes p ON c.paciente_id = p.id
-		WHERE c.id = $1
-	`, id).Scan(
-		&a.ID, &a.PacienteID, &a.Nombre, &a.Fecha, &a.Duracion,
-		&a.NombrePaciente, &a.TelefonoPaciente, &a.FechaNacimiento,
-	)
+	a, err := scanAppointment(r.db.QueryRow(selectAppointment+` WHERE c.id = $1`, id))
 	if err != nil {
 		return nil, database.MapSQLError(err, "AppointmentRepository.GetByID")
 	}
-	// Normalizar a zona de la clínica para respuestas JSON consistentes
-	a.Fecha = timeutil.NormalizeToClinic(a.Fecha)
-	return &a, nil
+	appts := []models.Appointment{*a}
+	if err := r.attachResources(appts); err != nil {
+		return nil, err
+	}
+	return &appts[0], nil
 }
 
-func (r *repository) GetByDate(date time.Time) ([]models.Appointment, error) {
+func (r *repository) GetByDate(date time.Time, filter models.AppointmentFilter) ([]models.Appointment, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
-	return r.GetBetween(startOfDay, endOfDay)
+	return r.GetBetween(startOfDay, endOfDay, filter)
 }
 
-func (r *repository) GetToday() ([]models.Appointment, error) {
-	return r.GetByDate(time.Now())
+func (r *repository) GetToday(filter models.AppointmentFilter) ([]models.Appointment, error) {
+	return r.GetByDate(time.Now(), filter)
 }
 
-func (r *repository) GetBetween(start, end time.Time) ([]models.Appointment, error) {
-	rows, err := r.db.Query(`
-		SELECT c.id, c.paciente_id, c.nombre, c.fecha, c.duracion,
-			   p.nombre, p.telefono, p.fecha_nacimiento
-		FROM citas c
-		LEFT JOIN pacientes p ON c.paciente_id = p.id
-		WHERE c.fecha >= $1 AND c.fecha < $2
-		ORDER BY c.fecha
-	`, start, end)
+func (r *repository) GetBetween(start, end time.Time, filter models.AppointmentFilter) ([]models.Appointment, error) {
+	query := selectAppointment + ` WHERE c.fecha >= $1 AND c.fecha < $2`
+	args := []interface{}{start, end}
+	if len(filter.Estados) > 0 {
+		args = append(args, filter.Estados)
+		query += " AND c.estado = ANY($" + strconv.Itoa(len(args)) + ")"
+	}
+	if filter.DoctorID != nil {
+		args = append(args, *filter.DoctorID)
+		query += " AND c.doctor_id = $" + strconv.Itoa(len(args))
+	}
+	query += " ORDER BY c.fecha"
+
+	return r.queryAppointments("AppointmentRepository.GetBetween", query, args...)
+}
+
+const selectAppointment = `
+	SELECT c.id, c.paciente_id, c.nombre, c.fecha, c.duracion, c.serie_id, c.estado, c.doctor_id, c.tipo_id,
+		   c.secuencia, c.fecha_modificacion,
+		   p.nombre, p.telefono, p.fecha_nacimiento, u.username, t.nombre, t.color, t.cuestionario_id
+	FROM citas c
+	LEFT JOIN pacientes p ON c.paciente_id = p.id
+	LEFT JOIN usuarios u ON c.doctor_id = u.id
+	LEFT JOIN tipos_cita t ON c.tipo_id = t.id`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAppointment(row scanner) (*models.Appointment, error) {
+	var a models.Appointment
+	if err := row.Scan(
+		&a.ID, &a.PacienteID, &a.Nombre, &a.Fecha, &a.Duracion, &a.SerieID, &a.Estado, &a.DoctorID, &a.TipoID,
+		&a.Secuencia, &a.FechaModificacion,
+		&a.NombrePaciente, &a.TelefonoPaciente, &a.FechaNacimiento, &a.NombreDoctor, &a.NombreTipo, &a.ColorTipo, &a.CuestionarioID,
+	); err != nil {
+		return nil, err
+	}
+	// Normalizar a zona de la clínica para respuestas JSON consistentes
+	a.Fecha = timeutil.NormalizeToClinic(a.Fecha)
+	return &a, nil
+}
+
+func (r *repository) queryAppointments(context, query string, args ...interface{}) ([]models.Appointment, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, database.MapSQLError(err, "AppointmentRepository.GetBetween")
+		return nil, database.MapSQLError(err, context)
 	}
 	defer rows.Close()
 
 	var appointments []models.Appointment
 	for rows.Next() {
-		var a models.Appointment
-		if err := rows.Scan(
-			&a.ID, &a.PacienteID, &a.Nombre, &a.Fecha, &a.Duracion,
-			&a.NombrePaciente, &a.TelefonoPaciente, &a.FechaNacimiento,
-		); err != nil {
-			return nil, appErr.Wrap("AppointmentRepository.GetBetween(scan)", appErr.ErrInternal, err)
+		a, err := scanAppointment(rows)
+		if err != nil {
+			return nil, appErr.Wrap(context+"(scan)", appErr.ErrInternal, err)
 		}
-		// Normalizar a zona de la clínica para respuestas JSON consistentes
-		a.Fecha = timeutil.NormalizeToClinic(a.Fecha)
-		appointments = append(appointments, a)
+		appointments = append(appointments, *a)
+	}
+	if err := r.attachResources(appointments); err != nil {
+		return nil, err
 	}
 	return appointments, nil
 }
 
+// attachResources carga los recursos ocupados por cada cita
+func (r *repository) attachResources(appts []models.Appointment) error {
+	if len(appts) == 0 {
+		return nil
+	}
+	ids := make([]int, len(appts))
+	index := make(map[int]int, len(appts))
+	for i, a := range appts {
+		ids[i] = a.ID
+		index[a.ID] = i
+	}
+
+	rows, err := r.db.Query(`
+		SELECT cita_id, recurso_id
+		FROM citas_recursos
+		WHERE cita_id = ANY($1)
+		ORDER BY recurso_id
+	`, ids)
+	if err != nil {
+		return database.MapSQLError(err, "AppointmentRepository.attachResources")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var citaID, recursoID int
+		if err := rows.Scan(&citaID, &recursoID); err != nil {
+			return appErr.Wrap("AppointmentRepository.attachResources(scan)", appErr.ErrInternal, err)
+		}
+		i := index[citaID]
+		appts[i].Recursos = append(appts[i].Recursos, recursoID)
+	}
+	return nil
+}
+
 func (r *repository) Create(appt *models.AppointmentCreateDTO) (int, error) {
 	var id int
-	err := r.db.QueryRow(`
-		INSERT INTO citas (paciente_id, nombre, fecha, duracion)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id
-	`, appt.PacienteID, appt.Nombre, appt.Fecha, appt.Duracion).Scan(&id)
+	err := database.RunInTx(r.db, "AppointmentRepository.Create", func(tx database.DBTX) error {
+		if err := tx.QueryRow(`
+			INSERT INTO citas (paciente_id, nombre, fecha, duracion, serie_id, doctor_id, tipo_id, uid_externo)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id
+		`, appt.PacienteID, appt.Nombre, appt.Fecha, appt.Duracion, appt.SerieID, appt.DoctorID, appt.TipoID, appt.UIDExterno).Scan(&id); err != nil {
+			return mapBookingError(err, "AppointmentRepository.Create")
+		}
+		return insertResources(tx, id, appt.Recursos)
+	})
 	if err != nil {
-		return 0, database.MapSQLError(err, "AppointmentRepository.Create")
+		return 0, err
 	}
 	return id, nil
 }
 
-func (r *repository) Update(id int, appt *models.AppointmentUpdateDTO) error {
-	if appt.Fecha == nil && appt.Duracion == nil {
-		return nil // nothing to update
+// insertResources registra los recursos que ocupa una cita recién creada
+func insertResources(tx database.DBTX, citaID int, recursos []int) error {
+	for _, recursoID := range recursos {
+		if _, err := tx.Exec(`
+			INSERT INTO citas_recursos (cita_id, recurso_id) VALUES ($1, $2)
+		`, citaID, recursoID); err != nil {
+			return database.MapSQLError(err, "AppointmentRepository.insertResources")
+		}
 	}
+	return nil
+}
 
-	query := "UPDATE citas SET "
+func (r *repository) Update(id int, appt *models.AppointmentUpdateDTO) error {
+	sets := []string{}
 	args := []interface{}{}
-	argIdx := 1
 
 	if appt.Fecha != nil {
-		query += "fecha = $" + string(rune(argIdx+'0'))
 		args = append(args, *appt.Fecha)
-		argIdx++
+		sets = append(sets, "fecha = $"+strconv.Itoa(len(args)))
 	}
 	if appt.Duracion != nil {
-		if argIdx > 1 {
-			query += ", "
-		}
-		query += "duracion = $" + string(rune(argIdx+'0'))
 		args = append(args, *appt.Duracion)
-		argIdx++
+		sets = append(sets, "duracion = $"+strconv.Itoa(len(args)))
+	}
+	if appt.DoctorID != nil {
+		args = append(args, *appt.DoctorID)
+		sets = append(sets, "doctor_id = $"+strconv.Itoa(len(args)))
+	}
+	if appt.TipoID != nil {
+		args = append(args, *appt.TipoID)
+		sets = append(sets, "tipo_id = $"+strconv.Itoa(len(args)))
 	}
-	query += " WHERE id = $" + string(rune(argIdx+'0'))
+	if len(sets) == 0 {
+		return nil // nothing to update
+	}
+
 	args = append(args, id)
+	query := "UPDATE citas SET " + strings.Join(sets, ", ") + " WHERE id = $" + strconv.Itoa(len(args))
 
-	res, err := r.db.Exec(query, args...)
+	return database.RunInTx(r.db, "AppointmentRepository.Update", func(tx database.DBTX) error {
+		res, err := tx.Exec(query, args...)
+		if err != nil {
+			return mapBookingError(err, "AppointmentRepository.Update")
+		}
+		rows, _ := res.RowsAffected()
+		if rows == 0 {
+			return appErr.Wrap("AppointmentRepository.Update", appErr.ErrNotFound, nil)
+		}
+
+		if appt.Recursos == nil {
+			return nil
+		}
+		if _, err := tx.Exec(`DELETE FROM citas_recursos WHERE cita_id = $1`, id); err != nil {
+			return database.MapSQLError(err, "AppointmentRepository.Update(resources)")
+		}
+		return insertResources(tx, id, appt.Recursos)
+	})
+}
+
+// LockDay toma un advisory lock de transacción con llave (AAAAMMDD, doctor). Las reservas
+// concurrentes del mismo día y doctor esperan a que la primera haga commit o rollback.
+func (r *repository) LockDay(day time.Time, doctorID *int) error {
+	day = timeutil.NormalizeToClinic(day)
+	dayKey := day.Year()*10000 + int(day.Month())*100 + day.Day()
+
+	doctorKey := 0
+	if doctorID != nil {
+		doctorKey = *doctorID
+	}
+
+	if _, err := r.db.Exec(`SELECT pg_advisory_xact_lock($1::int4, $2::int4)`, dayKey, doctorKey); err != nil {
+		return database.MapSQLError(err, "AppointmentRepository.LockDay")
+	}
+	return nil
+}
+
+// LockResources toma un advisory lock por recurso con llave (AAAAMMDD, -recurso); el signo
+// negativo separa estas llaves de las de LockDay. Se toman en orden ascendente para
+// que dos reservas con recursos en común no se bloqueen mutuamente.
+func (r *repository) LockResources(day time.Time, recursoIDs []int) error {
+	day = timeutil.NormalizeToClinic(day)
+	dayKey := day.Year()*10000 + int(day.Month())*100 + day.Day()
+
+	sorted := append([]int(nil), recursoIDs...)
+	sort.Ints(sorted)
+	for _, id := range sorted {
+		if _, err := r.db.Exec(`SELECT pg_advisory_xact_lock($1::int4, $2::int4)`, dayKey, -id); err != nil {
+			return database.MapSQLError(err, "AppointmentRepository.LockResources")
+		}
+	}
+	return nil
+}
+
+// mapBookingError traduce la violación de la restricción citas_sin_traslape al mismo
+// DomainError que devuelve la validación de traslapes del servicio.
+func mapBookingError(err error, context string) error {
+	if database.HasSQLState(err, database.CodeExclusionViolation) {
+		return appErr.NewDomainError(appErr.ErrConflict, "El horario solicitado traslapa con otras citas")
+	}
+	return database.MapSQLError(err, context)
+}
+
+// UpdateStatus aplica los cambios de estado y registra cada uno en el historial, en una sola transacción.
+// El cambio solo se aplica si la cita sigue en EstadoAnterior; de lo contrario otro usuario la modificó.
+func (r *repository) UpdateStatus(changes []models.StatusChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return database.RunInTx(r.db, "AppointmentRepository.UpdateStatus", func(tx database.DBTX) error {
+		for _, ch := range changes {
+			res, err := tx.Exec(`
+				UPDATE citas SET estado = $1
+				WHERE id = $2 AND estado = $3
+			`, ch.EstadoNuevo, ch.CitaID, ch.EstadoAnterior)
+			if err != nil {
+				return database.MapSQLError(err, "AppointmentRepository.UpdateStatus(update)")
+			}
+			rows, _ := res.RowsAffected()
+			if rows == 0 {
+				return appErr.NewDomainError(appErr.ErrConflict, "La cita fue modificada por otro usuario, intente de nuevo")
+			}
+
+			if _, err := tx.Exec(`
+				INSERT INTO citas_historial_estados (cita_id, estado_anterior, estado_nuevo, usuario_id, motivo, fecha)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, ch.CitaID, ch.EstadoAnterior, ch.EstadoNuevo, ch.UsuarioID, ch.Motivo, ch.Fecha); err != nil {
+				return database.MapSQLError(err, "AppointmentRepository.UpdateStatus(history)")
+			}
+		}
+		return nil
+	})
+}
+
+func (r *repository) GetStatusHistory(id int) ([]models.StatusChange, error) {
+	rows, err := r.db.Query(`
+		SELECT id, cita_id, estado_anterior, estado_nuevo, usuario_id, motivo, fecha
+		FROM citas_historial_estados
+		WHERE cita_id = $1
+		ORDER BY fecha, id
+	`, id)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AppointmentRepository.GetStatusHistory")
+	}
+	defer rows.Close()
+
+	history := []models.StatusChange{}
+	for rows.Next() {
+		var h models.StatusChange
+		if err := rows.Scan(&h.ID, &h.CitaID, &h.EstadoAnterior, &h.EstadoNuevo, &h.UsuarioID, &h.Motivo, &h.Fecha); err != nil {
+			return nil, appErr.Wrap("AppointmentRepository.GetStatusHistory(scan)", appErr.ErrInternal, err)
+		}
+		h.Fecha = timeutil.NormalizeToClinic(h.Fecha)
+		history = append(history, h)
+	}
+	return history, nil
+}
+
+// CreateSeries inserta la definición de la serie y todas sus ocurrencias en una sola transacción.
+func (r *repository) CreateSeries(series *models.Series, occurrences []models.AppointmentCreateDTO) (int, []int, error) {
+	var serieID int
+	ids := make([]int, 0, len(occurrences))
+
+	err := database.RunInTx(r.db, "AppointmentRepository.CreateSeries", func(tx database.DBTX) error {
+		err := tx.QueryRow(`
+			INSERT INTO series_citas (paciente_id, nombre, frecuencia, intervalo, fecha_inicio, duracion, hasta, ocurrencias, doctor_id, tipo_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING id
+		`, series.PacienteID, series.Nombre, series.Frecuencia, series.Intervalo,
+			series.FechaInicio, series.Duracion, series.Hasta, series.Ocurrencias, series.DoctorID, series.TipoID).Scan(&serieID)
+		if err != nil {
+			return database.MapSQLError(err, "AppointmentRepository.CreateSeries(insert series)")
+		}
+
+		for _, occ := range occurrences {
+			var id int
+			if err := tx.QueryRow(`
+				INSERT INTO citas (paciente_id, nombre, fecha, duracion, serie_id, doctor_id, tipo_id)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING id
+			`, occ.PacienteID, occ.Nombre, occ.Fecha, occ.Duracion, serieID, occ.DoctorID, occ.TipoID).Scan(&id); err != nil {
+				return mapBookingError(err, "AppointmentRepository.CreateSeries(insert cita)")
+			}
+			if err := insertResources(tx, id, occ.Recursos); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
 	if err != nil {
-		return database.MapSQLError(err, "AppointmentRepository.Update")
+		return 0, nil, err
+	}
+	return serieID, ids, nil
+}
+
+func (r *repository) GetBySeries(serieID int) ([]models.Appointment, error) {
+	return r.queryAppointments("AppointmentRepository.GetBySeries",
+		selectAppointment+` WHERE c.serie_id = $1 ORDER BY c.fecha`, serieID)
+}
+
+func (r *repository) DetachPatient(patientID int) error {
+	if _, err := r.db.Exec(`
+		UPDATE citas c
+		SET nombre = COALESCE(c.nombre, p.nombre), paciente_id = NULL
+		FROM pacientes p
+		WHERE c.paciente_id = p.id AND p.id = $1
+	`, patientID); err != nil {
+		return database.MapSQLError(err, "AppointmentRepository.DetachPatient(citas)")
+	}
+
+	if _, err := r.db.Exec(`
+		UPDATE series_citas s
+		SET nombre = COALESCE(s.nombre, p.nombre), paciente_id = NULL
+		FROM pacientes p
+		WHERE s.paciente_id = p.id AND p.id = $1
+	`, patientID); err != nil {
+		return database.MapSQLError(err, "AppointmentRepository.DetachPatient(series)")
+	}
+	return nil
+}
+
+func (r *repository) ReassignPatient(fromID, toID int) error {
+	if _, err := r.db.Exec(`UPDATE citas SET paciente_id = $2 WHERE paciente_id = $1`, fromID, toID); err != nil {
+		return database.MapSQLError(err, "AppointmentRepository.ReassignPatient(citas)")
 	}
-	rows, _ := res.RowsAffected()
-	if rows == 0 {
-		return appErr.Wrap("AppointmentRepository.Update", appErr.ErrNotFound, nil)
+	if _, err := r.db.Exec(`UPDATE series_citas SET paciente_id = $2 WHERE paciente_id = $1`, fromID, toID); err != nil {
+		return database.MapSQLError(err, "AppointmentRepository.ReassignPatient(series)")
 	}
 	return nil
 }
 
-func (r *repository) Delete(id int) error {
-	res, err := r.db.Exec(`DELETE FROM citas WHERE id = $1`, id)
+// SaveCalendarToken guarda el hash del token de feed del usuario, reemplazando el anterior
+func (r *repository) SaveCalendarToken(userID int, tokenHash string) error {
+	if _, err := r.db.Exec(`
+		INSERT INTO calendario_tokens (usuario_id, token_hash, fecha_creacion)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (usuario_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, fecha_creacion = NOW()
+	`, userID, tokenHash); err != nil {
+		return database.MapSQLError(err, "AppointmentRepository.SaveCalendarToken")
+	}
+	return nil
+}
+
+func (r *repository) GetCalendarTokenUser(tokenHash string) (int, error) {
+	var userID int
+	err := r.db.QueryRow(`SELECT usuario_id FROM calendario_tokens WHERE token_hash = $1`, tokenHash).Scan(&userID)
 	if err != nil {
-		return database.MapSQLError(err, "AppointmentRepository.Delete")
+		return 0, database.MapSQLError(err, "AppointmentRepository.GetCalendarTokenUser")
 	}
-	rows, _ := res.RowsAffected()
-	if rows == 0 {
-		return appErr.Wrap("AppointmentRepository.Delete", appErr.ErrNotFound, nil)
+	return userID, nil
+}
+
+func (r *repository) DeleteCalendarToken(userID int) error {
+	if _, err := r.db.Exec(`DELETE FROM calendario_tokens WHERE usuario_id = $1`, userID); err != nil {
+		return database.MapSQLError(err, "AppointmentRepository.DeleteCalendarToken")
 	}
 	return nil
 }
+
+func (r *repository) ExistsExternalUID(uid string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM citas WHERE uid_externo = $1)`, uid).Scan(&exists)
+	if err != nil {
+		return false, database.MapSQLError(err, "AppointmentRepository.ExistsExternalUID")
+	}
+	return exists, nil
+}
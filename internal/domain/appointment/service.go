@@ -1,11 +1,24 @@
+//go:generate mockgen -source=service.go -destination=mocks/service.go -package=mocks
+
 package appointment
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
 	patientModels "github.com/tonitomc/healthcare-crm-api/internal/domain/patient/models"
+	scheduleModels "github.com/tonitomc/healthcare-crm-api/internal/domain/schedule/models"
 	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/ical"
 	"github.com/tonitomc/healthcare-crm-api/pkg/timeutil"
 )
 
@@ -14,40 +27,145 @@ type PatientProvider interface {
 	GetByID(id int) (*patientModels.Patient, error)
 	Exists(id int) (bool, error)
 	Create(dto *patientModels.PatientCreateDTO) (int, error)
+	// WithTx devuelve un proveedor cuyas escrituras participan en la transacción tx
+	WithTx(tx database.DBTX) PatientProvider
 }
 
-// ScheduleValidator interface para validar horarios
+// ScheduleValidator interface para validar horarios.
+// doctorID nil usa el horario general de la clínica.
 type ScheduleValidator interface {
-	IsWithinBusinessHours(date, start, end time.Time) (bool, error)
-	GetEffectiveDay(date time.Time) (bool, error)
+	IsWithinBusinessHours(date, start, end time.Time, doctorID *int) (bool, error)
+	GetEffectiveDay(date time.Time, doctorID *int) (*scheduleModels.EffectiveDay, error)
+}
+
+// DoctorProvider interface para validar y listar los doctores a los que se asignan citas
+type DoctorProvider interface {
+	IsDoctor(userID int) (bool, error)
+	ListDoctors() ([]models.Doctor, error)
+}
+
+// TypeProvider interface para consultar el catálogo de tipos de cita
+type TypeProvider interface {
+	GetType(id int) (*models.AppointmentType, error)
+}
+
+// ConsultationStarter abre la consulta de una cita al hacer check-in
+type ConsultationStarter interface {
+	StartConsultation(start models.ConsultationStart) (int, error)
+	// WithTx devuelve un proveedor cuyas escrituras participan en la transacción tx
+	WithTx(tx database.DBTX) ConsultationStarter
+}
+
+// SlotListener recibe los horarios que quedan libres al cancelar o mover citas
+// (p. ej. para ofrecerlos a la lista de espera). Solo se notifican horarios futuros.
+type SlotListener interface {
+	SlotReleased(slot models.ReleasedSlot)
 }
 
 type Service interface {
 	GetByID(id int) (*models.Appointment, error)
-	GetByDate(date time.Time) ([]models.Appointment, error)
-	GetToday() ([]models.Appointment, error)
-	GetBetween(start, end time.Time) ([]models.Appointment, error)
-	GetAvailableSlots(date time.Time, slotDuration int64) ([]models.AvailabilitySlot, error)
+	GetByDate(date time.Time, filter models.AppointmentFilter) ([]models.Appointment, error)
+	GetToday(filter models.AppointmentFilter) ([]models.Appointment, error)
+	GetBetween(start, end time.Time, filter models.AppointmentFilter) ([]models.Appointment, error)
+	GetDayByProvider(date time.Time, estados []string) ([]models.ProviderColumn, error)
+	ListDoctors() ([]models.Doctor, error)
+	GetAvailableSlots(date time.Time, opts models.SlotOptions) ([]models.AvailabilitySlot, error)
+	GetNextAvailableSlots(from time.Time, count int, opts models.SlotOptions) ([]models.AvailabilitySlot, error)
 	Create(appt *models.AppointmentCreateDTO) (int, error)
 	CreateWithNewPatient(dto *models.AppointmentWithNewPatientDTO) (int, error)
 	Update(id int, appt *models.AppointmentUpdateDTO) error
-	Delete(id int) error
+
+	// Ciclo de vida: las citas no se eliminan, se cancelan
+	ChangeStatus(id int, userID int, dto *models.StatusChangeDTO) error
+	Cancel(id int, userID int, motivo *string) error
+	GetStatusHistory(id int) ([]models.StatusChange, error)
+	CheckIn(id int, userID int, dto *models.CheckInDTO) (*models.CheckInResult, error)
+	Complete(id int, userID int) error
+
+	// Series recurrentes
+	GetBySeries(serieID int) ([]models.Appointment, error)
+	CreateSeries(dto *models.SeriesCreateDTO) (*models.SeriesResult, error)
+	UpdateSeries(id int, scope string, appt *models.AppointmentUpdateDTO) (*models.SeriesResult, error)
+	CancelSeries(id int, scope string, userID int, motivo *string) (*models.SeriesResult, error)
+
+	// Calendario iCalendar
+	CreateCalendarToken(userID int) (string, error)
+	RevokeCalendarToken(userID int) error
+	ResolveCalendarToken(token string) (int, error)
+	GetCalendarFeed(userID int) (*ical.Calendar, error)
+	ImportCalendar(r io.Reader, opts models.ImportOptions) (*models.ImportResult, error)
+
+	// WithTx devuelve una copia del servicio cuyas escrituras se ejecutan en tx
+	WithTx(tx database.DBTX) Service
+}
+
+const (
+	// maxSeriesOccurrences limita el tamaño de una serie (~2 años de citas semanales)
+	maxSeriesOccurrences = 104
+
+	defaultSlotDuration = 900 // 15 min
+
+	// Límites de la búsqueda de próximos horarios disponibles
+	defaultNextSlots    = 5
+	maxNextSlots        = 50
+	nextSlotsHorizonDay = 60
+
+	// Ventana del feed de calendario alrededor de hoy
+	feedDaysBack  = 30
+	feedDaysAhead = 180
+	// maxImportEvents limita el número de eventos de un archivo .ics
+	maxImportEvents = 500
+)
+
+// Config permite personalizar el comportamiento del servicio de citas.
+type Config struct {
+	// BufferMinutes es el tiempo libre mínimo entre citas consecutivas
+	BufferMinutes int
 }
 
 type service struct {
 	repo              Repository
 	patientProvider   PatientProvider
 	scheduleValidator ScheduleValidator
+	doctorProvider    DoctorProvider
+	typeProvider      TypeProvider
+	consultations     ConsultationStarter
+	slotListener      SlotListener
+	uow               database.UnitOfWork
+	bufferMinutes     int
 }
 
-func NewService(repo Repository, patientProvider PatientProvider, scheduleValidator ScheduleValidator) Service {
+func NewService(repo Repository, patientProvider PatientProvider, scheduleValidator ScheduleValidator, doctorProvider DoctorProvider, typeProvider TypeProvider, consultations ConsultationStarter, slotListener SlotListener, uow database.UnitOfWork, cfg Config) Service {
+	if cfg.BufferMinutes < 0 {
+		cfg.BufferMinutes = 0
+	}
 	return &service{
 		repo:              repo,
 		patientProvider:   patientProvider,
 		scheduleValidator: scheduleValidator,
+		doctorProvider:    doctorProvider,
+		typeProvider:      typeProvider,
+		consultations:     consultations,
+		slotListener:      slotListener,
+		uow:               uow,
+		bufferMinutes:     cfg.BufferMinutes,
 	}
 }
 
+// withTx devuelve una copia del servicio cuyas escrituras se ejecutan en tx
+func (s *service) withTx(tx database.DBTX) *service {
+	txService := *s
+	txService.repo = s.repo.WithTx(tx)
+	txService.patientProvider = s.patientProvider.WithTx(tx)
+	txService.consultations = s.consultations.WithTx(tx)
+	txService.uow = database.JoinUnitOfWork(tx)
+	return &txService
+}
+
+func (s *service) WithTx(tx database.DBTX) Service {
+	return s.withTx(tx)
+}
+
 func (s *service) GetByID(id int) (*models.Appointment, error) {
 	if id <= 0 {
 		return nil, appErr.Wrap("AppointmentService.GetByID", appErr.ErrInvalidInput, nil)
@@ -55,25 +173,137 @@ func (s *service) GetByID(id int) (*models.Appointment, error) {
 	return s.repo.GetByID(id)
 }
 
-func (s *service) GetByDate(date time.Time) ([]models.Appointment, error) {
-	return s.repo.GetByDate(date)
+func (s *service) GetByDate(date time.Time, filter models.AppointmentFilter) ([]models.Appointment, error) {
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByDate(date, filter)
 }
 
-func (s *service) GetToday() ([]models.Appointment, error) {
-	return s.repo.GetToday()
+func (s *service) GetToday(filter models.AppointmentFilter) ([]models.Appointment, error) {
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.repo.GetToday(filter)
 }
 
-func (s *service) GetBetween(start, end time.Time) ([]models.Appointment, error) {
+func (s *service) GetBetween(start, end time.Time, filter models.AppointmentFilter) ([]models.Appointment, error) {
 	if start.After(end) {
 		return nil, appErr.Wrap("AppointmentService.GetBetween(invalid range)", appErr.ErrInvalidInput, nil)
 	}
-	return s.repo.GetBetween(start, end)
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBetween(start, end, filter)
+}
+
+// GetDayByProvider devuelve las citas del día en una columna por doctor, más una
+// columna final (Doctor nil) con las citas sin doctor asignado si existen.
+func (s *service) GetDayByProvider(date time.Time, estados []string) ([]models.ProviderColumn, error) {
+	filter := models.AppointmentFilter{Estados: estados}
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	doctors, err := s.doctorProvider.ListDoctors()
+	if err != nil {
+		return nil, err
+	}
+	appts, err := s.repo.GetByDate(date, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]models.ProviderColumn, 0, len(doctors)+1)
+	index := make(map[int]int, len(doctors))
+	for i := range doctors {
+		index[doctors[i].ID] = len(columns)
+		columns = append(columns, models.ProviderColumn{Doctor: &doctors[i], Citas: []models.Appointment{}})
+	}
+
+	unassigned := models.ProviderColumn{Citas: []models.Appointment{}}
+	for _, a := range appts {
+		if a.DoctorID != nil {
+			if i, ok := index[*a.DoctorID]; ok {
+				columns[i].Citas = append(columns[i].Citas, a)
+				continue
+			}
+		}
+		// Citas sin doctor, o de un usuario que ya no tiene rol de doctor
+		unassigned.Citas = append(unassigned.Citas, a)
+	}
+	if len(unassigned.Citas) > 0 {
+		columns = append(columns, unassigned)
+	}
+	return columns, nil
+}
+
+func (s *service) ListDoctors() ([]models.Doctor, error) {
+	return s.doctorProvider.ListDoctors()
+}
+
+// validateDoctor verifica que el usuario asignado tenga rol de doctor.
+func (s *service) validateDoctor(doctorID *int) error {
+	if doctorID == nil {
+		return nil
+	}
+	if *doctorID <= 0 {
+		return appErr.Wrap("AppointmentService.validateDoctor(invalid id)", appErr.ErrInvalidInput, nil)
+	}
+	ok, err := s.doctorProvider.IsDoctor(*doctorID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "El usuario asignado no es un doctor")
+	}
+	return nil
+}
+
+// resolveType aplica el tipo de cita: devuelve la duración (la indicada o, si es 0, la
+// del tipo) y los recursos que ocupará la cita. Sin tipo no se ocupan recursos.
+func (s *service) resolveType(tipoID *int, duracion int64) (int64, []int, error) {
+	if tipoID == nil {
+		return duracion, nil, nil
+	}
+	if *tipoID <= 0 {
+		return 0, nil, appErr.Wrap("AppointmentService.resolveType(invalid id)", appErr.ErrInvalidInput, nil)
+	}
+
+	t, err := s.typeProvider.GetType(*tipoID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !t.Activo {
+		return 0, nil, appErr.NewDomainError(appErr.ErrInvalidInput, "El tipo de cita está inactivo")
+	}
+
+	recursos := make([]int, 0, len(t.Recursos))
+	for _, r := range t.Recursos {
+		if !r.Activo {
+			return 0, nil, appErr.NewDomainError(appErr.ErrConflict,
+				fmt.Sprintf("El recurso '%s' requerido por el tipo de cita no está disponible", r.Nombre))
+		}
+		recursos = append(recursos, r.ID)
+	}
+
+	if duracion <= 0 {
+		duracion = t.Duracion
+	}
+	return duracion, recursos, nil
 }
 
 func (s *service) Create(appt *models.AppointmentCreateDTO) (int, error) {
 	if appt.PacienteID == nil && appt.Nombre == nil {
 		return 0, appErr.Wrap("AppointmentService.Create(must provide paciente_id or nombre)", appErr.ErrInvalidInput, nil)
 	}
+
+	duracion, recursos, err := s.resolveType(appt.TipoID, appt.Duracion)
+	if err != nil {
+		return 0, err
+	}
+	appt.Duracion = duracion
+	appt.Recursos = recursos
 	if appt.Duracion <= 0 {
 		return 0, appErr.Wrap("AppointmentService.Create(duracion must be > 0)", appErr.ErrInvalidInput, nil)
 	}
@@ -89,9 +319,12 @@ func (s *service) Create(appt *models.AppointmentCreateDTO) (int, error) {
 			return 0, appErr.Wrap("AppointmentService.Create(patient not found)", appErr.ErrNotFound, nil)
 		}
 	}
+	if err := s.validateDoctor(appt.DoctorID); err != nil {
+		return 0, err
+	}
 
 	endTime := appt.Fecha.Add(time.Duration(appt.Duracion) * time.Second)
-	withinHours, err := s.scheduleValidator.IsWithinBusinessHours(appt.Fecha, appt.Fecha, endTime)
+	withinHours, err := s.scheduleValidator.IsWithinBusinessHours(appt.Fecha, appt.Fecha, endTime, appt.DoctorID)
 	if err != nil {
 		return 0, err
 	}
@@ -99,97 +332,151 @@ func (s *service) Create(appt *models.AppointmentCreateDTO) (int, error) {
 		return 0, appErr.Wrap("AppointmentService.Create(time outside working hours)", appErr.ErrInvalidInput, nil)
 	}
 
-	const gapMinutes = 0
-	dayStart := timeutil.StartOfClinicDay(appt.Fecha)
-	dayEnd := dayStart.Add(24 * time.Hour)
-	existing, err := s.repo.GetBetween(dayStart, dayEnd)
+	var id int
+	err = s.reserve(appt.Fecha, endTime, appt.DoctorID, appt.Recursos, nil, func(repo Repository) error {
+		var err error
+		id, err = repo.Create(appt)
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
-
-	endTimeWithGap := endTime.Add(time.Duration(gapMinutes) * time.Minute)
-	for _, ex := range existing {
-		exEnd := ex.Fecha.Add(time.Duration(ex.Duracion) * time.Second)
-		exEndWithGap := exEnd.Add(time.Duration(gapMinutes) * time.Minute)
-		if appt.Fecha.Before(exEndWithGap) && endTimeWithGap.After(ex.Fecha) {
-			return 0, appErr.NewDomainError(appErr.ErrConflict, "El horario solicitado traslapa con otras citas")
-		}
-	}
-
-	return s.repo.Create(appt)
+	return id, nil
 }
 
 func (s *service) CreateWithNewPatient(dto *models.AppointmentWithNewPatientDTO) (int, error) {
-	if dto.AppointmentData.Duracion <= 0 {
+	if dto.AppointmentData.Duracion <= 0 && dto.AppointmentData.TipoID == nil {
 		return 0, appErr.Wrap("AppointmentService.CreateWithNewPatient(duracion must be > 0)", appErr.ErrInvalidInput, nil)
 	}
 
-	patientID, err := s.patientProvider.Create(&dto.PatientData)
+	// El paciente y la cita se crean en una sola transacción: si la cita no puede
+	// agendarse (traslape, fuera de horario) el paciente tampoco queda registrado
+	var appointmentID int
+	err := s.uow.Do(func(tx database.DBTX) error {
+		txService := s.withTx(tx)
+
+		patientID, err := txService.patientProvider.Create(&dto.PatientData)
+		if err != nil {
+			return err
+		}
+
+		appointmentDTO := &models.AppointmentCreateDTO{
+			PacienteID: &patientID,
+			Fecha:      dto.AppointmentData.Fecha,
+			Duracion:   dto.AppointmentData.Duracion,
+			DoctorID:   dto.AppointmentData.DoctorID,
+			TipoID:     dto.AppointmentData.TipoID,
+		}
+
+		appointmentID, err = txService.Create(appointmentDTO)
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
 
-	appointmentDTO := &models.AppointmentCreateDTO{
-		PacienteID: &patientID,
-		Fecha:      dto.AppointmentData.Fecha,
-		Duracion:   dto.AppointmentData.Duracion,
+	return appointmentID, nil
+}
+
+// GetAvailableSlots genera los horarios del día a partir de las franjas efectivas del
+// horario (turnos partidos, días especiales, almuerzos), respetando el buffer entre citas.
+func (s *service) GetAvailableSlots(date time.Time, opts models.SlotOptions) ([]models.AvailabilitySlot, error) {
+	opts, err := s.normalizeSlotOptions(opts)
+	if err != nil {
+		return nil, err
 	}
+	return s.daySlots(timeutil.StartOfClinicDay(date), opts)
+}
 
-	appointmentID, err := s.Create(appointmentDTO)
+// GetNextAvailableSlots devuelve los primeros count horarios libres a partir de from,
+// buscando hasta nextSlotsHorizonDay días hacia adelante.
+func (s *service) GetNextAvailableSlots(from time.Time, count int, opts models.SlotOptions) ([]models.AvailabilitySlot, error) {
+	if count <= 0 {
+		count = defaultNextSlots
+	}
+	if count > maxNextSlots {
+		return nil, appErr.Wrap("AppointmentService.GetNextAvailableSlots(count too large)", appErr.ErrInvalidInput, nil)
+	}
+	opts, err := s.normalizeSlotOptions(opts)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	return appointmentID, nil
+	from = timeutil.NormalizeToClinic(from)
+	day := timeutil.StartOfClinicDay(from)
+	found := make([]models.AvailabilitySlot, 0, count)
+	for i := 0; i < nextSlotsHorizonDay && len(found) < count; i++ {
+		slots, err := s.daySlots(day.AddDate(0, 0, i), opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, slot := range slots {
+			if !slot.Available || slot.Start.Before(from) {
+				continue
+			}
+			found = append(found, slot)
+			if len(found) == count {
+				break
+			}
+		}
+	}
+	return found, nil
 }
 
-func (s *service) GetAvailableSlots(date time.Time, slotDuration int64) ([]models.AvailabilitySlot, error) {
-	if slotDuration <= 0 {
-		slotDuration = 900 // 15 min default
+func (s *service) normalizeSlotOptions(opts models.SlotOptions) (models.SlotOptions, error) {
+	duracion, recursos, err := s.resolveType(opts.TipoID, opts.Duracion)
+	if err != nil {
+		return opts, err
+	}
+	opts.Duracion = duracion
+	opts.Recursos = recursos
+	if opts.Duracion <= 0 {
+		opts.Duracion = defaultSlotDuration
 	}
+	if opts.BufferMinutes == nil {
+		buffer := s.bufferMinutes
+		opts.BufferMinutes = &buffer
+	}
+	if *opts.BufferMinutes < 0 {
+		return opts, appErr.Wrap("AppointmentService.normalizeSlotOptions(buffer must be >= 0)", appErr.ErrInvalidInput, nil)
+	}
+	return opts, nil
+}
+
+// daySlots recorre cada franja laboral del día en pasos de duración + buffer.
+// dayStart debe ser la medianoche del día en la zona de la clínica.
+func (s *service) daySlots(dayStart time.Time, opts models.SlotOptions) ([]models.AvailabilitySlot, error) {
+	slots := []models.AvailabilitySlot{}
 
-	isOpen, err := s.scheduleValidator.GetEffectiveDay(date)
+	eff, err := s.scheduleValidator.GetEffectiveDay(dayStart, opts.DoctorID)
 	if err != nil {
 		return nil, err
 	}
-	if !isOpen {
-		return []models.AvailabilitySlot{}, nil
+	if eff == nil || !eff.Active || len(eff.Ranges) == 0 {
+		return slots, nil
 	}
 
-	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	dayEnd := dayStart.Add(24 * time.Hour)
-	appointments, err := s.repo.GetBetween(dayStart, dayEnd)
+	appointments, err := s.repo.GetBetween(dayStart, dayStart.Add(24*time.Hour), models.AppointmentFilter{})
 	if err != nil {
 		return nil, err
 	}
 
-	var slots []models.AvailabilitySlot
-	startTime := time.Date(date.Year(), date.Month(), date.Day(), 8, 0, 0, 0, date.Location())
-	endTime := time.Date(date.Year(), date.Month(), date.Day(), 18, 0, 0, 0, date.Location())
+	duration := time.Duration(opts.Duracion) * time.Second
+	step := duration + time.Duration(*opts.BufferMinutes)*time.Minute
 
-	currentTime := startTime
-	for currentTime.Before(endTime) {
-		slotEnd := currentTime.Add(time.Duration(slotDuration) * time.Second)
-		if slotEnd.After(endTime) {
-			break
-		}
+	for _, r := range eff.Ranges {
+		// Las franjas vienen ancladas a una fecha fija; solo interesa la hora del día
+		rangeStart := dayStart.Add(time.Duration(timeutil.TimeOfDayMinutes(r.Start)) * time.Minute)
+		rangeEnd := dayStart.Add(time.Duration(timeutil.TimeOfDayMinutes(r.End)) * time.Minute)
 
-		available := true
-		for _, appt := range appointments {
-			apptEnd := appt.Fecha.Add(time.Duration(appt.Duracion) * time.Second)
-			if currentTime.Before(apptEnd) && slotEnd.After(appt.Fecha) {
-				available = false
-				break
-			}
+		for current := rangeStart; !current.Add(duration).After(rangeEnd); current = current.Add(step) {
+			slotEnd := current.Add(duration)
+			slots = append(slots, models.AvailabilitySlot{
+				Start:     current,
+				End:       slotEnd,
+				Available: slotConflict(current, slotEnd, opts.DoctorID, opts.Recursos, appointments, *opts.BufferMinutes, nil) == "",
+			})
 		}
-
-		slots = append(slots, models.AvailabilitySlot{
-			Start:     currentTime,
-			End:       slotEnd,
-			Available: available,
-		})
-
-		currentTime = slotEnd
 	}
 
 	return slots, nil
@@ -203,11 +490,14 @@ func (s *service) Update(id int, appt *models.AppointmentUpdateDTO) error {
 		return appErr.Wrap("AppointmentService.Update(duracion must be > 0)", appErr.ErrInvalidInput, nil)
 	}
 
-	if appt.Fecha != nil || appt.Duracion != nil {
+	if appt.Fecha != nil || appt.Duracion != nil || appt.DoctorID != nil || appt.TipoID != nil {
 		current, err := s.repo.GetByID(id)
 		if err != nil {
 			return err
 		}
+		if !models.IsReschedulable(current.Estado) {
+			return appErr.NewDomainError(appErr.ErrConflict, "La cita ya no puede reprogramarse en su estado actual")
+		}
 
 		newFecha := current.Fecha
 		if appt.Fecha != nil {
@@ -217,9 +507,31 @@ func (s *service) Update(id int, appt *models.AppointmentUpdateDTO) error {
 		if appt.Duracion != nil {
 			newDuracion = *appt.Duracion
 		}
+		newDoctor := current.DoctorID
+		if appt.DoctorID != nil {
+			if err := s.validateDoctor(appt.DoctorID); err != nil {
+				return err
+			}
+			newDoctor = appt.DoctorID
+		}
+		newRecursos := current.Recursos
+		if appt.TipoID != nil {
+			// Un nuevo tipo trae sus propios recursos y, si no se indica otra, su duración
+			var typeDuracion int64
+			if appt.Duracion != nil {
+				typeDuracion = *appt.Duracion
+			}
+			typeDuracion, newRecursos, err = s.resolveType(appt.TipoID, typeDuracion)
+			if err != nil {
+				return err
+			}
+			newDuracion = typeDuracion
+			appt.Duracion = &newDuracion
+			appt.Recursos = newRecursos
+		}
 
 		endTime := newFecha.Add(time.Duration(newDuracion) * time.Second)
-		withinHours, err := s.scheduleValidator.IsWithinBusinessHours(newFecha, newFecha, endTime)
+		withinHours, err := s.scheduleValidator.IsWithinBusinessHours(newFecha, newFecha, endTime, newDoctor)
 		if err != nil {
 			return err
 		}
@@ -227,42 +539,1123 @@ func (s *service) Update(id int, appt *models.AppointmentUpdateDTO) error {
 			return appErr.NewDomainError(appErr.ErrConflict, "El horario solicitado se encuentra fuera del horario laboral")
 		}
 
-		const gapMinutes = 0
-		dayStart := timeutil.StartOfClinicDay(newFecha)
-		dayEnd := dayStart.Add(24 * time.Hour)
-		existing, err := s.repo.GetBetween(dayStart, dayEnd)
+		if appt.Fecha != nil {
+			*appt.Fecha = newFecha
+		}
+
+		err = s.reserve(newFecha, endTime, newDoctor, newRecursos, map[int]bool{id: true}, func(repo Repository) error {
+			return repo.Update(id, appt)
+		})
 		if err != nil {
 			return err
 		}
 
-		endTimeWithGap := endTime.Add(time.Duration(gapMinutes) * time.Minute)
-		for _, ex := range existing {
-			if ex.ID == id {
-				continue
-			}
-			exEnd := ex.Fecha.Add(time.Duration(ex.Duracion) * time.Second)
-			exEndWithGap := exEnd.Add(time.Duration(gapMinutes) * time.Minute)
-
-			// Allow touching appointments e.g. 10:00-10:20 and 10:20-10:40
-			if newFecha.Before(exEndWithGap) && endTimeWithGap.After(ex.Fecha) {
-				// touching allowed
-				if !newFecha.Equal(exEndWithGap) && !endTimeWithGap.Equal(ex.Fecha) {
-					return appErr.NewDomainError(appErr.ErrConflict, "El horario solicitado traslapa con otras citas")
-				}
+		if moved(current, newFecha, newDoctor) {
+			s.releaseSlot(current)
+		}
+		return nil
+	}
+
+	return s.repo.Update(id, appt)
+}
+
+// reserve verifica traslapes y ejecuta write bajo el candado del día, doctor y recursos, en
+// una sola transacción: de dos reservas simultáneas del mismo horario solo una pasa la verificación.
+func (s *service) reserve(start, end time.Time, doctorID *int, recursos []int, skip map[int]bool, write func(repo Repository) error) error {
+	return s.uow.Do(func(tx database.DBTX) error {
+		repo := s.repo.WithTx(tx)
+
+		dayStart := timeutil.StartOfClinicDay(start)
+		if err := repo.LockDay(dayStart, doctorID); err != nil {
+			return err
+		}
+		if len(recursos) > 0 {
+			if err := repo.LockResources(dayStart, recursos); err != nil {
+				return err
 			}
 		}
 
-		if appt.Fecha != nil {
-			*appt.Fecha = newFecha
+		existing, err := repo.GetBetween(dayStart, dayStart.Add(24*time.Hour), models.AppointmentFilter{})
+		if err != nil {
+			return err
+		}
+
+		// Allow touching appointments e.g. 10:00-10:20 and 10:20-10:40
+		if motivo := slotConflict(start, end, doctorID, recursos, existing, s.bufferMinutes, skip); motivo != "" {
+			return appErr.NewDomainError(appErr.ErrConflict, motivo)
 		}
+
+		return write(repo)
+	})
+}
+
+// lockSlots toma los candados de reserve para varios horarios a la vez, p. ej. las citas
+// de una serie. Primero todos los de día y doctor y después los de recursos, cada grupo
+// en orden ascendente; reserve sigue el mismo orden, así que dos reservas que comparten
+// días no se bloquean mutuamente.
+func lockSlots(repo Repository, slots []models.Appointment) error {
+	type dayLock struct {
+		day      time.Time
+		doctorID *int
+	}
+	var dayLocks []dayLock
+	resources := map[time.Time][]int{}
+	for _, slot := range slots {
+		dayStart := timeutil.StartOfClinicDay(slot.Fecha)
+		dayLocks = append(dayLocks, dayLock{day: dayStart, doctorID: slot.DoctorID})
+		resources[dayStart] = append(resources[dayStart], slot.Recursos...)
 	}
 
-	return s.repo.Update(id, appt)
+	sort.Slice(dayLocks, func(i, j int) bool {
+		if !dayLocks[i].day.Equal(dayLocks[j].day) {
+			return dayLocks[i].day.Before(dayLocks[j].day)
+		}
+		return doctorLockKey(dayLocks[i].doctorID) < doctorLockKey(dayLocks[j].doctorID)
+	})
+	for i, l := range dayLocks {
+		if i > 0 && l.day.Equal(dayLocks[i-1].day) && sameDoctor(l.doctorID, dayLocks[i-1].doctorID) {
+			continue // pg_advisory_xact_lock es reentrante, pero no hace falta pedirlo dos veces
+		}
+		if err := repo.LockDay(l.day, l.doctorID); err != nil {
+			return err
+		}
+	}
+
+	days := make([]time.Time, 0, len(resources))
+	for day, ids := range resources {
+		if len(ids) > 0 {
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	for _, day := range days {
+		if err := repo.LockResources(day, uniqueInts(resources[day])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// doctorLockKey es el doctor en la llave de LockDay; la agenda general usa 0.
+func doctorLockKey(doctorID *int) int {
+	if doctorID == nil {
+		return 0
+	}
+	return *doctorID
+}
+
+func uniqueInts(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	out := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// releaseSlot avisa al SlotListener que el horario de la cita quedó libre.
+func (s *service) releaseSlot(appt *models.Appointment) {
+	if s.slotListener == nil || !appt.Fecha.After(time.Now()) {
+		return
+	}
+	s.slotListener.SlotReleased(models.ReleasedSlot{
+		Fecha:    appt.Fecha,
+		Duracion: appt.Duracion,
+		DoctorID: appt.DoctorID,
+	})
+}
+
+// moved indica si la cita deja su horario original (otra hora u otro doctor)
+func moved(appt *models.Appointment, newFecha time.Time, newDoctor *int) bool {
+	return !appt.Fecha.Equal(newFecha) || !sameDoctor(appt.DoctorID, newDoctor)
+}
+
+// ============================================================================
+// CICLO DE VIDA
+// ============================================================================
+
+// ChangeStatus mueve la cita a un nuevo estado, registrando quién y por qué.
+func (s *service) ChangeStatus(id int, userID int, dto *models.StatusChangeDTO) error {
+	if id <= 0 || !models.IsValidStatus(dto.Estado) {
+		return appErr.Wrap("AppointmentService.ChangeStatus", appErr.ErrInvalidInput, nil)
+	}
+
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if !models.CanTransition(current.Estado, dto.Estado) {
+		return appErr.NewDomainError(appErr.ErrConflict,
+			fmt.Sprintf("No se puede cambiar una cita de '%s' a '%s'", current.Estado, dto.Estado))
+	}
+
+	if err := s.repo.UpdateStatus([]models.StatusChange{
+		newStatusChange(current, dto.Estado, userID, dto.Motivo),
+	}); err != nil {
+		return err
+	}
+
+	if models.BlocksSlot(current.Estado) && !models.BlocksSlot(dto.Estado) {
+		s.releaseSlot(current)
+	}
+	return nil
+}
+
+// Cancel cancela la cita; el registro se conserva y el horario queda libre.
+func (s *service) Cancel(id int, userID int, motivo *string) error {
+	return s.ChangeStatus(id, userID, &models.StatusChangeDTO{Estado: models.StatusCancelled, Motivo: motivo})
+}
+
+// CheckIn registra la llegada del paciente: abre su consulta con el cuestionario del
+// tipo de cita y pasa la cita a in-progress, todo en una sola transacción.
+func (s *service) CheckIn(id int, userID int, dto *models.CheckInDTO) (*models.CheckInResult, error) {
+	if id <= 0 {
+		return nil, appErr.Wrap("AppointmentService.CheckIn", appErr.ErrInvalidInput, nil)
+	}
+	if dto == nil {
+		dto = &models.CheckInDTO{}
+	}
+
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if current.PacienteID == nil {
+		return nil, appErr.NewDomainError(appErr.ErrInvalidInput, "La cita no tiene un paciente registrado; asigne uno antes del check-in")
+	}
+
+	var estados []string
+	switch current.Estado {
+	case models.StatusScheduled, models.StatusConfirmed:
+		estados = []string{models.StatusCheckedIn, models.StatusInProgress}
+	case models.StatusCheckedIn:
+		estados = []string{models.StatusInProgress}
+	default:
+		return nil, appErr.NewDomainError(appErr.ErrConflict,
+			fmt.Sprintf("No se puede hacer check-in de una cita en estado '%s'", current.Estado))
+	}
+
+	var cuestionarioID int
+	switch {
+	case current.CuestionarioID != nil:
+		cuestionarioID = *current.CuestionarioID
+	case dto.CuestionarioID != nil:
+		cuestionarioID = *dto.CuestionarioID
+	default:
+		return nil, appErr.NewDomainError(appErr.ErrInvalidInput, "El tipo de cita no define un cuestionario; indique cuestionario_id")
+	}
+
+	motivo := "Consulta"
+	switch {
+	case dto.Motivo != nil && strings.TrimSpace(*dto.Motivo) != "":
+		motivo = strings.TrimSpace(*dto.Motivo)
+	case current.NombreTipo != nil:
+		motivo = *current.NombreTipo
+	}
+
+	result := &models.CheckInResult{CitaID: id}
+	err = s.uow.Do(func(tx database.DBTX) error {
+		var err error
+		result.ConsultaID, err = s.consultations.WithTx(tx).StartConsultation(models.ConsultationStart{
+			CitaID:         id,
+			PacienteID:     *current.PacienteID,
+			CuestionarioID: cuestionarioID,
+			Motivo:         motivo,
+		})
+		if err != nil {
+			return err
+		}
+		return s.repo.WithTx(tx).UpdateStatus(statusPath(current, estados, userID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Complete cierra la cita cuando termina su consulta. Una cita ya completada no cambia.
+func (s *service) Complete(id int, userID int) error {
+	if id <= 0 {
+		return appErr.Wrap("AppointmentService.Complete", appErr.ErrInvalidInput, nil)
+	}
+
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	var estados []string
+	switch current.Estado {
+	case models.StatusCompleted:
+		return nil
+	case models.StatusCheckedIn:
+		estados = []string{models.StatusInProgress, models.StatusCompleted}
+	case models.StatusInProgress:
+		estados = []string{models.StatusCompleted}
+	default:
+		return appErr.NewDomainError(appErr.ErrConflict,
+			fmt.Sprintf("No se puede completar una cita en estado '%s'", current.Estado))
+	}
+	return s.repo.UpdateStatus(statusPath(current, estados, userID))
+}
+
+// statusPath encadena los cambios de estado para recorrer estados en orden, dejando
+// cada paso en el historial.
+func statusPath(appt *models.Appointment, estados []string, userID int) []models.StatusChange {
+	changes := make([]models.StatusChange, 0, len(estados))
+	step := *appt
+	for _, estado := range estados {
+		changes = append(changes, newStatusChange(&step, estado, userID, nil))
+		step.Estado = estado
+	}
+	return changes
 }
 
-func (s *service) Delete(id int) error {
+func (s *service) GetStatusHistory(id int) ([]models.StatusChange, error) {
 	if id <= 0 {
-		return appErr.Wrap("AppointmentService.Delete", appErr.ErrInvalidInput, nil)
+		return nil, appErr.Wrap("AppointmentService.GetStatusHistory", appErr.ErrInvalidInput, nil)
+	}
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetStatusHistory(id)
+}
+
+func newStatusChange(appt *models.Appointment, estado string, userID int, motivo *string) models.StatusChange {
+	ch := models.StatusChange{
+		CitaID:         appt.ID,
+		EstadoAnterior: appt.Estado,
+		EstadoNuevo:    estado,
+		Motivo:         motivo,
+		Fecha:          time.Now().In(timeutil.ClinicLocation()),
+	}
+	if userID > 0 {
+		ch.UsuarioID = &userID
+	}
+	return ch
+}
+
+func validateFilter(filter models.AppointmentFilter) error {
+	for _, e := range filter.Estados {
+		if !models.IsValidStatus(e) {
+			return appErr.Wrap("AppointmentService(invalid estado filter)", appErr.ErrInvalidInput, nil)
+		}
+	}
+	if filter.DoctorID != nil && *filter.DoctorID <= 0 {
+		return appErr.Wrap("AppointmentService(invalid doctor filter)", appErr.ErrInvalidInput, nil)
+	}
+	return nil
+}
+
+// ============================================================================
+// SERIES RECURRENTES
+// ============================================================================
+
+func (s *service) GetBySeries(serieID int) ([]models.Appointment, error) {
+	if serieID <= 0 {
+		return nil, appErr.Wrap("AppointmentService.GetBySeries", appErr.ErrInvalidInput, nil)
+	}
+	return s.repo.GetBySeries(serieID)
+}
+
+// CreateSeries expande la regla de recurrencia y agenda todas las ocurrencias válidas.
+// Las ocurrencias que chocan con el horario laboral o con otras citas se reportan
+// en Conflictos y no se crean; si ninguna es válida no se crea la serie.
+func (s *service) CreateSeries(dto *models.SeriesCreateDTO) (*models.SeriesResult, error) {
+	if dto.PacienteID == nil && dto.Nombre == nil {
+		return nil, appErr.Wrap("AppointmentService.CreateSeries(must provide paciente_id or nombre)", appErr.ErrInvalidInput, nil)
+	}
+	duracion, recursos, err := s.resolveType(dto.TipoID, dto.Duracion)
+	if err != nil {
+		return nil, err
+	}
+	dto.Duracion = duracion
+	if dto.Duracion <= 0 {
+		return nil, appErr.Wrap("AppointmentService.CreateSeries(duracion must be > 0)", appErr.ErrInvalidInput, nil)
+	}
+
+	if dto.PacienteID != nil {
+		exists, err := s.patientProvider.Exists(*dto.PacienteID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, appErr.Wrap("AppointmentService.CreateSeries(patient not found)", appErr.ErrNotFound, nil)
+		}
+	}
+	if err := s.validateDoctor(dto.DoctorID); err != nil {
+		return nil, err
+	}
+
+	dto.Fecha = timeutil.NormalizeToClinic(dto.Fecha)
+	dates, skipped, err := expandRecurrence(dto.Fecha, dto.Regla)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SeriesResult{Citas: []int{}, Conflictos: skipped}
+	var candidates []time.Time
+	for _, fecha := range dates {
+		motivo, err := s.hoursConflict(fecha, dto.Duracion, dto.DoctorID)
+		if err != nil {
+			return nil, err
+		}
+		if motivo != "" {
+			result.Conflictos = append(result.Conflictos, models.SeriesConflict{Fecha: fecha, Motivo: motivo})
+			continue
+		}
+		candidates = append(candidates, fecha)
+	}
+
+	series := &models.Series{
+		PacienteID:  dto.PacienteID,
+		Nombre:      dto.Nombre,
+		DoctorID:    dto.DoctorID,
+		TipoID:      dto.TipoID,
+		Frecuencia:  dto.Regla.Frecuencia,
+		Intervalo:   dto.Regla.Intervalo,
+		FechaInicio: dto.Fecha,
+		Duracion:    dto.Duracion,
+		Hasta:       dto.Regla.Hasta,
+		Ocurrencias: dto.Regla.Ocurrencias,
+	}
+
+	// Como en reserve: la verificación de traslapes y la inserción ocurren bajo los
+	// candados de cada día de la serie, así que una serie simultánea que pierde la
+	// carrera ve las citas de la otra y las reporta en Conflictos
+	var serieID int
+	var ids []int
+	err = s.uow.Do(func(tx database.DBTX) error {
+		repo := s.repo.WithTx(tx)
+
+		slots := make([]models.Appointment, 0, len(candidates))
+		for _, fecha := range candidates {
+			slots = append(slots, models.Appointment{Fecha: fecha, DoctorID: dto.DoctorID, Recursos: recursos})
+		}
+		if err := lockSlots(repo, slots); err != nil {
+			return err
+		}
+
+		days := &dayCache{repo: repo, days: map[int64][]models.Appointment{}}
+		var occurrences []models.AppointmentCreateDTO
+		for _, fecha := range candidates {
+			existing, err := days.get(fecha)
+			if err != nil {
+				return err
+			}
+			end := fecha.Add(time.Duration(dto.Duracion) * time.Second)
+			if motivo := slotConflict(fecha, end, dto.DoctorID, recursos, existing, s.bufferMinutes, nil); motivo != "" {
+				result.Conflictos = append(result.Conflictos, models.SeriesConflict{Fecha: fecha, Motivo: motivo})
+				continue
+			}
+			occurrences = append(occurrences, models.AppointmentCreateDTO{
+				PacienteID: dto.PacienteID,
+				Nombre:     dto.Nombre,
+				Fecha:      fecha,
+				Duracion:   dto.Duracion,
+				DoctorID:   dto.DoctorID,
+				TipoID:     dto.TipoID,
+				Recursos:   recursos,
+			})
+		}
+
+		if len(occurrences) == 0 {
+			return appErr.NewDomainError(appErr.ErrConflict, "Ninguna de las citas de la serie está disponible")
+		}
+
+		var err error
+		serieID, ids, err = repo.CreateSeries(series, occurrences)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.SerieID = serieID
+	result.Citas = ids
+	sortConflicts(result.Conflictos)
+	return result, nil
+}
+
+// UpdateSeries aplica un cambio de fecha y/o duración a la cita indicada según el alcance.
+// Para "following" y "all", el desplazamiento entre la fecha actual de la cita indicada y
+// la nueva fecha se aplica a cada cita afectada, conservando el patrón de la serie.
+func (s *service) UpdateSeries(id int, scope string, appt *models.AppointmentUpdateDTO) (*models.SeriesResult, error) {
+	if id <= 0 {
+		return nil, appErr.Wrap("AppointmentService.UpdateSeries(invalid id)", appErr.ErrInvalidInput, nil)
+	}
+	if appt.Duracion != nil && *appt.Duracion <= 0 {
+		return nil, appErr.Wrap("AppointmentService.UpdateSeries(duracion must be > 0)", appErr.ErrInvalidInput, nil)
+	}
+
+	anchor, targets, err := s.seriesTargets(id, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SeriesResult{Citas: []int{}, Conflictos: []models.SeriesConflict{}}
+	if anchor.SerieID != nil {
+		result.SerieID = *anchor.SerieID
+	}
+
+	if scope == models.ScopeThis {
+		if err := s.Update(id, appt); err != nil {
+			return nil, err
+		}
+		result.Citas = append(result.Citas, id)
+		return result, nil
+	}
+
+	var shift time.Duration
+	if appt.Fecha != nil {
+		shift = timeutil.NormalizeToClinic(*appt.Fecha).Sub(anchor.Fecha)
+	}
+	if err := s.validateDoctor(appt.DoctorID); err != nil {
+		return nil, err
+	}
+	var typeDuracion *int64
+	var typeRecursos []int
+	if appt.TipoID != nil {
+		var requested int64
+		if appt.Duracion != nil {
+			requested = *appt.Duracion
+		}
+		d, recursos, err := s.resolveType(appt.TipoID, requested)
+		if err != nil {
+			return nil, err
+		}
+		typeDuracion, typeRecursos = &d, recursos
+	}
+
+	var moves []seriesMove
+	for _, t := range targets {
+		if !models.IsReschedulable(t.Estado) {
+			continue // citas canceladas, atendidas o finalizadas no se mueven con la serie
+		}
+		m := seriesMove{cita: t, fecha: t.Fecha.Add(shift), duracion: t.Duracion, doctorID: t.DoctorID, recursos: t.Recursos}
+		if appt.Duracion != nil {
+			m.duracion = *appt.Duracion
+		}
+		if appt.DoctorID != nil {
+			m.doctorID = appt.DoctorID
+		}
+		if appt.TipoID != nil {
+			m.duracion, m.recursos = *typeDuracion, typeRecursos
+		}
+		moves = append(moves, m)
+	}
+
+	// Toda la serie se mueve en una transacción: si una escritura falla, ninguna cita cambia
+	var accepted []seriesMove
+	err = s.uow.Do(func(tx database.DBTX) error {
+		repo := s.repo.WithTx(tx)
+
+		// Los candados de los nuevos horarios, como en reserve, antes de verificarlos
+		slots := make([]models.Appointment, 0, len(moves))
+		for _, m := range moves {
+			slots = append(slots, models.Appointment{Fecha: m.fecha, DoctorID: m.doctorID, Recursos: m.recursos})
+		}
+		if err := lockSlots(repo, slots); err != nil {
+			return err
+		}
+
+		var conflicts []models.SeriesConflict
+		var err error
+		accepted, conflicts, err = s.planMoves(repo, moves)
+		if err != nil {
+			return err
+		}
+		result.Conflictos = append(result.Conflictos, conflicts...)
+
+		// Se escribe en la dirección del desplazamiento, para que cada cita llegue a un
+		// horario que la siguiente ya dejó libre y citas_sin_traslape no la rechace
+		ordered := append([]seriesMove(nil), accepted...)
+		sort.SliceStable(ordered, func(i, j int) bool {
+			if shift > 0 {
+				return ordered[i].cita.Fecha.After(ordered[j].cita.Fecha)
+			}
+			return ordered[i].cita.Fecha.Before(ordered[j].cita.Fecha)
+		})
+
+		for _, m := range ordered {
+			update := &models.AppointmentUpdateDTO{Duracion: appt.Duracion, DoctorID: appt.DoctorID}
+			if appt.TipoID != nil {
+				update.TipoID, update.Duracion, update.Recursos = appt.TipoID, typeDuracion, typeRecursos
+			}
+			if appt.Fecha != nil {
+				fecha := m.fecha
+				update.Fecha = &fecha
+			}
+			if err := repo.Update(m.cita.ID, update); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range accepted {
+		result.Citas = append(result.Citas, m.cita.ID)
+		if moved(&m.cita, m.fecha, m.doctorID) {
+			s.releaseSlot(&m.cita)
+		}
+	}
+	sortConflicts(result.Conflictos)
+	return result, nil
+}
+
+// seriesMove es el nuevo horario propuesto para una cita de la serie.
+type seriesMove struct {
+	cita     models.Appointment
+	fecha    time.Time
+	duracion int64
+	doctorID *int
+	recursos []int
+}
+
+func (m seriesMove) conflict(motivo string) models.SeriesConflict {
+	citaID := m.cita.ID
+	return models.SeriesConflict{Fecha: m.fecha, CitaID: &citaID, Motivo: motivo}
+}
+
+// planMoves decide qué citas de la serie se mueven. Una cita que no cabe en su nuevo
+// horario se queda donde está y ese horario sigue ocupado para las demás, así que la
+// verificación se repite hasta que ninguna otra cita queda fuera. Las citas que se
+// mueven se comparan también contra los nuevos horarios de sus hermanas.
+func (s *service) planMoves(repo Repository, moves []seriesMove) ([]seriesMove, []models.SeriesConflict, error) {
+	conflicts := []models.SeriesConflict{}
+	pending := make([]seriesMove, 0, len(moves))
+	for _, m := range moves {
+		motivo, err := s.hoursConflict(m.fecha, m.duracion, m.doctorID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if motivo != "" {
+			conflicts = append(conflicts, m.conflict(motivo))
+			continue
+		}
+		pending = append(pending, m)
+	}
+
+	days := &dayCache{repo: repo, days: map[int64][]models.Appointment{}}
+	for {
+		skip := make(map[int]bool, len(pending))
+		for _, m := range pending {
+			skip[m.cita.ID] = true
+		}
+
+		accepted := make([]seriesMove, 0, len(pending))
+		var placed []models.Appointment
+		for _, m := range pending {
+			existing, err := days.get(m.fecha)
+			if err != nil {
+				return nil, nil, err
+			}
+			end := m.fecha.Add(time.Duration(m.duracion) * time.Second)
+			motivo := slotConflict(m.fecha, end, m.doctorID, m.recursos, existing, s.bufferMinutes, skip)
+			if motivo == "" {
+				motivo = slotConflict(m.fecha, end, m.doctorID, m.recursos, placed, s.bufferMinutes, nil)
+			}
+			if motivo != "" {
+				conflicts = append(conflicts, m.conflict(motivo))
+				continue
+			}
+			accepted = append(accepted, m)
+			placed = append(placed, models.Appointment{
+				Fecha: m.fecha, Duracion: m.duracion, DoctorID: m.doctorID, Recursos: m.recursos, Estado: models.StatusScheduled,
+			})
+		}
+
+		if len(accepted) == len(pending) {
+			return accepted, conflicts, nil
+		}
+		pending = accepted
+	}
+}
+
+// dayCache lee las citas de cada día una sola vez por operación.
+type dayCache struct {
+	repo Repository
+	days map[int64][]models.Appointment
+}
+
+func (c *dayCache) get(fecha time.Time) ([]models.Appointment, error) {
+	dayStart := timeutil.StartOfClinicDay(fecha)
+	if appts, ok := c.days[dayStart.Unix()]; ok {
+		return appts, nil
+	}
+	appts, err := c.repo.GetBetween(dayStart, dayStart.Add(24*time.Hour), models.AppointmentFilter{})
+	if err != nil {
+		return nil, err
+	}
+	c.days[dayStart.Unix()] = appts
+	return appts, nil
+}
+
+// CancelSeries cancela la cita indicada, ésta y las siguientes, o toda la serie.
+// Las citas que ya no pueden cancelarse (p. ej. completadas) se reportan en Conflictos.
+func (s *service) CancelSeries(id int, scope string, userID int, motivo *string) (*models.SeriesResult, error) {
+	if id <= 0 {
+		return nil, appErr.Wrap("AppointmentService.CancelSeries(invalid id)", appErr.ErrInvalidInput, nil)
+	}
+
+	anchor, targets, err := s.seriesTargets(id, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SeriesResult{Citas: []int{}, Conflictos: []models.SeriesConflict{}}
+	if anchor.SerieID != nil {
+		result.SerieID = *anchor.SerieID
+	}
+
+	var changes []models.StatusChange
+	var released []models.Appointment
+	for _, t := range targets {
+		if t.Estado == models.StatusCancelled {
+			continue
+		}
+		if !models.CanTransition(t.Estado, models.StatusCancelled) {
+			citaID := t.ID
+			result.Conflictos = append(result.Conflictos, models.SeriesConflict{
+				Fecha: t.Fecha, CitaID: &citaID, Motivo: "La cita ya no puede cancelarse en su estado actual",
+			})
+			continue
+		}
+		changes = append(changes, newStatusChange(&t, models.StatusCancelled, userID, motivo))
+		result.Citas = append(result.Citas, t.ID)
+		if models.BlocksSlot(t.Estado) {
+			released = append(released, t)
+		}
+	}
+
+	if scope == models.ScopeThis && len(changes) == 0 {
+		return nil, appErr.NewDomainError(appErr.ErrConflict, "La cita ya no puede cancelarse en su estado actual")
+	}
+	if err := s.repo.UpdateStatus(changes); err != nil {
+		return nil, err
+	}
+	for i := range released {
+		s.releaseSlot(&released[i])
+	}
+	return result, nil
+}
+
+// seriesTargets resuelve la cita indicada y las citas afectadas según el alcance.
+func (s *service) seriesTargets(id int, scope string) (*models.Appointment, []models.Appointment, error) {
+	switch scope {
+	case models.ScopeThis, models.ScopeFollowing, models.ScopeAll:
+	default:
+		return nil, nil, appErr.Wrap("AppointmentService.seriesTargets(invalid scope)", appErr.ErrInvalidInput, nil)
+	}
+
+	anchor, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if scope == models.ScopeThis {
+		return anchor, []models.Appointment{*anchor}, nil
+	}
+	if anchor.SerieID == nil {
+		return nil, nil, appErr.NewDomainError(appErr.ErrInvalidInput, "La cita no pertenece a una serie")
+	}
+
+	all, err := s.repo.GetBySeries(*anchor.SerieID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var targets []models.Appointment
+	for _, a := range all {
+		if scope == models.ScopeFollowing && a.Fecha.Before(anchor.Fecha) {
+			continue
+		}
+		targets = append(targets, a)
+	}
+	return anchor, targets, nil
+}
+
+// hoursConflict devuelve el motivo por el que el horario queda fuera del horario laboral,
+// o "" si está dentro.
+func (s *service) hoursConflict(fecha time.Time, duracion int64, doctorID *int) (string, error) {
+	endTime := fecha.Add(time.Duration(duracion) * time.Second)
+
+	withinHours, err := s.scheduleValidator.IsWithinBusinessHours(fecha, fecha, endTime, doctorID)
+	if err != nil {
+		var domainErr *appErr.DomainError
+		if errors.As(err, &domainErr) {
+			return domainErr.Message, nil
+		}
+		return "", err
+	}
+	if !withinHours {
+		return "El horario solicitado se encuentra fuera del horario laboral", nil
+	}
+	return "", nil
+}
+
+// slotConflict devuelve el motivo por el que [start, end) no está libre, o "" si lo está:
+// el doctor ya tiene una cita en ese horario, o alguno de los recursos está ocupado.
+func slotConflict(start, end time.Time, doctorID *int, recursos []int, existing []models.Appointment, gapMinutes int, skip map[int]bool) string {
+	if overlaps(start, end, doctorID, existing, gapMinutes, skip) {
+		return "El horario solicitado traslapa con otras citas"
+	}
+	if resourceBusy(start, end, recursos, existing, gapMinutes, skip) {
+		return "Un recurso requerido por el tipo de cita ya está ocupado en ese horario"
+	}
+	return ""
+}
+
+// overlaps indica si el rango [start, end) choca con alguna cita existente del mismo
+// doctor, ignorando las citas en skip. Citas contiguas (10:00-10:20 y 10:20-10:40) no chocan.
+func overlaps(start, end time.Time, doctorID *int, existing []models.Appointment, gapMinutes int, skip map[int]bool) bool {
+	gap := time.Duration(gapMinutes) * time.Minute
+	endWithGap := end.Add(gap)
+	for _, ex := range existing {
+		if skip[ex.ID] || !models.BlocksSlot(ex.Estado) || !sameDoctor(ex.DoctorID, doctorID) {
+			continue
+		}
+		exEnd := ex.Fecha.Add(time.Duration(ex.Duracion) * time.Second)
+		if start.Before(exEnd.Add(gap)) && endWithGap.After(ex.Fecha) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceBusy indica si alguno de los recursos está ocupado por otra cita en [start, end),
+// sin importar el doctor de esa cita.
+func resourceBusy(start, end time.Time, recursos []int, existing []models.Appointment, gapMinutes int, skip map[int]bool) bool {
+	if len(recursos) == 0 {
+		return false
+	}
+	gap := time.Duration(gapMinutes) * time.Minute
+	endWithGap := end.Add(gap)
+	for _, ex := range existing {
+		if skip[ex.ID] || !models.BlocksSlot(ex.Estado) || !sharesResource(ex.Recursos, recursos) {
+			continue
+		}
+		exEnd := ex.Fecha.Add(time.Duration(ex.Duracion) * time.Second)
+		if start.Before(exEnd.Add(gap)) && endWithGap.After(ex.Fecha) {
+			return true
+		}
+	}
+	return false
+}
+
+func sharesResource(a, b []int) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameDoctor compara asignaciones; las citas sin doctor comparten la agenda general.
+func sameDoctor(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// expandRecurrence genera las fechas de una serie a partir de la primera ocurrencia.
+// Las ocurrencias mensuales cuyo día no existe en el mes (p. ej. 31 de abril) se
+// devuelven como conflictos en lugar de desplazarse al mes siguiente.
+func expandRecurrence(first time.Time, rule models.RecurrenceRule) ([]time.Time, []models.SeriesConflict, error) {
+	if rule.Frecuencia != models.FrequencyWeekly && rule.Frecuencia != models.FrequencyMonthly {
+		return nil, nil, appErr.Wrap("expandRecurrence(invalid frecuencia)", appErr.ErrInvalidInput, nil)
+	}
+	if rule.Intervalo == 0 {
+		rule.Intervalo = 1
+	}
+	if rule.Intervalo < 0 {
+		return nil, nil, appErr.Wrap("expandRecurrence(intervalo must be > 0)", appErr.ErrInvalidInput, nil)
+	}
+	if rule.Hasta == nil && rule.Ocurrencias == nil {
+		return nil, nil, appErr.Wrap("expandRecurrence(must provide hasta or ocurrencias)", appErr.ErrInvalidInput, nil)
+	}
+
+	limit := maxSeriesOccurrences
+	if rule.Ocurrencias != nil {
+		if *rule.Ocurrencias <= 0 || *rule.Ocurrencias > maxSeriesOccurrences {
+			return nil, nil, appErr.Wrap("expandRecurrence(ocurrencias out of range)", appErr.ErrInvalidInput, nil)
+		}
+		limit = *rule.Ocurrencias
+	}
+
+	var until time.Time
+	if rule.Hasta != nil {
+		// "hasta" es inclusivo: cualquier ocurrencia dentro de ese día clínico cuenta
+		until = timeutil.StartOfClinicDay(*rule.Hasta).Add(24 * time.Hour)
+		if !first.Before(until) {
+			return nil, nil, appErr.Wrap("expandRecurrence(hasta before fecha)", appErr.ErrInvalidInput, nil)
+		}
+	}
+
+	var dates []time.Time
+	var skipped []models.SeriesConflict
+	for i := 0; ; i++ {
+		var next time.Time
+		if rule.Frecuencia == models.FrequencyWeekly {
+			next = first.AddDate(0, 0, 7*rule.Intervalo*i)
+		} else {
+			next = first.AddDate(0, rule.Intervalo*i, 0)
+		}
+		if rule.Hasta != nil && !next.Before(until) {
+			break
+		}
+		if len(dates)+len(skipped) >= limit {
+			if rule.Ocurrencias == nil {
+				// Solo se definió "hasta" y excede el máximo permitido
+				return nil, nil, appErr.Wrap("expandRecurrence(too many occurrences)", appErr.ErrInvalidInput, nil)
+			}
+			break
+		}
+
+		if rule.Frecuencia == models.FrequencyMonthly && next.Day() != first.Day() {
+			skipped = append(skipped, models.SeriesConflict{
+				Fecha:  next,
+				Motivo: "El día de la cita no existe en ese mes",
+			})
+			continue
+		}
+		dates = append(dates, next)
+	}
+	return dates, skipped, nil
+}
+
+// sortConflicts ordena los conflictos cronológicamente.
+func sortConflicts(conflicts []models.SeriesConflict) {
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Fecha.Before(conflicts[j].Fecha)
+	})
+}
+
+// ============================================================================
+// CALENDARIO ICALENDAR
+// ============================================================================
+
+// CreateCalendarToken genera un token nuevo para el feed del usuario. Solo se guarda
+// su hash, por lo que el token se devuelve una única vez; el anterior queda revocado.
+func (s *service) CreateCalendarToken(userID int) (string, error) {
+	if userID <= 0 {
+		return "", appErr.Wrap("AppointmentService.CreateCalendarToken", appErr.ErrInvalidInput, nil)
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", appErr.Wrap("AppointmentService.CreateCalendarToken(rand)", appErr.ErrInternal, err)
+	}
+	token := hex.EncodeToString(raw)
+	if err := s.repo.SaveCalendarToken(userID, hashCalendarToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *service) RevokeCalendarToken(userID int) error {
+	if userID <= 0 {
+		return appErr.Wrap("AppointmentService.RevokeCalendarToken", appErr.ErrInvalidInput, nil)
+	}
+	return s.repo.DeleteCalendarToken(userID)
+}
+
+// ResolveCalendarToken devuelve el usuario dueño del token; un token vacío, revocado
+// o desconocido es ErrUnauthorized.
+func (s *service) ResolveCalendarToken(token string) (int, error) {
+	if token == "" {
+		return 0, appErr.Wrap("AppointmentService.ResolveCalendarToken(empty)", appErr.ErrUnauthorized, nil)
+	}
+	userID, err := s.repo.GetCalendarTokenUser(hashCalendarToken(token))
+	if errors.Is(err, appErr.ErrNotFound) {
+		return 0, appErr.Wrap("AppointmentService.ResolveCalendarToken(unknown)", appErr.ErrUnauthorized, nil)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetCalendarFeed arma el calendario del usuario: los doctores ven sus propias citas,
+// el resto del personal la agenda completa. Las citas canceladas se publican con
+// STATUS:CANCELLED para que los clientes las retiren.
+func (s *service) GetCalendarFeed(userID int) (*ical.Calendar, error) {
+	var filter models.AppointmentFilter
+	isDoctor, err := s.doctorProvider.IsDoctor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if isDoctor {
+		filter.DoctorID = &userID
+	}
+
+	today := timeutil.StartOfClinicDay(time.Now())
+	appts, err := s.repo.GetBetween(today.AddDate(0, 0, -feedDaysBack), today.AddDate(0, 0, feedDaysAhead), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &ical.Calendar{Name: "Agenda de citas", Events: make([]ical.Event, 0, len(appts))}
+	for i := range appts {
+		cal.Events = append(cal.Events, calendarEvent(&appts[i]))
+	}
+	return cal, nil
+}
+
+// calendarEvent convierte una cita en VEVENT. El UID es estable por cita y SEQUENCE
+// aumenta con cada cambio, así los clientes actualizan el evento en lugar de duplicarlo.
+// No se publican datos de contacto del paciente.
+func calendarEvent(a *models.Appointment) ical.Event {
+	summary := "Cita"
+	switch {
+	case a.NombrePaciente != nil:
+		summary = *a.NombrePaciente
+	case a.Nombre != nil:
+		summary = *a.Nombre
+	}
+	if a.NombreTipo != nil {
+		summary += " - " + *a.NombreTipo
+	}
+
+	desc := []string{"Estado: " + a.Estado}
+	if a.NombreDoctor != nil {
+		desc = append(desc, "Doctor: "+*a.NombreDoctor)
+	}
+
+	ev := ical.Event{
+		UID:          fmt.Sprintf("cita-%d@healthcare-crm", a.ID),
+		Sequence:     a.Secuencia,
+		Stamp:        a.FechaModificacion,
+		LastModified: a.FechaModificacion,
+		Start:        a.Fecha,
+		End:          a.Fecha.Add(time.Duration(a.Duracion) * time.Second),
+		Summary:      summary,
+		Description:  strings.Join(desc, "\n"),
+		Status:       ical.StatusConfirmed,
+	}
+	switch a.Estado {
+	case models.StatusCancelled, models.StatusNoShow:
+		ev.Status = ical.StatusCancelled
+	case models.StatusScheduled:
+		ev.Status = ical.StatusTentative
+	}
+	if a.ColorTipo != nil {
+		ev.Color = *a.ColorTipo
+	}
+	return ev
+}
+
+// ImportCalendar agenda los eventos de un archivo .ics como citas sin paciente. Cada
+// evento pasa por la validación normal de horario y traslapes; los que no pueden
+// agendarse (o ya fueron importados) se reportan en Conflictos y el resto se crea.
+func (s *service) ImportCalendar(r io.Reader, opts models.ImportOptions) (*models.ImportResult, error) {
+	if err := s.validateDoctor(opts.DoctorID); err != nil {
+		return nil, err
+	}
+	cal, err := ical.Parse(r, timeutil.ClinicLocation())
+	if err != nil {
+		return nil, appErr.NewDomainError(appErr.ErrInvalidInput, "El archivo no es un calendario iCalendar válido")
+	}
+	if len(cal.Events) > maxImportEvents {
+		return nil, appErr.NewDomainError(appErr.ErrInvalidInput,
+			fmt.Sprintf("El archivo supera el máximo de %d eventos", maxImportEvents))
+	}
+
+	result := &models.ImportResult{Citas: []int{}, Conflictos: []models.ImportConflict{}}
+	for _, ev := range cal.Events {
+		conflict := models.ImportConflict{UID: ev.UID, Resumen: ev.Summary, Fecha: ev.Start}
+
+		if motivo := skipImport(ev); motivo != "" {
+			conflict.Motivo = motivo
+			result.Conflictos = append(result.Conflictos, conflict)
+			continue
+		}
+		if ev.UID != "" {
+			exists, err := s.repo.ExistsExternalUID(ev.UID)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				conflict.Motivo = "El evento ya fue importado"
+				result.Conflictos = append(result.Conflictos, conflict)
+				continue
+			}
+		}
+
+		id, err := s.Create(importedAppointment(ev, opts))
+		if err != nil {
+			motivo, ok := importConflictReason(err)
+			if !ok {
+				return nil, err
+			}
+			conflict.Motivo = motivo
+			result.Conflictos = append(result.Conflictos, conflict)
+			continue
+		}
+		result.Citas = append(result.Citas, id)
+	}
+	return result, nil
+}
+
+// skipImport devuelve el motivo por el que un evento no se importa, o "" si es importable.
+func skipImport(ev ical.Event) string {
+	switch {
+	case ev.Status == ical.StatusCancelled:
+		return "El evento está cancelado"
+	case ev.AllDay:
+		return "Los eventos de día completo no se importan"
+	case ev.Start.IsZero():
+		return "El evento no tiene fecha de inicio"
+	case ev.End.Before(ev.Start):
+		return "El evento termina antes de comenzar"
+	}
+	return ""
+}
+
+func importedAppointment(ev ical.Event, opts models.ImportOptions) *models.AppointmentCreateDTO {
+	nombre := strings.TrimSpace(ev.Summary)
+	if nombre == "" {
+		nombre = "Cita importada"
+	}
+	dto := &models.AppointmentCreateDTO{
+		Nombre:   &nombre,
+		Fecha:    ev.Start,
+		Duracion: int64(ev.End.Sub(ev.Start) / time.Second), // 0 usa la duración del tipo
+		DoctorID: opts.DoctorID,
+		TipoID:   opts.TipoID,
+	}
+	if ev.UID != "" {
+		uid := ev.UID
+		dto.UIDExterno = &uid
+	}
+	return dto
+}
+
+// importConflictReason traduce los errores de validación de una cita a un motivo para
+// el reporte. Los errores de infraestructura no son conflictos y abortan la importación.
+func importConflictReason(err error) (string, bool) {
+	var domainErr *appErr.DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Message, true
+	}
+	switch {
+	case errors.Is(err, appErr.ErrInvalidInput):
+		return "El evento está fuera del horario laboral o tiene datos inválidos", true
+	case errors.Is(err, appErr.ErrConflict):
+		return "El horario del evento no está disponible", true
 	}
-	return s.repo.Delete(id)
+	return "", false
 }
@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/ical"
+)
+
+// -----------------------------------------------------------------------------
+// Feed tokens
+// -----------------------------------------------------------------------------
+
+func TestService_CalendarToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("only the hash of the token is stored", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		var stored string
+		f.repo.EXPECT().SaveCalendarToken(4, gomock.Any()).DoAndReturn(func(_ int, hash string) error {
+			stored = hash
+			return nil
+		})
+
+		token, err := f.svc.CreateCalendarToken(4)
+		require.NoError(t, err)
+		require.Len(t, token, 64)
+		require.NotEqual(t, token, stored)
+
+		f.repo.EXPECT().GetCalendarTokenUser(stored).Return(4, nil)
+		userID, err := f.svc.ResolveCalendarToken(token)
+		require.NoError(t, err)
+		require.Equal(t, 4, userID)
+	})
+
+	t.Run("unknown or empty token is unauthorized", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		_, err := f.svc.ResolveCalendarToken("")
+		require.ErrorIs(t, err, appErr.ErrUnauthorized)
+
+		f.repo.EXPECT().GetCalendarTokenUser(gomock.Any()).Return(0, appErr.Wrap("test", appErr.ErrNotFound, nil))
+		_, err = f.svc.ResolveCalendarToken("revoked")
+		require.ErrorIs(t, err, appErr.ErrUnauthorized)
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Feed
+// -----------------------------------------------------------------------------
+
+func TestService_GetCalendarFeed(t *testing.T) {
+	t.Parallel()
+
+	f := setup(t)
+	defer f.ctrl.Finish()
+
+	start := clinicTime(2025, time.March, 3, 9, 0)
+	modified := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	f.doctors.EXPECT().IsDoctor(7).Return(true, nil)
+	f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), models.AppointmentFilter{DoctorID: intPtr(7)}).Return([]models.Appointment{
+		{ID: 1, Nombre: strPtr("Walk-in"), Fecha: start, Duracion: 1800, Estado: models.StatusScheduled, Secuencia: 0, FechaModificacion: modified},
+		{ID: 2, NombrePaciente: strPtr("Ana López"), TelefonoPaciente: strPtr("5555-1234"), NombreTipo: strPtr("Control"),
+			ColorTipo: strPtr("#FF0000"), Fecha: start.Add(time.Hour), Duracion: 900, Estado: models.StatusCancelled, Secuencia: 2, FechaModificacion: modified},
+		{ID: 3, Nombre: strPtr("Seguimiento"), Fecha: start.Add(2 * time.Hour), Duracion: 900, Estado: models.StatusConfirmed},
+	}, nil)
+
+	cal, err := f.svc.GetCalendarFeed(7)
+	require.NoError(t, err)
+	require.Len(t, cal.Events, 3)
+
+	require.Equal(t, "cita-1@healthcare-crm", cal.Events[0].UID)
+	require.Equal(t, ical.StatusTentative, cal.Events[0].Status)
+	require.True(t, cal.Events[0].End.Equal(start.Add(30*time.Minute)))
+
+	cancelled := cal.Events[1]
+	require.Equal(t, ical.StatusCancelled, cancelled.Status)
+	require.Equal(t, 2, cancelled.Sequence)
+	require.Equal(t, "Ana López - Control", cancelled.Summary)
+	require.Equal(t, "#FF0000", cancelled.Color)
+	require.NotContains(t, cancelled.Description, "5555-1234")
+
+	require.Equal(t, ical.StatusConfirmed, cal.Events[2].Status)
+}
+
+// -----------------------------------------------------------------------------
+// Import
+// -----------------------------------------------------------------------------
+
+func icsEvents(events ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(events, "") + "END:VCALENDAR\r\n"
+}
+
+func icsEvent(uid, start, end, summary string, extra ...string) string {
+	lines := []string{"BEGIN:VEVENT", "UID:" + uid, "DTSTART:" + start, "DTEND:" + end, "SUMMARY:" + summary}
+	lines = append(lines, extra...)
+	lines = append(lines, "END:VEVENT")
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func TestService_ImportCalendar(t *testing.T) {
+	t.Parallel()
+
+	f := setup(t)
+	defer f.ctrl.Finish()
+
+	input := icsEvents(
+		icsEvent("a@ext", "20250303T090000", "20250303T093000", "Control"),
+		icsEvent("b@ext", "20250303T091500", "20250303T094500", "Traslapa"),
+		icsEvent("c@ext", "20250303T100000", "20250303T103000", "Repetido"),
+		icsEvent("d@ext", "20250303T110000", "20250303T113000", "Cancelado", "STATUS:CANCELLED"),
+	)
+
+	var booked []models.Appointment
+	f.repo.EXPECT().ExistsExternalUID("a@ext").Return(false, nil)
+	f.repo.EXPECT().ExistsExternalUID("b@ext").Return(false, nil)
+	f.repo.EXPECT().ExistsExternalUID("c@ext").Return(true, nil)
+	f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Nil()).Return(true, nil).Times(2)
+	f.repo.EXPECT().LockDay(gomock.Any(), gomock.Nil()).Return(nil).Times(2)
+	f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(time.Time, time.Time, models.AppointmentFilter) ([]models.Appointment, error) {
+			return booked, nil
+		}).Times(2)
+	f.repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(dto *models.AppointmentCreateDTO) (int, error) {
+		require.Equal(t, "a@ext", *dto.UIDExterno)
+		require.Equal(t, "Control", *dto.Nombre)
+		require.Equal(t, int64(1800), dto.Duracion)
+		booked = append(booked, models.Appointment{ID: 20, Fecha: dto.Fecha, Duracion: dto.Duracion, Estado: models.StatusScheduled})
+		return 20, nil
+	})
+
+	result, err := f.svc.ImportCalendar(strings.NewReader(input), models.ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []int{20}, result.Citas)
+	require.Len(t, result.Conflictos, 3)
+
+	reasons := map[string]string{}
+	for _, c := range result.Conflictos {
+		reasons[c.UID] = c.Motivo
+	}
+	require.Contains(t, reasons, "b@ext")
+	require.Equal(t, "El evento ya fue importado", reasons["c@ext"])
+	require.Equal(t, "El evento está cancelado", reasons["d@ext"])
+}
+
+func TestService_ImportCalendarRejectsInvalidFile(t *testing.T) {
+	t.Parallel()
+
+	f := setup(t)
+	defer f.ctrl.Finish()
+
+	_, err := f.svc.ImportCalendar(strings.NewReader("not a calendar"), models.ImportOptions{})
+	require.True(t, appErr.IsDomainError(err))
+}
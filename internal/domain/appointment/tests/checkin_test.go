@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// -----------------------------------------------------------------------------
+// CheckIn
+// -----------------------------------------------------------------------------
+
+func TestService_CheckIn(t *testing.T) {
+	t.Parallel()
+
+	t.Run("opens the consultation and moves the appointment to in-progress", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(1).Return(&models.Appointment{
+			ID: 1, PacienteID: intPtr(9), Estado: models.StatusConfirmed,
+			NombreTipo: strPtr("Control prenatal"), CuestionarioID: intPtr(4),
+		}, nil)
+		f.consults.EXPECT().StartConsultation(models.ConsultationStart{
+			CitaID: 1, PacienteID: 9, CuestionarioID: 4, Motivo: "Control prenatal",
+		}).Return(30, nil)
+		f.repo.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(changes []models.StatusChange) error {
+			require.Len(t, changes, 2)
+			require.Equal(t, models.StatusConfirmed, changes[0].EstadoAnterior)
+			require.Equal(t, models.StatusCheckedIn, changes[0].EstadoNuevo)
+			require.Equal(t, models.StatusCheckedIn, changes[1].EstadoAnterior)
+			require.Equal(t, models.StatusInProgress, changes[1].EstadoNuevo)
+			return nil
+		})
+
+		result, err := f.svc.CheckIn(1, 3, nil)
+		require.NoError(t, err)
+		require.Equal(t, 30, result.ConsultaID)
+		require.True(t, f.uow.committed)
+	})
+
+	t.Run("a failed status change rolls back the consultation", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(1).Return(&models.Appointment{ID: 1, PacienteID: intPtr(9), Estado: models.StatusCheckedIn}, nil)
+		f.consults.EXPECT().StartConsultation(gomock.Any()).Return(30, nil)
+		f.repo.EXPECT().UpdateStatus(gomock.Any()).Return(appErr.NewDomainError(appErr.ErrConflict, "modificada"))
+
+		_, err := f.svc.CheckIn(1, 3, &models.CheckInDTO{CuestionarioID: intPtr(2)})
+		require.True(t, appErr.IsDomainError(err))
+		require.True(t, f.uow.rolledBack)
+	})
+
+	t.Run("appointment without patient", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(1).Return(&models.Appointment{ID: 1, Nombre: strPtr("Walk-in"), Estado: models.StatusScheduled}, nil)
+
+		_, err := f.svc.CheckIn(1, 3, nil)
+		require.True(t, appErr.IsDomainError(err))
+	})
+
+	t.Run("type without questionnaire needs one in the request", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(1).Return(&models.Appointment{ID: 1, PacienteID: intPtr(9), Estado: models.StatusScheduled}, nil)
+
+		_, err := f.svc.CheckIn(1, 3, &models.CheckInDTO{})
+		require.True(t, appErr.IsDomainError(err))
+	})
+
+	t.Run("cancelled appointment cannot be checked in", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(1).Return(&models.Appointment{ID: 1, PacienteID: intPtr(9), Estado: models.StatusCancelled}, nil)
+
+		_, err := f.svc.CheckIn(1, 3, nil)
+		require.True(t, appErr.IsDomainError(err))
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Complete
+// -----------------------------------------------------------------------------
+
+func TestService_Complete(t *testing.T) {
+	t.Parallel()
+
+	t.Run("in-progress appointment is completed", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(1).Return(&models.Appointment{ID: 1, Estado: models.StatusInProgress}, nil)
+		f.repo.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(changes []models.StatusChange) error {
+			require.Len(t, changes, 1)
+			require.Equal(t, models.StatusCompleted, changes[0].EstadoNuevo)
+			require.Nil(t, changes[0].UsuarioID)
+			return nil
+		})
+
+		require.NoError(t, f.svc.Complete(1, 0))
+	})
+
+	t.Run("already completed is a no-op", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(1).Return(&models.Appointment{ID: 1, Estado: models.StatusCompleted}, nil)
+
+		require.NoError(t, f.svc.Complete(1, 0))
+	})
+}
@@ -0,0 +1,243 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment"
+	apptMocks "github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/mocks"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// -----------------------------------------------------------------------------
+// In-memory store with advisory-lock semantics
+// -----------------------------------------------------------------------------
+
+// memoryStore keeps appointments in memory. dayLock stands in for every
+// pg_advisory_xact_lock: taken by the first LockDay or LockResources of a unit of
+// work, released when it ends.
+type memoryStore struct {
+	mu      sync.Mutex
+	appts   []models.Appointment
+	dayLock sync.Mutex
+}
+
+// memoryTx is the transaction handle the unit of work passes to repositories.
+type memoryTx struct {
+	database.DBTX
+	locked bool
+}
+
+type memoryRepo struct {
+	appointment.Repository // methods the test does not need stay unimplemented
+	store                  *memoryStore
+	tx                     *memoryTx
+}
+
+func (r *memoryRepo) WithTx(tx database.DBTX) appointment.Repository {
+	return &memoryRepo{store: r.store, tx: tx.(*memoryTx)}
+}
+
+func (r *memoryRepo) LockDay(time.Time, *int) error {
+	if !r.tx.locked {
+		r.store.dayLock.Lock()
+		r.tx.locked = true
+	}
+	return nil
+}
+
+func (r *memoryRepo) LockResources(day time.Time, _ []int) error {
+	return r.LockDay(day, nil)
+}
+
+func (r *memoryRepo) GetBetween(start, end time.Time, _ models.AppointmentFilter) ([]models.Appointment, error) {
+	r.store.mu.Lock()
+	out := append([]models.Appointment(nil), r.store.appts...)
+	r.store.mu.Unlock()
+
+	// Widen the window between check and insert so an unserialized booking would race
+	time.Sleep(2 * time.Millisecond)
+	return out, nil
+}
+
+func (r *memoryRepo) Create(dto *models.AppointmentCreateDTO) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.insert(dto), nil
+}
+
+func (r *memoryRepo) CreateSeries(_ *models.Series, occurrences []models.AppointmentCreateDTO) (int, []int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	ids := make([]int, 0, len(occurrences))
+	for i := range occurrences {
+		ids = append(ids, r.insert(&occurrences[i]))
+	}
+	return 1, ids, nil
+}
+
+// insert is called with store.mu held
+func (r *memoryRepo) insert(dto *models.AppointmentCreateDTO) int {
+	id := len(r.store.appts) + 1
+	r.store.appts = append(r.store.appts, models.Appointment{
+		ID: id, Fecha: dto.Fecha, Duracion: dto.Duracion, DoctorID: dto.DoctorID, Recursos: dto.Recursos, Estado: models.StatusScheduled,
+	})
+	return id
+}
+
+type memoryUnitOfWork struct {
+	store *memoryStore
+}
+
+func (u memoryUnitOfWork) Do(fn func(tx database.DBTX) error) error {
+	tx := &memoryTx{}
+	err := fn(tx)
+	if tx.locked {
+		u.store.dayLock.Unlock()
+	}
+	return err
+}
+
+// -----------------------------------------------------------------------------
+// Concurrent booking
+// -----------------------------------------------------------------------------
+
+func TestService_ConcurrentBooking(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schedule := apptMocks.NewMockScheduleValidator(ctrl)
+	schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+
+	store := &memoryStore{}
+	svc := appointment.NewService(
+		&memoryRepo{store: store},
+		apptMocks.NewMockPatientProvider(ctrl),
+		schedule,
+		apptMocks.NewMockDoctorProvider(ctrl),
+		apptMocks.NewMockTypeProvider(ctrl),
+		apptMocks.NewMockConsultationStarter(ctrl),
+		nil,
+		memoryUnitOfWork{store: store},
+		appointment.Config{},
+	)
+
+	const bookings = 10
+	start := clinicTime(2025, time.March, 3, 9, 0)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, bookings)
+	for i := 0; i < bookings; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.Create(&models.AppointmentCreateDTO{Nombre: strPtr("Walk-in"), Fecha: start, Duracion: 1800})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	wins := 0
+	for err := range errs {
+		if err == nil {
+			wins++
+			continue
+		}
+		require.True(t, appErr.IsDomainError(err))
+	}
+	require.Equal(t, 1, wins)
+	require.Len(t, store.appts, 1)
+}
+
+func TestService_ConcurrentSeries(t *testing.T) {
+	t.Parallel()
+
+	newService := func(t *testing.T) (appointment.Service, *memoryStore) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		schedule := apptMocks.NewMockScheduleValidator(ctrl)
+		schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+		doctors := apptMocks.NewMockDoctorProvider(ctrl)
+		doctors.EXPECT().IsDoctor(gomock.Any()).Return(true, nil).AnyTimes()
+		types := apptMocks.NewMockTypeProvider(ctrl)
+		types.EXPECT().GetType(3).Return(&models.AppointmentType{
+			ID: 3, Duracion: 1800, Activo: true, Recursos: []models.Resource{{ID: 5, Nombre: "Ultrasonido", Activo: true}},
+		}, nil).AnyTimes()
+
+		store := &memoryStore{}
+		svc := appointment.NewService(
+			&memoryRepo{store: store},
+			apptMocks.NewMockPatientProvider(ctrl),
+			schedule,
+			doctors,
+			types,
+			apptMocks.NewMockConsultationStarter(ctrl),
+			nil,
+			memoryUnitOfWork{store: store},
+			appointment.Config{},
+		)
+		return svc, store
+	}
+
+	// Runs n series at once; series i uses the DTO returned by dto(i). Lost races must
+	// come back as conflicts, not as failed inserts.
+	run := func(t *testing.T, svc appointment.Service, n int, dto func(i int) *models.SeriesCreateDTO) int {
+		var wg sync.WaitGroup
+		errs := make(chan error, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := svc.CreateSeries(dto(i))
+				errs <- err
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+
+		wins := 0
+		for err := range errs {
+			if err == nil {
+				wins++
+				continue
+			}
+			require.True(t, appErr.IsDomainError(err), "unexpected error: %v", err)
+		}
+		return wins
+	}
+
+	start := clinicTime(2025, time.March, 3, 9, 0)
+	rule := models.RecurrenceRule{Frecuencia: models.FrequencyWeekly, Ocurrencias: intPtr(3)}
+
+	t.Run("same doctor", func(t *testing.T) {
+		svc, store := newService(t)
+
+		wins := run(t, svc, 10, func(int) *models.SeriesCreateDTO {
+			return &models.SeriesCreateDTO{Nombre: strPtr("Control"), Fecha: start, Duracion: 1800, DoctorID: intPtr(7), Regla: rule}
+		})
+		require.Equal(t, 1, wins)
+		require.Len(t, store.appts, 3)
+	})
+
+	t.Run("different doctors sharing a resource", func(t *testing.T) {
+		svc, store := newService(t)
+
+		wins := run(t, svc, 10, func(i int) *models.SeriesCreateDTO {
+			return &models.SeriesCreateDTO{Nombre: strPtr("Control"), Fecha: start, DoctorID: intPtr(10 + i), TipoID: intPtr(3), Regla: rule}
+		})
+		require.Equal(t, 1, wins)
+		require.Len(t, store.appts, 3)
+	})
+}
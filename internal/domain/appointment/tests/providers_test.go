@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// -----------------------------------------------------------------------------
+// Per-provider booking
+// -----------------------------------------------------------------------------
+
+func TestService_CreateWithDoctor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("assignee must be a doctor", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		f.doctors.EXPECT().IsDoctor(7).Return(false, nil)
+
+		_, err := f.svc.Create(&models.AppointmentCreateDTO{
+			Nombre:   strPtr("Walk-in"),
+			Fecha:    clinicTime(2025, time.March, 3, 9, 0),
+			Duracion: 1800,
+			DoctorID: intPtr(7),
+		})
+		require.True(t, appErr.IsDomainError(err))
+	})
+
+	t.Run("other doctor's appointment does not block", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		start := clinicTime(2025, time.March, 3, 9, 0)
+		f.doctors.EXPECT().IsDoctor(7).Return(true, nil)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), intPtr(7)).Return(true, nil)
+		f.repo.EXPECT().LockDay(clinicTime(2025, time.March, 3, 0, 0), intPtr(7)).Return(nil)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Appointment{
+			{ID: 1, Fecha: start, Duracion: 1800, Estado: models.StatusScheduled, DoctorID: intPtr(8)},
+		}, nil)
+		f.repo.EXPECT().Create(gomock.Any()).Return(2, nil)
+
+		id, err := f.svc.Create(&models.AppointmentCreateDTO{
+			Nombre:   strPtr("Walk-in"),
+			Fecha:    start,
+			Duracion: 1800,
+			DoctorID: intPtr(7),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, id)
+	})
+
+	t.Run("same doctor's appointment blocks", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		start := clinicTime(2025, time.March, 3, 9, 0)
+		f.doctors.EXPECT().IsDoctor(7).Return(true, nil)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), intPtr(7)).Return(true, nil)
+		f.repo.EXPECT().LockDay(clinicTime(2025, time.March, 3, 0, 0), intPtr(7)).Return(nil)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Appointment{
+			{ID: 1, Fecha: start, Duracion: 1800, Estado: models.StatusScheduled, DoctorID: intPtr(7)},
+		}, nil)
+
+		_, err := f.svc.Create(&models.AppointmentCreateDTO{
+			Nombre:   strPtr("Walk-in"),
+			Fecha:    start,
+			Duracion: 1800,
+			DoctorID: intPtr(7),
+		})
+		require.True(t, appErr.IsDomainError(err))
+	})
+}
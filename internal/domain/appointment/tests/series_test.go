@@ -0,0 +1,332 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment"
+	apptMocks "github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/mocks"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/timeutil"
+)
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+type fixture struct {
+	repo     *apptMocks.MockRepository
+	patients *apptMocks.MockPatientProvider
+	schedule *apptMocks.MockScheduleValidator
+	doctors  *apptMocks.MockDoctorProvider
+	types    *apptMocks.MockTypeProvider
+	consults *apptMocks.MockConsultationStarter
+	slots    *apptMocks.MockSlotListener
+	uow      *fakeUnitOfWork
+	svc      appointment.Service
+	ctrl     *gomock.Controller
+}
+
+func setup(t *testing.T) fixture {
+	ctrl := gomock.NewController(t)
+	f := fixture{
+		repo:     apptMocks.NewMockRepository(ctrl),
+		patients: apptMocks.NewMockPatientProvider(ctrl),
+		schedule: apptMocks.NewMockScheduleValidator(ctrl),
+		doctors:  apptMocks.NewMockDoctorProvider(ctrl),
+		types:    apptMocks.NewMockTypeProvider(ctrl),
+		consults: apptMocks.NewMockConsultationStarter(ctrl),
+		slots:    apptMocks.NewMockSlotListener(ctrl),
+		uow:      &fakeUnitOfWork{},
+		ctrl:     ctrl,
+	}
+	f.svc = appointment.NewService(f.repo, f.patients, f.schedule, f.doctors, f.types, f.consults, f.slots, f.uow, appointment.Config{})
+	// The fake unit of work hands out a nil tx; repository calls keep going to the same mock
+	f.repo.EXPECT().WithTx(gomock.Any()).Return(f.repo).AnyTimes()
+	f.consults.EXPECT().WithTx(gomock.Any()).Return(f.consults).AnyTimes()
+	return f
+}
+
+// fakeUnitOfWork runs fn without a database and records how it ended.
+type fakeUnitOfWork struct {
+	committed  bool
+	rolledBack bool
+}
+
+func (u *fakeUnitOfWork) Do(fn func(tx database.DBTX) error) error {
+	if err := fn(nil); err != nil {
+		u.rolledBack = true
+		return err
+	}
+	u.committed = true
+	return nil
+}
+
+func clinicTime(y int, m time.Month, d, h, min int) time.Time {
+	return time.Date(y, m, d, h, min, 0, 0, timeutil.ClinicLocation())
+}
+
+func intPtr(v int) *int { return &v }
+
+func strPtr(v string) *string { return &v }
+
+// -----------------------------------------------------------------------------
+// CreateSeries
+// -----------------------------------------------------------------------------
+
+func TestService_CreateSeries(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid rule", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		start := clinicTime(2025, time.March, 3, 9, 0)
+
+		_, err := f.svc.CreateSeries(&models.SeriesCreateDTO{
+			Nombre: strPtr("Walk-in"), Fecha: start, Duracion: 1800,
+			Regla: models.RecurrenceRule{Frecuencia: models.FrequencyWeekly},
+		})
+		require.ErrorIs(t, err, appErr.ErrInvalidInput)
+
+		_, err = f.svc.CreateSeries(&models.SeriesCreateDTO{
+			Nombre: strPtr("Walk-in"), Fecha: start, Duracion: 1800,
+			Regla: models.RecurrenceRule{Frecuencia: "daily", Ocurrencias: intPtr(3)},
+		})
+		require.ErrorIs(t, err, appErr.ErrInvalidInput)
+
+		_, err = f.svc.CreateSeries(&models.SeriesCreateDTO{
+			Fecha: start, Duracion: 1800,
+			Regla: models.RecurrenceRule{Frecuencia: models.FrequencyWeekly, Ocurrencias: intPtr(3)},
+		})
+		require.ErrorIs(t, err, appErr.ErrInvalidInput)
+	})
+
+	t.Run("every two weeks reports conflicts", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		start := clinicTime(2025, time.March, 3, 9, 0)
+		second := start.AddDate(0, 0, 14)
+		third := start.AddDate(0, 0, 28)
+
+		f.patients.EXPECT().Exists(7).Return(true, nil)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Nil()).Return(true, nil).Times(2)
+		f.schedule.EXPECT().IsWithinBusinessHours(third, third, gomock.Any(), gomock.Nil()).
+			Return(false, appErr.NewDomainError(appErr.ErrConflict, "El día está cerrado."))
+
+		// Only the days left after the business hours check are locked, in order
+		gomock.InOrder(
+			f.repo.EXPECT().LockDay(timeutil.StartOfClinicDay(start), gomock.Nil()).Return(nil),
+			f.repo.EXPECT().LockDay(timeutil.StartOfClinicDay(second), gomock.Nil()).Return(nil),
+		)
+		f.repo.EXPECT().GetBetween(timeutil.StartOfClinicDay(start), gomock.Any(), gomock.Any()).Return(nil, nil)
+		f.repo.EXPECT().GetBetween(timeutil.StartOfClinicDay(second), gomock.Any(), gomock.Any()).Return([]models.Appointment{
+			{ID: 99, Fecha: second.Add(15 * time.Minute), Duracion: 900},
+		}, nil)
+
+		f.repo.EXPECT().CreateSeries(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(s *models.Series, occ []models.AppointmentCreateDTO) (int, []int, error) {
+				require.Equal(t, 2, s.Intervalo)
+				require.Len(t, occ, 1)
+				require.True(t, occ[0].Fecha.Equal(start))
+				return 5, []int{11}, nil
+			})
+
+		res, err := f.svc.CreateSeries(&models.SeriesCreateDTO{
+			PacienteID: intPtr(7), Fecha: start, Duracion: 1800,
+			Regla: models.RecurrenceRule{Frecuencia: models.FrequencyWeekly, Intervalo: 2, Ocurrencias: intPtr(3)},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 5, res.SerieID)
+		require.Equal(t, []int{11}, res.Citas)
+		require.Len(t, res.Conflictos, 2)
+		require.True(t, res.Conflictos[0].Fecha.Equal(second))
+		require.Equal(t, "El día está cerrado.", res.Conflictos[1].Motivo)
+	})
+
+	t.Run("monthly until date skips missing days", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		start := clinicTime(2025, time.January, 31, 10, 0)
+		hasta := clinicTime(2025, time.March, 31, 0, 0)
+
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Nil()).Return(true, nil).Times(2)
+		f.repo.EXPECT().LockDay(gomock.Any(), gomock.Nil()).Return(nil).Times(2)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+		f.repo.EXPECT().CreateSeries(gomock.Any(), gomock.Len(2)).Return(8, []int{1, 2}, nil)
+
+		res, err := f.svc.CreateSeries(&models.SeriesCreateDTO{
+			Nombre: strPtr("Control"), Fecha: start, Duracion: 900,
+			Regla: models.RecurrenceRule{Frecuencia: models.FrequencyMonthly, Hasta: &hasta},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2}, res.Citas)
+		require.Len(t, res.Conflictos, 1)
+		require.Equal(t, time.March, res.Conflictos[0].Fecha.Month())
+	})
+
+	t.Run("nothing available", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		start := clinicTime(2025, time.March, 3, 9, 0)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Nil()).Return(false, nil).Times(2)
+
+		_, err := f.svc.CreateSeries(&models.SeriesCreateDTO{
+			Nombre: strPtr("Control"), Fecha: start, Duracion: 900,
+			Regla: models.RecurrenceRule{Frecuencia: models.FrequencyWeekly, Ocurrencias: intPtr(2)},
+		})
+		require.True(t, appErr.IsDomainError(err))
+	})
+}
+
+// -----------------------------------------------------------------------------
+// UpdateSeries / DeleteSeries
+// -----------------------------------------------------------------------------
+
+func TestService_UpdateSeries(t *testing.T) {
+	t.Parallel()
+
+	series := func() []models.Appointment {
+		return []models.Appointment{
+			{ID: 1, SerieID: intPtr(5), Fecha: clinicTime(2025, time.March, 3, 9, 0), Duracion: 1800, Estado: models.StatusScheduled},
+			{ID: 2, SerieID: intPtr(5), Fecha: clinicTime(2025, time.March, 10, 9, 0), Duracion: 1800, Estado: models.StatusScheduled},
+			{ID: 3, SerieID: intPtr(5), Fecha: clinicTime(2025, time.March, 17, 9, 0), Duracion: 1800, Estado: models.StatusScheduled},
+		}
+	}
+
+	t.Run("invalid scope", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		_, err := f.svc.UpdateSeries(2, "everything", &models.AppointmentUpdateDTO{})
+		require.ErrorIs(t, err, appErr.ErrInvalidInput)
+	})
+
+	t.Run("not part of a series", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(4).Return(&models.Appointment{ID: 4, Fecha: time.Now()}, nil)
+
+		_, err := f.svc.UpdateSeries(4, models.ScopeAll, &models.AppointmentUpdateDTO{})
+		require.True(t, appErr.IsDomainError(err))
+	})
+
+	t.Run("this and following shifts by the same offset", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		appts := series()
+		f.repo.EXPECT().GetByID(2).Return(&appts[1], nil)
+		f.repo.EXPECT().GetBySeries(5).Return(appts, nil)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Nil()).Return(true, nil).Times(2)
+		f.repo.EXPECT().LockDay(gomock.Any(), gomock.Nil()).Return(nil).Times(2)
+		// Las citas propias de la serie no cuentan como traslape
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return(appts, nil).Times(2)
+
+		f.repo.EXPECT().Update(2, gomock.Any()).DoAndReturn(func(_ int, dto *models.AppointmentUpdateDTO) error {
+			require.True(t, dto.Fecha.Equal(clinicTime(2025, time.March, 10, 10, 0)))
+			return nil
+		})
+		f.repo.EXPECT().Update(3, gomock.Any()).DoAndReturn(func(_ int, dto *models.AppointmentUpdateDTO) error {
+			require.True(t, dto.Fecha.Equal(clinicTime(2025, time.March, 17, 10, 0)))
+			return nil
+		})
+
+		newFecha := clinicTime(2025, time.March, 10, 10, 0)
+		res, err := f.svc.UpdateSeries(2, models.ScopeFollowing, &models.AppointmentUpdateDTO{Fecha: &newFecha})
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 3}, res.Citas)
+		require.Empty(t, res.Conflictos)
+	})
+
+	t.Run("an occurrence that stays blocks the sibling moving onto it", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		appts := series()
+		other := models.Appointment{ID: 99, Fecha: clinicTime(2025, time.March, 24, 9, 0), Duracion: 1800, Estado: models.StatusScheduled}
+		f.repo.EXPECT().GetByID(1).Return(&appts[0], nil)
+		f.repo.EXPECT().GetBySeries(5).Return(appts, nil)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Nil()).Return(true, nil).Times(3)
+		f.repo.EXPECT().LockDay(gomock.Any(), gomock.Nil()).Return(nil).Times(3)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return(append(series(), other), nil).AnyTimes()
+
+		// Shifting a week: 3 collides with another visit and stays, so 2 cannot take
+		// its slot, and then 1 cannot take 2's
+		newFecha := clinicTime(2025, time.March, 10, 9, 0)
+		res, err := f.svc.UpdateSeries(1, models.ScopeAll, &models.AppointmentUpdateDTO{Fecha: &newFecha})
+		require.NoError(t, err)
+		require.Empty(t, res.Citas)
+		require.Len(t, res.Conflictos, 3)
+	})
+
+	t.Run("writes in the direction of the shift", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		appts := series()
+		f.repo.EXPECT().GetByID(1).Return(&appts[0], nil)
+		f.repo.EXPECT().GetBySeries(5).Return(appts, nil)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Nil()).Return(true, nil).Times(3)
+		f.repo.EXPECT().LockDay(gomock.Any(), gomock.Nil()).Return(nil).Times(3)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return(series(), nil).AnyTimes()
+		// Each occurrence moves into the slot of the next one, which must be free by then
+		gomock.InOrder(
+			f.repo.EXPECT().Update(3, gomock.Any()).Return(nil),
+			f.repo.EXPECT().Update(2, gomock.Any()).Return(nil),
+			f.repo.EXPECT().Update(1, gomock.Any()).Return(nil),
+		)
+
+		newFecha := clinicTime(2025, time.March, 10, 9, 0)
+		res, err := f.svc.UpdateSeries(1, models.ScopeAll, &models.AppointmentUpdateDTO{Fecha: &newFecha})
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2, 3}, res.Citas)
+		require.True(t, f.uow.committed)
+	})
+
+	t.Run("a failed write rolls back the whole series", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		appts := series()
+		f.repo.EXPECT().GetByID(1).Return(&appts[0], nil)
+		f.repo.EXPECT().GetBySeries(5).Return(appts, nil)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Nil()).Return(true, nil).Times(3)
+		f.repo.EXPECT().LockDay(gomock.Any(), gomock.Nil()).Return(nil).Times(3)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return(series(), nil).AnyTimes()
+		f.repo.EXPECT().Update(1, gomock.Any()).Return(nil)
+		f.repo.EXPECT().Update(2, gomock.Any()).Return(appErr.Wrap("repo.Update", appErr.ErrInternal, nil))
+
+		newFecha := clinicTime(2025, time.March, 3, 8, 0)
+		_, err := f.svc.UpdateSeries(1, models.ScopeAll, &models.AppointmentUpdateDTO{Fecha: &newFecha})
+		require.ErrorIs(t, err, appErr.ErrInternal)
+		require.True(t, f.uow.rolledBack)
+	})
+
+	t.Run("cancel all keeps completed visits", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		appts := series()
+		appts[0].Estado = models.StatusCompleted
+		f.repo.EXPECT().GetByID(2).Return(&appts[1], nil)
+		f.repo.EXPECT().GetBySeries(5).Return(appts, nil)
+		f.repo.EXPECT().UpdateStatus(gomock.Len(2)).Return(nil)
+
+		res, err := f.svc.CancelSeries(2, models.ScopeAll, 1, nil)
+		require.NoError(t, err)
+		require.Equal(t, 5, res.SerieID)
+		require.Equal(t, []int{2, 3}, res.Citas)
+		require.Len(t, res.Conflictos, 1)
+		require.Equal(t, 1, *res.Conflictos[0].CitaID)
+	})
+}
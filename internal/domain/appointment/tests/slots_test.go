@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	scheduleModels "github.com/tonitomc/healthcare-crm-api/internal/domain/schedule/models"
+	"github.com/tonitomc/healthcare-crm-api/pkg/timeutil"
+)
+
+// openDay arma un EffectiveDay con franjas [h0,h1), [h2,h3)... ancladas como en schedule (2000-01-01)
+func openDay(day time.Time, hours ...int) *scheduleModels.EffectiveDay {
+	eff := &scheduleModels.EffectiveDay{Date: day, Active: len(hours) > 0}
+	for i := 0; i+1 < len(hours); i += 2 {
+		eff.Ranges = append(eff.Ranges, scheduleModels.TimeRange{
+			Start: time.Date(2000, 1, 1, hours[i], 0, 0, 0, timeutil.ClinicLocation()),
+			End:   time.Date(2000, 1, 1, hours[i+1], 0, 0, 0, timeutil.ClinicLocation()),
+		})
+	}
+	return eff
+}
+
+// -----------------------------------------------------------------------------
+// GetAvailableSlots
+// -----------------------------------------------------------------------------
+
+func TestService_GetAvailableSlots(t *testing.T) {
+	t.Parallel()
+
+	t.Run("closed day", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		day := clinicTime(2025, time.March, 2, 0, 0)
+		f.schedule.EXPECT().GetEffectiveDay(day, gomock.Nil()).Return(openDay(day), nil)
+
+		slots, err := f.svc.GetAvailableSlots(day, models.SlotOptions{})
+		require.NoError(t, err)
+		require.Empty(t, slots)
+	})
+
+	t.Run("split shift skips lunch break", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		day := clinicTime(2025, time.March, 3, 0, 0)
+		f.schedule.EXPECT().GetEffectiveDay(day, gomock.Nil()).Return(openDay(day, 9, 11, 14, 15), nil)
+		f.repo.EXPECT().GetBetween(day, day.Add(24*time.Hour), gomock.Any()).Return(nil, nil)
+
+		slots, err := f.svc.GetAvailableSlots(day, models.SlotOptions{Duracion: 3600})
+		require.NoError(t, err)
+		require.Len(t, slots, 3)
+		require.True(t, slots[0].Start.Equal(clinicTime(2025, time.March, 3, 9, 0)))
+		require.True(t, slots[1].Start.Equal(clinicTime(2025, time.March, 3, 10, 0)))
+		require.True(t, slots[2].Start.Equal(clinicTime(2025, time.March, 3, 14, 0)))
+	})
+
+	t.Run("buffer between appointments", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+		// El buffer por defecto del servicio se reemplaza por el de la solicitud
+		f.svc = appointment.NewService(f.repo, f.patients, f.schedule, f.doctors, f.types, f.consults, f.slots, f.uow, appointment.Config{BufferMinutes: 30})
+
+		day := clinicTime(2025, time.March, 3, 0, 0)
+		buffer := 10
+		f.schedule.EXPECT().GetEffectiveDay(day, gomock.Nil()).Return(openDay(day, 9, 11), nil)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Appointment{
+			{ID: 1, Fecha: clinicTime(2025, time.March, 3, 10, 50), Duracion: 600, Estado: models.StatusScheduled},
+		}, nil)
+
+		slots, err := f.svc.GetAvailableSlots(day, models.SlotOptions{Duracion: 1800, BufferMinutes: &buffer})
+		require.NoError(t, err)
+		// 09:00, 09:40, 10:20 (pasos de 30 min + 10 min de buffer)
+		require.Len(t, slots, 3)
+		require.True(t, slots[1].Start.Equal(clinicTime(2025, time.March, 3, 9, 40)))
+		require.True(t, slots[0].Available)
+		require.True(t, slots[1].Available)
+		// 10:20-10:50 no deja los 10 min de buffer antes de la cita de 10:50
+		require.False(t, slots[2].Available)
+	})
+}
+
+// -----------------------------------------------------------------------------
+// GetNextAvailableSlots
+// -----------------------------------------------------------------------------
+
+func TestService_GetNextAvailableSlots(t *testing.T) {
+	t.Parallel()
+
+	t.Run("skips closed and booked days", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		from := clinicTime(2025, time.March, 1, 0, 0) // sábado
+		sunday := from.AddDate(0, 0, 1)
+		monday := from.AddDate(0, 0, 2)
+
+		f.schedule.EXPECT().GetEffectiveDay(from, gomock.Nil()).Return(openDay(from, 9, 10), nil)
+		f.repo.EXPECT().GetBetween(from, gomock.Any(), gomock.Any()).Return([]models.Appointment{
+			{ID: 1, Fecha: clinicTime(2025, time.March, 1, 9, 0), Duracion: 3600, Estado: models.StatusConfirmed},
+		}, nil)
+		f.schedule.EXPECT().GetEffectiveDay(sunday, gomock.Nil()).Return(openDay(sunday), nil)
+		f.schedule.EXPECT().GetEffectiveDay(monday, gomock.Nil()).Return(openDay(monday, 8, 12), nil)
+		f.repo.EXPECT().GetBetween(monday, gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		slots, err := f.svc.GetNextAvailableSlots(from, 2, models.SlotOptions{Duracion: 3600})
+		require.NoError(t, err)
+		require.Len(t, slots, 2)
+		require.True(t, slots[0].Start.Equal(clinicTime(2025, time.March, 3, 8, 0)))
+		require.True(t, slots[1].Start.Equal(clinicTime(2025, time.March, 3, 9, 0)))
+	})
+}
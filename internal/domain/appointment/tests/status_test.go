@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// -----------------------------------------------------------------------------
+// ChangeStatus / Cancel
+// -----------------------------------------------------------------------------
+
+func TestService_ChangeStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown status", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		err := f.svc.ChangeStatus(1, 3, &models.StatusChangeDTO{Estado: "deleted"})
+		require.ErrorIs(t, err, appErr.ErrInvalidInput)
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(1).Return(&models.Appointment{ID: 1, Estado: models.StatusCompleted}, nil)
+
+		err := f.svc.ChangeStatus(1, 3, &models.StatusChangeDTO{Estado: models.StatusScheduled})
+		require.True(t, appErr.IsDomainError(err))
+	})
+
+	t.Run("cancel records actor and reason", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		motivo := "Paciente enfermo"
+		f.repo.EXPECT().GetByID(1).Return(&models.Appointment{ID: 1, Estado: models.StatusConfirmed}, nil)
+		f.repo.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(changes []models.StatusChange) error {
+			require.Len(t, changes, 1)
+			require.Equal(t, models.StatusConfirmed, changes[0].EstadoAnterior)
+			require.Equal(t, models.StatusCancelled, changes[0].EstadoNuevo)
+			require.Equal(t, 3, *changes[0].UsuarioID)
+			require.Equal(t, motivo, *changes[0].Motivo)
+			require.False(t, changes[0].Fecha.IsZero())
+			return nil
+		})
+
+		require.NoError(t, f.svc.Cancel(1, 3, &motivo))
+	})
+
+	t.Run("cancelling a future appointment releases its slot", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+		f.repo.EXPECT().GetByID(1).Return(&models.Appointment{
+			ID: 1, Fecha: start, Duracion: 1800, Estado: models.StatusScheduled, DoctorID: intPtr(7),
+		}, nil)
+		f.repo.EXPECT().UpdateStatus(gomock.Any()).Return(nil)
+		f.slots.EXPECT().SlotReleased(models.ReleasedSlot{Fecha: start, Duracion: 1800, DoctorID: intPtr(7)})
+
+		require.NoError(t, f.svc.Cancel(1, 3, nil))
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Freed slots
+// -----------------------------------------------------------------------------
+
+func TestService_CancelledFreesSlot(t *testing.T) {
+	t.Parallel()
+
+	t.Run("available slots ignore cancelled and no-show", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		day := clinicTime(2025, time.March, 3, 0, 0)
+		f.schedule.EXPECT().GetEffectiveDay(day, gomock.Nil()).Return(openDay(day, 8, 18), nil)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Appointment{
+			{ID: 1, Fecha: clinicTime(2025, time.March, 3, 8, 0), Duracion: 3600, Estado: models.StatusCancelled},
+			{ID: 2, Fecha: clinicTime(2025, time.March, 3, 9, 0), Duracion: 3600, Estado: models.StatusNoShow},
+			{ID: 3, Fecha: clinicTime(2025, time.March, 3, 10, 0), Duracion: 3600, Estado: models.StatusScheduled},
+		}, nil)
+
+		slots, err := f.svc.GetAvailableSlots(day, models.SlotOptions{Duracion: 3600})
+		require.NoError(t, err)
+		require.True(t, slots[0].Available)
+		require.True(t, slots[1].Available)
+		require.False(t, slots[2].Available)
+	})
+
+	t.Run("create over a cancelled appointment", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		start := clinicTime(2025, time.March, 3, 9, 0)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Nil()).Return(true, nil)
+		f.repo.EXPECT().LockDay(clinicTime(2025, time.March, 3, 0, 0), gomock.Nil()).Return(nil)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Appointment{
+			{ID: 1, Fecha: start, Duracion: 1800, Estado: models.StatusCancelled},
+		}, nil)
+		f.repo.EXPECT().Create(gomock.Any()).Return(2, nil)
+
+		id, err := f.svc.Create(&models.AppointmentCreateDTO{Nombre: strPtr("Walk-in"), Fecha: start, Duracion: 1800})
+		require.NoError(t, err)
+		require.Equal(t, 2, id)
+	})
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	patientModels "github.com/tonitomc/healthcare-crm-api/internal/domain/patient/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// -----------------------------------------------------------------------------
+// CreateWithNewPatient
+// -----------------------------------------------------------------------------
+
+func newPatientDTO(start time.Time) *models.AppointmentWithNewPatientDTO {
+	dto := &models.AppointmentWithNewPatientDTO{
+		PatientData: patientModels.PatientCreateDTO{Nombre: "Ana López", Sexo: "F", FechaNacimiento: "1990-05-01"},
+	}
+	dto.AppointmentData.Fecha = start
+	dto.AppointmentData.Duracion = 1800
+	return dto
+}
+
+func TestService_CreateWithNewPatient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("commits patient and appointment together", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		start := clinicTime(2025, time.March, 3, 9, 0)
+		f.patients.EXPECT().WithTx(gomock.Any()).Return(f.patients)
+		f.patients.EXPECT().Create(gomock.Any()).Return(10, nil)
+		f.patients.EXPECT().Exists(10).Return(true, nil)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Nil()).Return(true, nil)
+		f.repo.EXPECT().LockDay(clinicTime(2025, time.March, 3, 0, 0), gomock.Nil()).Return(nil)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+		f.repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(dto *models.AppointmentCreateDTO) (int, error) {
+			require.Equal(t, 10, *dto.PacienteID)
+			return 20, nil
+		})
+
+		id, err := f.svc.CreateWithNewPatient(newPatientDTO(start))
+		require.NoError(t, err)
+		require.Equal(t, 20, id)
+		require.True(t, f.uow.committed)
+	})
+
+	t.Run("overlap rolls back the new patient", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		start := clinicTime(2025, time.March, 3, 9, 0)
+		f.patients.EXPECT().WithTx(gomock.Any()).Return(f.patients)
+		f.patients.EXPECT().Create(gomock.Any()).Return(10, nil)
+		f.patients.EXPECT().Exists(10).Return(true, nil)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Nil()).Return(true, nil)
+		f.repo.EXPECT().LockDay(clinicTime(2025, time.March, 3, 0, 0), gomock.Nil()).Return(nil)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Appointment{
+			{ID: 1, Fecha: start, Duracion: 1800, Estado: models.StatusScheduled},
+		}, nil)
+
+		_, err := f.svc.CreateWithNewPatient(newPatientDTO(start))
+		require.True(t, appErr.IsDomainError(err))
+		require.True(t, f.uow.rolledBack)
+		require.False(t, f.uow.committed)
+	})
+}
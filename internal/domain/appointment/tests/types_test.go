@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointment/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// surgeryConsult is a 45-minute type that needs resource 5 (the procedure room).
+func surgeryConsult() *models.AppointmentType {
+	return &models.AppointmentType{
+		ID:       3,
+		Duracion: 2700,
+		Activo:   true,
+		Recursos: []models.Resource{{ID: 5, Nombre: "Sala de procedimientos", Activo: true}},
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Booking with an appointment type
+// -----------------------------------------------------------------------------
+
+func TestService_CreateWithType(t *testing.T) {
+	t.Parallel()
+
+	t.Run("uses the type's default duration and resources", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		start := clinicTime(2025, time.March, 3, 9, 0)
+		f.types.EXPECT().GetType(3).Return(surgeryConsult(), nil)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), start, start.Add(45*time.Minute), gomock.Nil()).Return(true, nil)
+		f.repo.EXPECT().LockDay(clinicTime(2025, time.March, 3, 0, 0), gomock.Nil()).Return(nil)
+		f.repo.EXPECT().LockResources(clinicTime(2025, time.March, 3, 0, 0), []int{5}).Return(nil)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+		f.repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(dto *models.AppointmentCreateDTO) (int, error) {
+			require.Equal(t, int64(2700), dto.Duracion)
+			require.Equal(t, []int{5}, dto.Recursos)
+			return 10, nil
+		})
+
+		id, err := f.svc.Create(&models.AppointmentCreateDTO{
+			Nombre: strPtr("Walk-in"),
+			Fecha:  start,
+			TipoID: intPtr(3),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 10, id)
+	})
+
+	t.Run("another doctor holding the room blocks the slot", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		start := clinicTime(2025, time.March, 3, 9, 0)
+		f.types.EXPECT().GetType(3).Return(surgeryConsult(), nil)
+		f.doctors.EXPECT().IsDoctor(7).Return(true, nil)
+		f.schedule.EXPECT().IsWithinBusinessHours(gomock.Any(), gomock.Any(), gomock.Any(), intPtr(7)).Return(true, nil)
+		f.repo.EXPECT().LockDay(gomock.Any(), intPtr(7)).Return(nil)
+		f.repo.EXPECT().LockResources(gomock.Any(), []int{5}).Return(nil)
+		f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Appointment{
+			{ID: 1, Fecha: start.Add(30 * time.Minute), Duracion: 1800, Estado: models.StatusScheduled, DoctorID: intPtr(8), Recursos: []int{5}},
+		}, nil)
+
+		_, err := f.svc.Create(&models.AppointmentCreateDTO{
+			Nombre:   strPtr("Walk-in"),
+			Fecha:    start,
+			DoctorID: intPtr(7),
+			TipoID:   intPtr(3),
+		})
+		require.True(t, appErr.IsDomainError(err))
+		require.Contains(t, err.Error(), "recurso")
+	})
+
+	t.Run("inactive resource cannot be booked", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		tipo := surgeryConsult()
+		tipo.Recursos[0].Activo = false
+		f.types.EXPECT().GetType(3).Return(tipo, nil)
+
+		_, err := f.svc.Create(&models.AppointmentCreateDTO{
+			Nombre: strPtr("Walk-in"),
+			Fecha:  clinicTime(2025, time.March, 3, 9, 0),
+			TipoID: intPtr(3),
+		})
+		require.True(t, appErr.IsDomainError(err))
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Availability with resources
+// -----------------------------------------------------------------------------
+
+func TestService_GetAvailableSlotsWithType(t *testing.T) {
+	t.Parallel()
+
+	f := setup(t)
+	defer f.ctrl.Finish()
+
+	day := clinicTime(2025, time.March, 3, 0, 0)
+	f.types.EXPECT().GetType(3).Return(surgeryConsult(), nil)
+	f.schedule.EXPECT().GetEffectiveDay(day, gomock.Nil()).Return(openDay(day, 9, 11), nil)
+	// The room is taken 09:00-09:45 by a doctor's appointment, which is not on the general agenda
+	f.repo.EXPECT().GetBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.Appointment{
+		{ID: 1, Fecha: clinicTime(2025, time.March, 3, 9, 0), Duracion: 2700, Estado: models.StatusScheduled, DoctorID: intPtr(8), Recursos: []int{5}},
+	}, nil)
+
+	slots, err := f.svc.GetAvailableSlots(day, models.SlotOptions{TipoID: intPtr(3)})
+	require.NoError(t, err)
+	require.Len(t, slots, 2) // 09:00-09:45 and 09:45-10:30
+	require.False(t, slots[0].Available)
+	require.True(t, slots[1].Available)
+	require.True(t, slots[1].Start.Equal(clinicTime(2025, time.March, 3, 9, 45)))
+}
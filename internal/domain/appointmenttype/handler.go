@@ -0,0 +1,163 @@
+package appointmenttype
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointmenttype/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(e *echo.Group) {
+	types := e.Group("/appointment-types", ErrorMiddleware(), middleware.Audit("tipo_cita", "id", h.auditedType))
+
+	types.GET("", h.GetAll, middleware.RequirePermission("ver-citas"))
+	types.GET("/:id", h.GetByID, middleware.RequirePermission("ver-citas"))
+	types.POST("", h.Create, middleware.RequirePermission("manejar-citas"))
+	types.PUT("/:id", h.Update, middleware.RequirePermission("manejar-citas"))
+	types.DELETE("/:id", h.Delete, middleware.RequirePermission("manejar-citas"))
+
+	resources := e.Group("/resources", ErrorMiddleware(), middleware.Audit("recurso", "id", h.auditedResource))
+
+	resources.GET("", h.GetAllResources, middleware.RequirePermission("ver-citas"))
+	resources.GET("/:id", h.GetResourceByID, middleware.RequirePermission("ver-citas"))
+	resources.POST("", h.CreateResource, middleware.RequirePermission("manejar-citas"))
+	resources.PUT("/:id", h.UpdateResource, middleware.RequirePermission("manejar-citas"))
+	resources.DELETE("/:id", h.DeleteResource, middleware.RequirePermission("manejar-citas"))
+}
+
+// States middleware.Audit records before and after a change
+func (h *Handler) auditedType(id int) (interface{}, error) {
+	return h.service.GetByID(id)
+}
+
+func (h *Handler) auditedResource(id int) (interface{}, error) {
+	return h.service.GetResourceByID(id)
+}
+
+// GetAll lista los tipos de cita activos; ?all=true incluye los inactivos
+func (h *Handler) GetAll(c echo.Context) error {
+	types, err := h.service.GetAll(c.QueryParam("all") == "true")
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, types)
+}
+
+func (h *Handler) GetByID(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	t, err := h.service.GetByID(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, t)
+}
+
+func (h *Handler) Create(c echo.Context) error {
+	var req models.AppointmentTypeCreateDTO
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("AppointmentType.Create.Bind", appErr.ErrInvalidInput, err)
+	}
+	id, err := h.service.Create(&req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, echo.Map{"id": id, "message": "Tipo de cita creado correctamente"})
+}
+
+func (h *Handler) Update(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	var req models.AppointmentTypeUpdateDTO
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("AppointmentType.Update.Bind", appErr.ErrInvalidInput, err)
+	}
+	if err := h.service.Update(id, &req); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": "Tipo de cita actualizado correctamente"})
+}
+
+func (h *Handler) Delete(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	if err := h.service.Delete(id); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": "Tipo de cita eliminado correctamente"})
+}
+
+func (h *Handler) GetAllResources(c echo.Context) error {
+	resources, err := h.service.GetAllResources()
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resources)
+}
+
+func (h *Handler) GetResourceByID(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	res, err := h.service.GetResourceByID(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) CreateResource(c echo.Context) error {
+	var req models.ResourceCreateDTO
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("AppointmentType.CreateResource.Bind", appErr.ErrInvalidInput, err)
+	}
+	id, err := h.service.CreateResource(&req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, echo.Map{"id": id, "message": "Recurso creado correctamente"})
+}
+
+func (h *Handler) UpdateResource(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	var req models.ResourceUpdateDTO
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("AppointmentType.UpdateResource.Bind", appErr.ErrInvalidInput, err)
+	}
+	if err := h.service.UpdateResource(id, &req); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": "Recurso actualizado correctamente"})
+}
+
+func (h *Handler) DeleteResource(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	if err := h.service.DeleteResource(id); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": "Recurso eliminado correctamente"})
+}
@@ -0,0 +1,16 @@
+package appointmenttype
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// ErrorMiddleware registers the error messages specific to /appointment-types and /resources routes; the
+// global error handler presents everything else.
+func ErrorMiddleware() echo.MiddlewareFunc {
+	return middleware.ErrorMessages(map[error]string{
+		appErr.ErrNotFound:      "Tipo de cita o recurso no encontrado.",
+		appErr.ErrAlreadyExists: "Ya existe un registro con ese nombre.",
+	})
+}
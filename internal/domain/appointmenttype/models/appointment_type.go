@@ -0,0 +1,64 @@
+package models
+
+// Clases de recurso que una cita puede requerir
+const (
+	ResourceRoom      = "room"      // consultorio, quirófano, sala de exámenes
+	ResourceEquipment = "equipment" // equipo que solo puede usarse en una cita a la vez
+)
+
+// Resource es un cuarto o equipo que no puede usarse en dos citas al mismo tiempo
+type Resource struct {
+	ID     int    `json:"id"`
+	Nombre string `json:"nombre"`
+	Tipo   string `json:"tipo"` // room | equipment
+	Activo bool   `json:"activo"`
+}
+
+// AppointmentType es una entrada del catálogo de tipos de cita (primera visita,
+// seguimiento, solo examen, ...) con su duración por defecto y sus requisitos.
+type AppointmentType struct {
+	ID             int        `json:"id"`
+	Nombre         string     `json:"nombre"`
+	Duracion       int64      `json:"duracion"`                  // segundos, se usa si la cita no indica otra
+	Color          string     `json:"color"`                     // #RRGGBB para la agenda
+	CuestionarioID *int       `json:"cuestionario_id,omitempty"` // cuestionario que se adjunta a la consulta
+	Activo         bool       `json:"activo"`
+	Recursos       []Resource `json:"recursos"` // recursos que ocupa cada cita de este tipo
+}
+
+// RecursoIDs devuelve los IDs de los recursos requeridos
+func (t *AppointmentType) RecursoIDs() []int {
+	ids := make([]int, 0, len(t.Recursos))
+	for _, r := range t.Recursos {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+type AppointmentTypeCreateDTO struct {
+	Nombre         string `json:"nombre" validate:"required"`
+	Duracion       int64  `json:"duracion" validate:"required,min=1"`
+	Color          string `json:"color" validate:"omitempty,hexcolor"`
+	CuestionarioID *int   `json:"cuestionario_id,omitempty" validate:"omitempty,min=1"`
+	Recursos       []int  `json:"recursos"`
+}
+
+type AppointmentTypeUpdateDTO struct {
+	Nombre         string `json:"nombre" validate:"required"`
+	Duracion       int64  `json:"duracion" validate:"required,min=1"`
+	Color          string `json:"color" validate:"omitempty,hexcolor"`
+	CuestionarioID *int   `json:"cuestionario_id,omitempty" validate:"omitempty,min=1"`
+	Activo         bool   `json:"activo"`
+	Recursos       []int  `json:"recursos"`
+}
+
+type ResourceCreateDTO struct {
+	Nombre string `json:"nombre" validate:"required"`
+	Tipo   string `json:"tipo" validate:"required,oneof=room equipment"`
+}
+
+type ResourceUpdateDTO struct {
+	Nombre string `json:"nombre" validate:"required"`
+	Tipo   string `json:"tipo" validate:"required,oneof=room equipment"`
+	Activo bool   `json:"activo"`
+}
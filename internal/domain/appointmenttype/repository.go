@@ -0,0 +1,282 @@
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
+package appointmenttype
+
+import (
+	"database/sql"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointmenttype/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+type Repository interface {
+	// Tipos de cita; includeInactive=false devuelve solo los que se pueden agendar
+	GetAll(includeInactive bool) ([]models.AppointmentType, error)
+	GetByID(id int) (*models.AppointmentType, error)
+	// Create y Update guardan el tipo y reemplazan su lista de recursos en una transacción
+	Create(t *models.AppointmentType) (int, error)
+	Update(t *models.AppointmentType) error
+	Delete(id int) error
+
+	// Recursos (cuartos y equipos)
+	GetAllResources() ([]models.Resource, error)
+	GetResourceByID(id int) (*models.Resource, error)
+	CreateResource(r *models.Resource) (int, error)
+	UpdateResource(r *models.Resource) error
+	DeleteResource(id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// ============================================================================
+// TIPOS DE CITA
+// ============================================================================
+
+func (r *repository) GetAll(includeInactive bool) ([]models.AppointmentType, error) {
+	query := `
+		SELECT id, nombre, duracion, color, cuestionario_id, activo
+		FROM tipos_cita`
+	if !includeInactive {
+		query += ` WHERE activo = true`
+	}
+	query += ` ORDER BY nombre`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AppointmentTypeRepository.GetAll")
+	}
+	defer rows.Close()
+
+	types := []models.AppointmentType{}
+	for rows.Next() {
+		var t models.AppointmentType
+		if err := rows.Scan(&t.ID, &t.Nombre, &t.Duracion, &t.Color, &t.CuestionarioID, &t.Activo); err != nil {
+			return nil, appErr.Wrap("AppointmentTypeRepository.GetAll(scan)", appErr.ErrInternal, err)
+		}
+		types = append(types, t)
+	}
+
+	resources, err := r.typeResources()
+	if err != nil {
+		return nil, err
+	}
+	for i := range types {
+		types[i].Recursos = resources[types[i].ID]
+		if types[i].Recursos == nil {
+			types[i].Recursos = []models.Resource{}
+		}
+	}
+	return types, nil
+}
+
+func (r *repository) GetByID(id int) (*models.AppointmentType, error) {
+	var t models.AppointmentType
+	err := r.db.QueryRow(`
+		SELECT id, nombre, duracion, color, cuestionario_id, activo
+		FROM tipos_cita
+		WHERE id = $1
+	`, id).Scan(&t.ID, &t.Nombre, &t.Duracion, &t.Color, &t.CuestionarioID, &t.Activo)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AppointmentTypeRepository.GetByID")
+	}
+
+	rows, err := r.db.Query(`
+		SELECT r.id, r.nombre, r.tipo, r.activo
+		FROM tipos_cita_recursos tr
+		JOIN recursos r ON tr.recurso_id = r.id
+		WHERE tr.tipo_id = $1
+		ORDER BY r.nombre
+	`, id)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AppointmentTypeRepository.GetByID(resources)")
+	}
+	defer rows.Close()
+
+	t.Recursos = []models.Resource{}
+	for rows.Next() {
+		var res models.Resource
+		if err := rows.Scan(&res.ID, &res.Nombre, &res.Tipo, &res.Activo); err != nil {
+			return nil, appErr.Wrap("AppointmentTypeRepository.GetByID(scan)", appErr.ErrInternal, err)
+		}
+		t.Recursos = append(t.Recursos, res)
+	}
+	return &t, nil
+}
+
+// typeResources devuelve los recursos requeridos por cada tipo de cita, indexados por tipo
+func (r *repository) typeResources() (map[int][]models.Resource, error) {
+	rows, err := r.db.Query(`
+		SELECT tr.tipo_id, r.id, r.nombre, r.tipo, r.activo
+		FROM tipos_cita_recursos tr
+		JOIN recursos r ON tr.recurso_id = r.id
+		ORDER BY r.nombre
+	`)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AppointmentTypeRepository.typeResources")
+	}
+	defer rows.Close()
+
+	byType := map[int][]models.Resource{}
+	for rows.Next() {
+		var tipoID int
+		var res models.Resource
+		if err := rows.Scan(&tipoID, &res.ID, &res.Nombre, &res.Tipo, &res.Activo); err != nil {
+			return nil, appErr.Wrap("AppointmentTypeRepository.typeResources(scan)", appErr.ErrInternal, err)
+		}
+		byType[tipoID] = append(byType[tipoID], res)
+	}
+	return byType, nil
+}
+
+func (r *repository) Create(t *models.AppointmentType) (int, error) {
+	var id int
+	err := database.RunInTx(r.db, "AppointmentTypeRepository.Create", func(tx database.DBTX) error {
+		if err := tx.QueryRow(`
+			INSERT INTO tipos_cita (nombre, duracion, color, cuestionario_id, activo)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, t.Nombre, t.Duracion, t.Color, t.CuestionarioID, t.Activo).Scan(&id); err != nil {
+			return database.MapSQLError(err, "AppointmentTypeRepository.Create(insert)")
+		}
+		return replaceTypeResources(tx, id, t.RecursoIDs())
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *repository) Update(t *models.AppointmentType) error {
+	return database.RunInTx(r.db, "AppointmentTypeRepository.Update", func(tx database.DBTX) error {
+		res, err := tx.Exec(`
+			UPDATE tipos_cita
+			SET nombre = $1, duracion = $2, color = $3, cuestionario_id = $4, activo = $5
+			WHERE id = $6
+		`, t.Nombre, t.Duracion, t.Color, t.CuestionarioID, t.Activo, t.ID)
+		if err != nil {
+			return database.MapSQLError(err, "AppointmentTypeRepository.Update")
+		}
+		rows, _ := res.RowsAffected()
+		if rows == 0 {
+			return appErr.Wrap("AppointmentTypeRepository.Update", appErr.ErrNotFound, nil)
+		}
+		return replaceTypeResources(tx, t.ID, t.RecursoIDs())
+	})
+}
+
+func replaceTypeResources(tx database.DBTX, tipoID int, recursoIDs []int) error {
+	if _, err := tx.Exec(`DELETE FROM tipos_cita_recursos WHERE tipo_id = $1`, tipoID); err != nil {
+		return database.MapSQLError(err, "AppointmentTypeRepository.replaceTypeResources(delete)")
+	}
+	for _, recursoID := range recursoIDs {
+		if _, err := tx.Exec(`
+			INSERT INTO tipos_cita_recursos (tipo_id, recurso_id) VALUES ($1, $2)
+		`, tipoID, recursoID); err != nil {
+			return database.MapSQLError(err, "AppointmentTypeRepository.replaceTypeResources(insert)")
+		}
+	}
+	return nil
+}
+
+func (r *repository) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM tipos_cita WHERE id = $1`, id)
+	if err != nil {
+		return database.MapSQLError(err, "AppointmentTypeRepository.Delete")
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return appErr.Wrap("AppointmentTypeRepository.Delete", appErr.ErrNotFound, nil)
+	}
+	return nil
+}
+
+// ============================================================================
+// RECURSOS
+// ============================================================================
+
+func (r *repository) GetAllResources() ([]models.Resource, error) {
+	rows, err := r.db.Query(`
+		SELECT id, nombre, tipo, activo
+		FROM recursos
+		ORDER BY tipo, nombre
+	`)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AppointmentTypeRepository.GetAllResources")
+	}
+	defer rows.Close()
+
+	resources := []models.Resource{}
+	for rows.Next() {
+		var res models.Resource
+		if err := rows.Scan(&res.ID, &res.Nombre, &res.Tipo, &res.Activo); err != nil {
+			return nil, appErr.Wrap("AppointmentTypeRepository.GetAllResources(scan)", appErr.ErrInternal, err)
+		}
+		resources = append(resources, res)
+	}
+	return resources, nil
+}
+
+func (r *repository) GetResourceByID(id int) (*models.Resource, error) {
+	var res models.Resource
+	err := r.db.QueryRow(`
+		SELECT id, nombre, tipo, activo
+		FROM recursos
+		WHERE id = $1
+	`, id).Scan(&res.ID, &res.Nombre, &res.Tipo, &res.Activo)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AppointmentTypeRepository.GetResourceByID")
+	}
+	return &res, nil
+}
+
+func (r *repository) CreateResource(res *models.Resource) (int, error) {
+	var id int
+	err := r.db.QueryRow(`
+		INSERT INTO recursos (nombre, tipo, activo)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, res.Nombre, res.Tipo, res.Activo).Scan(&id)
+	if err != nil {
+		return 0, database.MapSQLError(err, "AppointmentTypeRepository.CreateResource")
+	}
+	return id, nil
+}
+
+func (r *repository) UpdateResource(res *models.Resource) error {
+	result, err := r.db.Exec(`
+		UPDATE recursos
+		SET nombre = $1, tipo = $2, activo = $3
+		WHERE id = $4
+	`, res.Nombre, res.Tipo, res.Activo, res.ID)
+	if err != nil {
+		return database.MapSQLError(err, "AppointmentTypeRepository.UpdateResource")
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return appErr.Wrap("AppointmentTypeRepository.UpdateResource", appErr.ErrNotFound, nil)
+	}
+	return nil
+}
+
+func (r *repository) DeleteResource(id int) error {
+	res, err := r.db.Exec(`DELETE FROM recursos WHERE id = $1`, id)
+	if database.HasSQLState(err, database.CodeForeignKeyViolation) {
+		// citas_recursos conserva qué recursos ocupó cada cita
+		return appErr.NewDomainError(appErr.ErrConflict, "El recurso ya fue usado en citas; desactívelo en lugar de eliminarlo")
+	}
+	if err != nil {
+		return database.MapSQLError(err, "AppointmentTypeRepository.DeleteResource")
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return appErr.Wrap("AppointmentTypeRepository.DeleteResource", appErr.ErrNotFound, nil)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+//go:generate mockgen -source=service.go -destination=mocks/service.go -package=mocks
+
+package appointmenttype
+
+import (
+	"strings"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/appointmenttype/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/validator"
+)
+
+const defaultColor = "#3B82F6"
+
+type Service interface {
+	GetAll(includeInactive bool) ([]models.AppointmentType, error)
+	GetByID(id int) (*models.AppointmentType, error)
+	Create(dto *models.AppointmentTypeCreateDTO) (int, error)
+	Update(id int, dto *models.AppointmentTypeUpdateDTO) error
+	Delete(id int) error
+
+	GetAllResources() ([]models.Resource, error)
+	GetResourceByID(id int) (*models.Resource, error)
+	CreateResource(dto *models.ResourceCreateDTO) (int, error)
+	UpdateResource(id int, dto *models.ResourceUpdateDTO) error
+	DeleteResource(id int) error
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) GetAll(includeInactive bool) ([]models.AppointmentType, error) {
+	return s.repo.GetAll(includeInactive)
+}
+
+func (s *service) GetByID(id int) (*models.AppointmentType, error) {
+	if id <= 0 {
+		return nil, appErr.NewDomainError(appErr.ErrInvalidInput, "El ID del tipo de cita es inválido.")
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *service) Create(dto *models.AppointmentTypeCreateDTO) (int, error) {
+	if dto == nil {
+		return 0, appErr.NewDomainError(appErr.ErrInvalidInput, "Datos inválidos para crear el tipo de cita.")
+	}
+	if err := validator.Struct(dto); err != nil {
+		return 0, err
+	}
+
+	t := &models.AppointmentType{
+		Nombre:         strings.TrimSpace(dto.Nombre),
+		Duracion:       dto.Duracion,
+		Color:          dto.Color,
+		CuestionarioID: dto.CuestionarioID,
+		Activo:         true,
+	}
+	if err := s.prepareType(t, dto.Recursos); err != nil {
+		return 0, err
+	}
+	return s.repo.Create(t)
+}
+
+func (s *service) Update(id int, dto *models.AppointmentTypeUpdateDTO) error {
+	if id <= 0 || dto == nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "Datos inválidos para actualizar el tipo de cita.")
+	}
+	if err := validator.Struct(dto); err != nil {
+		return err
+	}
+
+	t, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	t.Nombre = strings.TrimSpace(dto.Nombre)
+	t.Duracion = dto.Duracion
+	t.Color = dto.Color
+	t.CuestionarioID = dto.CuestionarioID
+	t.Activo = dto.Activo
+
+	if err := s.prepareType(t, dto.Recursos); err != nil {
+		return err
+	}
+	return s.repo.Update(t)
+}
+
+func (s *service) Delete(id int) error {
+	if id <= 0 {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "El ID del tipo de cita es inválido.")
+	}
+	return s.repo.Delete(id)
+}
+
+// prepareType completa los valores por defecto del tipo (ya validado) y resuelve sus
+// recursos requeridos.
+func (s *service) prepareType(t *models.AppointmentType, recursoIDs []int) error {
+	if t.Color == "" {
+		t.Color = defaultColor
+	}
+
+	seen := make(map[int]bool, len(recursoIDs))
+	t.Recursos = make([]models.Resource, 0, len(recursoIDs))
+	for _, id := range recursoIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		res, err := s.GetResourceByID(id)
+		if err != nil {
+			return err
+		}
+		t.Recursos = append(t.Recursos, *res)
+	}
+	return nil
+}
+
+func (s *service) GetAllResources() ([]models.Resource, error) {
+	return s.repo.GetAllResources()
+}
+
+func (s *service) GetResourceByID(id int) (*models.Resource, error) {
+	if id <= 0 {
+		return nil, appErr.NewDomainError(appErr.ErrInvalidInput, "El ID del recurso es inválido.")
+	}
+	return s.repo.GetResourceByID(id)
+}
+
+func (s *service) CreateResource(dto *models.ResourceCreateDTO) (int, error) {
+	if dto == nil {
+		return 0, appErr.NewDomainError(appErr.ErrInvalidInput, "Datos inválidos para crear el recurso.")
+	}
+	if err := validator.Struct(dto); err != nil {
+		return 0, err
+	}
+	res := &models.Resource{Nombre: strings.TrimSpace(dto.Nombre), Tipo: dto.Tipo, Activo: true}
+	return s.repo.CreateResource(res)
+}
+
+func (s *service) UpdateResource(id int, dto *models.ResourceUpdateDTO) error {
+	if id <= 0 || dto == nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "Datos inválidos para actualizar el recurso.")
+	}
+	if err := validator.Struct(dto); err != nil {
+		return err
+	}
+	res := &models.Resource{ID: id, Nombre: strings.TrimSpace(dto.Nombre), Tipo: dto.Tipo, Activo: dto.Activo}
+	return s.repo.UpdateResource(res)
+}
+
+func (s *service) DeleteResource(id int) error {
+	if id <= 0 {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "El ID del recurso es inválido.")
+	}
+	return s.repo.DeleteResource(id)
+}
@@ -0,0 +1,165 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/audit/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/timeutil"
+)
+
+// Handler exposes the audit and access logs to administrators.
+type Handler struct {
+	service Service
+}
+
+// NewHandler constructs a new audit Handler.
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+// RegisterRoutes mounts /audit routes under the provided Echo group. The logs are
+// read-only: entries are written by middleware.Audit and middleware.AccessLog.
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	auditGroup := g.Group("/audit", ErrorMiddleware())
+
+	auditGroup.GET("", h.List, middleware.RequirePermission("ver-auditoria"))
+
+	// Chart reads
+	auditGroup.GET("/access", h.ListAccess, middleware.RequirePermission("ver-auditoria"))
+	auditGroup.GET("/access/unusual", h.UnusualAccess, middleware.RequirePermission("ver-auditoria"))
+	auditGroup.GET("/patients/:id/access", h.PatientAccess, middleware.RequirePermission("ver-auditoria"))
+}
+
+// listSpec declares the sort fields and filters accepted by GET /audit
+var listSpec = query.Spec{
+	Sortable: []string{"fecha", "id"},
+	Filters: map[string]query.FilterType{
+		"paciente_id": query.Int,
+		"usuario_id":  query.Int,
+		"entidad":     query.Exact,
+		"entidad_id":  query.Int,
+		"accion":      query.Exact,
+		"desde":       query.DateFrom,
+		"hasta":       query.DateTo,
+	},
+	DefaultSort: []query.Sort{{Field: "fecha", Desc: true}},
+}
+
+// listKey returns an entry's sort values for the next page cursor
+func listKey(e models.Entry, field string) interface{} {
+	if field == "fecha" {
+		return e.Fecha
+	}
+	return e.ID
+}
+
+// GET /audit?paciente_id=&usuario_id=&entidad=&entidad_id=&accion=&desde=&hasta=
+func (h *Handler) List(c echo.Context) error {
+	opts, err := query.Parse(c, listSpec)
+	if err != nil {
+		return err
+	}
+
+	entries, total, err := h.service.List(opts)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, query.NewPage(entries, total, opts, listKey))
+}
+
+// accessSpec declares the sort fields and filters accepted by GET /audit/access
+var accessSpec = query.Spec{
+	Sortable: []string{"fecha", "id"},
+	Filters: map[string]query.FilterType{
+		"paciente_id": query.Int,
+		"usuario_id":  query.Int,
+		"recurso":     query.Exact,
+		"desde":       query.DateFrom,
+		"hasta":       query.DateTo,
+	},
+	DefaultSort: []query.Sort{{Field: "fecha", Desc: true}},
+}
+
+// accessKey returns an access's sort values for the next page cursor
+func accessKey(a models.Access, field string) interface{} {
+	if field == "fecha" {
+		return a.Fecha
+	}
+	return a.ID
+}
+
+// GET /audit/access?paciente_id=&usuario_id=&recurso=&desde=&hasta=
+func (h *Handler) ListAccess(c echo.Context) error {
+	opts, err := query.Parse(c, accessSpec)
+	if err != nil {
+		return err
+	}
+
+	accesses, total, err := h.service.ListAccess(opts)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, query.NewPage(accesses, total, opts, accessKey))
+}
+
+// GET /audit/patients/:id/access
+func (h *Handler) PatientAccess(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.Wrap("AuditHandler.PatientAccess.ParseID", appErr.ErrInvalidInput, err)
+	}
+	opts, err := query.Parse(c, accessSpec)
+	if err != nil {
+		return err
+	}
+
+	accesses, total, err := h.service.PatientAccess(id, opts)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, query.NewPage(accesses, total, opts, accessKey))
+}
+
+// GET /audit/access/unusual?desde=AAAA-MM-DD&hasta=AAAA-MM-DD&minimo=10&ventana=30
+func (h *Handler) UnusualAccess(c echo.Context) error {
+	var q models.UnusualAccessQuery
+	if raw := c.QueryParam("desde"); raw != "" {
+		desde, err := timeutil.ParseYMDToClinic(raw)
+		if err != nil {
+			return appErr.NewDomainError(appErr.ErrInvalidInput, "El parámetro 'desde' debe tener el formato AAAA-MM-DD")
+		}
+		q.Desde = desde
+	}
+	if raw := c.QueryParam("hasta"); raw != "" {
+		hasta, err := timeutil.ParseYMDToClinic(raw)
+		if err != nil {
+			return appErr.NewDomainError(appErr.ErrInvalidInput, "El parámetro 'hasta' debe tener el formato AAAA-MM-DD")
+		}
+		// Incluye el día completo
+		q.Hasta = hasta.AddDate(0, 0, 1)
+	}
+	for name, dest := range map[string]*int{"minimo": &q.Minimo, "ventana": &q.VentanaDias} {
+		if raw := c.QueryParam(name); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				return appErr.NewDomainError(appErr.ErrInvalidInput, "El parámetro '"+name+"' debe ser un entero positivo")
+			}
+			*dest = n
+		}
+	}
+
+	report, err := h.service.UnusualAccess(q)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
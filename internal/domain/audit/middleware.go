@@ -0,0 +1,15 @@
+package audit
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// ErrorMiddleware registers the error messages specific to /audit routes; the
+// global error handler presents everything else.
+func ErrorMiddleware() echo.MiddlewareFunc {
+	return middleware.ErrorMessages(map[error]string{
+		appErr.ErrNotFound: "Entrada de auditoría no encontrada.",
+	})
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	query "github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	models "github.com/tonitomc/healthcare-crm-api/internal/domain/audit/models"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(entry *models.Entry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), entry)
+}
+
+// CreateAccess mocks base method.
+func (m *MockRepository) CreateAccess(access *models.Access) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccess", access)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAccess indicates an expected call of CreateAccess.
+func (mr *MockRepositoryMockRecorder) CreateAccess(access interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccess", reflect.TypeOf((*MockRepository)(nil).CreateAccess), access)
+}
+
+// List mocks base method.
+func (m *MockRepository) List(opts query.Options) ([]models.Entry, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", opts)
+	ret0, _ := ret[0].([]models.Entry)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockRepositoryMockRecorder) List(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepository)(nil).List), opts)
+}
+
+// ListAccess mocks base method.
+func (m *MockRepository) ListAccess(opts query.Options) ([]models.Access, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccess", opts)
+	ret0, _ := ret[0].([]models.Access)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAccess indicates an expected call of ListAccess.
+func (mr *MockRepositoryMockRecorder) ListAccess(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccess", reflect.TypeOf((*MockRepository)(nil).ListAccess), opts)
+}
+
+// UnusualAccess mocks base method.
+func (m *MockRepository) UnusualAccess(q models.UnusualAccessQuery) ([]models.UnusualAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnusualAccess", q)
+	ret0, _ := ret[0].([]models.UnusualAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnusualAccess indicates an expected call of UnusualAccess.
+func (mr *MockRepositoryMockRecorder) UnusualAccess(q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnusualAccess", reflect.TypeOf((*MockRepository)(nil).UnusualAccess), q)
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	query "github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	models "github.com/tonitomc/healthcare-crm-api/internal/domain/audit/models"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockService) List(opts query.Options) ([]models.Entry, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", opts)
+	ret0, _ := ret[0].([]models.Entry)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockServiceMockRecorder) List(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockService)(nil).List), opts)
+}
+
+// ListAccess mocks base method.
+func (m *MockService) ListAccess(opts query.Options) ([]models.Access, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccess", opts)
+	ret0, _ := ret[0].([]models.Access)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAccess indicates an expected call of ListAccess.
+func (mr *MockServiceMockRecorder) ListAccess(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccess", reflect.TypeOf((*MockService)(nil).ListAccess), opts)
+}
+
+// PatientAccess mocks base method.
+func (m *MockService) PatientAccess(patientID int, opts query.Options) ([]models.Access, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatientAccess", patientID, opts)
+	ret0, _ := ret[0].([]models.Access)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PatientAccess indicates an expected call of PatientAccess.
+func (mr *MockServiceMockRecorder) PatientAccess(patientID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatientAccess", reflect.TypeOf((*MockService)(nil).PatientAccess), patientID, opts)
+}
+
+// Record mocks base method.
+func (m *MockService) Record(rec *models.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", rec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockServiceMockRecorder) Record(rec interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockService)(nil).Record), rec)
+}
+
+// RecordAccess mocks base method.
+func (m *MockService) RecordAccess(rec *models.AccessRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAccess", rec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAccess indicates an expected call of RecordAccess.
+func (mr *MockServiceMockRecorder) RecordAccess(rec interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAccess", reflect.TypeOf((*MockService)(nil).RecordAccess), rec)
+}
+
+// UnusualAccess mocks base method.
+func (m *MockService) UnusualAccess(q models.UnusualAccessQuery) ([]models.UnusualAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnusualAccess", q)
+	ret0, _ := ret[0].([]models.UnusualAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnusualAccess indicates an expected call of UnusualAccess.
+func (mr *MockServiceMockRecorder) UnusualAccess(q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnusualAccess", reflect.TypeOf((*MockService)(nil).UnusualAccess), q)
+}
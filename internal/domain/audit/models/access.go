@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Recursos del expediente cuyo acceso se registra
+const (
+	ResourcePatientDetails      = "detalles_paciente"
+	ResourceExamFile            = "archivo_examen"
+	ResourceMedicalRecord       = "expediente"
+	ResourceConsultationDetails = "detalles_consulta"
+)
+
+// AccessRecord es una lectura del expediente tal como la reporta la API
+type AccessRecord struct {
+	UsuarioID  *int
+	PacienteID int
+	Recurso    string
+	RecursoID  *int
+	Motivo     string
+	Ruta       string
+	IP         string
+}
+
+// Access es una fila del registro de accesos
+type Access struct {
+	ID         int64     `json:"id"`
+	UsuarioID  *int      `json:"usuario_id"`
+	PacienteID int       `json:"paciente_id"`
+	Recurso    string    `json:"recurso"`
+	RecursoID  *int      `json:"recurso_id"`
+	Motivo     *string   `json:"motivo"`
+	Ruta       string    `json:"ruta"`
+	IP         *string   `json:"ip"`
+	Fecha      time.Time `json:"fecha"`
+}
+
+// UnusualAccessQuery son los parámetros del reporte de accesos inusuales
+type UnusualAccessQuery struct {
+	Desde       time.Time // inicio del periodo (inclusive)
+	Hasta       time.Time // fin del periodo (exclusivo)
+	Minimo      int       // pacientes sin relación a partir de los cuales se marca al usuario
+	VentanaDias int       // días antes y después del acceso en que una cita cuenta como relación
+}
+
+// UnusualAccess es un usuario que abrió los expedientes de varios pacientes con los
+// que no tenía una cita cercana
+type UnusualAccess struct {
+	UsuarioID *int    `json:"usuario_id"`
+	Username  *string `json:"username"`
+	Accesos   int     `json:"accesos"`   // lecturas sin relación en el periodo
+	Pacientes int     `json:"pacientes"` // pacientes distintos sin relación
+	// PacienteIDs son los pacientes sin relación, para revisar uno por uno
+	PacienteIDs []int `json:"paciente_ids"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Acciones registradas en la bitácora
+const (
+	ActionCreate = "crear"
+	ActionUpdate = "actualizar"
+	ActionDelete = "eliminar"
+)
+
+// EntityPatient es la entidad cuyos cambios se asocian a sí misma como paciente
+const EntityPatient = "paciente"
+
+// Record es un cambio tal como lo reporta la API, antes de convertirlo en Entry
+type Record struct {
+	UsuarioID *int
+	Accion    string
+	Entidad   string
+	EntidadID *int
+	Ruta      string
+	Antes     interface{} // nil = sin estado previo (creación o desconocido)
+	Despues   interface{} // nil = sin estado posterior (eliminación o desconocido)
+	IP        string
+}
+
+// Entry es una fila de la bitácora de auditoría
+type Entry struct {
+	ID         int64           `json:"id"`
+	UsuarioID  *int            `json:"usuario_id"`
+	Accion     string          `json:"accion"`
+	Entidad    string          `json:"entidad"`
+	EntidadID  *int            `json:"entidad_id"`
+	PacienteID *int            `json:"paciente_id"`
+	Ruta       string          `json:"ruta"`
+	Antes      json.RawMessage `json:"antes"`
+	Despues    json.RawMessage `json:"despues"`
+	Cambios    []Change        `json:"cambios"`
+	IP         *string         `json:"ip"`
+	Fecha      time.Time       `json:"fecha"`
+}
+
+// Change es un campo que cambió entre antes y después. Los campos anidados se
+// nombran con puntos ("seguro.poliza"); null = el campo no existía.
+type Change struct {
+	Campo   string          `json:"campo"`
+	Antes   json.RawMessage `json:"antes"`
+	Despues json.RawMessage `json:"despues"`
+}
@@ -0,0 +1,210 @@
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/audit/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// Repository solo inserta y consulta: las tablas rechazan UPDATE y DELETE
+type Repository interface {
+	Create(entry *models.Entry) error
+	List(opts query.Options) ([]models.Entry, int, error)
+
+	// Registro de accesos
+	CreateAccess(access *models.Access) error
+	ListAccess(opts query.Options) ([]models.Access, int, error)
+	// UnusualAccess agrupa por usuario los accesos a pacientes sin una cita con ese
+	// usuario como doctor dentro de la ventana
+	UnusualAccess(q models.UnusualAccessQuery) ([]models.UnusualAccess, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(entry *models.Entry) error {
+	cambios, err := json.Marshal(entry.Cambios)
+	if err != nil {
+		return appErr.Wrap("AuditRepository.Create(marshal)", appErr.ErrInternal, err)
+	}
+
+	err = r.db.QueryRow(`
+		INSERT INTO auditoria (usuario_id, accion, entidad, entidad_id, paciente_id, ruta, antes, despues, cambios, ip)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, fecha
+	`, entry.UsuarioID, entry.Accion, entry.Entidad, entry.EntidadID, entry.PacienteID, entry.Ruta,
+		jsonArg(entry.Antes), jsonArg(entry.Despues), string(cambios), entry.IP,
+	).Scan(&entry.ID, &entry.Fecha)
+	if err != nil {
+		return database.MapSQLError(err, "AuditRepository.Create")
+	}
+	return nil
+}
+
+// jsonArg envía un estado como texto JSON; sin estado se guarda NULL
+func jsonArg(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
+
+// entryColumns traduce los campos de orden y filtro del listado a columnas
+var entryColumns = query.Columns{
+	"id":          "id",
+	"fecha":       "fecha",
+	"paciente_id": "paciente_id",
+	"usuario_id":  "usuario_id",
+	"entidad":     "entidad",
+	"entidad_id":  "entidad_id",
+	"accion":      "accion",
+	"desde":       "fecha",
+	"hasta":       "fecha",
+}
+
+// List devuelve una página de la bitácora y el total que cumple los filtros
+func (r *repository) List(opts query.Options) ([]models.Entry, int, error) {
+	conds, args := opts.Conditions(entryColumns, nil)
+	where := query.Where(conds)
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM auditoria`+where, args...).Scan(&total); err != nil {
+		return nil, 0, database.MapSQLError(err, "AuditRepository.List(count)")
+	}
+
+	conds, args = opts.Seek(entryColumns, "id", conds, args)
+	page, args := opts.LimitOffset(args)
+	rows, err := r.db.Query(`
+		SELECT id, usuario_id, accion, entidad, entidad_id, paciente_id, ruta, antes, despues, cambios, ip, fecha
+		FROM auditoria`+query.Where(conds)+opts.OrderBy(entryColumns, "id")+page, args...)
+	if err != nil {
+		return nil, 0, database.MapSQLError(err, "AuditRepository.List")
+	}
+	defer rows.Close()
+
+	var entries []models.Entry
+	for rows.Next() {
+		var e models.Entry
+		var antes, despues, cambios []byte
+		if err := rows.Scan(&e.ID, &e.UsuarioID, &e.Accion, &e.Entidad, &e.EntidadID, &e.PacienteID,
+			&e.Ruta, &antes, &despues, &cambios, &e.IP, &e.Fecha); err != nil {
+			return nil, 0, appErr.Wrap("AuditRepository.List(scan)", appErr.ErrInternal, err)
+		}
+		if err := json.Unmarshal(cambios, &e.Cambios); err != nil {
+			return nil, 0, appErr.Wrap("AuditRepository.List(cambios)", appErr.ErrInternal, err)
+		}
+		e.Antes, e.Despues = antes, despues
+		entries = append(entries, e)
+	}
+
+	return entries, total, nil
+}
+
+// -----------------------------------------------------------------------------
+// Access log
+// -----------------------------------------------------------------------------
+
+func (r *repository) CreateAccess(access *models.Access) error {
+	err := r.db.QueryRow(`
+		INSERT INTO accesos (usuario_id, paciente_id, recurso, recurso_id, motivo, ruta, ip)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, fecha
+	`, access.UsuarioID, access.PacienteID, access.Recurso, access.RecursoID, access.Motivo, access.Ruta, access.IP,
+	).Scan(&access.ID, &access.Fecha)
+	if err != nil {
+		return database.MapSQLError(err, "AuditRepository.CreateAccess")
+	}
+	return nil
+}
+
+// accessColumns traduce los campos de orden y filtro del listado de accesos a columnas
+var accessColumns = query.Columns{
+	"id":          "id",
+	"fecha":       "fecha",
+	"paciente_id": "paciente_id",
+	"usuario_id":  "usuario_id",
+	"recurso":     "recurso",
+	"desde":       "fecha",
+	"hasta":       "fecha",
+}
+
+// ListAccess devuelve una página del registro de accesos y el total que cumple los filtros
+func (r *repository) ListAccess(opts query.Options) ([]models.Access, int, error) {
+	conds, args := opts.Conditions(accessColumns, nil)
+	where := query.Where(conds)
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM accesos`+where, args...).Scan(&total); err != nil {
+		return nil, 0, database.MapSQLError(err, "AuditRepository.ListAccess(count)")
+	}
+
+	conds, args = opts.Seek(accessColumns, "id", conds, args)
+	page, args := opts.LimitOffset(args)
+	rows, err := r.db.Query(`
+		SELECT id, usuario_id, paciente_id, recurso, recurso_id, motivo, ruta, ip, fecha
+		FROM accesos`+query.Where(conds)+opts.OrderBy(accessColumns, "id")+page, args...)
+	if err != nil {
+		return nil, 0, database.MapSQLError(err, "AuditRepository.ListAccess")
+	}
+	defer rows.Close()
+
+	var accesses []models.Access
+	for rows.Next() {
+		var a models.Access
+		if err := rows.Scan(&a.ID, &a.UsuarioID, &a.PacienteID, &a.Recurso, &a.RecursoID,
+			&a.Motivo, &a.Ruta, &a.IP, &a.Fecha); err != nil {
+			return nil, 0, appErr.Wrap("AuditRepository.ListAccess(scan)", appErr.ErrInternal, err)
+		}
+		accesses = append(accesses, a)
+	}
+
+	return accesses, total, nil
+}
+
+func (r *repository) UnusualAccess(q models.UnusualAccessQuery) ([]models.UnusualAccess, error) {
+	rows, err := r.db.Query(`
+		SELECT a.usuario_id, u.username, COUNT(*), COUNT(DISTINCT a.paciente_id),
+		       JSON_AGG(DISTINCT a.paciente_id ORDER BY a.paciente_id)
+		FROM accesos a
+		LEFT JOIN usuarios u ON u.id = a.usuario_id
+		WHERE a.fecha >= $1 AND a.fecha < $2
+		  AND NOT EXISTS (
+		      SELECT 1 FROM citas c
+		      WHERE c.paciente_id = a.paciente_id
+		        AND c.doctor_id = a.usuario_id
+		        AND c.fecha BETWEEN a.fecha - make_interval(days => $3::int) AND a.fecha + make_interval(days => $3::int)
+		  )
+		GROUP BY a.usuario_id, u.username
+		HAVING COUNT(DISTINCT a.paciente_id) >= $4
+		ORDER BY COUNT(DISTINCT a.paciente_id) DESC, a.usuario_id
+	`, q.Desde, q.Hasta, q.VentanaDias, q.Minimo)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AuditRepository.UnusualAccess")
+	}
+	defer rows.Close()
+
+	report := []models.UnusualAccess{}
+	for rows.Next() {
+		var u models.UnusualAccess
+		var patients []byte
+		if err := rows.Scan(&u.UsuarioID, &u.Username, &u.Accesos, &u.Pacientes, &patients); err != nil {
+			return nil, appErr.Wrap("AuditRepository.UnusualAccess(scan)", appErr.ErrInternal, err)
+		}
+		if err := json.Unmarshal(patients, &u.PacienteIDs); err != nil {
+			return nil, appErr.Wrap("AuditRepository.UnusualAccess(paciente_ids)", appErr.ErrInternal, err)
+		}
+		report = append(report, u)
+	}
+	return report, nil
+}
@@ -0,0 +1,242 @@
+//go:generate mockgen -source=service.go -destination=mocks/service.go -package=mocks
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/audit/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+type Service interface {
+	// Record guarda un cambio con la diferencia campo por campo entre antes y después
+	Record(rec *models.Record) error
+	List(opts query.Options) ([]models.Entry, int, error)
+
+	// Registro de accesos al expediente
+	RecordAccess(rec *models.AccessRecord) error
+	ListAccess(opts query.Options) ([]models.Access, int, error)
+	// PatientAccess es el historial de accesos al expediente de un paciente
+	PatientAccess(patientID int, opts query.Options) ([]models.Access, int, error)
+	// UnusualAccess marca a los usuarios que abrieron expedientes de muchos pacientes
+	// sin una cita con ellos; los campos vacíos de q toman los valores por defecto
+	UnusualAccess(q models.UnusualAccessQuery) ([]models.UnusualAccess, error)
+}
+
+// Valores por defecto del reporte de accesos inusuales
+const (
+	DefaultUnusualPeriodDays = 30
+	DefaultUnusualMinimum    = 10
+	DefaultUnusualWindowDays = 30
+	maxUnusualWindowDays     = 365
+)
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Record(rec *models.Record) error {
+	if rec == nil || rec.Entidad == "" {
+		return appErr.Wrap("AuditService.Record", appErr.ErrInvalidInput, nil)
+	}
+	switch rec.Accion {
+	case models.ActionCreate, models.ActionUpdate, models.ActionDelete:
+	default:
+		return appErr.Wrap("AuditService.Record(accion)", appErr.ErrInvalidInput, nil)
+	}
+
+	antes, err := marshalState(rec.Antes)
+	if err != nil {
+		return appErr.Wrap("AuditService.Record(antes)", appErr.ErrInternal, err)
+	}
+	despues, err := marshalState(rec.Despues)
+	if err != nil {
+		return appErr.Wrap("AuditService.Record(despues)", appErr.ErrInternal, err)
+	}
+
+	entry := &models.Entry{
+		UsuarioID:  rec.UsuarioID,
+		Accion:     rec.Accion,
+		Entidad:    rec.Entidad,
+		EntidadID:  rec.EntidadID,
+		PacienteID: patientOf(rec, antes, despues),
+		Ruta:       rec.Ruta,
+		Antes:      antes,
+		Despues:    despues,
+		Cambios:    Diff(antes, despues),
+	}
+	if rec.IP != "" {
+		entry.IP = &rec.IP
+	}
+	return s.repo.Create(entry)
+}
+
+func (s *service) List(opts query.Options) ([]models.Entry, int, error) {
+	return s.repo.List(opts)
+}
+
+// -----------------------------------------------------------------------------
+// Access log
+// -----------------------------------------------------------------------------
+
+func (s *service) RecordAccess(rec *models.AccessRecord) error {
+	if rec == nil || rec.PacienteID <= 0 || rec.Recurso == "" {
+		return appErr.Wrap("AuditService.RecordAccess", appErr.ErrInvalidInput, nil)
+	}
+
+	access := &models.Access{
+		UsuarioID:  rec.UsuarioID,
+		PacienteID: rec.PacienteID,
+		Recurso:    rec.Recurso,
+		RecursoID:  rec.RecursoID,
+		Ruta:       rec.Ruta,
+	}
+	if rec.Motivo != "" {
+		access.Motivo = &rec.Motivo
+	}
+	if rec.IP != "" {
+		access.IP = &rec.IP
+	}
+	return s.repo.CreateAccess(access)
+}
+
+func (s *service) ListAccess(opts query.Options) ([]models.Access, int, error) {
+	return s.repo.ListAccess(opts)
+}
+
+func (s *service) PatientAccess(patientID int, opts query.Options) ([]models.Access, int, error) {
+	if patientID <= 0 {
+		return nil, 0, appErr.Wrap("AuditService.PatientAccess", appErr.ErrInvalidInput, nil)
+	}
+	opts.Filters = append(opts.Filters, query.Filter{Field: "paciente_id", Type: query.Int, Value: patientID})
+	return s.repo.ListAccess(opts)
+}
+
+func (s *service) UnusualAccess(q models.UnusualAccessQuery) ([]models.UnusualAccess, error) {
+	if q.Hasta.IsZero() {
+		q.Hasta = time.Now()
+	}
+	if q.Desde.IsZero() {
+		q.Desde = q.Hasta.AddDate(0, 0, -DefaultUnusualPeriodDays)
+	}
+	if q.Minimo <= 0 {
+		q.Minimo = DefaultUnusualMinimum
+	}
+	if q.VentanaDias <= 0 {
+		q.VentanaDias = DefaultUnusualWindowDays
+	}
+
+	if !q.Desde.Before(q.Hasta) {
+		return nil, appErr.NewDomainError(appErr.ErrInvalidInput, "La fecha 'desde' debe ser anterior a 'hasta'")
+	}
+	if q.VentanaDias > maxUnusualWindowDays {
+		return nil, appErr.NewDomainError(appErr.ErrInvalidInput, "La ventana no puede ser mayor a 365 días")
+	}
+	return s.repo.UnusualAccess(q)
+}
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+// marshalState convierte un estado a JSON; nil (o un puntero nil) es "sin estado"
+func marshalState(state interface{}) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(state)
+	if err != nil || bytes.Equal(raw, []byte("null")) {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// patientOf devuelve el paciente afectado: el propio registro si la entidad es un
+// paciente, o el campo "paciente_id" del estado posterior o, si no hay, del anterior.
+func patientOf(rec *models.Record, antes, despues json.RawMessage) *int {
+	if rec.Entidad == models.EntityPatient {
+		return rec.EntidadID
+	}
+	for _, raw := range []json.RawMessage{despues, antes} {
+		var state struct {
+			PacienteID *int `json:"paciente_id"`
+		}
+		if len(raw) > 0 && json.Unmarshal(raw, &state) == nil && state.PacienteID != nil {
+			return state.PacienteID
+		}
+	}
+	return nil
+}
+
+// Diff lista los campos que cambiaron entre dos estados JSON, ordenados por nombre.
+// Los objetos anidados se comparan campo por campo ("seguro.poliza"); los arreglos,
+// completos. Solo hay diferencia si se conocen ambos estados: al crear o eliminar,
+// el estado completo ya está en la entrada.
+func Diff(antes, despues json.RawMessage) []models.Change {
+	changes := []models.Change{}
+	if len(antes) == 0 || len(despues) == 0 {
+		return changes
+	}
+
+	var a, d interface{}
+	if json.Unmarshal(antes, &a) != nil || json.Unmarshal(despues, &d) != nil {
+		return changes
+	}
+	before, after := map[string]interface{}{}, map[string]interface{}{}
+	flatten("", a, before)
+	flatten("", d, after)
+
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		va, inBefore := before[field]
+		vd, inAfter := after[field]
+		if inBefore == inAfter && reflect.DeepEqual(va, vd) {
+			continue
+		}
+		changes = append(changes, models.Change{Campo: field, Antes: rawValue(va), Despues: rawValue(vd)})
+	}
+	return changes
+}
+
+// flatten agrega a out los valores de v con su ruta; un objeto vacío no aporta campos
+func flatten(prefix string, v interface{}, out map[string]interface{}) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		out[prefix] = v
+		return
+	}
+	for key, value := range obj {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		flatten(key, value, out)
+	}
+}
+
+func rawValue(v interface{}) json.RawMessage {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return raw
+}
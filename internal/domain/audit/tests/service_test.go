@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/audit"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/audit/mocks"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/audit/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+type fixture struct {
+	repo *mocks.MockRepository
+	svc  audit.Service
+}
+
+func setup(t *testing.T) fixture {
+	ctrl := gomock.NewController(t)
+	f := fixture{repo: mocks.NewMockRepository(ctrl)}
+	f.svc = audit.NewService(f.repo)
+	return f
+}
+
+func intPtr(v int) *int { return &v }
+
+type consultation struct {
+	ID         int    `json:"id"`
+	PacienteID int    `json:"paciente_id"`
+	Motivo     string `json:"motivo"`
+}
+
+// -----------------------------------------------------------------------------
+// Diff
+// -----------------------------------------------------------------------------
+
+func TestDiff_ListsChangedFieldsInOrder(t *testing.T) {
+	t.Parallel()
+
+	before := json.RawMessage(`{"nombre":"Ana","seguro":{"poliza":"A1","aseguradora":"X"},"telefonos":["1"],"notas":"x"}`)
+	after := json.RawMessage(`{"nombre":"Ana","seguro":{"poliza":"B2","aseguradora":"X"},"telefonos":["1","2"],"correo":"a@b.c"}`)
+
+	changes := audit.Diff(before, after)
+
+	require.Len(t, changes, 4)
+	require.Equal(t, "correo", changes[0].Campo)
+	require.JSONEq(t, `null`, string(changes[0].Antes))
+	require.JSONEq(t, `"a@b.c"`, string(changes[0].Despues))
+	require.Equal(t, "notas", changes[1].Campo)
+	require.JSONEq(t, `null`, string(changes[1].Despues))
+	require.Equal(t, "seguro.poliza", changes[2].Campo)
+	require.JSONEq(t, `"A1"`, string(changes[2].Antes))
+	require.JSONEq(t, `"B2"`, string(changes[2].Despues))
+	require.Equal(t, "telefonos", changes[3].Campo)
+}
+
+func TestDiff_NeedsBothStates(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, audit.Diff(nil, json.RawMessage(`{"id":1}`)))
+	require.Empty(t, audit.Diff(json.RawMessage(`{"id":1}`), nil))
+	require.Empty(t, audit.Diff(json.RawMessage(`{"id":1}`), json.RawMessage(`{"id":1}`)))
+}
+
+// -----------------------------------------------------------------------------
+// Record
+// -----------------------------------------------------------------------------
+
+func TestRecord_Update(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(e *models.Entry) error {
+		require.Equal(t, models.ActionUpdate, e.Accion)
+		require.Equal(t, intPtr(7), e.UsuarioID)
+		require.Equal(t, intPtr(12), e.PacienteID, "patient comes from the state")
+		require.JSONEq(t, `{"id":3,"paciente_id":12,"motivo":"Dolor"}`, string(e.Antes))
+		require.Len(t, e.Cambios, 1)
+		require.Equal(t, "motivo", e.Cambios[0].Campo)
+		require.Equal(t, "10.0.0.1", *e.IP)
+		return nil
+	})
+
+	err := f.svc.Record(&models.Record{
+		UsuarioID: intPtr(7),
+		Accion:    models.ActionUpdate,
+		Entidad:   "consulta",
+		EntidadID: intPtr(3),
+		Ruta:      "PUT /api/consultations/:id",
+		Antes:     &consultation{ID: 3, PacienteID: 12, Motivo: "Dolor"},
+		Despues:   &consultation{ID: 3, PacienteID: 12, Motivo: "Control"},
+		IP:        "10.0.0.1",
+	})
+	require.NoError(t, err)
+}
+
+func TestRecord_PatientIsItsOwnPatient(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	var missing *consultation
+	f.repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(e *models.Entry) error {
+		require.Equal(t, intPtr(5), e.PacienteID)
+		require.Nil(t, e.Antes, "a nil pointer is no state")
+		require.Nil(t, e.Despues)
+		require.Nil(t, e.IP)
+		require.Empty(t, e.Cambios)
+		return nil
+	})
+
+	err := f.svc.Record(&models.Record{
+		Accion:    models.ActionDelete,
+		Entidad:   models.EntityPatient,
+		EntidadID: intPtr(5),
+		Ruta:      "DELETE /api/patients/:id/purge",
+		Antes:     missing,
+	})
+	require.NoError(t, err)
+}
+
+func TestRecord_RejectsUnknownAction(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	err := f.svc.Record(&models.Record{Accion: "leer", Entidad: "paciente"})
+	require.ErrorIs(t, err, appErr.ErrInvalidInput)
+}
+
+// -----------------------------------------------------------------------------
+// Access log
+// -----------------------------------------------------------------------------
+
+func TestRecordAccess(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().CreateAccess(gomock.Any()).DoAndReturn(func(a *models.Access) error {
+		require.Equal(t, 12, a.PacienteID)
+		require.Equal(t, models.ResourceExamFile, a.Recurso)
+		require.Nil(t, a.Motivo, "no purpose declared")
+		return nil
+	})
+
+	err := f.svc.RecordAccess(&models.AccessRecord{
+		UsuarioID:  intPtr(7),
+		PacienteID: 12,
+		Recurso:    models.ResourceExamFile,
+		RecursoID:  intPtr(40),
+		Ruta:       "GET /api/exams/:id/file",
+	})
+	require.NoError(t, err)
+
+	err = f.svc.RecordAccess(&models.AccessRecord{Recurso: models.ResourceExamFile})
+	require.ErrorIs(t, err, appErr.ErrInvalidInput, "an access needs its patient")
+}
+
+func TestPatientAccess_FiltersByPatient(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	opts := query.Options{Limit: 20, Page: 1}
+	f.repo.EXPECT().ListAccess(gomock.Any()).DoAndReturn(func(got query.Options) ([]models.Access, int, error) {
+		require.Equal(t, []query.Filter{{Field: "paciente_id", Type: query.Int, Value: 12}}, got.Filters)
+		return []models.Access{{ID: 1, PacienteID: 12}}, 1, nil
+	})
+
+	accesses, total, err := f.svc.PatientAccess(12, opts)
+	require.NoError(t, err)
+	require.Len(t, accesses, 1)
+	require.Equal(t, 1, total)
+}
+
+func TestUnusualAccess_Defaults(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().UnusualAccess(gomock.Any()).DoAndReturn(func(q models.UnusualAccessQuery) ([]models.UnusualAccess, error) {
+		require.Equal(t, audit.DefaultUnusualMinimum, q.Minimo)
+		require.Equal(t, audit.DefaultUnusualWindowDays, q.VentanaDias)
+		require.Equal(t, q.Hasta.AddDate(0, 0, -audit.DefaultUnusualPeriodDays), q.Desde)
+		return []models.UnusualAccess{}, nil
+	})
+
+	_, err := f.svc.UnusualAccess(models.UnusualAccessQuery{})
+	require.NoError(t, err)
+}
+
+func TestUnusualAccess_RejectsInvertedPeriod(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	now := time.Now()
+	_, err := f.svc.UnusualAccess(models.UnusualAccessQuery{Desde: now, Hasta: now.AddDate(0, 0, -1)})
+	require.True(t, appErr.IsDomainError(err))
+}
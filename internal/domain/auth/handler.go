@@ -2,7 +2,9 @@ package auth
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 	"github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
@@ -24,9 +26,46 @@ func NewHandler(s Service) *Handler {
 // The route group will have error-handling middleware attached externally (via routes.go).
 func (h *Handler) RegisterRoutes(g *echo.Group) {
 	authGroup := g.Group("/auth", ErrorMiddleware())
-	authGroup.POST("/register", h.Register, middleware.RequirePermission("manejar-usuarios"))
+	authGroup.POST("/register", h.Register, middleware.RequirePermission("manejar-usuarios"), middleware.Audit("usuario", "", nil))
 	authGroup.POST("/login", h.Login)
+	authGroup.POST("/refresh", h.Refresh)
+	authGroup.POST("/logout", h.Logout)
 	authGroup.POST("/change-password", h.ChangePassword, middleware.RequireAuth())
+
+	// --- Sessions (admin) ---
+	authGroup.GET("/users/:id/sessions", h.ListSessions, middleware.RequirePermission("manejar-usuarios"))
+	authGroup.DELETE("/users/:id/sessions/:sessionId", h.RevokeSession, middleware.RequirePermission("manejar-usuarios"), middleware.Audit("usuario", "id", nil))
+	authGroup.DELETE("/users/:id/sessions", h.RevokeAllSessions, middleware.RequirePermission("manejar-usuarios"), middleware.Audit("usuario", "id", nil))
+
+	// --- Two-step verification ---
+	authGroup.POST("/mfa/verify", h.VerifyMFA)
+	authGroup.POST("/mfa/pending/enroll", h.BeginPendingEnrollment)
+	authGroup.GET("/mfa", h.MFAStatus, middleware.RequireAuth())
+	authGroup.POST("/mfa/enroll", h.BeginEnrollment, middleware.RequireAuth())
+	authGroup.POST("/mfa/confirm", h.ConfirmEnrollment, middleware.RequireAuth())
+	authGroup.POST("/mfa/recovery-codes", h.RegenerateRecoveryCodes, middleware.RequireAuth())
+	authGroup.POST("/mfa/disable", h.DisableMFA, middleware.RequireAuth())
+	authGroup.DELETE("/users/:id/mfa", h.ResetMFA, middleware.RequirePermission("manejar-usuarios"), middleware.Audit("usuario", "id", nil))
+
+	// --- Password reset and invitations ---
+	authGroup.POST("/password/forgot", h.ForgotPassword)
+	authGroup.POST("/password/reset", h.ResetPassword)
+	authGroup.POST("/invitations", h.Invite, middleware.RequirePermission("manejar-usuarios"), middleware.Audit("usuario", "", nil))
+	authGroup.POST("/invitations/accept", h.AcceptInvitation)
+	authGroup.POST("/users/:id/invitation", h.ResendInvitation, middleware.RequirePermission("manejar-usuarios"), middleware.Audit("usuario", "id", nil))
+
+	// --- Lockouts (admin) ---
+	authGroup.GET("/lockouts", h.ListLockouts, middleware.RequirePermission("manejar-usuarios"))
+	authGroup.DELETE("/users/:id/lockout", h.Unlock, middleware.RequirePermission("manejar-usuarios"), middleware.Audit("usuario", "id", nil))
+
+	// --- Signing keys (admin) ---
+	authGroup.POST("/keys/rotate", h.RotateKeys, middleware.RequirePermission("manejar-usuarios"))
+}
+
+// RegisterWellKnownRoutes mounts /.well-known/jwks.json at the root, where JWT
+// libraries look for it.
+func (h *Handler) RegisterWellKnownRoutes(e *echo.Echo) {
+	e.GET("/.well-known/jwks.json", h.JWKS)
 }
 
 // -----------------------------------------------------------------------------
@@ -60,13 +99,57 @@ func (h *Handler) Login(c echo.Context) error {
 		return appErr.Wrap("Auth.Login.Bind", appErr.ErrInvalidRequest, err)
 	}
 
-	token, err := h.service.Login(req.Identifier, req.Password)
+	client := authModels.Client{IP: c.RealIP(), UserAgent: c.Request().UserAgent()}
+	result, err := h.service.Login(req.Identifier, req.Password, client)
 	if err != nil {
+		setRetryAfter(c, err)
 		return err // handled by middleware
 	}
 
+	return c.JSON(http.StatusOK, result)
+}
+
+// setRetryAfter tells a locked out client when to try again
+func setRetryAfter(c echo.Context, err error) {
+	var locked *authModels.LockedError
+	if errors.As(err, &locked) {
+		retry := int(math.Ceil(locked.RetryAfter.Seconds()))
+		c.Response().Header().Set("Retry-After", strconv.Itoa(max(retry, 1)))
+	}
+}
+
+// -----------------------------------------------------------------------------
+// POST /auth/refresh
+// -----------------------------------------------------------------------------
+func (h *Handler) Refresh(c echo.Context) error {
+	var req authModels.RefreshRequest
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Auth.Refresh.Bind", appErr.ErrInvalidRequest, err)
+	}
+
+	tokens, err := h.service.Refresh(req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, tokens)
+}
+
+// -----------------------------------------------------------------------------
+// POST /auth/logout
+// -----------------------------------------------------------------------------
+func (h *Handler) Logout(c echo.Context) error {
+	var req authModels.RefreshRequest
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Auth.Logout.Bind", appErr.ErrInvalidRequest, err)
+	}
+
+	if err := h.service.Logout(req.RefreshToken); err != nil {
+		return err
+	}
+
 	return c.JSON(http.StatusOK, echo.Map{
-		"token": token,
+		"message": "Sesión cerrada correctamente",
 	})
 }
 
@@ -81,7 +164,7 @@ func (h *Handler) ChangePassword(c echo.Context) error {
 		return appErr.Wrap("Invalid claims", appErr.ErrUnauthorized, errors.New("Invalid claims"))
 	}
 
-	if err := h.service.ChangePassword(claims.UserID, req.OldPassword, req.NewPassword); err != nil {
+	if err := h.service.ChangePassword(claims.UserID, claims.SessionID, req.OldPassword, req.NewPassword); err != nil {
 		return err // service already wrapped errors
 	}
 
@@ -89,3 +172,331 @@ func (h *Handler) ChangePassword(c echo.Context) error {
 		"message": "Contraseña actualizada correctamente",
 	})
 }
+
+// -----------------------------------------------------------------------------
+// Sessions
+// -----------------------------------------------------------------------------
+
+// GET /auth/users/:id/sessions
+func (h *Handler) ListSessions(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.Wrap("Auth.ListSessions.ParseID", appErr.ErrInvalidInput, err)
+	}
+
+	sessions, err := h.service.ListSessions(userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, sessions)
+}
+
+// DELETE /auth/users/:id/sessions/:sessionId
+func (h *Handler) RevokeSession(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.Wrap("Auth.RevokeSession.ParseID", appErr.ErrInvalidInput, err)
+	}
+
+	if err := h.service.RevokeSession(userID, c.Param("sessionId")); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Sesión revocada correctamente",
+	})
+}
+
+// DELETE /auth/users/:id/sessions
+func (h *Handler) RevokeAllSessions(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.Wrap("Auth.RevokeAllSessions.ParseID", appErr.ErrInvalidInput, err)
+	}
+
+	if err := h.service.RevokeAllSessions(userID); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Sesiones revocadas correctamente",
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Lockouts
+// -----------------------------------------------------------------------------
+
+// GET /auth/lockouts?all=true
+func (h *Handler) ListLockouts(c echo.Context) error {
+	all, _ := strconv.ParseBool(c.QueryParam("all"))
+
+	lockouts, err := h.service.ListLockouts(all)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, lockouts)
+}
+
+// DELETE /auth/users/:id/lockout
+func (h *Handler) Unlock(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.Wrap("Auth.Unlock.ParseID", appErr.ErrInvalidInput, err)
+	}
+
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return appErr.Wrap("Invalid claims", appErr.ErrUnauthorized, errors.New("Invalid claims"))
+	}
+
+	if err := h.service.Unlock(userID, claims.UserID); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Cuenta desbloqueada correctamente",
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Two-step verification
+// -----------------------------------------------------------------------------
+
+// POST /auth/mfa/verify
+func (h *Handler) VerifyMFA(c echo.Context) error {
+	var req authModels.MFAVerifyRequest
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Auth.VerifyMFA.Bind", appErr.ErrInvalidRequest, err)
+	}
+
+	client := authModels.Client{IP: c.RealIP(), UserAgent: c.Request().UserAgent()}
+	result, err := h.service.VerifyMFA(req.MFAToken, req.Codigo, client)
+	if err != nil {
+		setRetryAfter(c, err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// POST /auth/mfa/pending/enroll
+func (h *Handler) BeginPendingEnrollment(c echo.Context) error {
+	var req authModels.MFATokenRequest
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Auth.BeginPendingEnrollment.Bind", appErr.ErrInvalidRequest, err)
+	}
+
+	enrollment, err := h.service.BeginPendingEnrollment(req.MFAToken)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, enrollment)
+}
+
+// GET /auth/mfa
+func (h *Handler) MFAStatus(c echo.Context) error {
+	status, err := h.service.MFAStatus(middleware.GetClaims(c).UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, status)
+}
+
+// POST /auth/mfa/enroll
+func (h *Handler) BeginEnrollment(c echo.Context) error {
+	enrollment, err := h.service.BeginEnrollment(middleware.GetClaims(c).UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, enrollment)
+}
+
+// POST /auth/mfa/confirm
+func (h *Handler) ConfirmEnrollment(c echo.Context) error {
+	var req authModels.MFACodeRequest
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Auth.ConfirmEnrollment.Bind", appErr.ErrInvalidRequest, err)
+	}
+
+	codes, err := h.service.ConfirmEnrollment(middleware.GetClaims(c).UserID, req.Codigo)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message":              "Verificación en dos pasos activada. Guarde los códigos de recuperación.",
+		"codigos_recuperacion": codes,
+	})
+}
+
+// POST /auth/mfa/recovery-codes
+func (h *Handler) RegenerateRecoveryCodes(c echo.Context) error {
+	var req authModels.MFACodeRequest
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Auth.RegenerateRecoveryCodes.Bind", appErr.ErrInvalidRequest, err)
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(middleware.GetClaims(c).UserID, req.Codigo)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"codigos_recuperacion": codes})
+}
+
+// POST /auth/mfa/disable
+func (h *Handler) DisableMFA(c echo.Context) error {
+	var req authModels.MFACodeRequest
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Auth.DisableMFA.Bind", appErr.ErrInvalidRequest, err)
+	}
+
+	if err := h.service.DisableMFA(middleware.GetClaims(c).UserID, req.Codigo); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Verificación en dos pasos desactivada",
+	})
+}
+
+// DELETE /auth/users/:id/mfa
+func (h *Handler) ResetMFA(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.Wrap("Auth.ResetMFA.ParseID", appErr.ErrInvalidInput, err)
+	}
+
+	if err := h.service.ResetMFA(userID); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Verificación en dos pasos restablecida correctamente",
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Password reset and invitations
+// -----------------------------------------------------------------------------
+
+// POST /auth/password/forgot
+func (h *Handler) ForgotPassword(c echo.Context) error {
+	var req authModels.ForgotPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Auth.ForgotPassword.Bind", appErr.ErrInvalidRequest, err)
+	}
+
+	if err := h.service.ForgotPassword(req.Identifier); err != nil {
+		return err
+	}
+
+	// Same answer whether the account exists or not
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Si la cuenta existe, recibirá un enlace para restablecer la contraseña",
+	})
+}
+
+// POST /auth/password/reset
+func (h *Handler) ResetPassword(c echo.Context) error {
+	var req authModels.SetPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Auth.ResetPassword.Bind", appErr.ErrInvalidRequest, err)
+	}
+
+	if err := h.service.ResetPassword(req.Token, req.Password); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Contraseña restablecida correctamente",
+	})
+}
+
+// POST /auth/invitations
+func (h *Handler) Invite(c echo.Context) error {
+	var req authModels.InvitationRequest
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Auth.Invite.Bind", appErr.ErrInvalidRequest, err)
+	}
+
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return appErr.Wrap("Invalid claims", appErr.ErrUnauthorized, errors.New("Invalid claims"))
+	}
+
+	id, err := h.service.Invite(claims.UserID, req.Username, req.Email, req.RoleIDs)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, echo.Map{
+		"id":      id,
+		"message": "Invitación enviada correctamente",
+	})
+}
+
+// POST /auth/users/:id/invitation
+func (h *Handler) ResendInvitation(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.Wrap("Auth.ResendInvitation.ParseID", appErr.ErrInvalidInput, err)
+	}
+
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return appErr.Wrap("Invalid claims", appErr.ErrUnauthorized, errors.New("Invalid claims"))
+	}
+
+	if err := h.service.ResendInvitation(claims.UserID, userID); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Invitación reenviada correctamente",
+	})
+}
+
+// POST /auth/invitations/accept
+func (h *Handler) AcceptInvitation(c echo.Context) error {
+	var req authModels.SetPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Auth.AcceptInvitation.Bind", appErr.ErrInvalidRequest, err)
+	}
+
+	if err := h.service.AcceptInvitation(req.Token, req.Password); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Cuenta activada correctamente. Ya puede iniciar sesión",
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Signing keys
+// -----------------------------------------------------------------------------
+
+// GET /.well-known/jwks.json
+func (h *Handler) JWKS(c echo.Context) error {
+	// The next key is published a day before it signs; an hour of cache is enough
+	c.Response().Header().Set("Cache-Control", "public, max-age=3600")
+	return c.JSON(http.StatusOK, h.service.JWKS())
+}
+
+// POST /auth/keys/rotate
+func (h *Handler) RotateKeys(c echo.Context) error {
+	if err := h.service.RotateKeys(true); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Clave de firma rotada correctamente",
+	})
+}
@@ -0,0 +1,567 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	database "github.com/tonitomc/healthcare-crm-api/internal/database"
+	auth "github.com/tonitomc/healthcare-crm-api/internal/domain/auth"
+	models "github.com/tonitomc/healthcare-crm-api/internal/domain/auth/models"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddPasswordHistory mocks base method.
+func (m *MockRepository) AddPasswordHistory(userID int, passwordHash string, keep int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPasswordHistory", userID, passwordHash, keep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPasswordHistory indicates an expected call of AddPasswordHistory.
+func (mr *MockRepositoryMockRecorder) AddPasswordHistory(userID, passwordHash, keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPasswordHistory", reflect.TypeOf((*MockRepository)(nil).AddPasswordHistory), userID, passwordHash, keep)
+}
+
+// ConfirmMFA mocks base method.
+func (m *MockRepository) ConfirmMFA(userID int, step int64, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmMFA", userID, step, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmMFA indicates an expected call of ConfirmMFA.
+func (mr *MockRepositoryMockRecorder) ConfirmMFA(userID, step, codeHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmMFA", reflect.TypeOf((*MockRepository)(nil).ConfirmMFA), userID, step, codeHashes)
+}
+
+// CountRecoveryCodes mocks base method.
+func (m *MockRepository) CountRecoveryCodes(userID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRecoveryCodes", userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRecoveryCodes indicates an expected call of CountRecoveryCodes.
+func (mr *MockRepositoryMockRecorder) CountRecoveryCodes(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRecoveryCodes", reflect.TypeOf((*MockRepository)(nil).CountRecoveryCodes), userID)
+}
+
+// CountUserTokens mocks base method.
+func (m *MockRepository) CountUserTokens(userID int, kind string, since time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserTokens", userID, kind, since)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserTokens indicates an expected call of CountUserTokens.
+func (mr *MockRepositoryMockRecorder) CountUserTokens(userID, kind, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserTokens", reflect.TypeOf((*MockRepository)(nil).CountUserTokens), userID, kind, since)
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockRepository) CreateRefreshToken(t *models.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockRepositoryMockRecorder) CreateRefreshToken(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockRepository)(nil).CreateRefreshToken), t)
+}
+
+// CreateSession mocks base method.
+func (m *MockRepository) CreateSession(s *models.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockRepositoryMockRecorder) CreateSession(s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockRepository)(nil).CreateSession), s)
+}
+
+// CreateSigningKey mocks base method.
+func (m *MockRepository) CreateSigningKey(k *models.SigningKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSigningKey", k)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSigningKey indicates an expected call of CreateSigningKey.
+func (mr *MockRepositoryMockRecorder) CreateSigningKey(k interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSigningKey", reflect.TypeOf((*MockRepository)(nil).CreateSigningKey), k)
+}
+
+// CreateUserToken mocks base method.
+func (m *MockRepository) CreateUserToken(t *models.UserToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserToken", t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUserToken indicates an expected call of CreateUserToken.
+func (mr *MockRepositoryMockRecorder) CreateUserToken(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserToken", reflect.TypeOf((*MockRepository)(nil).CreateUserToken), t)
+}
+
+// DeleteMFA mocks base method.
+func (m *MockRepository) DeleteMFA(userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMFA", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMFA indicates an expected call of DeleteMFA.
+func (mr *MockRepositoryMockRecorder) DeleteMFA(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMFA", reflect.TypeOf((*MockRepository)(nil).DeleteMFA), userID)
+}
+
+// ExtendSession mocks base method.
+func (m *MockRepository) ExtendSession(id string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendSession", id, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExtendSession indicates an expected call of ExtendSession.
+func (mr *MockRepositoryMockRecorder) ExtendSession(id, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendSession", reflect.TypeOf((*MockRepository)(nil).ExtendSession), id, expiresAt)
+}
+
+// GetLoginCounter mocks base method.
+func (m *MockRepository) GetLoginCounter(key string) (*models.LoginCounter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginCounter", key)
+	ret0, _ := ret[0].(*models.LoginCounter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginCounter indicates an expected call of GetLoginCounter.
+func (mr *MockRepositoryMockRecorder) GetLoginCounter(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginCounter", reflect.TypeOf((*MockRepository)(nil).GetLoginCounter), key)
+}
+
+// GetMFA mocks base method.
+func (m *MockRepository) GetMFA(userID int) (*models.MFA, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMFA", userID)
+	ret0, _ := ret[0].(*models.MFA)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMFA indicates an expected call of GetMFA.
+func (mr *MockRepositoryMockRecorder) GetMFA(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMFA", reflect.TypeOf((*MockRepository)(nil).GetMFA), userID)
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockRepository) GetRefreshToken(hash string) (*models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", hash)
+	ret0, _ := ret[0].(*models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockRepositoryMockRecorder) GetRefreshToken(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockRepository)(nil).GetRefreshToken), hash)
+}
+
+// GetSession mocks base method.
+func (m *MockRepository) GetSession(id string) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", id)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockRepositoryMockRecorder) GetSession(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockRepository)(nil).GetSession), id)
+}
+
+// GetUserToken mocks base method.
+func (m *MockRepository) GetUserToken(tokenHash string) (*models.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserToken", tokenHash)
+	ret0, _ := ret[0].(*models.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserToken indicates an expected call of GetUserToken.
+func (mr *MockRepositoryMockRecorder) GetUserToken(tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserToken", reflect.TypeOf((*MockRepository)(nil).GetUserToken), tokenHash)
+}
+
+// InvalidateUserTokens mocks base method.
+func (m *MockRepository) InvalidateUserTokens(userID int, kind string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateUserTokens", userID, kind)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateUserTokens indicates an expected call of InvalidateUserTokens.
+func (mr *MockRepositoryMockRecorder) InvalidateUserTokens(userID, kind interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateUserTokens", reflect.TypeOf((*MockRepository)(nil).InvalidateUserTokens), userID, kind)
+}
+
+// ListLockouts mocks base method.
+func (m *MockRepository) ListLockouts(all bool) ([]models.Lockout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLockouts", all)
+	ret0, _ := ret[0].([]models.Lockout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLockouts indicates an expected call of ListLockouts.
+func (mr *MockRepositoryMockRecorder) ListLockouts(all interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLockouts", reflect.TypeOf((*MockRepository)(nil).ListLockouts), all)
+}
+
+// ListSessions mocks base method.
+func (m *MockRepository) ListSessions(userID int) ([]models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", userID)
+	ret0, _ := ret[0].([]models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockRepositoryMockRecorder) ListSessions(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockRepository)(nil).ListSessions), userID)
+}
+
+// ListSigningKeys mocks base method.
+func (m *MockRepository) ListSigningKeys() ([]models.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSigningKeys")
+	ret0, _ := ret[0].([]models.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSigningKeys indicates an expected call of ListSigningKeys.
+func (mr *MockRepositoryMockRecorder) ListSigningKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSigningKeys", reflect.TypeOf((*MockRepository)(nil).ListSigningKeys))
+}
+
+// Lock mocks base method.
+func (m *MockRepository) Lock(lockout *models.Lockout) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", lockout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockRepositoryMockRecorder) Lock(lockout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockRepository)(nil).Lock), lockout)
+}
+
+// MarkRefreshTokenUsed mocks base method.
+func (m *MockRepository) MarkRefreshTokenUsed(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRefreshTokenUsed", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRefreshTokenUsed indicates an expected call of MarkRefreshTokenUsed.
+func (mr *MockRepositoryMockRecorder) MarkRefreshTokenUsed(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRefreshTokenUsed", reflect.TypeOf((*MockRepository)(nil).MarkRefreshTokenUsed), id)
+}
+
+// RecentPasswordHashes mocks base method.
+func (m *MockRepository) RecentPasswordHashes(userID, n int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecentPasswordHashes", userID, n)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecentPasswordHashes indicates an expected call of RecentPasswordHashes.
+func (mr *MockRepositoryMockRecorder) RecentPasswordHashes(userID, n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecentPasswordHashes", reflect.TypeOf((*MockRepository)(nil).RecentPasswordHashes), userID, n)
+}
+
+// RecordLoginFailure mocks base method.
+func (m *MockRepository) RecordLoginFailure(key string, window time.Duration) (*models.LoginCounter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginFailure", key, window)
+	ret0, _ := ret[0].(*models.LoginCounter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockRepositoryMockRecorder) RecordLoginFailure(key, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockRepository)(nil).RecordLoginFailure), key, window)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockRepository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", userID, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockRepositoryMockRecorder) ReplaceRecoveryCodes(userID, codeHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockRepository)(nil).ReplaceRecoveryCodes), userID, codeHashes)
+}
+
+// ResetLoginCounter mocks base method.
+func (m *MockRepository) ResetLoginCounter(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetLoginCounter", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetLoginCounter indicates an expected call of ResetLoginCounter.
+func (mr *MockRepositoryMockRecorder) ResetLoginCounter(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetLoginCounter", reflect.TypeOf((*MockRepository)(nil).ResetLoginCounter), key)
+}
+
+// RetireSigningKey mocks base method.
+func (m *MockRepository) RetireSigningKey(kid string, signUntil, verifyUntil time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireSigningKey", kid, signUntil, verifyUntil)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetireSigningKey indicates an expected call of RetireSigningKey.
+func (mr *MockRepositoryMockRecorder) RetireSigningKey(kid, signUntil, verifyUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireSigningKey", reflect.TypeOf((*MockRepository)(nil).RetireSigningKey), kid, signUntil, verifyUntil)
+}
+
+// RevokeSession mocks base method.
+func (m *MockRepository) RevokeSession(id, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockRepositoryMockRecorder) RevokeSession(id, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockRepository)(nil).RevokeSession), id, reason)
+}
+
+// RevokeUserSessions mocks base method.
+func (m *MockRepository) RevokeUserSessions(userID int, keep, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSessions", userID, keep, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserSessions indicates an expected call of RevokeUserSessions.
+func (mr *MockRepositoryMockRecorder) RevokeUserSessions(userID, keep, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessions", reflect.TypeOf((*MockRepository)(nil).RevokeUserSessions), userID, keep, reason)
+}
+
+// SaveMFASecret mocks base method.
+func (m *MockRepository) SaveMFASecret(userID int, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMFASecret", userID, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMFASecret indicates an expected call of SaveMFASecret.
+func (mr *MockRepositoryMockRecorder) SaveMFASecret(userID, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMFASecret", reflect.TypeOf((*MockRepository)(nil).SaveMFASecret), userID, secret)
+}
+
+// UnlockUser mocks base method.
+func (m *MockRepository) UnlockUser(userID, unlockedBy int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockUser", userID, unlockedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockUser indicates an expected call of UnlockUser.
+func (mr *MockRepositoryMockRecorder) UnlockUser(userID, unlockedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUser", reflect.TypeOf((*MockRepository)(nil).UnlockUser), userID, unlockedBy)
+}
+
+// UseMFAStep mocks base method.
+func (m *MockRepository) UseMFAStep(userID int, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseMFAStep", userID, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseMFAStep indicates an expected call of UseMFAStep.
+func (mr *MockRepositoryMockRecorder) UseMFAStep(userID, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseMFAStep", reflect.TypeOf((*MockRepository)(nil).UseMFAStep), userID, step)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockRepository) UseRecoveryCode(userID int, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", userID, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockRepositoryMockRecorder) UseRecoveryCode(userID, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockRepository)(nil).UseRecoveryCode), userID, codeHash)
+}
+
+// UseUserToken mocks base method.
+func (m *MockRepository) UseUserToken(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseUserToken", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseUserToken indicates an expected call of UseUserToken.
+func (mr *MockRepositoryMockRecorder) UseUserToken(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseUserToken", reflect.TypeOf((*MockRepository)(nil).UseUserToken), id)
+}
+
+// WithTx mocks base method.
+func (m *MockRepository) WithTx(tx database.DBTX) auth.Repository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(auth.Repository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRepositoryMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepository)(nil).WithTx), tx)
+}
+
+// Mockscanner is a mock of scanner interface.
+type Mockscanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockscannerMockRecorder
+}
+
+// MockscannerMockRecorder is the mock recorder for Mockscanner.
+type MockscannerMockRecorder struct {
+	mock *Mockscanner
+}
+
+// NewMockscanner creates a new mock instance.
+func NewMockscanner(ctrl *gomock.Controller) *Mockscanner {
+	mock := &Mockscanner{ctrl: ctrl}
+	mock.recorder = &MockscannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockscanner) EXPECT() *MockscannerMockRecorder {
+	return m.recorder
+}
+
+// Scan mocks base method.
+func (m *Mockscanner) Scan(dest ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range dest {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Scan", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockscannerMockRecorder) Scan(dest ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*Mockscanner)(nil).Scan), dest...)
+}
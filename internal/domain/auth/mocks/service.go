@@ -0,0 +1,481 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/tonitomc/healthcare-crm-api/internal/domain/auth/models"
+	jwtkeys "github.com/tonitomc/healthcare-crm-api/pkg/jwtkeys"
+)
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(n models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", n)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), n)
+}
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// AcceptInvitation mocks base method.
+func (m *MockService) AcceptInvitation(token, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvitation", token, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptInvitation indicates an expected call of AcceptInvitation.
+func (mr *MockServiceMockRecorder) AcceptInvitation(token, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockService)(nil).AcceptInvitation), token, newPassword)
+}
+
+// BeginEnrollment mocks base method.
+func (m *MockService) BeginEnrollment(userID int) (*models.Enrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginEnrollment", userID)
+	ret0, _ := ret[0].(*models.Enrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginEnrollment indicates an expected call of BeginEnrollment.
+func (mr *MockServiceMockRecorder) BeginEnrollment(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginEnrollment", reflect.TypeOf((*MockService)(nil).BeginEnrollment), userID)
+}
+
+// BeginPendingEnrollment mocks base method.
+func (m *MockService) BeginPendingEnrollment(mfaToken string) (*models.Enrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginPendingEnrollment", mfaToken)
+	ret0, _ := ret[0].(*models.Enrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginPendingEnrollment indicates an expected call of BeginPendingEnrollment.
+func (mr *MockServiceMockRecorder) BeginPendingEnrollment(mfaToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginPendingEnrollment", reflect.TypeOf((*MockService)(nil).BeginPendingEnrollment), mfaToken)
+}
+
+// ChangePassword mocks base method.
+func (m *MockService) ChangePassword(userID int, sessionID, oldPassword, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", userID, sessionID, oldPassword, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockServiceMockRecorder) ChangePassword(userID, sessionID, oldPassword, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockService)(nil).ChangePassword), userID, sessionID, oldPassword, newPassword)
+}
+
+// ConfirmEnrollment mocks base method.
+func (m *MockService) ConfirmEnrollment(userID int, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEnrollment", userID, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmEnrollment indicates an expected call of ConfirmEnrollment.
+func (mr *MockServiceMockRecorder) ConfirmEnrollment(userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEnrollment", reflect.TypeOf((*MockService)(nil).ConfirmEnrollment), userID, code)
+}
+
+// DisableMFA mocks base method.
+func (m *MockService) DisableMFA(userID int, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableMFA", userID, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableMFA indicates an expected call of DisableMFA.
+func (mr *MockServiceMockRecorder) DisableMFA(userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMFA", reflect.TypeOf((*MockService)(nil).DisableMFA), userID, code)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockService) ForgotPassword(identifier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", identifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockServiceMockRecorder) ForgotPassword(identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockService)(nil).ForgotPassword), identifier)
+}
+
+// Invite mocks base method.
+func (m *MockService) Invite(adminID int, username, email string, roleIDs []int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invite", adminID, username, email, roleIDs)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Invite indicates an expected call of Invite.
+func (mr *MockServiceMockRecorder) Invite(adminID, username, email, roleIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invite", reflect.TypeOf((*MockService)(nil).Invite), adminID, username, email, roleIDs)
+}
+
+// IsSessionActive mocks base method.
+func (m *MockService) IsSessionActive(sessionID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSessionActive", sessionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsSessionActive indicates an expected call of IsSessionActive.
+func (mr *MockServiceMockRecorder) IsSessionActive(sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSessionActive", reflect.TypeOf((*MockService)(nil).IsSessionActive), sessionID)
+}
+
+// JWKS mocks base method.
+func (m *MockService) JWKS() jwtkeys.JWKS {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(jwtkeys.JWKS)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockServiceMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockService)(nil).JWKS))
+}
+
+// Keyfunc mocks base method.
+func (m *MockService) Keyfunc(t *jwt.Token) (any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keyfunc", t)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Keyfunc indicates an expected call of Keyfunc.
+func (mr *MockServiceMockRecorder) Keyfunc(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keyfunc", reflect.TypeOf((*MockService)(nil).Keyfunc), t)
+}
+
+// ListLockouts mocks base method.
+func (m *MockService) ListLockouts(all bool) ([]models.Lockout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLockouts", all)
+	ret0, _ := ret[0].([]models.Lockout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLockouts indicates an expected call of ListLockouts.
+func (mr *MockServiceMockRecorder) ListLockouts(all interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLockouts", reflect.TypeOf((*MockService)(nil).ListLockouts), all)
+}
+
+// ListSessions mocks base method.
+func (m *MockService) ListSessions(userID int) ([]models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", userID)
+	ret0, _ := ret[0].([]models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockServiceMockRecorder) ListSessions(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockService)(nil).ListSessions), userID)
+}
+
+// Login mocks base method.
+func (m *MockService) Login(identifier, password string, client models.Client) (*models.LoginResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", identifier, password, client)
+	ret0, _ := ret[0].(*models.LoginResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockServiceMockRecorder) Login(identifier, password, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockService)(nil).Login), identifier, password, client)
+}
+
+// Logout mocks base method.
+func (m *MockService) Logout(refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockServiceMockRecorder) Logout(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockService)(nil).Logout), refreshToken)
+}
+
+// MFAStatus mocks base method.
+func (m *MockService) MFAStatus(userID int) (*models.MFAStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MFAStatus", userID)
+	ret0, _ := ret[0].(*models.MFAStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MFAStatus indicates an expected call of MFAStatus.
+func (mr *MockServiceMockRecorder) MFAStatus(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MFAStatus", reflect.TypeOf((*MockService)(nil).MFAStatus), userID)
+}
+
+// Refresh mocks base method.
+func (m *MockService) Refresh(refreshToken string) (*models.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", refreshToken)
+	ret0, _ := ret[0].(*models.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockServiceMockRecorder) Refresh(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockService)(nil).Refresh), refreshToken)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockService) RegenerateRecoveryCodes(userID int, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", userID, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockServiceMockRecorder) RegenerateRecoveryCodes(userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockService)(nil).RegenerateRecoveryCodes), userID, code)
+}
+
+// Register mocks base method.
+func (m *MockService) Register(username, email, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", username, email, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockServiceMockRecorder) Register(username, email, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockService)(nil).Register), username, email, password)
+}
+
+// ResendInvitation mocks base method.
+func (m *MockService) ResendInvitation(adminID, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendInvitation", adminID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendInvitation indicates an expected call of ResendInvitation.
+func (mr *MockServiceMockRecorder) ResendInvitation(adminID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendInvitation", reflect.TypeOf((*MockService)(nil).ResendInvitation), adminID, userID)
+}
+
+// ResetMFA mocks base method.
+func (m *MockService) ResetMFA(userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetMFA", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetMFA indicates an expected call of ResetMFA.
+func (mr *MockServiceMockRecorder) ResetMFA(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetMFA", reflect.TypeOf((*MockService)(nil).ResetMFA), userID)
+}
+
+// ResetPassword mocks base method.
+func (m *MockService) ResetPassword(token, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", token, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockServiceMockRecorder) ResetPassword(token, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockService)(nil).ResetPassword), token, newPassword)
+}
+
+// RevokeAllSessions mocks base method.
+func (m *MockService) RevokeAllSessions(userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllSessions", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllSessions indicates an expected call of RevokeAllSessions.
+func (mr *MockServiceMockRecorder) RevokeAllSessions(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllSessions", reflect.TypeOf((*MockService)(nil).RevokeAllSessions), userID)
+}
+
+// RevokeSession mocks base method.
+func (m *MockService) RevokeSession(userID int, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockServiceMockRecorder) RevokeSession(userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockService)(nil).RevokeSession), userID, sessionID)
+}
+
+// RotateKeys mocks base method.
+func (m *MockService) RotateKeys(force bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateKeys", force)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateKeys indicates an expected call of RotateKeys.
+func (mr *MockServiceMockRecorder) RotateKeys(force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateKeys", reflect.TypeOf((*MockService)(nil).RotateKeys), force)
+}
+
+// Unlock mocks base method.
+func (m *MockService) Unlock(userID, adminID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", userID, adminID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockServiceMockRecorder) Unlock(userID, adminID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockService)(nil).Unlock), userID, adminID)
+}
+
+// ValidateToken mocks base method.
+func (m *MockService) ValidateToken(tokenStr string) (*jwt.Token, *models.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateToken", tokenStr)
+	ret0, _ := ret[0].(*jwt.Token)
+	ret1, _ := ret[1].(*models.Claims)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ValidateToken indicates an expected call of ValidateToken.
+func (mr *MockServiceMockRecorder) ValidateToken(tokenStr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockService)(nil).ValidateToken), tokenStr)
+}
+
+// VerifyMFA mocks base method.
+func (m *MockService) VerifyMFA(mfaToken, code string, client models.Client) (*models.LoginResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMFA", mfaToken, code, client)
+	ret0, _ := ret[0].(*models.LoginResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyMFA indicates an expected call of VerifyMFA.
+func (mr *MockServiceMockRecorder) VerifyMFA(mfaToken, code, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMFA", reflect.TypeOf((*MockService)(nil).VerifyMFA), mfaToken, code, client)
+}
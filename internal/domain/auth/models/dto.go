@@ -17,3 +17,42 @@ type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password"`
 	NewPassword string `json:"new_password"`
 }
+
+// RefreshRequest is the body of /auth/refresh and /auth/logout
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// MFATokenRequest carries the token login returned when a second step is needed
+type MFATokenRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+// MFAVerifyRequest is the body of /auth/mfa/verify; the code is a TOTP or a recovery code
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Codigo   string `json:"codigo" validate:"required"`
+}
+
+// MFACodeRequest confirms a sensitive MFA change with a current code
+type MFACodeRequest struct {
+	Codigo string `json:"codigo" validate:"required"`
+}
+
+// ForgotPasswordRequest is the body of /auth/password/forgot
+type ForgotPasswordRequest struct {
+	Identifier string `json:"identifier" validate:"required"` // username or email
+}
+
+// SetPasswordRequest sets a password with a reset or invitation token
+type SetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// InvitationRequest is the body of /auth/invitations
+type InvitationRequest struct {
+	Username string `json:"username" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
+	RoleIDs  []int  `json:"roles"`
+}
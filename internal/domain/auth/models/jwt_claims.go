@@ -7,5 +7,6 @@ type Claims struct {
 	Username    string   `json:"username"`
 	Roles       []string `json:"roles"`
 	Permissions []string `json:"permissions"`
+	SessionID   string   `json:"sid"` // session it was issued for; revoking the session invalidates the token
 	jwt.RegisteredClaims
 }
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SigningKey is a stored access token signing key.
+type SigningKey struct {
+	Kid          string
+	Algoritmo    string
+	ClavePrivada string // sealed PKCS#8 PEM
+	FirmaDesde   time.Time
+	FirmaHasta   time.Time // signs until then
+	ExpiraEn     time.Time // verifies until then
+}
+
+// Signs reports whether the key signs new tokens at now.
+func (k *SigningKey) Signs(now time.Time) bool {
+	return !now.Before(k.FirmaDesde) && now.Before(k.FirmaHasta)
+}
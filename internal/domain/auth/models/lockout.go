@@ -0,0 +1,41 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// LoginCounter holds the recent failed logins of an account or an IP.
+type LoginCounter struct {
+	Clave          string
+	Fallos         int
+	UltimoFallo    time.Time
+	BloqueadoHasta *time.Time
+}
+
+// Lockout is one time an account or an IP was locked.
+type Lockout struct {
+	ID              int        `json:"id"`
+	Clave           string     `json:"clave"`
+	UsuarioID       *int       `json:"usuario_id,omitempty"`
+	IP              *string    `json:"ip,omitempty"`
+	Fallos          int        `json:"fallos"`
+	Desde           time.Time  `json:"desde"`
+	Hasta           time.Time  `json:"hasta"`
+	DesbloqueadoEn  *time.Time `json:"desbloqueado_en,omitempty"`
+	DesbloqueadoPor *int       `json:"desbloqueado_por,omitempty"`
+}
+
+// LockedError is returned while logins are blocked for an account or an IP. It is
+// the same whether or not the account exists.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%v: reintente en %s", appErr.ErrTooManyRequests, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LockedError) Unwrap() error { return appErr.ErrTooManyRequests }
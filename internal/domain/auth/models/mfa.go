@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MFAAudience is the audience of MFA tokens, so they are never taken for access tokens
+const MFAAudience = "mfa"
+
+// MFA is the TOTP enrollment of a user.
+type MFA struct {
+	UsuarioID    int
+	Secreto      string // base32
+	ConfirmadoEn *time.Time
+	UltimoPaso   int64 // last accepted time step; a code is never accepted twice
+}
+
+// IsActive reports whether the enrollment was confirmed.
+func (m *MFA) IsActive() bool {
+	return m != nil && m.ConfirmadoEn != nil
+}
+
+// MFAClaims are the claims of the short-lived token login returns when a second
+// step is needed. It only works at the /auth/mfa endpoints that take an mfa_token.
+type MFAClaims struct {
+	UserID int  `json:"user_id"`
+	Enroll bool `json:"enrolar,omitempty"` // the user must enroll before verifying
+	jwt.RegisteredClaims
+}
+
+// LoginResult is the response of login: either the tokens, or an MFA token to
+// exchange at /auth/mfa/verify.
+type LoginResult struct {
+	*TokenPair
+	MFARequired    bool     `json:"mfa_requerido,omitempty"`
+	MFAToken       string   `json:"mfa_token,omitempty"`
+	MFAExpiresIn   int      `json:"mfa_expires_in,omitempty"`
+	EnrollRequired bool     `json:"enrolamiento_requerido,omitempty"`
+	RecoveryCodes  []string `json:"codigos_recuperacion,omitempty"` // shown once, when enrolling at login
+}
+
+// Enrollment is what an authenticator app needs to start generating codes.
+type Enrollment struct {
+	Secreto string `json:"secreto"`
+	URI     string `json:"uri"` // otpauth:// URI, to show as a QR code
+}
+
+// MFAStatus is the two-step verification state of a user.
+type MFAStatus struct {
+	Activo           bool `json:"activo"`
+	Requerido        bool `json:"requerido"` // one of the user's roles requires it
+	CodigosRestantes int  `json:"codigos_recuperacion_restantes"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Reasons a session is revoked
+const (
+	RevokedLogout         = "logout"
+	RevokedByAdmin        = "admin"
+	RevokedReuse          = "reuso"             // an already used refresh token was presented
+	RevokedPasswordChange = "cambio_contrasena" // the other sessions, on password change
+)
+
+// Session is one login and the family of refresh tokens that keeps it alive.
+type Session struct {
+	ID               string     `json:"id"`
+	UsuarioID        int        `json:"usuario_id"`
+	CreadaEn         time.Time  `json:"creada_en"`
+	UltimoUso        time.Time  `json:"ultimo_uso"`
+	ExpiraEn         time.Time  `json:"expira_en"`
+	RevocadaEn       *time.Time `json:"revocada_en,omitempty"`
+	MotivoRevocacion *string    `json:"motivo_revocacion,omitempty"`
+	IP               *string    `json:"ip,omitempty"`
+	UserAgent        *string    `json:"user_agent,omitempty"`
+}
+
+// IsActive reports whether the session can still be used.
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevocadaEn == nil && now.Before(s.ExpiraEn)
+}
+
+// RefreshToken is an issued refresh token; the plain token is never stored.
+type RefreshToken struct {
+	ID        int
+	SesionID  string
+	TokenHash string
+	ExpiraEn  time.Time
+	UsadoEn   *time.Time
+}
+
+// Client identifies where a session is started or refreshed from.
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+// TokenPair is the response of login and refresh.
+type TokenPair struct {
+	Token        string `json:"token"`         // access token (JWT)
+	RefreshToken string `json:"refresh_token"` // single use, at /auth/refresh
+	ExpiresIn    int    `json:"expires_in"`    // access token lifetime in seconds
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Kinds of user token
+const (
+	TokenPasswordReset = "restablecer"
+	TokenInvitation    = "invitacion"
+)
+
+// UserToken is a single-use token sent to a user to set their password; the plain
+// token is never stored.
+type UserToken struct {
+	ID        int
+	UsuarioID int
+	Tipo      string // TokenPasswordReset or TokenInvitation
+	TokenHash string
+	ExpiraEn  time.Time
+	UsadoEn   *time.Time
+	CreadoPor *int // admin who sent the invitation
+}
+
+// Notification is a message with a link to set the password, for a Notifier.
+type Notification struct {
+	Tipo     string    `json:"tipo"` // TokenPasswordReset or TokenInvitation
+	Para     string    `json:"para"` // email address
+	Usuario  string    `json:"usuario"`
+	Enlace   string    `json:"enlace"` // carries the token
+	ExpiraEn time.Time `json:"expira_en"`
+}
@@ -0,0 +1,617 @@
+//go:generate mockgen -source=repository.go -destination=./mocks/repository.go -package=mocks
+
+package auth
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	authModels "github.com/tonitomc/healthcare-crm-api/internal/domain/auth/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// Repository persists sessions and their refresh tokens.
+type Repository interface {
+	// --- Sessions ---
+	CreateSession(s *authModels.Session) error
+	GetSession(id string) (*authModels.Session, error)
+	ListSessions(userID int) ([]authModels.Session, error)
+	// ExtendSession records a use of the session and moves its expiry
+	ExtendSession(id string, expiresAt time.Time) error
+	// RevokeSession fails with ErrNotFound if the session does not exist; revoking an
+	// already revoked session keeps the first reason.
+	RevokeSession(id, reason string) error
+	// RevokeUserSessions revokes every active session of the user except keep
+	RevokeUserSessions(userID int, keep, reason string) error
+
+	// --- Refresh tokens ---
+	CreateRefreshToken(t *authModels.RefreshToken) error
+	GetRefreshToken(hash string) (*authModels.RefreshToken, error)
+	// MarkRefreshTokenUsed fails with ErrConflict if the token was already used, so
+	// two concurrent refreshes with the same token cannot both succeed.
+	MarkRefreshTokenUsed(id int) error
+
+	// --- Failed logins ---
+	// GetLoginCounter returns an empty counter when there were no recent failures
+	GetLoginCounter(key string) (*authModels.LoginCounter, error)
+	// RecordLoginFailure counts a failure; failures older than window start over
+	RecordLoginFailure(key string, window time.Duration) (*authModels.LoginCounter, error)
+	// Lock blocks the key until lockout.Hasta and records the lockout
+	Lock(lockout *authModels.Lockout) error
+	ResetLoginCounter(key string) error
+	// UnlockUser clears the account's counter and closes its active lockouts
+	UnlockUser(userID, unlockedBy int) error
+	// ListLockouts returns the active lockouts, or every lockout if all is true
+	ListLockouts(all bool) ([]authModels.Lockout, error)
+
+	// --- Two-step verification ---
+	GetMFA(userID int) (*authModels.MFA, error)
+	// SaveMFASecret starts (or restarts) an enrollment with a new, unconfirmed secret
+	SaveMFASecret(userID int, secret string) error
+	// ConfirmMFA activates the enrollment and replaces the recovery codes
+	ConfirmMFA(userID int, step int64, codeHashes []string) error
+	// UseMFAStep fails with ErrConflict if step is not newer than the last one used
+	UseMFAStep(userID int, step int64) error
+	// UseRecoveryCode fails with ErrNotFound if the code does not exist or was used
+	UseRecoveryCode(userID int, codeHash string) error
+	ReplaceRecoveryCodes(userID int, codeHashes []string) error
+	CountRecoveryCodes(userID int) (int, error)
+	// DeleteMFA removes the enrollment and the recovery codes
+	DeleteMFA(userID int) error
+	// --- Password reset and invitations ---
+	CreateUserToken(t *authModels.UserToken) error
+	GetUserToken(tokenHash string) (*authModels.UserToken, error)
+	// UseUserToken fails with ErrConflict if the token was already used
+	UseUserToken(id int) error
+	// InvalidateUserTokens marks the unused tokens of a kind as used
+	InvalidateUserTokens(userID int, kind string) error
+	CountUserTokens(userID int, kind string, since time.Time) (int, error)
+
+	// --- Password history ---
+	// RecentPasswordHashes returns the last n password hashes, newest first
+	RecentPasswordHashes(userID, n int) ([]string, error)
+	// AddPasswordHistory records a new password hash and keeps only the last keep
+	AddPasswordHistory(userID int, passwordHash string, keep int) error
+	// --- Signing keys ---
+	// ListSigningKeys returns the keys that still verify, oldest first
+	ListSigningKeys() ([]authModels.SigningKey, error)
+	CreateSigningKey(k *authModels.SigningKey) error
+	// RetireSigningKey stops a key from signing at signUntil and verifying at verifyUntil
+	RetireSigningKey(kid string, signUntil, verifyUntil time.Time) error
+
+	// WithTx returns a copy of the repository that runs its queries in tx
+	WithTx(tx database.DBTX) Repository
+}
+
+// Concrete implementation backed by PostgreSQL.
+type repository struct {
+	db database.DBTX
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) WithTx(tx database.DBTX) Repository {
+	return &repository{db: tx}
+}
+
+// -----------------------------------------------------------------------------
+// Sessions
+// -----------------------------------------------------------------------------
+
+const selectSession = `
+	SELECT id, usuario_id, creada_en, ultimo_uso, expira_en, revocada_en, motivo_revocacion, ip, user_agent
+	FROM sesiones
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSession(row scanner) (*authModels.Session, error) {
+	var s authModels.Session
+	if err := row.Scan(&s.ID, &s.UsuarioID, &s.CreadaEn, &s.UltimoUso, &s.ExpiraEn,
+		&s.RevocadaEn, &s.MotivoRevocacion, &s.IP, &s.UserAgent); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *repository) CreateSession(s *authModels.Session) error {
+	err := r.db.QueryRow(`
+		INSERT INTO sesiones (id, usuario_id, expira_en, ip, user_agent)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING creada_en, ultimo_uso
+	`, s.ID, s.UsuarioID, s.ExpiraEn, s.IP, s.UserAgent).Scan(&s.CreadaEn, &s.UltimoUso)
+	if err != nil {
+		return database.MapSQLError(err, "AuthRepository.CreateSession")
+	}
+	return nil
+}
+
+func (r *repository) GetSession(id string) (*authModels.Session, error) {
+	s, err := scanSession(r.db.QueryRow(selectSession+` WHERE id = $1`, id))
+	if err != nil {
+		return nil, database.MapSQLError(err, "AuthRepository.GetSession")
+	}
+	return s, nil
+}
+
+func (r *repository) ListSessions(userID int) ([]authModels.Session, error) {
+	rows, err := r.db.Query(selectSession+` WHERE usuario_id = $1 ORDER BY creada_en DESC`, userID)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AuthRepository.ListSessions")
+	}
+	defer rows.Close()
+
+	sessions := []authModels.Session{}
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, appErr.Wrap("AuthRepository.ListSessions(scan)", appErr.ErrInternal, err)
+		}
+		sessions = append(sessions, *s)
+	}
+	return sessions, nil
+}
+
+func (r *repository) ExtendSession(id string, expiresAt time.Time) error {
+	if _, err := r.db.Exec(`
+		UPDATE sesiones SET ultimo_uso = NOW(), expira_en = $2 WHERE id = $1
+	`, id, expiresAt); err != nil {
+		return database.MapSQLError(err, "AuthRepository.ExtendSession")
+	}
+	return nil
+}
+
+func (r *repository) RevokeSession(id, reason string) error {
+	res, err := r.db.Exec(`
+		UPDATE sesiones
+		SET revocada_en = COALESCE(revocada_en, NOW()),
+		    motivo_revocacion = COALESCE(motivo_revocacion, $2)
+		WHERE id = $1
+	`, id, reason)
+	if err != nil {
+		return database.MapSQLError(err, "AuthRepository.RevokeSession")
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return appErr.Wrap("AuthRepository.RevokeSession", appErr.ErrNotFound, nil)
+	}
+	return nil
+}
+
+func (r *repository) RevokeUserSessions(userID int, keep, reason string) error {
+	if _, err := r.db.Exec(`
+		UPDATE sesiones SET revocada_en = NOW(), motivo_revocacion = $3
+		WHERE usuario_id = $1 AND id <> $2 AND revocada_en IS NULL
+	`, userID, keep, reason); err != nil {
+		return database.MapSQLError(err, "AuthRepository.RevokeUserSessions")
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Refresh tokens
+// -----------------------------------------------------------------------------
+
+func (r *repository) CreateRefreshToken(t *authModels.RefreshToken) error {
+	err := r.db.QueryRow(`
+		INSERT INTO refresh_tokens (sesion_id, token_hash, expira_en)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, t.SesionID, t.TokenHash, t.ExpiraEn).Scan(&t.ID)
+	if err != nil {
+		return database.MapSQLError(err, "AuthRepository.CreateRefreshToken")
+	}
+	return nil
+}
+
+func (r *repository) GetRefreshToken(hash string) (*authModels.RefreshToken, error) {
+	var t authModels.RefreshToken
+	err := r.db.QueryRow(`
+		SELECT id, sesion_id, token_hash, expira_en, usado_en
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`, hash).Scan(&t.ID, &t.SesionID, &t.TokenHash, &t.ExpiraEn, &t.UsadoEn)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AuthRepository.GetRefreshToken")
+	}
+	return &t, nil
+}
+
+func (r *repository) MarkRefreshTokenUsed(id int) error {
+	res, err := r.db.Exec(`
+		UPDATE refresh_tokens SET usado_en = NOW() WHERE id = $1 AND usado_en IS NULL
+	`, id)
+	if err != nil {
+		return database.MapSQLError(err, "AuthRepository.MarkRefreshTokenUsed")
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return appErr.Wrap("AuthRepository.MarkRefreshTokenUsed", appErr.ErrConflict, nil)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Failed logins
+// -----------------------------------------------------------------------------
+
+func (r *repository) GetLoginCounter(key string) (*authModels.LoginCounter, error) {
+	c := authModels.LoginCounter{Clave: key}
+	err := r.db.QueryRow(`
+		SELECT fallos, ultimo_fallo, bloqueado_hasta FROM contadores_login WHERE clave = $1
+	`, key).Scan(&c.Fallos, &c.UltimoFallo, &c.BloqueadoHasta)
+	if err == sql.ErrNoRows {
+		return &c, nil
+	}
+	if err != nil {
+		return nil, database.MapSQLError(err, "AuthRepository.GetLoginCounter")
+	}
+	return &c, nil
+}
+
+func (r *repository) RecordLoginFailure(key string, window time.Duration) (*authModels.LoginCounter, error) {
+	c := authModels.LoginCounter{Clave: key}
+	err := r.db.QueryRow(`
+		INSERT INTO contadores_login (clave, fallos, ultimo_fallo)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (clave) DO UPDATE SET
+			fallos = CASE
+				WHEN contadores_login.ultimo_fallo < NOW() - make_interval(secs => $2::int) THEN 1
+				ELSE contadores_login.fallos + 1
+			END,
+			ultimo_fallo = NOW()
+		RETURNING fallos, ultimo_fallo, bloqueado_hasta
+	`, key, int(window.Seconds())).Scan(&c.Fallos, &c.UltimoFallo, &c.BloqueadoHasta)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AuthRepository.RecordLoginFailure")
+	}
+	return &c, nil
+}
+
+func (r *repository) Lock(lockout *authModels.Lockout) error {
+	return database.RunInTx(r.db, "AuthRepository.Lock", func(tx database.DBTX) error {
+		if _, err := tx.Exec(`
+			UPDATE contadores_login SET bloqueado_hasta = $2 WHERE clave = $1
+		`, lockout.Clave, lockout.Hasta); err != nil {
+			return database.MapSQLError(err, "AuthRepository.Lock(counter)")
+		}
+		err := tx.QueryRow(`
+			INSERT INTO bloqueos_login (clave, usuario_id, ip, fallos, hasta)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, desde
+		`, lockout.Clave, lockout.UsuarioID, lockout.IP, lockout.Fallos, lockout.Hasta).Scan(&lockout.ID, &lockout.Desde)
+		if err != nil {
+			return database.MapSQLError(err, "AuthRepository.Lock(history)")
+		}
+		return nil
+	})
+}
+
+func (r *repository) ResetLoginCounter(key string) error {
+	if _, err := r.db.Exec(`DELETE FROM contadores_login WHERE clave = $1`, key); err != nil {
+		return database.MapSQLError(err, "AuthRepository.ResetLoginCounter")
+	}
+	return nil
+}
+
+func (r *repository) UnlockUser(userID, unlockedBy int) error {
+	return database.RunInTx(r.db, "AuthRepository.UnlockUser", func(tx database.DBTX) error {
+		if _, err := tx.Exec(`DELETE FROM contadores_login WHERE clave = $1`, userLoginKey(userID)); err != nil {
+			return database.MapSQLError(err, "AuthRepository.UnlockUser(counter)")
+		}
+		if _, err := tx.Exec(`
+			UPDATE bloqueos_login SET desbloqueado_en = NOW(), desbloqueado_por = $2
+			WHERE usuario_id = $1 AND hasta > NOW() AND desbloqueado_en IS NULL
+		`, userID, unlockedBy); err != nil {
+			return database.MapSQLError(err, "AuthRepository.UnlockUser(history)")
+		}
+		return nil
+	})
+}
+
+func (r *repository) ListLockouts(all bool) ([]authModels.Lockout, error) {
+	where := ` WHERE hasta > NOW() AND desbloqueado_en IS NULL`
+	if all {
+		where = ""
+	}
+	rows, err := r.db.Query(`
+		SELECT id, clave, usuario_id, ip, fallos, desde, hasta, desbloqueado_en, desbloqueado_por
+		FROM bloqueos_login` + where + ` ORDER BY desde DESC, id DESC`)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AuthRepository.ListLockouts")
+	}
+	defer rows.Close()
+
+	lockouts := []authModels.Lockout{}
+	for rows.Next() {
+		var l authModels.Lockout
+		if err := rows.Scan(&l.ID, &l.Clave, &l.UsuarioID, &l.IP, &l.Fallos, &l.Desde, &l.Hasta,
+			&l.DesbloqueadoEn, &l.DesbloqueadoPor); err != nil {
+			return nil, appErr.Wrap("AuthRepository.ListLockouts(scan)", appErr.ErrInternal, err)
+		}
+		lockouts = append(lockouts, l)
+	}
+	return lockouts, nil
+}
+
+// -----------------------------------------------------------------------------
+// Two-step verification
+// -----------------------------------------------------------------------------
+
+func (r *repository) GetMFA(userID int) (*authModels.MFA, error) {
+	m := authModels.MFA{UsuarioID: userID}
+	err := r.db.QueryRow(`
+		SELECT secreto, confirmado_en, ultimo_paso FROM mfa_usuarios WHERE usuario_id = $1
+	`, userID).Scan(&m.Secreto, &m.ConfirmadoEn, &m.UltimoPaso)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AuthRepository.GetMFA")
+	}
+	return &m, nil
+}
+
+func (r *repository) SaveMFASecret(userID int, secret string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO mfa_usuarios (usuario_id, secreto) VALUES ($1, $2)
+		ON CONFLICT (usuario_id) DO UPDATE SET
+			secreto = EXCLUDED.secreto, creado_en = NOW(), confirmado_en = NULL, ultimo_paso = 0
+	`, userID, secret)
+	if err != nil {
+		return database.MapSQLError(err, "AuthRepository.SaveMFASecret")
+	}
+	return nil
+}
+
+func (r *repository) ConfirmMFA(userID int, step int64, codeHashes []string) error {
+	return database.RunInTx(r.db, "AuthRepository.ConfirmMFA", func(tx database.DBTX) error {
+		res, err := tx.Exec(`
+			UPDATE mfa_usuarios SET confirmado_en = NOW(), ultimo_paso = $2
+			WHERE usuario_id = $1 AND confirmado_en IS NULL
+		`, userID, step)
+		if err != nil {
+			return database.MapSQLError(err, "AuthRepository.ConfirmMFA")
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return appErr.Wrap("AuthRepository.ConfirmMFA", appErr.ErrConflict, nil)
+		}
+		return insertRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func (r *repository) UseMFAStep(userID int, step int64) error {
+	res, err := r.db.Exec(`
+		UPDATE mfa_usuarios SET ultimo_paso = $2 WHERE usuario_id = $1 AND ultimo_paso < $2
+	`, userID, step)
+	if err != nil {
+		return database.MapSQLError(err, "AuthRepository.UseMFAStep")
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return appErr.Wrap("AuthRepository.UseMFAStep", appErr.ErrConflict, nil)
+	}
+	return nil
+}
+
+func (r *repository) UseRecoveryCode(userID int, codeHash string) error {
+	res, err := r.db.Exec(`
+		UPDATE codigos_recuperacion SET usado_en = NOW()
+		WHERE usuario_id = $1 AND codigo_hash = $2 AND usado_en IS NULL
+	`, userID, codeHash)
+	if err != nil {
+		return database.MapSQLError(err, "AuthRepository.UseRecoveryCode")
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return appErr.Wrap("AuthRepository.UseRecoveryCode", appErr.ErrNotFound, nil)
+	}
+	return nil
+}
+
+func (r *repository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	return database.RunInTx(r.db, "AuthRepository.ReplaceRecoveryCodes", func(tx database.DBTX) error {
+		return insertRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// insertRecoveryCodes replaces every recovery code of the user
+func insertRecoveryCodes(tx database.DBTX, userID int, codeHashes []string) error {
+	if _, err := tx.Exec(`DELETE FROM codigos_recuperacion WHERE usuario_id = $1`, userID); err != nil {
+		return database.MapSQLError(err, "AuthRepository.insertRecoveryCodes(delete)")
+	}
+	for _, h := range codeHashes {
+		if _, err := tx.Exec(`
+			INSERT INTO codigos_recuperacion (usuario_id, codigo_hash) VALUES ($1, $2)
+		`, userID, h); err != nil {
+			return database.MapSQLError(err, "AuthRepository.insertRecoveryCodes")
+		}
+	}
+	return nil
+}
+
+func (r *repository) CountRecoveryCodes(userID int) (int, error) {
+	var n int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM codigos_recuperacion WHERE usuario_id = $1 AND usado_en IS NULL
+	`, userID).Scan(&n)
+	if err != nil {
+		return 0, database.MapSQLError(err, "AuthRepository.CountRecoveryCodes")
+	}
+	return n, nil
+}
+
+func (r *repository) DeleteMFA(userID int) error {
+	return database.RunInTx(r.db, "AuthRepository.DeleteMFA", func(tx database.DBTX) error {
+		if _, err := tx.Exec(`DELETE FROM codigos_recuperacion WHERE usuario_id = $1`, userID); err != nil {
+			return database.MapSQLError(err, "AuthRepository.DeleteMFA(codes)")
+		}
+		if _, err := tx.Exec(`DELETE FROM mfa_usuarios WHERE usuario_id = $1`, userID); err != nil {
+			return database.MapSQLError(err, "AuthRepository.DeleteMFA")
+		}
+		return nil
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Password reset and invitations
+// -----------------------------------------------------------------------------
+
+func (r *repository) CreateUserToken(t *authModels.UserToken) error {
+	err := r.db.QueryRow(`
+		INSERT INTO tokens_usuario (usuario_id, tipo, token_hash, expira_en, creado_por)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, t.UsuarioID, t.Tipo, t.TokenHash, t.ExpiraEn, t.CreadoPor).Scan(&t.ID)
+	if err != nil {
+		return database.MapSQLError(err, "AuthRepository.CreateUserToken")
+	}
+	return nil
+}
+
+func (r *repository) GetUserToken(tokenHash string) (*authModels.UserToken, error) {
+	var t authModels.UserToken
+	err := r.db.QueryRow(`
+		SELECT id, usuario_id, tipo, token_hash, expira_en, usado_en, creado_por
+		FROM tokens_usuario WHERE token_hash = $1
+	`, tokenHash).Scan(&t.ID, &t.UsuarioID, &t.Tipo, &t.TokenHash, &t.ExpiraEn, &t.UsadoEn, &t.CreadoPor)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AuthRepository.GetUserToken")
+	}
+	return &t, nil
+}
+
+func (r *repository) UseUserToken(id int) error {
+	res, err := r.db.Exec(`
+		UPDATE tokens_usuario SET usado_en = NOW() WHERE id = $1 AND usado_en IS NULL
+	`, id)
+	if err != nil {
+		return database.MapSQLError(err, "AuthRepository.UseUserToken")
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return appErr.Wrap("AuthRepository.UseUserToken", appErr.ErrConflict, nil)
+	}
+	return nil
+}
+
+func (r *repository) InvalidateUserTokens(userID int, kind string) error {
+	_, err := r.db.Exec(`
+		UPDATE tokens_usuario SET usado_en = NOW()
+		WHERE usuario_id = $1 AND tipo = $2 AND usado_en IS NULL
+	`, userID, kind)
+	if err != nil {
+		return database.MapSQLError(err, "AuthRepository.InvalidateUserTokens")
+	}
+	return nil
+}
+
+func (r *repository) CountUserTokens(userID int, kind string, since time.Time) (int, error) {
+	var n int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM tokens_usuario WHERE usuario_id = $1 AND tipo = $2 AND creado_en >= $3
+	`, userID, kind, since).Scan(&n)
+	if err != nil {
+		return 0, database.MapSQLError(err, "AuthRepository.CountUserTokens")
+	}
+	return n, nil
+}
+
+// -----------------------------------------------------------------------------
+// Password history
+// -----------------------------------------------------------------------------
+
+func (r *repository) RecentPasswordHashes(userID, n int) ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT password_hash FROM historial_contrasenas
+		WHERE usuario_id = $1
+		ORDER BY creado_en DESC, id DESC
+		LIMIT $2
+	`, userID, n)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AuthRepository.RecentPasswordHashes")
+	}
+	defer rows.Close()
+
+	hashes := []string{}
+	for rows.Next() {
+		var h string
+		if err := rows.Scan(&h); err != nil {
+			return nil, appErr.Wrap("AuthRepository.RecentPasswordHashes(scan)", appErr.ErrInternal, err)
+		}
+		hashes = append(hashes, h)
+	}
+	return hashes, nil
+}
+
+func (r *repository) AddPasswordHistory(userID int, passwordHash string, keep int) error {
+	return database.RunInTx(r.db, "AuthRepository.AddPasswordHistory", func(tx database.DBTX) error {
+		if _, err := tx.Exec(`
+			INSERT INTO historial_contrasenas (usuario_id, password_hash) VALUES ($1, $2)
+		`, userID, passwordHash); err != nil {
+			return database.MapSQLError(err, "AuthRepository.AddPasswordHistory")
+		}
+		if _, err := tx.Exec(`
+			DELETE FROM historial_contrasenas
+			WHERE usuario_id = $1 AND id NOT IN (
+				SELECT id FROM historial_contrasenas WHERE usuario_id = $1
+				ORDER BY creado_en DESC, id DESC LIMIT $2
+			)
+		`, userID, keep); err != nil {
+			return database.MapSQLError(err, "AuthRepository.AddPasswordHistory(prune)")
+		}
+		return nil
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Signing keys
+// -----------------------------------------------------------------------------
+
+func (r *repository) ListSigningKeys() ([]authModels.SigningKey, error) {
+	rows, err := r.db.Query(`
+		SELECT kid, algoritmo, clave_privada, firma_desde, firma_hasta, expira_en
+		FROM claves_jwt
+		WHERE expira_en > NOW()
+		ORDER BY firma_desde, creada_en
+	`)
+	if err != nil {
+		return nil, database.MapSQLError(err, "AuthRepository.ListSigningKeys")
+	}
+	defer rows.Close()
+
+	keys := []authModels.SigningKey{}
+	for rows.Next() {
+		var k authModels.SigningKey
+		if err := rows.Scan(&k.Kid, &k.Algoritmo, &k.ClavePrivada, &k.FirmaDesde, &k.FirmaHasta, &k.ExpiraEn); err != nil {
+			return nil, appErr.Wrap("AuthRepository.ListSigningKeys(scan)", appErr.ErrInternal, err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func (r *repository) CreateSigningKey(k *authModels.SigningKey) error {
+	_, err := r.db.Exec(`
+		INSERT INTO claves_jwt (kid, algoritmo, clave_privada, firma_desde, firma_hasta, expira_en)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, k.Kid, k.Algoritmo, k.ClavePrivada, k.FirmaDesde, k.FirmaHasta, k.ExpiraEn)
+	if err != nil {
+		return database.MapSQLError(err, "AuthRepository.CreateSigningKey")
+	}
+	return nil
+}
+
+func (r *repository) RetireSigningKey(kid string, signUntil, verifyUntil time.Time) error {
+	res, err := r.db.Exec(`
+		UPDATE claves_jwt
+		SET firma_hasta = LEAST(firma_hasta, $2), expira_en = LEAST(expira_en, $3)
+		WHERE kid = $1
+	`, kid, signUntil, verifyUntil)
+	if err != nil {
+		return database.MapSQLError(err, "AuthRepository.RetireSigningKey")
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return appErr.Wrap("AuthRepository.RetireSigningKey", appErr.ErrNotFound, nil)
+	}
+	return nil
+}
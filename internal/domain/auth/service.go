@@ -1,28 +1,113 @@
+//go:generate mockgen -source=service.go -destination=./mocks/service.go -package=mocks
+
 // internal/domain/auth/service.go
 package auth
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
 	authModels "github.com/tonitomc/healthcare-crm-api/internal/domain/auth/models"
 	rbacDomain "github.com/tonitomc/healthcare-crm-api/internal/domain/rbac"
 	rbacModels "github.com/tonitomc/healthcare-crm-api/internal/domain/rbac/models"
 	userDomain "github.com/tonitomc/healthcare-crm-api/internal/domain/user"
+	userModels "github.com/tonitomc/healthcare-crm-api/internal/domain/user/models"
 	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/jwtkeys"
+	"github.com/tonitomc/healthcare-crm-api/pkg/password"
+	"github.com/tonitomc/healthcare-crm-api/pkg/totp"
 )
 
 // -----------------------------------------------------------------------------
 // Service Interface
 // -----------------------------------------------------------------------------
 
+// Notifier delivers the password reset and invitation links (email, SMS...).
+type Notifier interface {
+	Notify(n authModels.Notification) error
+}
+
 type Service interface {
 	Register(username, email, password string) error
-	Login(identifier, password string) (string, error)
+	// Login opens a session and returns its first access and refresh tokens. Accounts
+	// with two-step verification get an MFA token instead, for VerifyMFA.
+	Login(identifier, password string, client authModels.Client) (*authModels.LoginResult, error)
+	// Refresh exchanges a refresh token for a new pair. Each refresh token works once:
+	// presenting a used one revokes its whole session.
+	Refresh(refreshToken string) (*authModels.TokenPair, error)
+	// Logout revokes the session of the refresh token
+	Logout(refreshToken string) error
 	ValidateToken(tokenStr string) (*jwt.Token, *authModels.Claims, error)
-	ChangePassword(userID int, oldPassword, newPassword string) error
+
+	// --- Signing keys ---
+	// RotateKeys loads the signing keys and creates the next one when the current is
+	// about to stop signing. force retires the current key right away. HS256 has
+	// nothing to rotate.
+	RotateKeys(force bool) error
+	// Keyfunc verifies access tokens, for middleware.JWTMiddleware
+	Keyfunc(t *jwt.Token) (any, error)
+	// JWKS is the public keys, for other services to verify our tokens
+	JWKS() jwtkeys.JWKS
+	// ChangePassword also closes every other session of the user
+	ChangePassword(userID int, sessionID, oldPassword, newPassword string) error
+
+	// --- Sessions ---
+	// IsSessionActive implements middleware.SessionValidator
+	IsSessionActive(sessionID string) (bool, error)
+	ListSessions(userID int) ([]authModels.Session, error)
+	RevokeSession(userID int, sessionID string) error
+	RevokeAllSessions(userID int) error
+
+	// --- Lockouts ---
+	ListLockouts(all bool) ([]authModels.Lockout, error)
+	// Unlock lets a locked account log in again right away
+	Unlock(userID, adminID int) error
+
+	// --- Two-step verification ---
+	// VerifyMFA completes a login with a TOTP or recovery code. If the login required
+	// enrolling, the code confirms the enrollment and the recovery codes are returned.
+	VerifyMFA(mfaToken, code string, client authModels.Client) (*authModels.LoginResult, error)
+	MFAStatus(userID int) (*authModels.MFAStatus, error)
+	// BeginEnrollment generates a new secret; it is active once confirmed with a code
+	BeginEnrollment(userID int) (*authModels.Enrollment, error)
+	// BeginPendingEnrollment is BeginEnrollment for a login that requires enrolling
+	BeginPendingEnrollment(mfaToken string) (*authModels.Enrollment, error)
+	// ConfirmEnrollment activates the secret and returns the recovery codes
+	ConfirmEnrollment(userID int, code string) ([]string, error)
+	RegenerateRecoveryCodes(userID int, code string) ([]string, error)
+	// DisableMFA is not allowed while one of the user's roles requires it
+	DisableMFA(userID int, code string) error
+	// ResetMFA removes the enrollment of a user who lost their device
+	ResetMFA(userID int) error
+
+	// --- Password reset and invitations ---
+	// ForgotPassword sends a reset link if the account exists; it says nothing otherwise
+	ForgotPassword(identifier string) error
+	// ResetPassword also closes every session of the user
+	ResetPassword(token, newPassword string) error
+	// Invite creates an account without password and sends the invitation to set it.
+	// If only the sending fails, the account is still returned and stays invited.
+	Invite(adminID int, username, email string, roleIDs []int) (int, error)
+	// ResendInvitation sends a new invitation to an account that never set its
+	// password; earlier links stop working
+	ResendInvitation(adminID, userID int) error
+	AcceptInvitation(token, newPassword string) error
 }
 
 // -----------------------------------------------------------------------------
@@ -30,31 +115,118 @@ type Service interface {
 // -----------------------------------------------------------------------------
 
 type service struct {
+	repo        Repository
+	uow         database.UnitOfWork
 	userService userDomain.Service
 	rbacService rbacDomain.Service
-	jwtSecret   []byte
+	keys        *jwtkeys.Set
+	mfaKey      []byte
 	accessTTL   time.Duration
+	refreshTTL  time.Duration
 	issuer      string
+	maxAttempts int
+	lockout     time.Duration
+
+	notifier        Notifier
+	policy          password.Policy
+	passwordHistory int
+	resetTTL        time.Duration
+	invitationTTL   time.Duration
+	appURL          string
+
+	algorithm   string
+	keyRotation time.Duration
+	sealKey     []byte
+	keysMu      sync.Mutex
+	lastMiss    time.Time // last reload caused by an unknown kid
 }
 
 // Config allows customizing the Auth service behavior.
 type Config struct {
 	JWTSecret string
-	AccessTTL time.Duration
-	Issuer    string
+	// SigningAlgorithm of the access tokens: HS256 (JWTSecret, the default), RS256 or
+	// EdDSA (keys stored in the database, sealed with JWTSecret, and rotated)
+	SigningAlgorithm string
+	KeyRotation      time.Duration // how long each RS256/EdDSA key signs (default 30 days)
+	AccessTTL        time.Duration // access token lifetime (default 15m)
+	RefreshTTL       time.Duration // refresh token lifetime; an unused session expires after it (default 14 days)
+	Issuer           string
+
+	LoginMaxAttempts int           // failed logins before an account is locked (default 10); an IP gets 5 times as many
+	LoginLockout     time.Duration // first lockout; each further failure doubles it, up to a day (default 15m)
+
+	PasswordMinLength int           // default 12
+	PasswordHistory   int           // last passwords that cannot be reused, the current one included (default 5)
+	BreachedPasswords password.List // optional
+	ResetTTL          time.Duration // password reset links (default 1h)
+	InvitationTTL     time.Duration // invitation links (default 72h)
+	AppURL            string        // frontend the links point to, e.g. https://crm.example.com
 }
 
 // NewService constructs a new Auth service.
-func NewService(userSvc userDomain.Service, rbacSvc rbacDomain.Service, cfg Config) Service {
+func NewService(repo Repository, userSvc userDomain.Service, rbacSvc rbacDomain.Service, notifier Notifier, uow database.UnitOfWork, cfg Config) Service {
 	if cfg.AccessTTL == 0 {
-		cfg.AccessTTL = 24 * time.Hour
+		cfg.AccessTTL = 15 * time.Minute
+	}
+	if cfg.RefreshTTL == 0 {
+		cfg.RefreshTTL = 14 * 24 * time.Hour
+	}
+	if cfg.LoginMaxAttempts == 0 {
+		cfg.LoginMaxAttempts = 10
+	}
+	if cfg.LoginLockout == 0 {
+		cfg.LoginLockout = 15 * time.Minute
+	}
+	if cfg.PasswordMinLength == 0 {
+		cfg.PasswordMinLength = 12
 	}
+	if cfg.PasswordHistory == 0 {
+		cfg.PasswordHistory = 5
+	}
+	if cfg.ResetTTL == 0 {
+		cfg.ResetTTL = time.Hour
+	}
+	if cfg.InvitationTTL == 0 {
+		cfg.InvitationTTL = 72 * time.Hour
+	}
+	if cfg.SigningAlgorithm == "" {
+		cfg.SigningAlgorithm = jwtkeys.HS256
+	}
+	if cfg.KeyRotation == 0 {
+		cfg.KeyRotation = 30 * 24 * time.Hour
+	}
+
+	// HS256 keeps accepting the tokens signed before they carried a "kid"
+	keys := jwtkeys.NewSet(nil)
+	if cfg.SigningAlgorithm == jwtkeys.HS256 {
+		secret := jwtkeys.Secret(hs256KeyID, []byte(cfg.JWTSecret))
+		keys.Replace(secret)
+		keys.AcceptWithoutID(secret)
+	}
+
 	return &service{
+		repo:        repo,
+		uow:         uow,
 		userService: userSvc,
 		rbacService: rbacSvc,
-		jwtSecret:   []byte(cfg.JWTSecret),
+		keys:        keys,
+		mfaKey:      mfaKey(cfg.JWTSecret),
 		accessTTL:   cfg.AccessTTL,
+		refreshTTL:  cfg.RefreshTTL,
 		issuer:      cfg.Issuer,
+		maxAttempts: cfg.LoginMaxAttempts,
+		lockout:     cfg.LoginLockout,
+
+		notifier:        notifier,
+		policy:          password.Policy{MinLength: cfg.PasswordMinLength, Breached: cfg.BreachedPasswords},
+		passwordHistory: cfg.PasswordHistory,
+		resetTTL:        cfg.ResetTTL,
+		invitationTTL:   cfg.InvitationTTL,
+		appURL:          strings.TrimRight(cfg.AppURL, "/"),
+
+		algorithm:   cfg.SigningAlgorithm,
+		keyRotation: cfg.KeyRotation,
+		sealKey:     sealKey(cfg.JWTSecret),
 	}
 }
 
@@ -62,12 +234,15 @@ func NewService(userSvc userDomain.Service, rbacSvc rbacDomain.Service, cfg Conf
 // Register / Login / Validate
 // -----------------------------------------------------------------------------
 
-func (s *service) Register(username, email, password string) error {
-	if username == "" || email == "" || password == "" {
+func (s *service) Register(username, email, pw string) error {
+	if username == "" || email == "" || pw == "" {
 		return appErr.Wrap("AuthService.Register", appErr.ErrInvalidInput, nil)
 	}
+	if err := s.policy.Check("password", pw); err != nil {
+		return err
+	}
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hash, err := bcrypt.GenerateFromPassword([]byte(pw), bcrypt.DefaultCost)
 	if err != nil {
 		return appErr.Wrap("AuthService.Register(hash)", appErr.ErrInternal, err)
 	}
@@ -75,34 +250,170 @@ func (s *service) Register(username, email, password string) error {
 	if err := s.userService.CreateUser(username, email, string(hash)); err != nil {
 		return err // already wrapped
 	}
-	return nil
+
+	u, err := s.userService.GetByUsernameOrEmail(username)
+	if err != nil {
+		return err
+	}
+	return s.repo.AddPasswordHistory(u.ID, string(hash), s.passwordHistory)
 }
 
-func (s *service) Login(identifier, password string) (string, error) {
+func (s *service) Login(identifier, password string, client authModels.Client) (*authModels.LoginResult, error) {
 	if identifier == "" || password == "" {
-		return "", appErr.Wrap("AuthService.Login", appErr.ErrInvalidInput, nil)
+		return nil, appErr.Wrap("AuthService.Login", appErr.ErrInvalidInput, nil)
+	}
+
+	now := time.Now()
+	var ipKey string
+	if client.IP != "" {
+		ipKey = ipLoginKey(client.IP)
+		if err := s.checkLoginAllowed(ipKey, now); err != nil {
+			return nil, err
+		}
 	}
 
+	// Unknown identifiers are counted, locked and timed like real accounts, so the
+	// answer never tells whether the account exists
+	accountKey, passwordHash := identifierLoginKey(identifier), unknownAccountHash
 	u, err := s.userService.GetByUsernameOrEmail(identifier)
+	switch {
+	case err == nil:
+		accountKey, passwordHash = userLoginKey(u.ID), u.PasswordHash
+	case !errors.Is(err, appErr.ErrNotFound):
+		// A failed lookup is not a failed attempt: nothing is counted
+		return nil, err
+	}
+	if err := s.checkLoginAllowed(accountKey, now); err != nil {
+		return nil, err
+	}
+
+	if cmpErr := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)); cmpErr != nil || err != nil {
+		var userID *int
+		if err == nil {
+			userID = &u.ID
+		}
+		if err := s.recordLoginFailure(accountKey, userID, now); err != nil {
+			return nil, err
+		}
+		if ipKey != "" {
+			if err := s.recordLoginFailure(ipKey, nil, now); err != nil {
+				return nil, err
+			}
+		}
+		if err != nil {
+			return nil, appErr.Wrap("AuthService.Login(user lookup)", appErr.ErrInvalidCredentials, err)
+		}
+		return nil, appErr.Wrap("AuthService.Login(compare)", appErr.ErrInvalidCredentials, cmpErr)
+	}
+
+	// The failure counter is kept until the second step succeeds too, so the codes
+	// cannot be guessed by logging in again between attempts
+	if pending, err := s.secondStep(u.ID); pending != nil || err != nil {
+		return pending, err
+	}
+	if err := s.repo.ResetLoginCounter(accountKey); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.openSession(u.ID, client)
 	if err != nil {
-		return "", appErr.Wrap("AuthService.Login(user lookup)", appErr.ErrInvalidCredentials, err)
+		return nil, err
 	}
+	return &authModels.LoginResult{TokenPair: tokens}, nil
+}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
-		return "", appErr.Wrap("AuthService.Login(compare)", appErr.ErrInvalidCredentials, err)
+// openSession starts a session for the user and issues its first tokens
+func (s *service) openSession(userID int, client authModels.Client) (*authModels.TokenPair, error) {
+	sessionID, err := randomToken(16)
+	if err != nil {
+		return nil, appErr.Wrap("AuthService.openSession(session id)", appErr.ErrInternal, err)
+	}
+	session := &authModels.Session{
+		ID:        sessionID,
+		UsuarioID: userID,
+		ExpiraEn:  time.Now().Add(s.refreshTTL),
+		IP:        optional(client.IP),
+		UserAgent: optional(client.UserAgent),
 	}
+	if err := s.repo.CreateSession(session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(userID, session.ID, session.ExpiraEn)
+}
 
-	rbacCtx, err := s.rbacService.GetUserAccess(u.ID)
+func (s *service) Refresh(refreshToken string) (*authModels.TokenPair, error) {
+	token, session, err := s.lookupRefreshToken(refreshToken)
 	if err != nil {
-		return "", appErr.Wrap("AuthService.Login(rbac)", appErr.ErrInternal, err)
+		return nil, err
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) {
+		return nil, appErr.Wrap("AuthService.Refresh(session)", appErr.ErrInvalidToken, nil)
+	}
+	if token.UsadoEn != nil {
+		return nil, s.revokeOnReuse(session)
+	}
+	if !now.Before(token.ExpiraEn) {
+		return nil, appErr.Wrap("AuthService.Refresh(expired)", appErr.ErrInvalidToken, nil)
 	}
 
-	token, err := s.generateJWT(rbacCtx)
+	// Lost race: someone else used the same token first
+	if err := s.repo.MarkRefreshTokenUsed(token.ID); err != nil {
+		if errors.Is(err, appErr.ErrConflict) {
+			return nil, s.revokeOnReuse(session)
+		}
+		return nil, err
+	}
+
+	expiresAt := now.Add(s.refreshTTL)
+	if err := s.repo.ExtendSession(session.ID, expiresAt); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(session.UsuarioID, session.ID, expiresAt)
+}
+
+func (s *service) Logout(refreshToken string) error {
+	_, session, err := s.lookupRefreshToken(refreshToken)
 	if err != nil {
-		return "", appErr.Wrap("AuthService.Login(token)", appErr.ErrInternal, err)
+		return err
 	}
+	return s.repo.RevokeSession(session.ID, authModels.RevokedLogout)
+}
 
-	return token, nil
+// lookupRefreshToken finds a refresh token and its session; unknown tokens are
+// ErrInvalidToken.
+func (s *service) lookupRefreshToken(refreshToken string) (*authModels.RefreshToken, *authModels.Session, error) {
+	if refreshToken == "" {
+		return nil, nil, appErr.Wrap("AuthService.lookupRefreshToken", appErr.ErrInvalidToken, nil)
+	}
+
+	token, err := s.repo.GetRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, appErr.ErrNotFound) {
+			return nil, nil, appErr.Wrap("AuthService.lookupRefreshToken", appErr.ErrInvalidToken, err)
+		}
+		return nil, nil, err
+	}
+
+	session, err := s.repo.GetSession(token.SesionID)
+	if err != nil {
+		if errors.Is(err, appErr.ErrNotFound) {
+			return nil, nil, appErr.Wrap("AuthService.lookupRefreshToken(session)", appErr.ErrInvalidToken, err)
+		}
+		return nil, nil, err
+	}
+	return token, session, nil
+}
+
+// revokeOnReuse handles a refresh token presented twice: one of the two holders
+// stole it, so the whole session goes.
+func (s *service) revokeOnReuse(session *authModels.Session) error {
+	if err := s.repo.RevokeSession(session.ID, authModels.RevokedReuse); err != nil {
+		return err
+	}
+	return appErr.Wrap("AuthService.Refresh(reuse, session "+session.ID+")", appErr.ErrInvalidToken, nil)
 }
 
 func (s *service) ValidateToken(tokenStr string) (*jwt.Token, *authModels.Claims, error) {
@@ -111,11 +422,9 @@ func (s *service) ValidateToken(tokenStr string) (*jwt.Token, *authModels.Claims
 	}
 
 	claims := &authModels.Claims{}
-	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	parser := jwt.NewParser(jwt.WithValidMethods(s.keys.Algorithms()))
 
-	token, err := parser.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
-		return s.jwtSecret, nil
-	})
+	token, err := parser.ParseWithClaims(tokenStr, claims, s.Keyfunc)
 	if err != nil {
 		return nil, nil, appErr.Wrap("AuthService.ValidateToken(parse)", appErr.ErrInvalidToken, err)
 	}
@@ -130,10 +439,485 @@ func (s *service) ValidateToken(tokenStr string) (*jwt.Token, *authModels.Claims
 }
 
 // -----------------------------------------------------------------------------
-// JWT generator
+// Failed logins
 // -----------------------------------------------------------------------------
 
-func (s *service) generateJWT(rbacCtx *rbacModels.RBAC) (string, error) {
+const (
+	// Failures are forgotten after a day without new ones
+	loginFailureWindow = 24 * time.Hour
+	// From the third failure on, each attempt waits 1s, 2s, 4s... before the next
+	loginDelayAfter = 3
+	loginMaxDelay   = 30 * time.Second
+	loginMaxLockout = 24 * time.Hour
+	// Failures an IP may accumulate, in multiples of the per-account limit
+	ipAttemptsFactor = 5
+)
+
+// unknownAccountHash is compared against when the identifier does not exist, so
+// those attempts take as long as real ones.
+const unknownAccountHash = "$2a$10$CdwF0uSbpxkh6.tM7GmuMe00gxksHZkLUu6.nnJwAq9CPOHoGo2lm"
+
+func userLoginKey(userID int) string { return "usuario:" + strconv.Itoa(userID) }
+
+func identifierLoginKey(identifier string) string {
+	return "identificador:" + strings.ToLower(strings.TrimSpace(identifier))
+}
+
+func ipLoginKey(ip string) string { return "ip:" + ip }
+
+// checkLoginAllowed fails with a LockedError while key is locked or still has to
+// wait after its last failure.
+func (s *service) checkLoginAllowed(key string, now time.Time) error {
+	counter, err := s.repo.GetLoginCounter(key)
+	if err != nil {
+		return err
+	}
+	if counter.BloqueadoHasta != nil && now.Before(*counter.BloqueadoHasta) {
+		return &authModels.LockedError{RetryAfter: counter.BloqueadoHasta.Sub(now)}
+	}
+	if counter.Fallos < loginDelayAfter || now.Sub(counter.UltimoFallo) > loginFailureWindow {
+		return nil
+	}
+
+	delay := time.Second << min(counter.Fallos-loginDelayAfter, 5)
+	if next := counter.UltimoFallo.Add(min(delay, loginMaxDelay)); now.Before(next) {
+		return &authModels.LockedError{RetryAfter: next.Sub(now)}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failure for key and locks it once it reaches its limit.
+// Every failure past the limit locks again, for twice as long.
+func (s *service) recordLoginFailure(key string, userID *int, now time.Time) error {
+	counter, err := s.repo.RecordLoginFailure(key, loginFailureWindow)
+	if err != nil {
+		return err
+	}
+
+	limit := s.maxAttempts
+	var ip *string
+	if strings.HasPrefix(key, "ip:") {
+		limit *= ipAttemptsFactor
+		addr := strings.TrimPrefix(key, "ip:")
+		ip = &addr
+	}
+	if counter.Fallos < limit {
+		return nil
+	}
+
+	duration := s.lockout << min(counter.Fallos-limit, 10)
+	return s.repo.Lock(&authModels.Lockout{
+		Clave:     key,
+		UsuarioID: userID,
+		IP:        ip,
+		Fallos:    counter.Fallos,
+		Hasta:     now.Add(min(duration, loginMaxLockout)),
+	})
+}
+
+func (s *service) ListLockouts(all bool) ([]authModels.Lockout, error) {
+	return s.repo.ListLockouts(all)
+}
+
+func (s *service) Unlock(userID, adminID int) error {
+	if userID <= 0 {
+		return appErr.Wrap("AuthService.Unlock", appErr.ErrInvalidInput, nil)
+	}
+	if _, err := s.userService.GetByID(userID); err != nil {
+		return err
+	}
+	return s.repo.UnlockUser(userID, adminID)
+}
+
+// -----------------------------------------------------------------------------
+// Two-step verification
+// -----------------------------------------------------------------------------
+
+const (
+	mfaTokenTTL = 10 * time.Minute
+	// Codes of the previous and next 30-second step are accepted too (clock drift)
+	mfaSkew           = 1
+	recoveryCodeCount = 10
+)
+
+// mfaKey signs MFA tokens. It differs from the access token key, so an MFA token
+// can never pass for an access token.
+func mfaKey(jwtSecret string) []byte {
+	sum := sha256.Sum256([]byte("mfa:" + jwtSecret))
+	return sum[:]
+}
+
+// secondStep returns the pending login of a user with two-step verification, or nil
+// if the password is enough.
+func (s *service) secondStep(userID int) (*authModels.LoginResult, error) {
+	mfa, err := s.repo.GetMFA(userID)
+	if err != nil && !errors.Is(err, appErr.ErrNotFound) {
+		return nil, err
+	}
+	enroll := !mfa.IsActive()
+	if enroll {
+		required, err := s.mfaRequired(userID)
+		if err != nil || !required {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	claims := authModels.MFAClaims{
+		UserID: userID,
+		Enroll: enroll,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{authModels.MFAAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(mfaTokenTTL)),
+			Issuer:    s.issuer,
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.mfaKey)
+	if err != nil {
+		return nil, appErr.Wrap("AuthService.secondStep(sign)", appErr.ErrInternal, err)
+	}
+
+	return &authModels.LoginResult{
+		MFARequired:    true,
+		MFAToken:       token,
+		MFAExpiresIn:   int(mfaTokenTTL.Seconds()),
+		EnrollRequired: enroll,
+	}, nil
+}
+
+// mfaRequired reports whether one of the user's roles requires two-step verification
+func (s *service) mfaRequired(userID int) (bool, error) {
+	rbacCtx, err := s.rbacService.GetUserAccess(userID)
+	if err != nil {
+		return false, appErr.Wrap("AuthService.mfaRequired(rbac)", appErr.ErrInternal, err)
+	}
+	for _, r := range rbacCtx.Roles {
+		if r.RequiresMFA {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *service) parseMFAToken(tokenStr string) (*authModels.MFAClaims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(authModels.MFAAudience),
+	}
+	if s.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(s.issuer))
+	}
+
+	claims := &authModels.MFAClaims{}
+	_, err := jwt.NewParser(opts...).ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
+		return s.mfaKey, nil
+	})
+	if err != nil || claims.UserID <= 0 {
+		return nil, appErr.NewDomainError(appErr.ErrInvalidToken, "El token de verificación es inválido o expiró. Inicie sesión de nuevo.")
+	}
+	return claims, nil
+}
+
+func (s *service) VerifyMFA(mfaToken, code string, client authModels.Client) (*authModels.LoginResult, error) {
+	claims, err := s.parseMFAToken(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wrong codes count as failed logins of the account
+	now := time.Now()
+	key := userLoginKey(claims.UserID)
+	if err := s.checkLoginAllowed(key, now); err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.getMFA(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	var recoveryCodes []string
+	if mfa.IsActive() {
+		err = s.checkCode(mfa, code, now)
+	} else {
+		recoveryCodes, err = s.confirm(mfa, code, now)
+	}
+	if err != nil {
+		if errors.Is(err, appErr.ErrInvalidCredentials) {
+			if err := s.recordLoginFailure(key, &claims.UserID, now); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+	if err := s.repo.ResetLoginCounter(key); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.openSession(claims.UserID, client)
+	if err != nil {
+		return nil, err
+	}
+	return &authModels.LoginResult{TokenPair: tokens, RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *service) MFAStatus(userID int) (*authModels.MFAStatus, error) {
+	if userID <= 0 {
+		return nil, appErr.Wrap("AuthService.MFAStatus", appErr.ErrInvalidInput, nil)
+	}
+
+	required, err := s.mfaRequired(userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &authModels.MFAStatus{Requerido: required}
+
+	mfa, err := s.repo.GetMFA(userID)
+	if err != nil && !errors.Is(err, appErr.ErrNotFound) {
+		return nil, err
+	}
+	if mfa.IsActive() {
+		status.Activo = true
+		if status.CodigosRestantes, err = s.repo.CountRecoveryCodes(userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+func (s *service) BeginEnrollment(userID int) (*authModels.Enrollment, error) {
+	if userID <= 0 {
+		return nil, appErr.Wrap("AuthService.BeginEnrollment", appErr.ErrInvalidInput, nil)
+	}
+
+	mfa, err := s.repo.GetMFA(userID)
+	if err != nil && !errors.Is(err, appErr.ErrNotFound) {
+		return nil, err
+	}
+	if mfa.IsActive() {
+		return nil, appErr.NewDomainError(appErr.ErrConflict, "La verificación en dos pasos ya está activa.")
+	}
+
+	u, err := s.userService.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return nil, appErr.Wrap("AuthService.BeginEnrollment(secret)", appErr.ErrInternal, err)
+	}
+	if err := s.repo.SaveMFASecret(userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &authModels.Enrollment{
+		Secreto: secret,
+		URI:     totp.ProvisioningURI(secret, s.issuer, u.Username),
+	}, nil
+}
+
+func (s *service) BeginPendingEnrollment(mfaToken string) (*authModels.Enrollment, error) {
+	claims, err := s.parseMFAToken(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+	if !claims.Enroll {
+		return nil, appErr.NewDomainError(appErr.ErrConflict, "La verificación en dos pasos ya está activa.")
+	}
+	return s.BeginEnrollment(claims.UserID)
+}
+
+func (s *service) ConfirmEnrollment(userID int, code string) ([]string, error) {
+	if userID <= 0 || code == "" {
+		return nil, appErr.Wrap("AuthService.ConfirmEnrollment", appErr.ErrInvalidInput, nil)
+	}
+
+	mfa, err := s.getMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.IsActive() {
+		return nil, appErr.NewDomainError(appErr.ErrConflict, "La verificación en dos pasos ya está activa.")
+	}
+	return s.confirm(mfa, code, time.Now())
+}
+
+func (s *service) RegenerateRecoveryCodes(userID int, code string) ([]string, error) {
+	mfa, err := s.activeMFA(userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRecoveryCodes(mfa.UsuarioID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *service) DisableMFA(userID int, code string) error {
+	required, err := s.mfaRequired(userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return appErr.NewDomainError(appErr.ErrOperationNotAllowed, "Uno de sus roles requiere verificación en dos pasos.")
+	}
+
+	if _, err := s.activeMFA(userID, code); err != nil {
+		return err
+	}
+	return s.repo.DeleteMFA(userID)
+}
+
+func (s *service) ResetMFA(userID int) error {
+	if userID <= 0 {
+		return appErr.Wrap("AuthService.ResetMFA", appErr.ErrInvalidInput, nil)
+	}
+	if _, err := s.userService.GetByID(userID); err != nil {
+		return err
+	}
+	return s.repo.DeleteMFA(userID)
+}
+
+// getMFA returns the enrollment of the user, active or pending
+func (s *service) getMFA(userID int) (*authModels.MFA, error) {
+	mfa, err := s.repo.GetMFA(userID)
+	if err != nil {
+		if errors.Is(err, appErr.ErrNotFound) {
+			return nil, appErr.NewDomainError(appErr.ErrInvalidInput, "Inicie el enrolamiento de la verificación en dos pasos primero.")
+		}
+		return nil, err
+	}
+	return mfa, nil
+}
+
+// activeMFA returns the active enrollment of the user once code is checked against it
+func (s *service) activeMFA(userID int, code string) (*authModels.MFA, error) {
+	if userID <= 0 || code == "" {
+		return nil, appErr.Wrap("AuthService.activeMFA", appErr.ErrInvalidInput, nil)
+	}
+
+	mfa, err := s.repo.GetMFA(userID)
+	if err != nil && !errors.Is(err, appErr.ErrNotFound) {
+		return nil, err
+	}
+	if !mfa.IsActive() {
+		return nil, appErr.NewDomainError(appErr.ErrOperationNotAllowed, "La verificación en dos pasos no está activa.")
+	}
+	if err := s.checkCode(mfa, code, time.Now()); err != nil {
+		return nil, err
+	}
+	return mfa, nil
+}
+
+// checkCode accepts a TOTP code, once, or an unused recovery code
+func (s *service) checkCode(mfa *authModels.MFA, code string, now time.Time) error {
+	code = normalizeCode(code)
+
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(mfa.Secreto, code, now, mfaSkew)
+		if !ok {
+			return appErr.Wrap("AuthService.checkCode(totp)", appErr.ErrInvalidCredentials, nil)
+		}
+		if err := s.repo.UseMFAStep(mfa.UsuarioID, step); err != nil {
+			if errors.Is(err, appErr.ErrConflict) {
+				return appErr.Wrap("AuthService.checkCode(reused)", appErr.ErrInvalidCredentials, err)
+			}
+			return err
+		}
+		return nil
+	}
+
+	if err := s.repo.UseRecoveryCode(mfa.UsuarioID, hashToken(code)); err != nil {
+		if errors.Is(err, appErr.ErrNotFound) {
+			return appErr.Wrap("AuthService.checkCode(recovery)", appErr.ErrInvalidCredentials, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// confirm activates a pending enrollment with its first code
+func (s *service) confirm(mfa *authModels.MFA, code string, now time.Time) ([]string, error) {
+	step, ok := totp.Validate(mfa.Secreto, normalizeCode(code), now, mfaSkew)
+	if !ok {
+		return nil, appErr.Wrap("AuthService.confirm(totp)", appErr.ErrInvalidCredentials, nil)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ConfirmMFA(mfa.UsuarioID, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// newRecoveryCodes returns the codes to show once, like "k3jd8-2mxq7", and the
+// hashes to store
+func newRecoveryCodes() ([]string, []string, error) {
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, appErr.Wrap("AuthService.newRecoveryCodes", appErr.ErrInternal, err)
+		}
+		code := strings.ToLower(enc.EncodeToString(b))[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = hashToken(code)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeCode drops the separators codes are usually typed with
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+// -----------------------------------------------------------------------------
+// Token generation
+// -----------------------------------------------------------------------------
+
+// issueTokens signs a new access token with the user's current roles and permissions
+// and a new refresh token for the session.
+func (s *service) issueTokens(userID int, sessionID string, refreshExpiresAt time.Time) (*authModels.TokenPair, error) {
+	rbacCtx, err := s.rbacService.GetUserAccess(userID)
+	if err != nil {
+		return nil, appErr.Wrap("AuthService.issueTokens(rbac)", appErr.ErrInternal, err)
+	}
+
+	access, err := s.generateJWT(rbacCtx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := randomToken(32)
+	if err != nil {
+		return nil, appErr.Wrap("AuthService.issueTokens(refresh)", appErr.ErrInternal, err)
+	}
+	if err := s.repo.CreateRefreshToken(&authModels.RefreshToken{
+		SesionID:  sessionID,
+		TokenHash: hashToken(refresh),
+		ExpiraEn:  refreshExpiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &authModels.TokenPair{
+		Token:        access,
+		RefreshToken: refresh,
+		ExpiresIn:    int(s.accessTTL.Seconds()),
+	}, nil
+}
+
+func (s *service) generateJWT(rbacCtx *rbacModels.RBAC, sessionID string) (string, error) {
 	roleNames := make([]string, 0, len(rbacCtx.Roles))
 	for _, r := range rbacCtx.Roles {
 		roleNames = append(roleNames, r.Name)
@@ -150,6 +934,7 @@ func (s *service) generateJWT(rbacCtx *rbacModels.RBAC) (string, error) {
 		Username:    rbacCtx.User.Username,
 		Roles:       roleNames,
 		Permissions: permNames,
+		SessionID:   sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)),
@@ -157,8 +942,7 @@ func (s *service) generateJWT(rbacCtx *rbacModels.RBAC) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString(s.jwtSecret)
+	signed, err := s.keys.Sign(claims)
 	if err != nil {
 		return "", appErr.Wrap("AuthService.generateJWT", appErr.ErrInternal, err)
 	}
@@ -166,7 +950,267 @@ func (s *service) generateJWT(rbacCtx *rbacModels.RBAC) (string, error) {
 	return signed, nil
 }
 
-func (s *service) ChangePassword(userID int, oldPassword, newPassword string) error {
+// randomToken returns n random bytes, URL-safe encoded
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is how refresh tokens are stored: a leaked table does not leak sessions
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func optional(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+// -----------------------------------------------------------------------------
+// Signing keys
+// -----------------------------------------------------------------------------
+
+const (
+	hs256KeyID = "hs256"
+	// The next key is published this long before it signs, so the services caching
+	// our JWKS already know it
+	keyPublishAhead = 24 * time.Hour
+	// A retired key keeps verifying the access tokens it signed, plus some clock skew
+	keyVerifyMargin = 5 * time.Minute
+	// A token with an unknown "kid" reloads the keys at most this often: another
+	// instance may have rotated them
+	keyReloadInterval = time.Minute
+)
+
+// sealKey encrypts the stored private keys, so a database dump alone cannot sign tokens.
+func sealKey(jwtSecret string) []byte {
+	sum := sha256.Sum256([]byte("jwt-keys:" + jwtSecret))
+	return sum[:]
+}
+
+func (s *service) RotateKeys(force bool) error {
+	if s.algorithm == jwtkeys.HS256 {
+		if force {
+			return appErr.NewDomainError(appErr.ErrOperationNotAllowed, "La rotación de claves requiere RS256 o EdDSA.")
+		}
+		return nil
+	}
+
+	s.keysMu.Lock()
+	defer s.keysMu.Unlock()
+
+	now := time.Now()
+	stored, err := s.repo.ListSigningKeys()
+	if err != nil {
+		return err
+	}
+
+	current := s.currentKey(stored, now)
+	if current != nil && force {
+		if err := s.repo.RetireSigningKey(current.Kid, now, now.Add(s.accessTTL+keyVerifyMargin)); err != nil {
+			return err
+		}
+		current = nil
+	}
+	if current == nil {
+		if current, err = s.createKey(now); err != nil {
+			return err
+		}
+	}
+	if current.FirmaHasta.Sub(now) < keyPublishAhead && !hasNextKey(stored, current) {
+		if _, err := s.createKey(current.FirmaHasta); err != nil {
+			return err
+		}
+	}
+
+	return s.loadKeys(now)
+}
+
+func (s *service) Keyfunc(t *jwt.Token) (any, error) {
+	key, err := s.keys.Keyfunc(t)
+	if !errors.Is(err, jwtkeys.ErrUnknownKey) || s.algorithm == jwtkeys.HS256 {
+		return key, err
+	}
+
+	s.keysMu.Lock()
+	now := time.Now()
+	reload := now.Sub(s.lastMiss) >= keyReloadInterval
+	if reload {
+		s.lastMiss = now
+		if err := s.loadKeys(now); err != nil {
+			s.keysMu.Unlock()
+			return nil, err
+		}
+	}
+	s.keysMu.Unlock()
+
+	if !reload {
+		return nil, err
+	}
+	return s.keys.Keyfunc(t)
+}
+
+func (s *service) JWKS() jwtkeys.JWKS {
+	return s.keys.JWKS()
+}
+
+// loadKeys installs the stored keys. Keys sealed with another JWT_SECRET, or of
+// another algorithm, are left out. Callers hold keysMu.
+func (s *service) loadKeys(now time.Time) error {
+	stored, err := s.repo.ListSigningKeys()
+	if err != nil {
+		return err
+	}
+
+	current := s.currentKey(stored, now)
+	var signing *jwtkeys.Key
+	others := []*jwtkeys.Key{}
+	for i := range stored {
+		k := &stored[i]
+		if k.Algoritmo != s.algorithm {
+			continue
+		}
+		key, err := s.openKey(k)
+		if err != nil {
+			continue
+		}
+		if k == current {
+			signing = key
+		} else {
+			others = append(others, key)
+		}
+	}
+	if signing == nil {
+		return appErr.Wrap("AuthService.loadKeys", appErr.ErrInternal, jwtkeys.ErrNoSigningKey)
+	}
+
+	s.keys.Replace(signing, others...)
+	return nil
+}
+
+// currentKey is the newest key of the configured algorithm that signs at now and
+// can be opened.
+func (s *service) currentKey(stored []authModels.SigningKey, now time.Time) *authModels.SigningKey {
+	var current *authModels.SigningKey
+	for i := range stored {
+		k := &stored[i]
+		if k.Algoritmo != s.algorithm || !k.Signs(now) {
+			continue
+		}
+		if _, err := s.openKey(k); err != nil {
+			continue
+		}
+		if current == nil || !k.FirmaDesde.Before(current.FirmaDesde) {
+			current = k
+		}
+	}
+	return current
+}
+
+func hasNextKey(stored []authModels.SigningKey, current *authModels.SigningKey) bool {
+	for _, k := range stored {
+		if k.Algoritmo == current.Algoritmo && !k.FirmaDesde.Before(current.FirmaHasta) {
+			return true
+		}
+	}
+	return false
+}
+
+// createKey stores a new key that signs from from on, for the rotation period.
+func (s *service) createKey(from time.Time) (*authModels.SigningKey, error) {
+	kid, err := randomToken(12)
+	if err != nil {
+		return nil, appErr.Wrap("AuthService.createKey(kid)", appErr.ErrInternal, err)
+	}
+	key, err := jwtkeys.Generate(kid, s.algorithm)
+	if err != nil {
+		return nil, appErr.Wrap("AuthService.createKey(generate)", appErr.ErrInternal, err)
+	}
+	sealed, err := s.sealPrivate(key)
+	if err != nil {
+		return nil, err
+	}
+
+	until := from.Add(s.keyRotation)
+	stored := &authModels.SigningKey{
+		Kid:          kid,
+		Algoritmo:    s.algorithm,
+		ClavePrivada: sealed,
+		FirmaDesde:   from,
+		FirmaHasta:   until,
+		ExpiraEn:     until.Add(s.accessTTL + keyVerifyMargin),
+	}
+	if err := s.repo.CreateSigningKey(stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+func (s *service) sealPrivate(key *jwtkeys.Key) (string, error) {
+	pem, err := key.MarshalPrivate()
+	if err != nil {
+		return "", appErr.Wrap("AuthService.sealPrivate(marshal)", appErr.ErrInternal, err)
+	}
+	gcm, err := s.keyCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", appErr.Wrap("AuthService.sealPrivate(nonce)", appErr.ErrInternal, err)
+	}
+	// The kid is authenticated too, so a sealed key cannot be moved to another row
+	sealed := gcm.Seal(nonce, nonce, []byte(pem), []byte(key.ID))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *service) openKey(k *authModels.SigningKey) (*jwtkeys.Key, error) {
+	sealed, err := base64.StdEncoding.DecodeString(k.ClavePrivada)
+	if err != nil {
+		return nil, appErr.Wrap("AuthService.openKey(decode)", appErr.ErrInternal, err)
+	}
+	gcm, err := s.keyCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, appErr.Wrap("AuthService.openKey", appErr.ErrInternal, nil)
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	pem, err := gcm.Open(nil, nonce, ciphertext, []byte(k.Kid))
+	if err != nil {
+		return nil, appErr.Wrap("AuthService.openKey(open)", appErr.ErrInternal, err)
+	}
+	key, err := jwtkeys.ParsePrivate(k.Kid, k.Algoritmo, string(pem))
+	if err != nil {
+		return nil, appErr.Wrap("AuthService.openKey(parse)", appErr.ErrInternal, err)
+	}
+	return key, nil
+}
+
+func (s *service) keyCipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.sealKey)
+	if err != nil {
+		return nil, appErr.Wrap("AuthService.keyCipher", appErr.ErrInternal, err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, appErr.Wrap("AuthService.keyCipher", appErr.ErrInternal, err)
+	}
+	return gcm, nil
+}
+
+// -----------------------------------------------------------------------------
+// Password
+// -----------------------------------------------------------------------------
+
+func (s *service) ChangePassword(userID int, sessionID, oldPassword, newPassword string) error {
 	if userID <= 0 || oldPassword == "" || newPassword == "" {
 		return appErr.Wrap("AuthService.ChangePassword", appErr.ErrInvalidInput, nil)
 	}
@@ -181,16 +1225,326 @@ func (s *service) ChangePassword(userID int, oldPassword, newPassword string) er
 		return appErr.Wrap("AuthService.ChangePassword(compare)", appErr.ErrInvalidCredentials, err)
 	}
 
-	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err := s.checkNewPassword(u, "new_password", newPassword); err != nil {
+		return err
+	}
+	err = s.uow.Do(func(tx database.DBTX) error {
+		return s.setPassword(s.userService.WithTx(tx), s.repo.WithTx(tx), u, newPassword)
+	})
 	if err != nil {
-		return appErr.Wrap("AuthService.ChangePassword(hash)", appErr.ErrInternal, err)
+		return err
+	}
+
+	// Whoever knew the old password is logged out everywhere else
+	return s.repo.RevokeUserSessions(userID, sessionID, authModels.RevokedPasswordChange)
+}
+
+// checkNewPassword applies the password policy, including the password history
+func (s *service) checkNewPassword(u *userModels.User, field, pw string) error {
+	if err := s.policy.Check(field, pw); err != nil {
+		return err
+	}
+
+	recent, err := s.repo.RecentPasswordHashes(u.ID, s.passwordHistory)
+	if err != nil {
+		return err
+	}
+	for _, h := range append([]string{u.PasswordHash}, recent...) {
+		if bcrypt.CompareHashAndPassword([]byte(h), []byte(pw)) == nil {
+			return appErr.NewValidationError(field, "reused",
+				fmt.Sprintf("La contraseña no puede ser igual a ninguna de las últimas %d.", s.passwordHistory))
+		}
+	}
+	return nil
+}
+
+// setPassword stores an already checked password and records it in the history. The
+// caller runs it in a transaction, with users and repo bound to it.
+func (s *service) setPassword(users userDomain.Service, repo Repository, u *userModels.User, pw string) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(pw), bcrypt.DefaultCost)
+	if err != nil {
+		return appErr.Wrap("AuthService.setPassword(hash)", appErr.ErrInternal, err)
 	}
 
 	u.PasswordHash = string(hashed)
+	if err := users.UpdateUser(u); err != nil {
+		return err
+	}
+	return repo.AddPasswordHistory(u.ID, u.PasswordHash, s.passwordHistory)
+}
+
+// -----------------------------------------------------------------------------
+// Password reset and invitations
+// -----------------------------------------------------------------------------
+
+const (
+	// Reset links a user may request per hour; further requests are ignored
+	maxResetsPerHour = 3
+	resetPath        = "/restablecer-contrasena"
+	invitationPath   = "/aceptar-invitacion"
+)
+
+func (s *service) ForgotPassword(identifier string) error {
+	if identifier == "" {
+		return appErr.Wrap("AuthService.ForgotPassword", appErr.ErrInvalidInput, nil)
+	}
+
+	u, err := s.userService.GetByUsernameOrEmail(identifier)
+	if err != nil {
+		if errors.Is(err, appErr.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	sent, err := s.repo.CountUserTokens(u.ID, authModels.TokenPasswordReset, time.Now().Add(-time.Hour))
+	if err != nil || sent >= maxResetsPerHour {
+		return err
+	}
+
+	// Only the latest link works
+	if err := s.repo.InvalidateUserTokens(u.ID, authModels.TokenPasswordReset); err != nil {
+		return err
+	}
+	return s.sendUserToken(u, authModels.TokenPasswordReset, s.resetTTL, nil)
+}
+
+func (s *service) ResetPassword(token, newPassword string) error {
+	u, err := s.redeemUserToken(token, authModels.TokenPasswordReset, newPassword)
+	if err != nil {
+		return err
+	}
+
+	// Whoever was locked out can log in with the new password right away
+	if err := s.repo.UnlockUser(u.ID, u.ID); err != nil {
+		return err
+	}
+	return s.repo.RevokeUserSessions(u.ID, "", authModels.RevokedPasswordChange)
+}
+
+func (s *service) Invite(adminID int, username, email string, roleIDs []int) (int, error) {
+	if username == "" || email == "" {
+		return 0, appErr.Wrap("AuthService.Invite", appErr.ErrInvalidInput, nil)
+	}
+
+	// A random password nobody knows: the account cannot be used until the invitation is accepted
+	unusable, err := randomToken(32)
+	if err != nil {
+		return 0, appErr.Wrap("AuthService.Invite(password)", appErr.ErrInternal, err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(unusable), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, appErr.Wrap("AuthService.Invite(hash)", appErr.ErrInternal, err)
+	}
+
+	// The account, its roles and the invitation are created together, so there is
+	// never an account nobody can activate
+	var (
+		u      *userModels.User
+		token  string
+		invite *authModels.UserToken
+	)
+	err = s.uow.Do(func(tx database.DBTX) error {
+		users := s.userService.WithTx(tx)
+		if err := users.CreateUser(username, email, string(hash)); err != nil {
+			return err
+		}
+
+		var err error
+		if u, err = users.GetByUsernameOrEmail(username); err != nil {
+			return err
+		}
+		for _, roleID := range roleIDs {
+			if err := users.AddRole(u.ID, roleID); err != nil {
+				return err
+			}
+		}
+
+		token, invite, err = s.createUserToken(s.repo.WithTx(tx), u.ID, authModels.TokenInvitation, s.invitationTTL, &adminID)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Sent only once committed; if it fails, the invitation can be sent again
+	if err := s.notifyUserToken(u, invite, token); err != nil {
+		return u.ID, err
+	}
+	return u.ID, nil
+}
+
+func (s *service) ResendInvitation(adminID, userID int) error {
+	if userID <= 0 {
+		return appErr.Wrap("AuthService.ResendInvitation", appErr.ErrInvalidInput, nil)
+	}
+
+	u, err := s.userService.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	// Pending: the account was invited and has never set a password
+	invited, err := s.repo.CountUserTokens(u.ID, authModels.TokenInvitation, time.Time{})
+	if err != nil {
+		return err
+	}
+	passwords, err := s.repo.RecentPasswordHashes(u.ID, 1)
+	if err != nil {
+		return err
+	}
+	if invited == 0 || len(passwords) > 0 {
+		return appErr.NewDomainError(appErr.ErrOperationNotAllowed, "El usuario no tiene una invitación pendiente.")
+	}
+
+	// Only the latest link works
+	if err := s.repo.InvalidateUserTokens(u.ID, authModels.TokenInvitation); err != nil {
+		return err
+	}
+	return s.sendUserToken(u, authModels.TokenInvitation, s.invitationTTL, &adminID)
+}
+
+func (s *service) AcceptInvitation(token, newPassword string) error {
+	_, err := s.redeemUserToken(token, authModels.TokenInvitation, newPassword)
+	return err
+}
 
-	if err := s.userService.UpdateUser(u); err != nil {
+// sendUserToken creates a token of the given kind and sends its link to the user
+func (s *service) sendUserToken(u *userModels.User, kind string, ttl time.Duration, createdBy *int) error {
+	token, t, err := s.createUserToken(s.repo, u.ID, kind, ttl, createdBy)
+	if err != nil {
 		return err
 	}
+	return s.notifyUserToken(u, t, token)
+}
 
+// createUserToken stores a new token of the given kind and returns it with the
+// value its link carries
+func (s *service) createUserToken(repo Repository, userID int, kind string, ttl time.Duration, createdBy *int) (string, *authModels.UserToken, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", nil, appErr.Wrap("AuthService.createUserToken", appErr.ErrInternal, err)
+	}
+
+	t := &authModels.UserToken{
+		UsuarioID: userID,
+		Tipo:      kind,
+		TokenHash: hashToken(token),
+		ExpiraEn:  time.Now().Add(ttl),
+		CreadoPor: createdBy,
+	}
+	if err := repo.CreateUserToken(t); err != nil {
+		return "", nil, err
+	}
+	return token, t, nil
+}
+
+// notifyUserToken sends the link of a token created by createUserToken
+func (s *service) notifyUserToken(u *userModels.User, t *authModels.UserToken, token string) error {
+	path := resetPath
+	if t.Tipo == authModels.TokenInvitation {
+		path = invitationPath
+	}
+	err := s.notifier.Notify(authModels.Notification{
+		Tipo:     t.Tipo,
+		Para:     u.Email,
+		Usuario:  u.Username,
+		Enlace:   s.appURL + path + "?token=" + url.QueryEscape(token),
+		ExpiraEn: t.ExpiraEn,
+	})
+	if err != nil {
+		return appErr.Wrap("AuthService.notifyUserToken", appErr.ErrInternal, err)
+	}
 	return nil
 }
+
+// redeemUserToken sets the password of the token's user and uses up the token. The
+// password is checked first, and both writes share a transaction, so a rejected
+// password or a failed write does not waste the link.
+func (s *service) redeemUserToken(token, kind, newPassword string) (*userModels.User, error) {
+	invalid := appErr.NewDomainError(appErr.ErrInvalidToken, "El enlace es inválido o expiró. Solicite uno nuevo.")
+	if token == "" || newPassword == "" {
+		return nil, appErr.Wrap("AuthService.redeemUserToken", appErr.ErrInvalidInput, nil)
+	}
+
+	t, err := s.repo.GetUserToken(hashToken(token))
+	if err != nil {
+		if errors.Is(err, appErr.ErrNotFound) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if t.Tipo != kind || t.UsadoEn != nil || !time.Now().Before(t.ExpiraEn) {
+		return nil, invalid
+	}
+
+	u, err := s.userService.GetByID(t.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNewPassword(u, "password", newPassword); err != nil {
+		return nil, err
+	}
+
+	err = s.uow.Do(func(tx database.DBTX) error {
+		repo := s.repo.WithTx(tx)
+		if err := repo.UseUserToken(t.ID); err != nil {
+			if errors.Is(err, appErr.ErrConflict) {
+				return invalid
+			}
+			return err
+		}
+		return s.setPassword(s.userService.WithTx(tx), repo, u, newPassword)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// -----------------------------------------------------------------------------
+// Sessions
+// -----------------------------------------------------------------------------
+
+func (s *service) IsSessionActive(sessionID string) (bool, error) {
+	if sessionID == "" {
+		return false, nil
+	}
+	session, err := s.repo.GetSession(sessionID)
+	if err != nil {
+		if errors.Is(err, appErr.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return session.IsActive(time.Now()), nil
+}
+
+func (s *service) ListSessions(userID int) ([]authModels.Session, error) {
+	if userID <= 0 {
+		return nil, appErr.Wrap("AuthService.ListSessions", appErr.ErrInvalidInput, nil)
+	}
+	return s.repo.ListSessions(userID)
+}
+
+func (s *service) RevokeSession(userID int, sessionID string) error {
+	if userID <= 0 || sessionID == "" {
+		return appErr.Wrap("AuthService.RevokeSession", appErr.ErrInvalidInput, nil)
+	}
+
+	session, err := s.repo.GetSession(sessionID)
+	if err != nil && !errors.Is(err, appErr.ErrNotFound) {
+		return err
+	}
+	if session == nil || session.UsuarioID != userID {
+		return appErr.NewDomainError(appErr.ErrNotFound, "Sesión no encontrada.")
+	}
+	return s.repo.RevokeSession(sessionID, authModels.RevokedByAdmin)
+}
+
+func (s *service) RevokeAllSessions(userID int) error {
+	if userID <= 0 {
+		return appErr.Wrap("AuthService.RevokeAllSessions", appErr.ErrInvalidInput, nil)
+	}
+	return s.repo.RevokeUserSessions(userID, "", authModels.RevokedByAdmin)
+}
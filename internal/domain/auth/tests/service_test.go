@@ -0,0 +1,938 @@
+package tests
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/auth"
+	authMocks "github.com/tonitomc/healthcare-crm-api/internal/domain/auth/mocks"
+	authModels "github.com/tonitomc/healthcare-crm-api/internal/domain/auth/models"
+	rbacMocks "github.com/tonitomc/healthcare-crm-api/internal/domain/rbac/mocks"
+	rbacModels "github.com/tonitomc/healthcare-crm-api/internal/domain/rbac/models"
+	roleModels "github.com/tonitomc/healthcare-crm-api/internal/domain/role/models"
+	userMocks "github.com/tonitomc/healthcare-crm-api/internal/domain/user/mocks"
+	userModels "github.com/tonitomc/healthcare-crm-api/internal/domain/user/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/jwtkeys"
+	"github.com/tonitomc/healthcare-crm-api/pkg/password"
+	"github.com/tonitomc/healthcare-crm-api/pkg/totp"
+)
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+type fixture struct {
+	repo     *authMocks.MockRepository
+	users    *userMocks.MockService
+	rbac     *rbacMocks.MockService
+	notifier *authMocks.MockNotifier
+	uow      *fakeUnitOfWork
+	svc      auth.Service
+}
+
+func setup(t *testing.T) fixture {
+	ctrl := gomock.NewController(t)
+	f := fixture{
+		repo:     authMocks.NewMockRepository(ctrl),
+		users:    userMocks.NewMockService(ctrl),
+		rbac:     rbacMocks.NewMockService(ctrl),
+		notifier: authMocks.NewMockNotifier(ctrl),
+		uow:      &fakeUnitOfWork{},
+	}
+	f.svc = auth.NewService(f.repo, f.users, f.rbac, f.notifier, f.uow, auth.Config{
+		JWTSecret:         "secret",
+		Issuer:            "test",
+		AppURL:            "https://crm.test/",
+		BreachedPasswords: password.List{},
+	})
+	f.repo.EXPECT().WithTx(gomock.Any()).Return(f.repo).AnyTimes()
+	f.users.EXPECT().WithTx(gomock.Any()).Return(f.users).AnyTimes()
+	return f
+}
+
+// fakeUnitOfWork runs fn without a database and records how it ended.
+type fakeUnitOfWork struct {
+	committed  bool
+	rolledBack bool
+}
+
+func (u *fakeUnitOfWork) Do(fn func(tx database.DBTX) error) error {
+	if err := fn(nil); err != nil {
+		u.rolledBack = true
+		return err
+	}
+	u.committed = true
+	return nil
+}
+
+func hash(t *testing.T, password string) string {
+	h, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	return string(h)
+}
+
+func activeSession(id string) *authModels.Session {
+	return &authModels.Session{ID: id, UsuarioID: 3, ExpiraEn: time.Now().Add(time.Hour)}
+}
+
+// expectIssue expects a new access token with the user's access and a stored refresh token
+func (f fixture) expectIssue(sessionID string) {
+	f.rbac.EXPECT().GetUserAccess(3).Return(&rbacModels.RBAC{
+		User:        &userModels.User{ID: 3, Username: "ana"},
+		Roles:       []roleModels.Role{{Name: "doctor"}},
+		Permissions: []roleModels.Permission{{Name: "ver-pacientes"}},
+	}, nil)
+	f.repo.EXPECT().CreateRefreshToken(gomock.Any()).DoAndReturn(func(t *authModels.RefreshToken) error {
+		if t.SesionID != sessionID || len(t.TokenHash) != 64 {
+			return appErr.Wrap("unexpected refresh token", appErr.ErrInternal, nil)
+		}
+		return nil
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Login
+// -----------------------------------------------------------------------------
+
+func TestLogin_OpensSession(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().GetLoginCounter("ip:10.0.0.1").Return(&authModels.LoginCounter{}, nil)
+	f.users.EXPECT().GetByUsernameOrEmail("ana").Return(&userModels.User{ID: 3, PasswordHash: hash(t, "clave")}, nil)
+	f.repo.EXPECT().GetLoginCounter("usuario:3").Return(&authModels.LoginCounter{Fallos: 2, UltimoFallo: time.Now()}, nil)
+	f.repo.EXPECT().GetMFA(3).Return(nil, appErr.Wrap("AuthRepository.GetMFA", appErr.ErrNotFound, nil))
+	f.repo.EXPECT().ResetLoginCounter("usuario:3").Return(nil)
+	var sessionID string
+	f.repo.EXPECT().CreateSession(gomock.Any()).DoAndReturn(func(s *authModels.Session) error {
+		require.Equal(t, 3, s.UsuarioID)
+		require.Equal(t, "10.0.0.1", *s.IP)
+		require.Nil(t, s.UserAgent)
+		sessionID = s.ID
+		return nil
+	})
+	f.rbac.EXPECT().GetUserAccess(3).Return(&rbacModels.RBAC{User: &userModels.User{ID: 3}}, nil).Times(2)
+	f.repo.EXPECT().CreateRefreshToken(gomock.Any()).Return(nil)
+
+	tokens, err := f.svc.Login("ana", "clave", authModels.Client{IP: "10.0.0.1"})
+	require.NoError(t, err)
+	require.NotEmpty(t, tokens.RefreshToken)
+	require.Equal(t, 15*60, tokens.ExpiresIn)
+
+	_, claims, err := f.svc.ValidateToken(tokens.Token)
+	require.NoError(t, err)
+	require.Equal(t, sessionID, claims.SessionID)
+}
+
+func TestLogin_WrongPassword(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.users.EXPECT().GetByUsernameOrEmail("ana").Return(&userModels.User{ID: 3, PasswordHash: hash(t, "clave")}, nil)
+	f.repo.EXPECT().GetLoginCounter("usuario:3").Return(&authModels.LoginCounter{}, nil)
+	f.repo.EXPECT().RecordLoginFailure("usuario:3", gomock.Any()).Return(&authModels.LoginCounter{Fallos: 1}, nil)
+
+	_, err := f.svc.Login("ana", "otra", authModels.Client{})
+	require.ErrorIs(t, err, appErr.ErrInvalidCredentials)
+}
+
+func TestLogin_LocksAccountAfterMaxFailures(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().GetLoginCounter("ip:10.0.0.1").Return(&authModels.LoginCounter{}, nil)
+	f.users.EXPECT().GetByUsernameOrEmail("ana").Return(&userModels.User{ID: 3, PasswordHash: hash(t, "clave")}, nil)
+	f.repo.EXPECT().GetLoginCounter("usuario:3").Return(&authModels.LoginCounter{Fallos: 9, UltimoFallo: time.Now().Add(-time.Minute)}, nil)
+	f.repo.EXPECT().RecordLoginFailure("usuario:3", gomock.Any()).Return(&authModels.LoginCounter{Fallos: 10}, nil)
+	f.repo.EXPECT().RecordLoginFailure("ip:10.0.0.1", gomock.Any()).Return(&authModels.LoginCounter{Fallos: 10}, nil)
+	f.repo.EXPECT().Lock(gomock.Any()).DoAndReturn(func(l *authModels.Lockout) error {
+		require.Equal(t, "usuario:3", l.Clave)
+		require.Equal(t, 3, *l.UsuarioID)
+		require.Nil(t, l.IP)
+		require.WithinDuration(t, time.Now().Add(15*time.Minute), l.Hasta, time.Minute)
+		return nil
+	})
+
+	_, err := f.svc.Login("ana", "otra", authModels.Client{IP: "10.0.0.1"})
+	require.ErrorIs(t, err, appErr.ErrInvalidCredentials)
+}
+
+func TestLogin_LockedAccountRejectsCorrectPassword(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	until := time.Now().Add(10 * time.Minute)
+	f.users.EXPECT().GetByUsernameOrEmail("ana").Return(&userModels.User{ID: 3, PasswordHash: hash(t, "clave")}, nil)
+	f.repo.EXPECT().GetLoginCounter("usuario:3").Return(&authModels.LoginCounter{Fallos: 10, BloqueadoHasta: &until}, nil)
+
+	_, err := f.svc.Login("ana", "clave", authModels.Client{})
+	require.ErrorIs(t, err, appErr.ErrTooManyRequests)
+
+	var locked *authModels.LockedError
+	require.ErrorAs(t, err, &locked)
+	require.InDelta(t, 10*time.Minute, locked.RetryAfter, float64(time.Second))
+}
+
+func TestLogin_UnknownAccountIsCountedLikeAnyOther(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.users.EXPECT().GetByUsernameOrEmail("Nadie").Return(nil, appErr.Wrap("UserRepository.Get", appErr.ErrNotFound, nil))
+	f.repo.EXPECT().GetLoginCounter("identificador:nadie").Return(&authModels.LoginCounter{}, nil)
+	f.repo.EXPECT().RecordLoginFailure("identificador:nadie", gomock.Any()).Return(&authModels.LoginCounter{Fallos: 10}, nil)
+	f.repo.EXPECT().Lock(gomock.Any()).DoAndReturn(func(l *authModels.Lockout) error {
+		require.Nil(t, l.UsuarioID)
+		return nil
+	})
+
+	_, err := f.svc.Login("Nadie", "clave", authModels.Client{})
+	require.ErrorIs(t, err, appErr.ErrInvalidCredentials)
+}
+
+func TestLogin_LookupErrorIsNotCounted(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	// No counter is read or recorded: the database error is returned as is
+	f.users.EXPECT().GetByUsernameOrEmail("ana").Return(nil, appErr.Wrap("UserRepository.Get", appErr.ErrInternal, sql.ErrConnDone))
+
+	_, err := f.svc.Login("ana", "clave", authModels.Client{})
+	require.ErrorIs(t, err, appErr.ErrInternal)
+	require.NotErrorIs(t, err, appErr.ErrInvalidCredentials)
+}
+
+func TestLogin_DelaysAttemptsAfterRepeatedFailures(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	// Fifth failure a second ago: the next attempt must wait 4s
+	f.users.EXPECT().GetByUsernameOrEmail("ana").Return(&userModels.User{ID: 3, PasswordHash: hash(t, "clave")}, nil)
+	f.repo.EXPECT().GetLoginCounter("usuario:3").Return(&authModels.LoginCounter{Fallos: 5, UltimoFallo: time.Now().Add(-time.Second)}, nil)
+
+	_, err := f.svc.Login("ana", "clave", authModels.Client{})
+	var locked *authModels.LockedError
+	require.ErrorAs(t, err, &locked)
+	require.InDelta(t, 3*time.Second, locked.RetryAfter, float64(500*time.Millisecond))
+}
+
+func TestLogin_LocksIPAfterManyFailures(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().GetLoginCounter("ip:10.0.0.1").Return(&authModels.LoginCounter{}, nil)
+	f.users.EXPECT().GetByUsernameOrEmail("luis").Return(&userModels.User{ID: 4, PasswordHash: hash(t, "clave")}, nil)
+	f.repo.EXPECT().GetLoginCounter("usuario:4").Return(&authModels.LoginCounter{}, nil)
+	f.repo.EXPECT().RecordLoginFailure("usuario:4", gomock.Any()).Return(&authModels.LoginCounter{Fallos: 1}, nil)
+	f.repo.EXPECT().RecordLoginFailure("ip:10.0.0.1", gomock.Any()).Return(&authModels.LoginCounter{Fallos: 50}, nil)
+	f.repo.EXPECT().Lock(gomock.Any()).DoAndReturn(func(l *authModels.Lockout) error {
+		require.Equal(t, "10.0.0.1", *l.IP)
+		require.Nil(t, l.UsuarioID)
+		return nil
+	})
+
+	_, err := f.svc.Login("luis", "otra", authModels.Client{IP: "10.0.0.1"})
+	require.ErrorIs(t, err, appErr.ErrInvalidCredentials)
+}
+
+func TestUnlock(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.users.EXPECT().GetByID(3).Return(&userModels.User{ID: 3}, nil)
+	f.repo.EXPECT().UnlockUser(3, 1).Return(nil)
+
+	require.NoError(t, f.svc.Unlock(3, 1))
+}
+
+// -----------------------------------------------------------------------------
+// Two-step verification
+// -----------------------------------------------------------------------------
+
+func enrolled(t *testing.T) *authModels.MFA {
+	secret, err := totp.NewSecret()
+	require.NoError(t, err)
+	confirmed := time.Now().Add(-24 * time.Hour)
+	return &authModels.MFA{UsuarioID: 3, Secreto: secret, ConfirmadoEn: &confirmed}
+}
+
+func code(t *testing.T, mfa *authModels.MFA) string {
+	c, err := totp.Code(mfa.Secreto, time.Now())
+	require.NoError(t, err)
+	return c
+}
+
+// expectPasswordOK expects a login of ana with the right password
+func (f fixture) expectPasswordOK(t *testing.T) {
+	f.users.EXPECT().GetByUsernameOrEmail("ana").Return(&userModels.User{ID: 3, PasswordHash: hash(t, "clave")}, nil)
+	f.repo.EXPECT().GetLoginCounter("usuario:3").Return(&authModels.LoginCounter{}, nil)
+}
+
+func (f fixture) pendingLogin(t *testing.T, mfa *authModels.MFA) string {
+	f.expectPasswordOK(t)
+	f.repo.EXPECT().GetMFA(3).Return(mfa, nil)
+
+	result, err := f.svc.Login("ana", "clave", authModels.Client{})
+	require.NoError(t, err)
+	require.True(t, result.MFARequired)
+	require.Nil(t, result.TokenPair)
+	return result.MFAToken
+}
+
+func TestLogin_WithMFAReturnsPendingToken(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	mfaToken := f.pendingLogin(t, enrolled(t))
+
+	// The MFA token is no access token
+	_, _, err := f.svc.ValidateToken(mfaToken)
+	require.ErrorIs(t, err, appErr.ErrInvalidToken)
+}
+
+func TestLogin_RoleRequiringMFAForcesEnrollment(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.expectPasswordOK(t)
+	f.repo.EXPECT().GetMFA(3).Return(nil, appErr.Wrap("AuthRepository.GetMFA", appErr.ErrNotFound, nil))
+	f.rbac.EXPECT().GetUserAccess(3).Return(&rbacModels.RBAC{
+		User:  &userModels.User{ID: 3},
+		Roles: []roleModels.Role{{Name: "admin", RequiresMFA: true}},
+	}, nil)
+
+	result, err := f.svc.Login("ana", "clave", authModels.Client{})
+	require.NoError(t, err)
+	require.True(t, result.EnrollRequired)
+
+	f.repo.EXPECT().GetMFA(3).Return(nil, appErr.Wrap("AuthRepository.GetMFA", appErr.ErrNotFound, nil))
+	f.users.EXPECT().GetByID(3).Return(&userModels.User{ID: 3, Username: "ana"}, nil)
+	f.repo.EXPECT().SaveMFASecret(3, gomock.Any()).Return(nil)
+
+	enrollment, err := f.svc.BeginPendingEnrollment(result.MFAToken)
+	require.NoError(t, err)
+	require.Contains(t, enrollment.URI, "otpauth://totp/test:ana")
+
+	// The first code confirms the enrollment and completes the login
+	pending := &authModels.MFA{UsuarioID: 3, Secreto: enrollment.Secreto}
+	f.repo.EXPECT().GetLoginCounter("usuario:3").Return(&authModels.LoginCounter{}, nil)
+	f.repo.EXPECT().GetMFA(3).Return(pending, nil)
+	f.repo.EXPECT().ConfirmMFA(3, gomock.Any(), gomock.Len(10)).Return(nil)
+	f.repo.EXPECT().ResetLoginCounter("usuario:3").Return(nil)
+	f.repo.EXPECT().CreateSession(gomock.Any()).Return(nil)
+	f.rbac.EXPECT().GetUserAccess(3).Return(&rbacModels.RBAC{User: &userModels.User{ID: 3}}, nil)
+	f.repo.EXPECT().CreateRefreshToken(gomock.Any()).Return(nil)
+
+	tokens, err := f.svc.VerifyMFA(result.MFAToken, code(t, pending), authModels.Client{})
+	require.NoError(t, err)
+	require.NotEmpty(t, tokens.Token)
+	require.Len(t, tokens.RecoveryCodes, 10)
+}
+
+func TestVerifyMFA_OpensSession(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	mfa := enrolled(t)
+	mfaToken := f.pendingLogin(t, mfa)
+
+	f.repo.EXPECT().GetLoginCounter("usuario:3").Return(&authModels.LoginCounter{}, nil)
+	f.repo.EXPECT().GetMFA(3).Return(mfa, nil)
+	f.repo.EXPECT().UseMFAStep(3, gomock.Any()).Return(nil)
+	f.repo.EXPECT().ResetLoginCounter("usuario:3").Return(nil)
+	f.repo.EXPECT().CreateSession(gomock.Any()).Return(nil)
+	f.rbac.EXPECT().GetUserAccess(3).Return(&rbacModels.RBAC{User: &userModels.User{ID: 3}}, nil)
+	f.repo.EXPECT().CreateRefreshToken(gomock.Any()).Return(nil)
+
+	result, err := f.svc.VerifyMFA(mfaToken, code(t, mfa), authModels.Client{})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Token)
+	require.Empty(t, result.RecoveryCodes)
+}
+
+func TestVerifyMFA_ReusedCodeCountsAsFailure(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	mfa := enrolled(t)
+	mfaToken := f.pendingLogin(t, mfa)
+
+	f.repo.EXPECT().GetLoginCounter("usuario:3").Return(&authModels.LoginCounter{}, nil)
+	f.repo.EXPECT().GetMFA(3).Return(mfa, nil)
+	f.repo.EXPECT().UseMFAStep(3, gomock.Any()).Return(appErr.Wrap("AuthRepository.UseMFAStep", appErr.ErrConflict, nil))
+	f.repo.EXPECT().RecordLoginFailure("usuario:3", gomock.Any()).Return(&authModels.LoginCounter{Fallos: 1}, nil)
+
+	_, err := f.svc.VerifyMFA(mfaToken, code(t, mfa), authModels.Client{})
+	require.ErrorIs(t, err, appErr.ErrInvalidCredentials)
+}
+
+func TestVerifyMFA_AcceptsRecoveryCode(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	mfa := enrolled(t)
+	mfaToken := f.pendingLogin(t, mfa)
+
+	f.repo.EXPECT().GetLoginCounter("usuario:3").Return(&authModels.LoginCounter{}, nil)
+	f.repo.EXPECT().GetMFA(3).Return(mfa, nil)
+	f.repo.EXPECT().UseRecoveryCode(3, gomock.Any()).Return(appErr.Wrap("AuthRepository.UseRecoveryCode", appErr.ErrNotFound, nil))
+	f.repo.EXPECT().RecordLoginFailure("usuario:3", gomock.Any()).Return(&authModels.LoginCounter{Fallos: 1}, nil)
+
+	_, err := f.svc.VerifyMFA(mfaToken, "abcde-fghij", authModels.Client{})
+	require.ErrorIs(t, err, appErr.ErrInvalidCredentials)
+}
+
+func TestVerifyMFA_RejectsForgedToken(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	_, err := f.svc.VerifyMFA("not-a-token", "123456", authModels.Client{})
+	require.True(t, appErr.IsDomainError(err))
+}
+
+func TestDisableMFA_NotAllowedWhenRoleRequiresIt(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.rbac.EXPECT().GetUserAccess(3).Return(&rbacModels.RBAC{
+		User:  &userModels.User{ID: 3},
+		Roles: []roleModels.Role{{Name: "admin", RequiresMFA: true}},
+	}, nil)
+
+	err := f.svc.DisableMFA(3, "123456")
+	require.True(t, appErr.IsDomainError(err))
+}
+
+// -----------------------------------------------------------------------------
+// Refresh
+// -----------------------------------------------------------------------------
+
+func TestRefresh_RotatesToken(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().GetRefreshToken(gomock.Any()).Return(&authModels.RefreshToken{
+		ID: 8, SesionID: "s1", ExpiraEn: time.Now().Add(time.Hour),
+	}, nil)
+	f.repo.EXPECT().GetSession("s1").Return(activeSession("s1"), nil)
+	f.repo.EXPECT().MarkRefreshTokenUsed(8).Return(nil)
+	f.repo.EXPECT().ExtendSession("s1", gomock.Any()).Return(nil)
+	f.expectIssue("s1")
+
+	tokens, err := f.svc.Refresh("old-token")
+	require.NoError(t, err)
+	require.NotEqual(t, "old-token", tokens.RefreshToken)
+}
+
+func TestRefresh_ReuseRevokesSession(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	used := time.Now().Add(-time.Minute)
+	f.repo.EXPECT().GetRefreshToken(gomock.Any()).Return(&authModels.RefreshToken{
+		ID: 8, SesionID: "s1", ExpiraEn: time.Now().Add(time.Hour), UsadoEn: &used,
+	}, nil)
+	f.repo.EXPECT().GetSession("s1").Return(activeSession("s1"), nil)
+	f.repo.EXPECT().RevokeSession("s1", authModels.RevokedReuse).Return(nil)
+
+	_, err := f.svc.Refresh("stolen-token")
+	require.ErrorIs(t, err, appErr.ErrInvalidToken)
+}
+
+func TestRefresh_ConcurrentUseRevokesSession(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().GetRefreshToken(gomock.Any()).Return(&authModels.RefreshToken{
+		ID: 8, SesionID: "s1", ExpiraEn: time.Now().Add(time.Hour),
+	}, nil)
+	f.repo.EXPECT().GetSession("s1").Return(activeSession("s1"), nil)
+	f.repo.EXPECT().MarkRefreshTokenUsed(8).Return(appErr.Wrap("repo", appErr.ErrConflict, nil))
+	f.repo.EXPECT().RevokeSession("s1", authModels.RevokedReuse).Return(nil)
+
+	_, err := f.svc.Refresh("raced-token")
+	require.ErrorIs(t, err, appErr.ErrInvalidToken)
+}
+
+func TestRefresh_RevokedSession(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	session := activeSession("s1")
+	revoked := time.Now()
+	session.RevocadaEn = &revoked
+	f.repo.EXPECT().GetRefreshToken(gomock.Any()).Return(&authModels.RefreshToken{
+		ID: 8, SesionID: "s1", ExpiraEn: time.Now().Add(time.Hour),
+	}, nil)
+	f.repo.EXPECT().GetSession("s1").Return(session, nil)
+
+	_, err := f.svc.Refresh("token")
+	require.ErrorIs(t, err, appErr.ErrInvalidToken)
+}
+
+func TestRefresh_UnknownToken(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().GetRefreshToken(gomock.Any()).Return(nil, appErr.Wrap("repo", appErr.ErrNotFound, nil))
+
+	_, err := f.svc.Refresh("nope")
+	require.ErrorIs(t, err, appErr.ErrInvalidToken)
+}
+
+// -----------------------------------------------------------------------------
+// Sessions
+// -----------------------------------------------------------------------------
+
+func TestIsSessionActive(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().GetSession("s1").Return(activeSession("s1"), nil)
+	f.repo.EXPECT().GetSession("gone").Return(nil, appErr.Wrap("repo", appErr.ErrNotFound, nil))
+
+	active, err := f.svc.IsSessionActive("s1")
+	require.NoError(t, err)
+	require.True(t, active)
+
+	active, err = f.svc.IsSessionActive("gone")
+	require.NoError(t, err)
+	require.False(t, active)
+
+	active, err = f.svc.IsSessionActive("")
+	require.NoError(t, err)
+	require.False(t, active, "tokens issued without a session")
+}
+
+func TestRevokeSession_OtherUser(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().GetSession("s1").Return(activeSession("s1"), nil)
+
+	err := f.svc.RevokeSession(4, "s1")
+	require.True(t, appErr.IsDomainError(err))
+}
+
+func TestChangePassword_ClosesOtherSessions(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.users.EXPECT().GetByID(3).Return(&userModels.User{ID: 3, PasswordHash: hash(t, "vieja")}, nil)
+	f.repo.EXPECT().RecentPasswordHashes(3, 5).Return(nil, nil)
+	f.users.EXPECT().UpdateUser(gomock.Any()).Return(nil)
+	f.repo.EXPECT().AddPasswordHistory(3, gomock.Any(), 5).Return(nil)
+	f.repo.EXPECT().RevokeUserSessions(3, "s1", authModels.RevokedPasswordChange).Return(nil)
+
+	require.NoError(t, f.svc.ChangePassword(3, "s1", "vieja", "una frase nueva"))
+}
+
+func TestChangePassword_RejectsRecentPassword(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.users.EXPECT().GetByID(3).Return(&userModels.User{ID: 3, PasswordHash: hash(t, "vieja")}, nil)
+	f.repo.EXPECT().RecentPasswordHashes(3, 5).Return([]string{hash(t, "una frase anterior")}, nil)
+
+	err := f.svc.ChangePassword(3, "s1", "vieja", "una frase anterior")
+	details, ok := appErr.ValidationDetails(err)
+	require.True(t, ok)
+	require.Equal(t, "reused", details[0].Regla)
+}
+
+// -----------------------------------------------------------------------------
+// Password policy, reset and invitations
+// -----------------------------------------------------------------------------
+
+func TestRegister_RejectsShortPassword(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	err := f.svc.Register("ana", "ana@example.com", "corta")
+	details, ok := appErr.ValidationDetails(err)
+	require.True(t, ok)
+	require.Equal(t, "password", details[0].Campo)
+}
+
+func TestForgotPassword_UnknownAccountSendsNothing(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.users.EXPECT().GetByUsernameOrEmail("nadie").Return(nil, appErr.Wrap("UserRepository.Get", appErr.ErrNotFound, nil))
+
+	require.NoError(t, f.svc.ForgotPassword("nadie"))
+}
+
+func TestForgotPassword_SendsLink(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.users.EXPECT().GetByUsernameOrEmail("ana").Return(&userModels.User{ID: 3, Username: "ana", Email: "ana@example.com"}, nil)
+	f.repo.EXPECT().CountUserTokens(3, authModels.TokenPasswordReset, gomock.Any()).Return(0, nil)
+	f.repo.EXPECT().InvalidateUserTokens(3, authModels.TokenPasswordReset).Return(nil)
+	var stored string
+	f.repo.EXPECT().CreateUserToken(gomock.Any()).DoAndReturn(func(tok *authModels.UserToken) error {
+		require.Equal(t, authModels.TokenPasswordReset, tok.Tipo)
+		require.WithinDuration(t, time.Now().Add(time.Hour), tok.ExpiraEn, time.Minute)
+		stored = tok.TokenHash
+		return nil
+	})
+	f.notifier.EXPECT().Notify(gomock.Any()).DoAndReturn(func(n authModels.Notification) error {
+		require.Equal(t, "ana@example.com", n.Para)
+		require.True(t, strings.HasPrefix(n.Enlace, "https://crm.test/restablecer-contrasena?token="))
+		token := strings.TrimPrefix(n.Enlace, "https://crm.test/restablecer-contrasena?token=")
+		require.NotEqual(t, token, stored) // only the hash is stored
+		return nil
+	})
+
+	require.NoError(t, f.svc.ForgotPassword("ana"))
+}
+
+func TestForgotPassword_IgnoresTooManyRequests(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.users.EXPECT().GetByUsernameOrEmail("ana").Return(&userModels.User{ID: 3}, nil)
+	f.repo.EXPECT().CountUserTokens(3, authModels.TokenPasswordReset, gomock.Any()).Return(3, nil)
+
+	require.NoError(t, f.svc.ForgotPassword("ana"))
+}
+
+func TestResetPassword_ClosesSessions(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().GetUserToken(gomock.Any()).Return(&authModels.UserToken{
+		ID: 4, UsuarioID: 3, Tipo: authModels.TokenPasswordReset, ExpiraEn: time.Now().Add(time.Minute),
+	}, nil)
+	f.users.EXPECT().GetByID(3).Return(&userModels.User{ID: 3, PasswordHash: hash(t, "olvidada")}, nil)
+	f.repo.EXPECT().RecentPasswordHashes(3, 5).Return(nil, nil)
+	f.repo.EXPECT().UseUserToken(4).Return(nil)
+	f.users.EXPECT().UpdateUser(gomock.Any()).Return(nil)
+	f.repo.EXPECT().AddPasswordHistory(3, gomock.Any(), 5).Return(nil)
+	f.repo.EXPECT().UnlockUser(3, 3).Return(nil)
+	f.repo.EXPECT().RevokeUserSessions(3, "", authModels.RevokedPasswordChange).Return(nil)
+
+	require.NoError(t, f.svc.ResetPassword("token", "una frase nueva"))
+	require.True(t, f.uow.committed)
+}
+
+func TestResetPassword_FailedWriteKeepsToken(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	// The token is only used up together with the new password, so the same link
+	// works again after the failure
+	f.repo.EXPECT().GetUserToken(gomock.Any()).Return(&authModels.UserToken{
+		ID: 4, UsuarioID: 3, Tipo: authModels.TokenPasswordReset, ExpiraEn: time.Now().Add(time.Minute),
+	}, nil).Times(2)
+	stored := hash(t, "olvidada")
+	f.users.EXPECT().GetByID(3).DoAndReturn(func(int) (*userModels.User, error) {
+		return &userModels.User{ID: 3, PasswordHash: stored}, nil
+	}).Times(2)
+	f.repo.EXPECT().RecentPasswordHashes(3, 5).Return(nil, nil).Times(2)
+	f.repo.EXPECT().UseUserToken(4).Return(nil).Times(2)
+	f.users.EXPECT().UpdateUser(gomock.Any()).Return(nil).Times(2)
+	gomock.InOrder(
+		f.repo.EXPECT().AddPasswordHistory(3, gomock.Any(), 5).Return(appErr.Wrap("AuthRepository.AddPasswordHistory", appErr.ErrInternal, sql.ErrConnDone)),
+		f.repo.EXPECT().AddPasswordHistory(3, gomock.Any(), 5).Return(nil),
+	)
+	f.repo.EXPECT().UnlockUser(3, 3).Return(nil)
+	f.repo.EXPECT().RevokeUserSessions(3, "", authModels.RevokedPasswordChange).Return(nil)
+
+	err := f.svc.ResetPassword("token", "una frase nueva")
+	require.ErrorIs(t, err, appErr.ErrInternal)
+	require.True(t, f.uow.rolledBack)
+
+	require.NoError(t, f.svc.ResetPassword("token", "una frase nueva"))
+	require.True(t, f.uow.committed)
+}
+
+func TestResetPassword_RejectsInvitationOrExpiredToken(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().GetUserToken(gomock.Any()).Return(&authModels.UserToken{
+		ID: 4, UsuarioID: 3, Tipo: authModels.TokenInvitation, ExpiraEn: time.Now().Add(time.Minute),
+	}, nil)
+	f.repo.EXPECT().GetUserToken(gomock.Any()).Return(&authModels.UserToken{
+		ID: 5, UsuarioID: 3, Tipo: authModels.TokenPasswordReset, ExpiraEn: time.Now().Add(-time.Minute),
+	}, nil)
+
+	require.True(t, appErr.IsDomainError(f.svc.ResetPassword("invitacion", "una frase nueva")))
+	require.True(t, appErr.IsDomainError(f.svc.ResetPassword("vencido", "una frase nueva")))
+}
+
+func TestResetPassword_WeakPasswordKeepsToken(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.repo.EXPECT().GetUserToken(gomock.Any()).Return(&authModels.UserToken{
+		ID: 4, UsuarioID: 3, Tipo: authModels.TokenPasswordReset, ExpiraEn: time.Now().Add(time.Minute),
+	}, nil)
+	f.users.EXPECT().GetByID(3).Return(&userModels.User{ID: 3, PasswordHash: hash(t, "olvidada")}, nil)
+
+	err := f.svc.ResetPassword("token", "corta")
+	require.ErrorIs(t, err, appErr.ErrInvalidInput)
+}
+
+func TestInvite_CreatesAccountAndSendsLink(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.users.EXPECT().CreateUser("luis", "luis@example.com", gomock.Any()).Return(nil)
+	f.users.EXPECT().GetByUsernameOrEmail("luis").Return(&userModels.User{ID: 9, Username: "luis", Email: "luis@example.com"}, nil)
+	f.users.EXPECT().AddRole(9, 2).Return(nil)
+	f.repo.EXPECT().CreateUserToken(gomock.Any()).DoAndReturn(func(tok *authModels.UserToken) error {
+		require.Equal(t, authModels.TokenInvitation, tok.Tipo)
+		require.Equal(t, 1, *tok.CreadoPor)
+		return nil
+	})
+	f.notifier.EXPECT().Notify(gomock.Any()).DoAndReturn(func(n authModels.Notification) error {
+		require.Contains(t, n.Enlace, "/aceptar-invitacion?token=")
+		return nil
+	})
+
+	id, err := f.svc.Invite(1, "luis", "luis@example.com", []int{2})
+	require.NoError(t, err)
+	require.Equal(t, 9, id)
+	require.True(t, f.uow.committed)
+}
+
+func TestInvite_FailedRoleLeavesNoAccount(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	// No token is created and nothing is sent
+	f.users.EXPECT().CreateUser("luis", "luis@example.com", gomock.Any()).Return(nil)
+	f.users.EXPECT().GetByUsernameOrEmail("luis").Return(&userModels.User{ID: 9, Username: "luis", Email: "luis@example.com"}, nil)
+	f.users.EXPECT().AddRole(9, 2).Return(appErr.Wrap("UserRepository.GetByID", appErr.ErrNotFound, nil))
+
+	_, err := f.svc.Invite(1, "luis", "luis@example.com", []int{2})
+	require.ErrorIs(t, err, appErr.ErrNotFound)
+	require.True(t, f.uow.rolledBack)
+}
+
+func TestInvite_FailedNotificationKeepsInvitation(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.users.EXPECT().CreateUser("luis", "luis@example.com", gomock.Any()).Return(nil)
+	f.users.EXPECT().GetByUsernameOrEmail("luis").Return(&userModels.User{ID: 9, Username: "luis", Email: "luis@example.com"}, nil)
+	f.repo.EXPECT().CreateUserToken(gomock.Any()).Return(nil)
+	f.notifier.EXPECT().Notify(gomock.Any()).Return(errors.New("smtp caído"))
+
+	id, err := f.svc.Invite(1, "luis", "luis@example.com", nil)
+	require.ErrorIs(t, err, appErr.ErrInternal)
+	require.Equal(t, 9, id)
+	require.True(t, f.uow.committed)
+}
+
+func TestResendInvitation_SendsNewLink(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.users.EXPECT().GetByID(9).Return(&userModels.User{ID: 9, Username: "luis", Email: "luis@example.com"}, nil)
+	f.repo.EXPECT().CountUserTokens(9, authModels.TokenInvitation, time.Time{}).Return(1, nil)
+	f.repo.EXPECT().RecentPasswordHashes(9, 1).Return(nil, nil)
+	gomock.InOrder(
+		f.repo.EXPECT().InvalidateUserTokens(9, authModels.TokenInvitation).Return(nil),
+		f.repo.EXPECT().CreateUserToken(gomock.Any()).DoAndReturn(func(tok *authModels.UserToken) error {
+			require.Equal(t, authModels.TokenInvitation, tok.Tipo)
+			require.Equal(t, 1, *tok.CreadoPor)
+			return nil
+		}),
+	)
+	f.notifier.EXPECT().Notify(gomock.Any()).DoAndReturn(func(n authModels.Notification) error {
+		require.Contains(t, n.Enlace, "/aceptar-invitacion?token=")
+		return nil
+	})
+
+	require.NoError(t, f.svc.ResendInvitation(1, 9))
+}
+
+func TestResendInvitation_AcceptedInvitation(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	f.users.EXPECT().GetByID(9).Return(&userModels.User{ID: 9, Username: "luis", Email: "luis@example.com"}, nil)
+	f.repo.EXPECT().CountUserTokens(9, authModels.TokenInvitation, time.Time{}).Return(1, nil)
+	f.repo.EXPECT().RecentPasswordHashes(9, 1).Return([]string{"hash"}, nil)
+
+	err := f.svc.ResendInvitation(1, 9)
+	require.True(t, appErr.IsDomainError(err))
+}
+
+// -----------------------------------------------------------------------------
+// Signing keys
+// -----------------------------------------------------------------------------
+
+// keyStore stands in for claves_jwt, shared by the services of several instances
+type keyStore struct {
+	keys []authModels.SigningKey
+}
+
+func setupKeys(t *testing.T, store *keyStore, secret string) fixture {
+	f := setup(t)
+	f.svc = auth.NewService(f.repo, f.users, f.rbac, f.notifier, f.uow, auth.Config{
+		JWTSecret:        secret,
+		Issuer:           "test",
+		SigningAlgorithm: jwtkeys.EdDSA,
+	})
+	f.repo.EXPECT().ListSigningKeys().DoAndReturn(func() ([]authModels.SigningKey, error) {
+		keys := []authModels.SigningKey{}
+		for _, k := range store.keys {
+			if k.ExpiraEn.After(time.Now()) {
+				keys = append(keys, k)
+			}
+		}
+		return keys, nil
+	}).AnyTimes()
+	f.repo.EXPECT().CreateSigningKey(gomock.Any()).DoAndReturn(func(k *authModels.SigningKey) error {
+		store.keys = append(store.keys, *k)
+		return nil
+	}).AnyTimes()
+	f.repo.EXPECT().RetireSigningKey(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(kid string, signUntil, verifyUntil time.Time) error {
+		for i := range store.keys {
+			if store.keys[i].Kid == kid {
+				store.keys[i].FirmaHasta, store.keys[i].ExpiraEn = signUntil, verifyUntil
+			}
+		}
+		return nil
+	}).AnyTimes()
+	return f
+}
+
+func tokenWithKid(kid string) *jwt.Token {
+	return &jwt.Token{Method: jwt.SigningMethodEdDSA, Header: map[string]any{"kid": kid}}
+}
+
+func TestRotateKeys_CreatesFirstKeyAndSignsWithIt(t *testing.T) {
+	t.Parallel()
+	store := &keyStore{}
+	f := setupKeys(t, store, "secret")
+
+	require.NoError(t, f.svc.RotateKeys(false))
+	require.Len(t, store.keys, 1)
+	key := store.keys[0]
+	require.Equal(t, jwtkeys.EdDSA, key.Algoritmo)
+	require.NotContains(t, key.ClavePrivada, "PRIVATE KEY", "stored sealed")
+	require.WithinDuration(t, time.Now().Add(30*24*time.Hour), key.FirmaHasta, time.Minute)
+	require.Equal(t, key.FirmaHasta.Add(15*time.Minute+5*time.Minute), key.ExpiraEn)
+
+	jwks := f.svc.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	require.Equal(t, key.Kid, jwks.Keys[0].Kid)
+	require.Equal(t, "OKP", jwks.Keys[0].Kty)
+
+	f.repo.EXPECT().GetLoginCounter("ip:10.0.0.1").Return(&authModels.LoginCounter{}, nil)
+	f.users.EXPECT().GetByUsernameOrEmail("ana").Return(&userModels.User{ID: 3, PasswordHash: hash(t, "clave")}, nil)
+	f.repo.EXPECT().GetLoginCounter("usuario:3").Return(&authModels.LoginCounter{}, nil)
+	f.repo.EXPECT().GetMFA(3).Return(nil, appErr.Wrap("AuthRepository.GetMFA", appErr.ErrNotFound, nil))
+	f.repo.EXPECT().ResetLoginCounter("usuario:3").Return(nil)
+	f.repo.EXPECT().CreateSession(gomock.Any()).Return(nil)
+	f.rbac.EXPECT().GetUserAccess(3).Return(&rbacModels.RBAC{User: &userModels.User{ID: 3}}, nil).Times(2)
+	f.repo.EXPECT().CreateRefreshToken(gomock.Any()).Return(nil)
+
+	tokens, err := f.svc.Login("ana", "clave", authModels.Client{IP: "10.0.0.1"})
+	require.NoError(t, err)
+	token, _, err := f.svc.ValidateToken(tokens.Token)
+	require.NoError(t, err)
+	require.Equal(t, key.Kid, token.Header["kid"])
+
+	// An HS256 token signed with JWT_SECRET is no longer an access token
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, authModels.Claims{UserID: 3}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	_, _, err = f.svc.ValidateToken(forged)
+	require.ErrorIs(t, err, appErr.ErrInvalidToken)
+}
+
+func TestRotateKeys_PublishesNextKeyBeforeItSigns(t *testing.T) {
+	t.Parallel()
+	store := &keyStore{}
+	f := setupKeys(t, store, "secret")
+	require.NoError(t, f.svc.RotateKeys(false))
+
+	// Nothing to do while the current key has time left
+	require.NoError(t, f.svc.RotateKeys(false))
+	require.Len(t, store.keys, 1)
+
+	store.keys[0].FirmaHasta = time.Now().Add(2 * time.Hour)
+	require.NoError(t, f.svc.RotateKeys(false))
+	require.Len(t, store.keys, 2)
+	current, next := store.keys[0], store.keys[1]
+	require.Equal(t, current.FirmaHasta, next.FirmaDesde)
+
+	// Published, but the current key still signs
+	require.Len(t, f.svc.JWKS().Keys, 2)
+	_, err := f.svc.Keyfunc(tokenWithKid(next.Kid))
+	require.NoError(t, err)
+	require.NoError(t, f.svc.RotateKeys(false))
+	require.Len(t, store.keys, 2)
+}
+
+func TestRotateKeys_ForceRetiresCurrentKey(t *testing.T) {
+	t.Parallel()
+	store := &keyStore{}
+	f := setupKeys(t, store, "secret")
+	require.NoError(t, f.svc.RotateKeys(false))
+	old := store.keys[0]
+
+	require.NoError(t, f.svc.RotateKeys(true))
+	require.Len(t, store.keys, 2)
+	require.True(t, store.keys[0].FirmaHasta.Before(old.FirmaHasta))
+	require.WithinDuration(t, time.Now().Add(20*time.Minute), store.keys[0].ExpiraEn, time.Minute)
+
+	// Tokens signed with the old key still verify until they expire
+	_, err := f.svc.Keyfunc(tokenWithKid(old.Kid))
+	require.NoError(t, err)
+}
+
+func TestRotateKeys_ReplacesKeysSealedWithAnotherSecret(t *testing.T) {
+	t.Parallel()
+	store := &keyStore{}
+	require.NoError(t, setupKeys(t, store, "old-secret").svc.RotateKeys(false))
+
+	f := setupKeys(t, store, "new-secret")
+	require.NoError(t, f.svc.RotateKeys(false))
+	require.Len(t, store.keys, 2)
+
+	jwks := f.svc.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	require.Equal(t, store.keys[1].Kid, jwks.Keys[0].Kid)
+}
+
+func TestKeyfunc_ReloadsKeysRotatedByAnotherInstance(t *testing.T) {
+	t.Parallel()
+	store := &keyStore{}
+	a := setupKeys(t, store, "secret")
+	b := setupKeys(t, store, "secret")
+	require.NoError(t, a.svc.RotateKeys(false))
+	require.NoError(t, b.svc.RotateKeys(false))
+
+	require.NoError(t, a.svc.RotateKeys(true))
+	_, err := b.svc.Keyfunc(tokenWithKid(store.keys[1].Kid))
+	require.NoError(t, err)
+
+	// Unknown kids reload at most once a minute
+	_, err = b.svc.Keyfunc(tokenWithKid("unknown"))
+	require.ErrorIs(t, err, jwtkeys.ErrUnknownKey)
+}
+
+func TestRotateKeys_NotAllowedWithHS256(t *testing.T) {
+	t.Parallel()
+	f := setup(t)
+
+	require.NoError(t, f.svc.RotateKeys(false))
+	err := f.svc.RotateKeys(true)
+	require.True(t, appErr.IsDomainError(err))
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/consultation/models"
 	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
 )
@@ -22,46 +23,106 @@ func NewHandler(s Service) *Handler {
 // ===================== ROUTES =====================
 
 func (h *Handler) RegisterRoutes(g *echo.Group) {
-	consultations := g.Group("/consultations", ErrorMiddleware())
+	consultations := g.Group("/consultations", ErrorMiddleware(), middleware.Audit("consulta", "id", h.auditedConsultation))
 
 	// --- Consultations ---
 	consultations.GET("", h.GetAll, middleware.RequirePermission("ver-consultas"))
 	consultations.GET("/:id", h.GetByID, middleware.RequirePermission("ver-consultas"))
 	consultations.GET("/patient/:patientId", h.GetByPatient, middleware.RequirePermission("ver-consultas"))
-	consultations.GET("/:id/details", h.GetDetails, middleware.RequirePermission("ver-consultas"))
+	consultations.GET("/:id/details", h.GetDetails, middleware.RequirePermission("ver-consultas"), middleware.AccessLog("detalles_consulta", "id", h.consultationPatient))
 	consultations.POST("", h.Create, middleware.RequirePermission("manejar-consultas"))
 	consultations.PUT("/:id", h.Update, middleware.RequirePermission("manejar-consultas"))
 	consultations.DELETE("/:id", h.Delete, middleware.RequirePermission("manejar-consultas"))
 
+	// Diagnostics, treatments and answers are audited as their own entities
+	auditDiagnostic := middleware.Audit("diagnostico", "diagId", h.auditedDiagnostic)
+	auditTreatment := middleware.Audit("tratamiento", "treatmentId", h.auditedTreatment)
+	auditAnswers := middleware.Audit("respuestas", "id", h.auditedAnswers)
+
 	// --- Diagnostics ---
 	consultations.GET("/:id/diagnostics", h.GetDiagnosticsByConsultation, middleware.RequirePermission("ver-consultas"))
 	consultations.GET("/:id/diagnostics/:diagId", h.GetDiagnosticByID, middleware.RequirePermission("ver-consultas"))
-	consultations.POST("/:id/diagnostics", h.CreateDiagnostic, middleware.RequirePermission("manejar-consultas"))
-	consultations.PUT("/:id/diagnostics/:diagId", h.UpdateDiagnostic, middleware.RequirePermission("manejar-consultas"))
-	consultations.DELETE("/:id/diagnostics/:diagId", h.DeleteDiagnostic, middleware.RequirePermission("manejar-consultas"))
+	consultations.POST("/:id/diagnostics", h.CreateDiagnostic, middleware.RequirePermission("manejar-consultas"), auditDiagnostic)
+	consultations.PUT("/:id/diagnostics/:diagId", h.UpdateDiagnostic, middleware.RequirePermission("manejar-consultas"), auditDiagnostic)
+	consultations.DELETE("/:id/diagnostics/:diagId", h.DeleteDiagnostic, middleware.RequirePermission("manejar-consultas"), auditDiagnostic)
 
 	// --- Treatments ---
 	consultations.GET("/:id/diagnostics/:diagId/treatments", h.GetTreatmentsByDiagnostic, middleware.RequirePermission("ver-consultas"))
 	consultations.GET("/:id/diagnostics/:diagId/treatments/:treatmentId", h.GetTreatmentByID, middleware.RequirePermission("ver-consultas"))
-	consultations.POST("/:id/diagnostics/:diagId/treatments", h.CreateTreatment, middleware.RequirePermission("manejar-consultas"))
-	consultations.PUT("/:id/diagnostics/:diagId/treatments/:treatmentId", h.UpdateTreatment, middleware.RequirePermission("manejar-consultas"))
-	consultations.DELETE("/:id/diagnostics/:diagId/treatments/:treatmentId", h.DeleteTreatment, middleware.RequirePermission("manejar-consultas"))
+	consultations.POST("/:id/diagnostics/:diagId/treatments", h.CreateTreatment, middleware.RequirePermission("manejar-consultas"), auditTreatment)
+	consultations.PUT("/:id/diagnostics/:diagId/treatments/:treatmentId", h.UpdateTreatment, middleware.RequirePermission("manejar-consultas"), auditTreatment)
+	consultations.DELETE("/:id/diagnostics/:diagId/treatments/:treatmentId", h.DeleteTreatment, middleware.RequirePermission("manejar-consultas"), auditTreatment)
 
 	// --- Answers ---
 	consultations.GET("/:id/answers", h.GetAnswersByConsultation, middleware.RequirePermission("ver-consultas"))
-	consultations.POST("/:id/answers", h.AddAnswers, middleware.RequirePermission("manejar-consultas"))
-	consultations.PUT("/:id/answers", h.UpdateAnswers, middleware.RequirePermission("manejar-consultas"))
-	consultations.DELETE("/:id/answers", h.DeleteAnswers, middleware.RequirePermission("manejar-consultas"))
+	consultations.POST("/:id/answers", h.AddAnswers, middleware.RequirePermission("manejar-consultas"), auditAnswers)
+	consultations.PUT("/:id/answers", h.UpdateAnswers, middleware.RequirePermission("manejar-consultas"), auditAnswers)
+	consultations.DELETE("/:id/answers", h.DeleteAnswers, middleware.RequirePermission("manejar-consultas"), auditAnswers)
+}
+
+// consultationPatient resolves the patient of a consultation for middleware.AccessLog
+func (h *Handler) consultationPatient(id int) (int, error) {
+	consultation, err := h.service.GetByID(id)
+	if err != nil {
+		return 0, err
+	}
+	return consultation.PacienteID, nil
+}
+
+// States middleware.Audit records before and after a change
+func (h *Handler) auditedConsultation(id int) (interface{}, error) {
+	return h.service.GetByID(id)
+}
+
+func (h *Handler) auditedDiagnostic(id int) (interface{}, error) {
+	return h.service.GetDiagnosticByID(id)
+}
+
+func (h *Handler) auditedTreatment(id int) (interface{}, error) {
+	return h.service.GetTreatmentByID(id)
+}
+
+func (h *Handler) auditedAnswers(consultationID int) (interface{}, error) {
+	return h.service.GetAnswersByConsultation(consultationID)
 }
 
 // ===================== CONSULTATIONS =====================
 
+// listSpec declara el orden y los filtros que acepta GET /consultations
+var listSpec = query.Spec{
+	Sortable: []string{"id", "fecha", "paciente_id"},
+	Filters: map[string]query.FilterType{
+		"paciente_id": query.Int,
+		"cita_id":     query.Int,
+		"completada":  query.Bool,
+		"motivo":      query.Text,
+		"desde":       query.DateFrom,
+		"hasta":       query.DateTo,
+	},
+	DefaultSort: []query.Sort{{Field: "fecha", Desc: true}},
+}
+
+// listKey da los valores de orden de una consulta para el cursor de la página siguiente
+func listKey(c models.Consultation, field string) interface{} {
+	switch field {
+	case "fecha":
+		return c.Fecha
+	case "paciente_id":
+		return c.PacienteID
+	}
+	return c.ID
+}
+
 func (h *Handler) GetAll(c echo.Context) error {
-	consultations, err := h.service.GetAll()
+	opts, err := query.Parse(c, listSpec)
 	if err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, consultations)
+	consultations, total, err := h.service.GetAll(opts)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, query.NewPage(consultations, total, opts, listKey))
 }
 
 func (h *Handler) GetByID(c echo.Context) error {
@@ -109,7 +170,11 @@ func (h *Handler) Update(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return appErr.Wrap("ConsultationHandler.Update.Bind", appErr.ErrInvalidInput, err)
 	}
-	if err := h.service.Update(id, &req); err != nil {
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return appErr.Wrap("ConsultationHandler.Update.GetClaims", appErr.ErrUnauthorized, nil)
+	}
+	if err := h.service.Update(id, claims.UserID, &req); err != nil {
 		return err
 	}
 	return c.JSON(http.StatusOK, echo.Map{"message": "Consulta actualizada correctamente"})
@@ -9,6 +9,7 @@ type Consultation struct {
 	CuestionarioID int       `json:"cuestionario_id,omitempty"`
 	Fecha          time.Time `json:"fecha"`
 	Completada     bool      `json:"completada"`
+	CitaID         *int      `json:"cita_id,omitempty"` // cita desde la que se hizo check-in
 }
 
 // ConsultationWithDetails represents a consultation and its related diagnostics and treatments.
@@ -19,5 +20,6 @@ type ConsultationWithDetails struct {
 	CuestionarioID int                        `json:"cuestionario_id,omitempty"`
 	Fecha          string                     `json:"fecha"`
 	Completada     bool                       `json:"completada"`
+	CitaID         *int                       `json:"cita_id,omitempty"`
 	Diagnostics    []DiagnosticWithTreatments `json:"diagnostics"`
 }
@@ -3,13 +3,31 @@ package models
 import "encoding/json"
 
 type ConsultationCreateDTO struct {
-	PacienteID     int    `json:"paciente_id" validate:"required"`
-	Motivo         string `json:"motivo"`
-	CuestionarioID int    `json:"cuestionario_id,omitempty"`
+	PacienteID     int    `json:"paciente_id" validate:"required,min=1"`
+	Motivo         string `json:"motivo" validate:"required"`
+	CuestionarioID int    `json:"cuestionario_id,omitempty" validate:"required,min=1"`
+
+	// Diagnósticos opcionales que se registran junto con la consulta, en una sola transacción
+	Diagnosticos []ConsultationDiagnosticDTO `json:"diagnosticos,omitempty" validate:"dive"`
+}
+
+// ConsultationDiagnosticDTO es un diagnóstico creado junto con su consulta
+type ConsultationDiagnosticDTO struct {
+	Nombre        string  `json:"nombre" validate:"required"`
+	Recomendacion *string `json:"recomendacion"`
+}
+
+// AppointmentConsultationDTO crea la consulta de una cita al hacer check-in.
+// CuestionarioID puede ser cualquier versión: se usa la versión activa de ese cuestionario.
+type AppointmentConsultationDTO struct {
+	CitaID         int    `validate:"required,min=1"`
+	PacienteID     int    `validate:"required,min=1"`
+	CuestionarioID int    `validate:"required,min=1"`
+	Motivo         string `validate:"required"`
 }
 
 type ConsultationUpdateDTO struct {
-	Motivo     string `json:"motivo"`
+	Motivo     string `json:"motivo" validate:"required"`
 	Completada bool   `json:"completada"`
 }
 
@@ -5,6 +5,7 @@ package consultation
 import (
 	"database/sql"
 
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
 	"github.com/tonitomc/healthcare-crm-api/internal/database"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/consultation/models"
 	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
@@ -12,9 +13,10 @@ import (
 
 type Repository interface {
 	// Consultations
-	GetAll() ([]models.Consultation, error)
+	GetAll(opts query.Options) ([]models.Consultation, int, error)
 	GetByID(id int) (*models.Consultation, error)
 	GetByPatient(patientID int) ([]models.Consultation, error)
+	GetByAppointment(citaID int) (*models.Consultation, error)
 	Create(consultation *models.Consultation) (int, error)
 	Update(consultation *models.Consultation) error
 	Delete(id int) error
@@ -38,10 +40,20 @@ type Repository interface {
 	AddAnswers(a *models.Answers) (int, error)
 	UpdateAnswers(a *models.Answers) error
 	DeleteAnswers(consultationID int) error
+
+	// DeleteByPatient borra todas las consultas del paciente junto con sus diagnósticos,
+	// tratamientos y respuestas
+	DeleteByPatient(patientID int) error
+	// ReassignPatient pasa las consultas de un paciente a otro (fusión de duplicados);
+	// diagnósticos, tratamientos y respuestas de cuestionarios cuelgan de la consulta
+	ReassignPatient(fromID, toID int) error
+
+	// WithTx devuelve una copia del repositorio que ejecuta sus consultas en tx
+	WithTx(tx database.DBTX) Repository
 }
 
 type repository struct {
-	db        *sql.DB
+	db        database.DBTX
 	validator QuestionnaireValidator
 }
 
@@ -49,14 +61,39 @@ func NewRepository(db *sql.DB) Repository {
 	return &repository{db: db}
 }
 
-func (r *repository) GetAll() ([]models.Consultation, error) {
+func (r *repository) WithTx(tx database.DBTX) Repository {
+	return &repository{db: tx, validator: r.validator}
+}
+
+// consultationColumns traduce los campos de orden y filtro del listado a columnas
+var consultationColumns = query.Columns{
+	"id":          "id",
+	"fecha":       "fecha",
+	"paciente_id": "paciente_id",
+	"motivo":      "motivo",
+	"completada":  "completada",
+	"cita_id":     "cita_id",
+	"desde":       "fecha",
+	"hasta":       "fecha",
+}
+
+// GetAll devuelve una página de consultas y el total que cumple los filtros
+func (r *repository) GetAll(opts query.Options) ([]models.Consultation, int, error) {
+	conds, args := opts.Conditions(consultationColumns, nil)
+	where := query.Where(conds)
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM consultas`+where, args...).Scan(&total); err != nil {
+		return nil, 0, database.MapSQLError(err, "ConsultationRepository.GetAll(count)")
+	}
+
+	conds, args = opts.Seek(consultationColumns, "id", conds, args)
+	page, args := opts.LimitOffset(args)
 	rows, err := r.db.Query(`
-		SELECT id, paciente_id, motivo, cuestionario_id, fecha, completada
-		FROM consultas
-		ORDER BY fecha DESC
-	`)
+		SELECT id, paciente_id, motivo, cuestionario_id, fecha, completada, cita_id
+		FROM consultas`+query.Where(conds)+opts.OrderBy(consultationColumns, "id")+page, args...)
 	if err != nil {
-		return nil, database.MapSQLError(err, "ConsultationRepository.GetAll")
+		return nil, 0, database.MapSQLError(err, "ConsultationRepository.GetAll")
 	}
 	defer rows.Close()
 
@@ -70,22 +107,23 @@ func (r *repository) GetAll() ([]models.Consultation, error) {
 			&c.CuestionarioID,
 			&c.Fecha,
 			&c.Completada,
+			&c.CitaID,
 		); err != nil {
-			return nil, appErr.Wrap("ConsultationRepository.GetAll(scan)", appErr.ErrInternal, err)
+			return nil, 0, appErr.Wrap("ConsultationRepository.GetAll(scan)", appErr.ErrInternal, err)
 		}
 		consultations = append(consultations, c)
 	}
 
-	return consultations, nil
+	return consultations, total, nil
 }
 
 func (r *repository) GetByID(id int) (*models.Consultation, error) {
 	var c models.Consultation
 	err := r.db.QueryRow(`
-		SELECT id, paciente_id, motivo, cuestionario_id, fecha, completada
+		SELECT id, paciente_id, motivo, cuestionario_id, fecha, completada, cita_id
 		FROM consultas
 		WHERE id = $1
-	`, id).Scan(&c.ID, &c.PacienteID, &c.Motivo, &c.CuestionarioID, &c.Fecha, &c.Completada)
+	`, id).Scan(&c.ID, &c.PacienteID, &c.Motivo, &c.CuestionarioID, &c.Fecha, &c.Completada, &c.CitaID)
 	if err != nil {
 		return nil, database.MapSQLError(err, "ConsultationRepository.GetByID")
 	}
@@ -93,9 +131,23 @@ func (r *repository) GetByID(id int) (*models.Consultation, error) {
 	return &c, nil
 }
 
+func (r *repository) GetByAppointment(citaID int) (*models.Consultation, error) {
+	var c models.Consultation
+	err := r.db.QueryRow(`
+		SELECT id, paciente_id, motivo, cuestionario_id, fecha, completada, cita_id
+		FROM consultas
+		WHERE cita_id = $1
+	`, citaID).Scan(&c.ID, &c.PacienteID, &c.Motivo, &c.CuestionarioID, &c.Fecha, &c.Completada, &c.CitaID)
+	if err != nil {
+		return nil, database.MapSQLError(err, "ConsultationRepository.GetByAppointment")
+	}
+
+	return &c, nil
+}
+
 func (r *repository) GetByPatient(patientID int) ([]models.Consultation, error) {
 	rows, err := r.db.Query(`
-		SELECT id, paciente_id, motivo, cuestionario_id, fecha, completada
+		SELECT id, paciente_id, motivo, cuestionario_id, fecha, completada, cita_id
 		FROM consultas
 		WHERE paciente_id = $1
 		ORDER BY fecha DESC
@@ -108,7 +160,7 @@ func (r *repository) GetByPatient(patientID int) ([]models.Consultation, error)
 	var consultations []models.Consultation
 	for rows.Next() {
 		var c models.Consultation
-		if err := rows.Scan(&c.ID, &c.PacienteID, &c.Motivo, &c.CuestionarioID, &c.Fecha, &c.Completada); err != nil {
+		if err := rows.Scan(&c.ID, &c.PacienteID, &c.Motivo, &c.CuestionarioID, &c.Fecha, &c.Completada, &c.CitaID); err != nil {
 			return nil, appErr.Wrap("ConsultationRepository.GetByPatient(scan)", appErr.ErrInternal, err)
 		}
 		consultations = append(consultations, c)
@@ -120,10 +172,10 @@ func (r *repository) GetByPatient(patientID int) ([]models.Consultation, error)
 func (r *repository) Create(consultation *models.Consultation) (int, error) {
 	var id int
 	err := r.db.QueryRow(`
-		INSERT INTO consultas (paciente_id, motivo, cuestionario_id, fecha, completada)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO consultas (paciente_id, motivo, cuestionario_id, fecha, completada, cita_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id
-	`, consultation.PacienteID, consultation.Motivo, consultation.CuestionarioID, consultation.Fecha, consultation.Completada).Scan(&id)
+	`, consultation.PacienteID, consultation.Motivo, consultation.CuestionarioID, consultation.Fecha, consultation.Completada, consultation.CitaID).Scan(&id)
 	if err != nil {
 		return 0, database.MapSQLError(err, "ConsultationRepository.Create")
 	}
@@ -162,6 +214,40 @@ func (r *repository) Delete(id int) error {
 	return nil
 }
 
+func (r *repository) DeleteByPatient(patientID int) error {
+	return database.RunInTx(r.db, "ConsultationRepository.DeleteByPatient", func(tx database.DBTX) error {
+		if _, err := tx.Exec(`
+			DELETE FROM tratamientos
+			WHERE diagnostico_id IN (
+				SELECT d.id FROM diagnosticos d
+				JOIN consultas c ON d.consulta_id = c.id
+				WHERE c.paciente_id = $1
+			)
+		`, patientID); err != nil {
+			return database.MapSQLError(err, "ConsultationRepository.DeleteByPatient(tratamientos)")
+		}
+
+		if _, err := tx.Exec(`
+			DELETE FROM diagnosticos
+			WHERE consulta_id IN (SELECT id FROM consultas WHERE paciente_id = $1)
+		`, patientID); err != nil {
+			return database.MapSQLError(err, "ConsultationRepository.DeleteByPatient(diagnosticos)")
+		}
+
+		if _, err := tx.Exec(`
+			DELETE FROM respuestas_cuestionarios
+			WHERE consulta_id IN (SELECT id FROM consultas WHERE paciente_id = $1)
+		`, patientID); err != nil {
+			return database.MapSQLError(err, "ConsultationRepository.DeleteByPatient(respuestas)")
+		}
+
+		if _, err := tx.Exec(`DELETE FROM consultas WHERE paciente_id = $1`, patientID); err != nil {
+			return database.MapSQLError(err, "ConsultationRepository.DeleteByPatient(consultas)")
+		}
+		return nil
+	})
+}
+
 func (r *repository) GetDiagnosticsByConsultation(consultationID int) ([]models.Diagnostic, error) {
 	rows, err := r.db.Query(`
 		SELECT id, consulta_id, nombre, recomendacion
@@ -413,3 +499,10 @@ func (r *repository) DeleteAnswers(consultationID int) error {
 	}
 	return nil
 }
+
+func (r *repository) ReassignPatient(fromID, toID int) error {
+	if _, err := r.db.Exec(`UPDATE consultas SET paciente_id = $2 WHERE paciente_id = $1`, fromID, toID); err != nil {
+		return database.MapSQLError(err, "ConsultationRepository.ReassignPatient")
+	}
+	return nil
+}
@@ -6,8 +6,11 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/consultation/models"
 	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/validator"
 )
 
 // QuestionnaireValidator validates a set of answers against its questionnaire definition.
@@ -15,15 +18,32 @@ type QuestionnaireValidator interface {
 	Validate(questionnaireID int, answers json.RawMessage) error
 }
 
+// QuestionnaireResolver resolves the active version of a questionnaire. Any version's
+// ID may be given; the active version is the one sharing its name.
+type QuestionnaireResolver interface {
+	ActiveVersion(questionnaireID int) (int, error)
+}
+
+// AppointmentCloser closes the appointment a consultation was started from.
+type AppointmentCloser interface {
+	// CompleteAppointment registra a userID como quien completó la cita
+	CompleteAppointment(citaID, userID int) error
+	// WithTx devuelve un cerrador cuyas escrituras participan en la transacción tx
+	WithTx(tx database.DBTX) AppointmentCloser
+}
+
 type Service interface {
-	GetAll() ([]models.Consultation, error)
+	GetAll(opts query.Options) ([]models.Consultation, int, error)
 	GetByID(id int) (*models.Consultation, error)
 	GetByPatient(patientID int) ([]models.Consultation, error)
 	GetByPatientWithDetails(patientID int) ([]models.ConsultationWithDetails, error)
+	GetByAppointment(citaID int) (*models.Consultation, error)
 	Create(dto *models.ConsultationCreateDTO) (int, error)
-	Update(id int, dto *models.ConsultationUpdateDTO) error
+	CreateForAppointment(dto *models.AppointmentConsultationDTO) (int, error)
+	// Update y MarkComplete registran a userID como quien completó la cita de la consulta
+	Update(id, userID int, dto *models.ConsultationUpdateDTO) error
 	Delete(id int) error
-	MarkComplete(id int) error
+	MarkComplete(id, userID int) error
 	MarkPending(id int) error
 
 	// --- Diagnostics ---
@@ -44,19 +64,33 @@ type Service interface {
 	AddAnswers(consultaID int, dto *models.AnswersCreateDTO) (int, error)
 	UpdateAnswers(consultaID int, dto *models.AnswersUpdateDTO) error
 	DeleteAnswers(consultaID int) error
+
+	// WithTx devuelve una copia del servicio cuyas escrituras se ejecutan en tx
+	WithTx(tx database.DBTX) Service
 }
 
 type service struct {
-	repo      Repository
-	validator QuestionnaireValidator
+	repo         Repository
+	validator    QuestionnaireValidator
+	resolver     QuestionnaireResolver
+	appointments AppointmentCloser
+	uow          database.UnitOfWork
+}
+
+func NewService(repo Repository, validator QuestionnaireValidator, resolver QuestionnaireResolver, appointments AppointmentCloser, uow database.UnitOfWork) Service {
+	return &service{repo: repo, validator: validator, resolver: resolver, appointments: appointments, uow: uow}
 }
 
-func NewService(repo Repository, validator QuestionnaireValidator) Service {
-	return &service{repo: repo, validator: validator}
+func (s *service) WithTx(tx database.DBTX) Service {
+	txService := *s
+	txService.repo = s.repo.WithTx(tx)
+	txService.appointments = s.appointments.WithTx(tx)
+	txService.uow = database.JoinUnitOfWork(tx)
+	return &txService
 }
 
-func (s *service) GetAll() ([]models.Consultation, error) {
-	return s.repo.GetAll()
+func (s *service) GetAll(opts query.Options) ([]models.Consultation, int, error) {
+	return s.repo.GetAll(opts)
 }
 
 func (s *service) GetByPatientWithDetails(patientID int) ([]models.ConsultationWithDetails, error) {
@@ -99,6 +133,7 @@ func (s *service) GetByPatientWithDetails(patientID int) ([]models.ConsultationW
 			Motivo:      c.Motivo,
 			Fecha:       c.Fecha.Format("02-01-2006"),
 			Completada:  c.Completada,
+			CitaID:      c.CitaID,
 			Diagnostics: diagDetails,
 		})
 	}
@@ -120,18 +155,19 @@ func (s *service) GetByPatient(patientID int) ([]models.Consultation, error) {
 	return s.repo.GetByPatient(patientID)
 }
 
+func (s *service) GetByAppointment(citaID int) (*models.Consultation, error) {
+	if citaID <= 0 {
+		return nil, appErr.Wrap("ConsultationService.GetByAppointment", appErr.ErrInvalidInput, nil)
+	}
+	return s.repo.GetByAppointment(citaID)
+}
+
 func (s *service) Create(dto *models.ConsultationCreateDTO) (int, error) {
 	if dto == nil {
 		return 0, appErr.Wrap("ConsultationService.Create", appErr.ErrInvalidInput, nil)
 	}
-	if dto.PacienteID <= 0 {
-		return 0, appErr.NewDomainError(appErr.ErrInvalidInput, "El ID del paciente es inválido.")
-	}
-	if dto.Motivo == "" {
-		return 0, appErr.NewDomainError(appErr.ErrInvalidInput, "El motivo de la consulta es requerido.")
-	}
-	if dto.CuestionarioID <= 0 {
-		return 0, appErr.NewDomainError(appErr.ErrInvalidInput, "El cuestionario asociado es inválido.")
+	if err := validator.Struct(dto); err != nil {
+		return 0, err
 	}
 
 	now := time.Now().Truncate(24 * time.Hour)
@@ -144,7 +180,28 @@ func (s *service) Create(dto *models.ConsultationCreateDTO) (int, error) {
 		Completada:     false,
 	}
 
-	id, err := s.repo.Create(consultation)
+	// La consulta y sus diagnósticos se crean juntos o no se crea nada
+	var id int
+	err := s.uow.Do(func(tx database.DBTX) error {
+		repo := s.repo.WithTx(tx)
+
+		var err error
+		id, err = repo.Create(consultation)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range dto.Diagnosticos {
+			if _, err := repo.CreateDiagnostic(&models.Diagnostic{
+				ConsultaID:    id,
+				Nombre:        d.Nombre,
+				Recomendacion: d.Recomendacion,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -152,28 +209,54 @@ func (s *service) Create(dto *models.ConsultationCreateDTO) (int, error) {
 	return id, nil
 }
 
-func (s *service) Update(id int, dto *models.ConsultationUpdateDTO) error {
+// CreateForAppointment crea la consulta de una cita al hacer check-in, con la versión
+// activa del cuestionario indicado y la hora real de llegada.
+func (s *service) CreateForAppointment(dto *models.AppointmentConsultationDTO) (int, error) {
+	if dto == nil {
+		return 0, appErr.Wrap("ConsultationService.CreateForAppointment", appErr.ErrInvalidInput, nil)
+	}
+	if err := validator.Struct(dto); err != nil {
+		return 0, err
+	}
+
+	cuestionarioID, err := s.resolver.ActiveVersion(dto.CuestionarioID)
+	if err != nil {
+		return 0, err
+	}
+
+	if existing, err := s.repo.GetByAppointment(dto.CitaID); err == nil && existing != nil {
+		return 0, appErr.NewDomainError(appErr.ErrConflict, "La cita ya tiene una consulta registrada.")
+	}
+
+	citaID := dto.CitaID
+	return s.repo.Create(&models.Consultation{
+		PacienteID:     dto.PacienteID,
+		Motivo:         dto.Motivo,
+		CuestionarioID: cuestionarioID,
+		Fecha:          time.Now(),
+		Completada:     false,
+		CitaID:         &citaID,
+	})
+}
+
+func (s *service) Update(id, userID int, dto *models.ConsultationUpdateDTO) error {
 	if id <= 0 || dto == nil {
 		return appErr.NewDomainError(appErr.ErrInvalidInput, "Datos inválidos para actualización.")
 	}
+	if err := validator.Struct(dto); err != nil {
+		return err
+	}
 
 	existing, err := s.repo.GetByID(id)
 	if err != nil {
 		return err
 	}
 
-	if dto.Motivo == "" {
-		return appErr.NewDomainError(appErr.ErrInvalidInput, "El motivo de la consulta es requerido.")
-	}
-
+	completing := dto.Completada && !existing.Completada
 	existing.Motivo = dto.Motivo
 	existing.Completada = dto.Completada
 
-	if err := s.repo.Update(existing); err != nil {
-		return err
-	}
-
-	return nil
+	return s.save(existing, completing, userID)
 }
 
 func (s *service) Delete(id int) error {
@@ -183,7 +266,7 @@ func (s *service) Delete(id int) error {
 	return s.repo.Delete(id)
 }
 
-func (s *service) MarkComplete(id int) error {
+func (s *service) MarkComplete(id, userID int) error {
 	if id <= 0 {
 		return appErr.Wrap("ConsultationService.MarkComplete", appErr.ErrInvalidInput, nil)
 	}
@@ -193,9 +276,25 @@ func (s *service) MarkComplete(id int) error {
 		return err
 	}
 
+	completing := !consultation.Completada
 	consultation.Completada = true
 
-	return s.repo.Update(consultation)
+	return s.save(consultation, completing, userID)
+}
+
+// save persiste la consulta. Al completarse una consulta iniciada desde una cita, la
+// cita se cierra en la misma transacción, a nombre de userID.
+func (s *service) save(consultation *models.Consultation, completing bool, userID int) error {
+	if !completing || consultation.CitaID == nil {
+		return s.repo.Update(consultation)
+	}
+
+	return s.uow.Do(func(tx database.DBTX) error {
+		if err := s.repo.WithTx(tx).Update(consultation); err != nil {
+			return err
+		}
+		return s.appointments.WithTx(tx).CompleteAppointment(*consultation.CitaID, userID)
+	})
 }
 
 func (s *service) MarkPending(id int) error {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/exam/models"
 	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
 )
@@ -19,7 +20,7 @@ func NewHandler(service Service) *Handler {
 }
 
 func (h *Handler) RegisterRoutes(e *echo.Group) {
-	exams := e.Group("/exams", ErrorMiddleware()) // attach error middleware
+	exams := e.Group("/exams", ErrorMiddleware(), middleware.Audit("examen", "id", h.auditedExam)) // attach error middleware
 
 	exams.GET("/:id", h.GetByID, middleware.RequirePermission("ver-examenes"))
 	exams.GET("/pending", h.GetPending, middleware.RequirePermission("ver-examenes"))
@@ -30,7 +31,21 @@ func (h *Handler) RegisterRoutes(e *echo.Group) {
 	exams.DELETE("/:id", h.Delete, middleware.RequirePermission("manejar-examenes"))
 	exams.POST("/:id/upload", h.UploadExam, middleware.RequirePermission("manejar-examenes"))
 
-	exams.GET("/:id/file", h.DownloadExam, middleware.RequirePermission("ver-examenes"))
+	exams.GET("/:id/file", h.DownloadExam, middleware.RequirePermission("ver-examenes"), middleware.AccessLog("archivo_examen", "id", h.examPatient))
+}
+
+// examPatient resolves the patient of an exam for middleware.AccessLog
+func (h *Handler) examPatient(id int) (int, error) {
+	exam, err := h.service.GetByID(id)
+	if err != nil {
+		return 0, err
+	}
+	return exam.PacienteID, nil
+}
+
+// auditedExam is the state middleware.Audit records before and after a change
+func (h *Handler) auditedExam(id int) (interface{}, error) {
+	return h.service.GetByID(id)
 }
 
 // ============================================================================
@@ -96,13 +111,41 @@ func (h *Handler) Delete(c echo.Context) error {
 	return c.JSON(http.StatusOK, echo.Map{"message": "Examen eliminado correctamente"})
 }
 
+// pendingSpec declara el orden y los filtros que acepta GET /exams/pending
+var pendingSpec = query.Spec{
+	Sortable: []string{"id", "fecha", "tipo"},
+	Filters: map[string]query.FilterType{
+		"paciente_id": query.Int,
+		"tipo":        query.Text,
+		"desde":       query.DateFrom,
+		"hasta":       query.DateTo,
+	},
+	DefaultSort: []query.Sort{{Field: "fecha", Desc: true}},
+}
+
+// pendingKey da los valores de orden de un examen para el cursor de la página siguiente
+func pendingKey(e models.ExamDTO, field string) interface{} {
+	switch field {
+	case "fecha":
+		return e.Fecha
+	case "tipo":
+		return e.Tipo
+	}
+	return e.ID
+}
+
 func (h *Handler) GetPending(c echo.Context) error {
-	exams, err := h.service.GetPending()
+	opts, err := query.Parse(c, pendingSpec)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, exams)
+	exams, total, err := h.service.GetPending(opts)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, query.NewPage(exams, total, opts, pendingKey))
 }
 
 func (h *Handler) GetByPatientID(c echo.Context) error {
@@ -3,9 +3,9 @@ package models
 import "time"
 
 type ExamCreateDTO struct {
-	PacienteID int        `json:"paciente_id" validate:"required"`
+	PacienteID int        `json:"paciente_id" validate:"required,min=1"`
 	Tipo       string     `json:"tipo" validate:"required"`
-	Fecha      *time.Time `jsjon:"fecha,omitempty"`
+	Fecha      *time.Time `json:"fecha,omitempty"`
 }
 
 type ExamUploadDTO struct {
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
 	"github.com/tonitomc/healthcare-crm-api/internal/database"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/exam/models"
 	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
@@ -17,17 +18,29 @@ type Repository interface {
 	Create(exam *models.Exam) (int, error)
 	Update(exam *models.Exam) error
 	Delete(id int) error
-	GetPending() ([]models.Exam, error)
+	GetPending(opts query.Options) ([]models.Exam, int, error)
+
+	// DeleteByPatient borra todos los exámenes del paciente
+	DeleteByPatient(patientID int) error
+	// ReassignPatient pasa los exámenes de un paciente a otro (fusión de duplicados)
+	ReassignPatient(fromID, toID int) error
+
+	// WithTx devuelve una copia del repositorio que ejecuta sus consultas en tx
+	WithTx(tx database.DBTX) Repository
 }
 
 type repository struct {
-	db *sql.DB
+	db database.DBTX
 }
 
 func NewRepository(db *sql.DB) Repository {
 	return &repository{db: db}
 }
 
+func (r *repository) WithTx(tx database.DBTX) Repository {
+	return &repository{db: tx}
+}
+
 func (r *repository) GetByID(id int) (*models.Exam, error) {
 	var e models.Exam
 	err := r.db.QueryRow(`
@@ -124,17 +137,36 @@ func (r *repository) Delete(id int) error {
 	return nil
 }
 
-func (r *repository) GetPending() ([]models.Exam, error) {
+// pendingColumns traduce los campos de orden y filtro de exámenes pendientes a columnas
+var pendingColumns = query.Columns{
+	"id":          "e.id",
+	"fecha":       "e.fecha",
+	"tipo":        "e.tipo",
+	"paciente_id": "e.paciente_id",
+	"desde":       "e.fecha",
+	"hasta":       "e.fecha",
+}
+
+// GetPending devuelve una página de exámenes sin archivo y el total que cumple los filtros
+func (r *repository) GetPending(opts query.Options) ([]models.Exam, int, error) {
+	conds, args := opts.Conditions(pendingColumns, nil)
+	conds = append([]string{"(e.s3_key IS NULL OR e.s3_key = '')"}, conds...)
+	where := query.Where(conds)
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM examenes e`+where, args...).Scan(&total); err != nil {
+		return nil, 0, database.MapSQLError(err, "ExamRepository.GetPending(count)")
+	}
+
+	conds, args = opts.Seek(pendingColumns, "e.id", conds, args)
+	page, args := opts.LimitOffset(args)
 	rows, err := r.db.Query(`
 		SELECT e.id, e.paciente_id, e.consulta_id, e.tipo, e.fecha, e.s3_key, e.file_size, e.mime_type,
 		       p.nombre as nombre_paciente
 		FROM examenes e
-		LEFT JOIN pacientes p ON e.paciente_id = p.id
-		WHERE e.s3_key IS NULL OR e.s3_key = ''
-		ORDER BY e.fecha DESC NULLS LAST
-	`)
+		LEFT JOIN pacientes p ON e.paciente_id = p.id`+query.Where(conds)+opts.OrderBy(pendingColumns, "e.id")+page, args...)
 	if err != nil {
-		return nil, database.MapSQLError(err, "ExamRepository.GetPending")
+		return nil, 0, database.MapSQLError(err, "ExamRepository.GetPending")
 	}
 	defer rows.Close()
 
@@ -143,12 +175,12 @@ func (r *repository) GetPending() ([]models.Exam, error) {
 		var e models.Exam
 		var nombrePaciente *string
 		if err := rows.Scan(&e.ID, &e.PacienteID, &e.ConsultaID, &e.Tipo, &e.Fecha, &e.S3Key, &e.FileSize, &e.MimeType, &nombrePaciente); err != nil {
-			return nil, appErr.Wrap("ExamRepository.GetPending(scan)", appErr.ErrInternal, err)
+			return nil, 0, appErr.Wrap("ExamRepository.GetPending(scan)", appErr.ErrInternal, err)
 		}
 		exams = append(exams, e)
 	}
 
-	return exams, nil
+	return exams, total, nil
 }
 
 func (r *repository) GetCompleted() ([]models.Exam, error) {
@@ -177,3 +209,17 @@ func (r *repository) GetCompleted() ([]models.Exam, error) {
 
 	return exams, nil
 }
+
+func (r *repository) DeleteByPatient(patientID int) error {
+	if _, err := r.db.Exec(`DELETE FROM examenes WHERE paciente_id = $1`, patientID); err != nil {
+		return database.MapSQLError(err, "ExamRepository.DeleteByPatient")
+	}
+	return nil
+}
+
+func (r *repository) ReassignPatient(fromID, toID int) error {
+	if _, err := r.db.Exec(`UPDATE examenes SET paciente_id = $2 WHERE paciente_id = $1`, fromID, toID); err != nil {
+		return database.MapSQLError(err, "ExamRepository.ReassignPatient")
+	}
+	return nil
+}
@@ -6,8 +6,10 @@ import (
 	"mime/multipart"
 	"time"
 
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/exam/models"
 	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/validator"
 )
 
 type FileStorage interface {
@@ -22,7 +24,7 @@ type Service interface {
 	Create(examDTO *models.ExamCreateDTO) (int, error)
 	Update(id int, dto *models.ExamDTO) error
 	Delete(id int) error
-	GetPending() ([]models.ExamDTO, error)
+	GetPending(opts query.Options) ([]models.ExamDTO, int, error)
 	UploadExam(id int, dto *models.ExamUploadDTO, file multipart.File) (*models.ExamDTO, error)
 
 	DownloadExamFile(key string) (io.ReadCloser, error)
@@ -78,11 +80,8 @@ func (s *service) GetByPatient(patientID int) ([]models.ExamDTO, error) {
 }
 
 func (s *service) Create(examDTO *models.ExamCreateDTO) (int, error) {
-	if examDTO.PacienteID <= 0 {
-		return 0, appErr.Wrap("ExamService.Create(invalid paciente_id)", appErr.ErrInvalidInput, nil)
-	}
-	if examDTO.Tipo == "" {
-		return 0, appErr.Wrap("ExamService.Create(tipo required)", appErr.ErrInvalidInput, nil)
+	if err := validator.Struct(examDTO); err != nil {
+		return 0, err
 	}
 
 	now := time.Now()
@@ -160,22 +159,22 @@ func (s *service) Delete(id int) error {
 	return s.repo.Delete(id)
 }
 
-func (s *service) GetPending() ([]models.ExamDTO, error) {
-	pendingExams, err := s.repo.GetPending()
+func (s *service) GetPending(opts query.Options) ([]models.ExamDTO, int, error) {
+	pendingExams, total, err := s.repo.GetPending(opts)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	enriched := make([]models.ExamDTO, 0, len(pendingExams))
 	for _, exam := range pendingExams {
 		dto, err := s.enrich(exam)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		enriched = append(enriched, *dto)
 	}
 
-	return enriched, nil
+	return enriched, total, nil
 }
 
 func (s *service) enrich(e models.Exam) (*models.ExamDTO, error) {
@@ -24,18 +24,24 @@ func NewHandler(s Service) *Handler {
 //
 // ============================================================================
 func (h *Handler) RegisterRoutes(g *echo.Group) {
-	mr := g.Group("/medical-records", ErrorMiddleware())
+	mr := g.Group("/medical-records", ErrorMiddleware(), middleware.Audit("expediente", "patient_id", h.auditedRecord))
 
 	// You can change permission name to whatever you decide later.
 	mr.GET("/:patient_id",
 		h.GetByPatientID,
-		middleware.RequirePermission("ver-pacientes"))
+		middleware.RequirePermission("ver-pacientes"),
+		middleware.AccessLog("expediente", "patient_id", nil))
 
 	mr.PUT("/:patient_id",
 		h.Update,
 		middleware.RequirePermission("ver-pacientes"))
 }
 
+// auditedRecord is the state middleware.Audit records before and after a change
+func (h *Handler) auditedRecord(patientID int) (interface{}, error) {
+	return h.service.GetByPatientID(patientID)
+}
+
 // ============================================================================
 //
 //	GET /medical-records/:patient_id
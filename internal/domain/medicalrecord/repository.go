@@ -4,6 +4,7 @@ package medicalrecord
 
 import (
 	"database/sql"
+	"strings"
 
 	"github.com/tonitomc/healthcare-crm-api/internal/database"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/medicalrecord/models"
@@ -14,16 +15,29 @@ type Repository interface {
 	GetByPatientID(patientID int) (*models.MedicalRecord, error)
 	Create(patientID int) error
 	Update(patientID int, record *models.MedicalRecord) error
+
+	// DeleteByPatient borra los antecedentes del paciente
+	DeleteByPatient(patientID int) error
+	// MergeInto pasa los antecedentes de un paciente a otro (fusión de duplicados). Si
+	// ambos tienen, cada campo del fusionado se agrega al del que se conserva.
+	MergeInto(fromID, toID int) error
+
+	// WithTx devuelve una copia del repositorio que ejecuta sus consultas en tx
+	WithTx(tx database.DBTX) Repository
 }
 
 type repository struct {
-	db *sql.DB
+	db database.DBTX
 }
 
 func NewRepository(db *sql.DB) Repository {
 	return &repository{db: db}
 }
 
+func (r *repository) WithTx(tx database.DBTX) Repository {
+	return &repository{db: tx}
+}
+
 func (r *repository) GetByPatientID(patientID int) (*models.MedicalRecord, error) {
 	var rec models.MedicalRecord
 	err := r.db.QueryRow(`
@@ -64,3 +78,50 @@ func (r *repository) Update(patientID int, record *models.MedicalRecord) error {
 
 	return nil
 }
+
+func (r *repository) DeleteByPatient(patientID int) error {
+	if _, err := r.db.Exec(`DELETE FROM antecedentes WHERE paciente_id = $1`, patientID); err != nil {
+		return database.MapSQLError(err, "MedicalRecordRepository.DeleteByPatient")
+	}
+	return nil
+}
+
+// recordFields son los campos de texto de antecedentes
+var recordFields = []string{"medicos", "familiares", "oculares", "alergicos", "otros"}
+
+func (r *repository) MergeInto(fromID, toID int) error {
+	return database.RunInTx(r.db, "MedicalRecordRepository.MergeInto", func(tx database.DBTX) error {
+		// Sin antecedentes propios, el paciente conservado se queda con los del fusionado
+		res, err := tx.Exec(`
+			UPDATE antecedentes SET paciente_id = $2
+			WHERE paciente_id = $1 AND NOT EXISTS (SELECT 1 FROM antecedentes WHERE paciente_id = $2)
+		`, fromID, toID)
+		if err != nil {
+			return database.MapSQLError(err, "MedicalRecordRepository.MergeInto(move)")
+		}
+		if rows, _ := res.RowsAffected(); rows > 0 {
+			return nil
+		}
+
+		sets := make([]string, 0, len(recordFields))
+		for _, f := range recordFields {
+			sets = append(sets, f+` = CASE
+				WHEN COALESCE(f.`+f+`, '') = '' OR f.`+f+` = a.`+f+` THEN a.`+f+`
+				WHEN COALESCE(a.`+f+`, '') = '' THEN f.`+f+`
+				ELSE a.`+f+` || E'\n' || f.`+f+`
+			END`)
+		}
+		if _, err := tx.Exec(`
+			UPDATE antecedentes a SET `+strings.Join(sets, ", ")+`
+			FROM antecedentes f
+			WHERE a.paciente_id = $2 AND f.paciente_id = $1
+		`, fromID, toID); err != nil {
+			return database.MapSQLError(err, "MedicalRecordRepository.MergeInto(merge)")
+		}
+
+		if _, err := tx.Exec(`DELETE FROM antecedentes WHERE paciente_id = $1`, fromID); err != nil {
+			return database.MapSQLError(err, "MedicalRecordRepository.MergeInto(delete)")
+		}
+		return nil
+	})
+}
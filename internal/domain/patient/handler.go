@@ -7,6 +7,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/consultation"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/exam"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/medicalrecord"
@@ -26,28 +27,68 @@ func NewHandler(s Service, examService exam.Service, consultationService consult
 }
 
 func (h *Handler) RegisterRoutes(g *echo.Group) {
-	patients := g.Group("/patients", ErrorMiddleware())
+	patients := g.Group("/patients", ErrorMiddleware(), middleware.Audit("paciente", "id", h.auditedPatient))
 
 	patients.GET("", h.GetAll, middleware.RequirePermission("ver-pacientes"))
 	patients.GET("/:id", h.GetByID, middleware.RequirePermission("ver-pacientes"))
-	patients.GET("/:id/details", h.GetDetails, middleware.RequirePermission("ver-pacientes"))
+	patients.GET("/:id/details", h.GetDetails, middleware.RequirePermission("ver-pacientes"), middleware.AccessLog("detalles_paciente", "id", nil))
 	patients.POST("", h.Create, middleware.RequirePermission("manejar-pacientes"))
 	patients.PUT("/:id", h.Update, middleware.RequirePermission("manejar-pacientes"))
-	patients.DELETE("/:id", h.Delete, middleware.RequirePermission("manejar-pacientes"))
-	patients.GET("/search", h.SearchByName, middleware.RequirePermission("ver-pacientes"))
+	patients.DELETE("/:id", h.Archive, middleware.RequirePermission("manejar-pacientes"))
+	patients.POST("/:id/restore", h.Restore, middleware.RequirePermission("manejar-pacientes"))
+	patients.DELETE("/:id/purge", h.Purge, middleware.RequirePermission("purgar-pacientes"))
+	patients.GET("/search", h.Search, middleware.RequirePermission("ver-pacientes"))
+	patients.GET("/duplicates", h.FindDuplicates, middleware.RequirePermission("ver-pacientes"))
+	patients.POST("/:id/merge", h.Merge, middleware.RequirePermission("manejar-pacientes"))
+	patients.GET("/:id/merges", h.GetMerges, middleware.RequirePermission("ver-pacientes"))
+}
+
+// auditedPatient is the state middleware.Audit records before and after a change
+func (h *Handler) auditedPatient(id int) (interface{}, error) {
+	return h.service.GetByID(id)
+}
+
+// listSpec declara el orden y los filtros que acepta GET /patients
+var listSpec = query.Spec{
+	Sortable: []string{"id", "nombre", "fecha_nacimiento"},
+	Filters: map[string]query.FilterType{
+		"nombre":       query.Text,
+		"telefono":     query.Text,
+		"sexo":         query.Exact,
+		"nacido_desde": query.DateFrom,
+		"nacido_hasta": query.DateTo,
+	},
+	DefaultSort: []query.Sort{{Field: "nombre"}},
+}
+
+// listKey da los valores de orden de un paciente para el cursor de la página siguiente
+func listKey(p models.Patient, field string) interface{} {
+	switch field {
+	case "nombre":
+		return p.Nombre
+	case "fecha_nacimiento":
+		return p.FechaNacimiento
+	}
+	return p.ID
 }
 
 func (h *Handler) GetAll(c echo.Context) error {
-	patients, err := h.service.GetAll()
+	opts, err := query.Parse(c, listSpec)
 	if err != nil {
 		return err
 	}
 
-	if len(patients) == 0 {
-		return c.JSON(http.StatusOK, []models.Patient{})
+	archived, err := includeArchived(c)
+	if err != nil {
+		return appErr.Wrap("PatientHandler.GetAll.ParseArchived", appErr.ErrInvalidInput, err)
+	}
+
+	patients, total, err := h.service.GetAll(opts, archived)
+	if err != nil {
+		return err
 	}
 
-	return c.JSON(http.StatusOK, patients)
+	return c.JSON(http.StatusOK, query.NewPage(patients, total, opts, listKey))
 }
 
 func (h *Handler) GetByID(c echo.Context) error {
@@ -96,26 +137,85 @@ func (h *Handler) Update(c echo.Context) error {
 	return c.JSON(http.StatusOK, echo.Map{"message": "Paciente actualizado correctamente"})
 }
 
-func (h *Handler) Delete(c echo.Context) error {
+// Archive es el borrado de pacientes: los oculta conservando su historial clínico
+func (h *Handler) Archive(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return appErr.Wrap("PatientHandler.Delete.ParseID", appErr.ErrInvalidInput, err)
+		return appErr.Wrap("PatientHandler.Archive.ParseID", appErr.ErrInvalidInput, err)
 	}
 
-	if err := h.service.Delete(id); err != nil {
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return appErr.Wrap("PatientHandler.Archive.GetClaims", appErr.ErrUnauthorized, nil)
+	}
+
+	if err := h.service.Archive(id, claims.UserID); err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, echo.Map{"message": "Paciente eliminado correctamente"})
+	return c.JSON(http.StatusOK, echo.Map{"message": "Paciente archivado correctamente"})
 }
 
-func (h *Handler) SearchByName(c echo.Context) error {
-	name := c.QueryParam("name")
-	if name == "" {
-		return appErr.Wrap("PatientHandler.SearchByName", appErr.ErrInvalidInput, nil)
+func (h *Handler) Restore(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.Wrap("PatientHandler.Restore.ParseID", appErr.ErrInvalidInput, err)
+	}
+
+	if err := h.service.Restore(id); err != nil {
+		return err
 	}
 
-	results, err := h.service.SearchByName(name)
+	return c.JSON(http.StatusOK, echo.Map{"message": "Paciente restaurado correctamente"})
+}
+
+// Purge elimina definitivamente un paciente archivado y todos sus datos
+func (h *Handler) Purge(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.Wrap("PatientHandler.Purge.ParseID", appErr.ErrInvalidInput, err)
+	}
+
+	if err := h.service.Purge(id); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"message": "Paciente eliminado definitivamente"})
+}
+
+// includeArchived lee ?incluir_archivados=true; por defecto los archivados se ocultan
+func includeArchived(c echo.Context) (bool, error) {
+	raw := c.QueryParam("incluir_archivados")
+	if raw == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(raw)
+}
+
+// Search es la búsqueda para autocompletar: ?q= acepta nombre, teléfono o fecha de
+// nacimiento (?name= se mantiene por compatibilidad), ?limit= hasta 50 resultados e
+// ?incluir_archivados=true.
+func (h *Handler) Search(c echo.Context) error {
+	term := c.QueryParam("q")
+	if term == "" {
+		term = c.QueryParam("name")
+	}
+
+	limit := 0
+	if raw := c.QueryParam("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return appErr.Wrap("PatientHandler.Search.ParseLimit", appErr.ErrInvalidInput, err)
+		}
+		limit = n
+	}
+
+	archived, err := includeArchived(c)
+	if err != nil {
+		return appErr.Wrap("PatientHandler.Search.ParseArchived", appErr.ErrInvalidInput, err)
+	}
+
+	results, err := h.service.Search(term, limit, archived)
 	if err != nil {
 		return err
 	}
@@ -123,6 +223,74 @@ func (h *Handler) SearchByName(c echo.Context) error {
 	return c.JSON(http.StatusOK, results)
 }
 
+// FindDuplicates reporta pares de pacientes que podrían ser la misma persona.
+// ?paciente_id= limita a los pares de un paciente, ?min_score= (0 a 1) y ?limit=.
+func (h *Handler) FindDuplicates(c echo.Context) error {
+	var q models.DuplicateQuery
+	var err error
+	if raw := c.QueryParam("paciente_id"); raw != "" {
+		if q.PacienteID, err = strconv.Atoi(raw); err != nil {
+			return appErr.Wrap("PatientHandler.FindDuplicates.ParsePatientID", appErr.ErrInvalidInput, err)
+		}
+	}
+	if raw := c.QueryParam("min_score"); raw != "" {
+		if q.MinScore, err = strconv.ParseFloat(raw, 64); err != nil {
+			return appErr.Wrap("PatientHandler.FindDuplicates.ParseMinScore", appErr.ErrInvalidInput, err)
+		}
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		if q.Limit, err = strconv.Atoi(raw); err != nil {
+			return appErr.Wrap("PatientHandler.FindDuplicates.ParseLimit", appErr.ErrInvalidInput, err)
+		}
+	}
+
+	candidates, err := h.service.FindDuplicates(q)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, candidates)
+}
+
+// Merge fusiona el paciente fusionado_id en el de la ruta, que se conserva
+func (h *Handler) Merge(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.Wrap("PatientHandler.Merge.ParseID", appErr.ErrInvalidInput, err)
+	}
+
+	var req models.PatientMergeDTO
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("PatientHandler.Merge.Bind", appErr.ErrInvalidInput, err)
+	}
+
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		return appErr.Wrap("PatientHandler.Merge.GetClaims", appErr.ErrUnauthorized, nil)
+	}
+
+	merge, err := h.service.Merge(id, req.FusionadoID, claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"merge": merge, "message": "Pacientes fusionados correctamente"})
+}
+
+func (h *Handler) GetMerges(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.Wrap("PatientHandler.GetMerges.ParseID", appErr.ErrInvalidInput, err)
+	}
+
+	merges, err := h.service.GetMerges(id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, merges)
+}
+
 func (h *Handler) GetDetails(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -0,0 +1,243 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	query "github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	database "github.com/tonitomc/healthcare-crm-api/internal/database"
+	patient "github.com/tonitomc/healthcare-crm-api/internal/domain/patient"
+	models "github.com/tonitomc/healthcare-crm-api/internal/domain/patient/models"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Archive mocks base method.
+func (m *MockRepository) Archive(id, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockRepositoryMockRecorder) Archive(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockRepository)(nil).Archive), id, userID)
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(patient *models.PatientCreateDTO) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", patient)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(patient interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), patient)
+}
+
+// Delete mocks base method.
+func (m *MockRepository) Delete(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), id)
+}
+
+// FindDuplicates mocks base method.
+func (m *MockRepository) FindDuplicates(q models.DuplicateQuery) ([]models.DuplicateCandidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDuplicates", q)
+	ret0, _ := ret[0].([]models.DuplicateCandidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDuplicates indicates an expected call of FindDuplicates.
+func (mr *MockRepositoryMockRecorder) FindDuplicates(q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDuplicates", reflect.TypeOf((*MockRepository)(nil).FindDuplicates), q)
+}
+
+// GetAll mocks base method.
+func (m *MockRepository) GetAll(opts query.Options, archived bool) ([]models.Patient, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", opts, archived)
+	ret0, _ := ret[0].([]models.Patient)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockRepositoryMockRecorder) GetAll(opts, archived interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRepository)(nil).GetAll), opts, archived)
+}
+
+// GetByID mocks base method.
+func (m *MockRepository) GetByID(id int) (*models.Patient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Patient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRepository)(nil).GetByID), id)
+}
+
+// GetMerges mocks base method.
+func (m *MockRepository) GetMerges(patientID int) ([]models.PatientMerge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerges", patientID)
+	ret0, _ := ret[0].([]models.PatientMerge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerges indicates an expected call of GetMerges.
+func (mr *MockRepositoryMockRecorder) GetMerges(patientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerges", reflect.TypeOf((*MockRepository)(nil).GetMerges), patientID)
+}
+
+// LastActivity mocks base method.
+func (m *MockRepository) LastActivity(id int) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastActivity", id)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastActivity indicates an expected call of LastActivity.
+func (mr *MockRepositoryMockRecorder) LastActivity(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastActivity", reflect.TypeOf((*MockRepository)(nil).LastActivity), id)
+}
+
+// LockForUpdate mocks base method.
+func (m *MockRepository) LockForUpdate(ids []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockForUpdate", ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockForUpdate indicates an expected call of LockForUpdate.
+func (mr *MockRepositoryMockRecorder) LockForUpdate(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockForUpdate", reflect.TypeOf((*MockRepository)(nil).LockForUpdate), ids)
+}
+
+// Restore mocks base method.
+func (m *MockRepository) Restore(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRepositoryMockRecorder) Restore(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepository)(nil).Restore), id)
+}
+
+// SaveMerge mocks base method.
+func (m *MockRepository) SaveMerge(merge *models.PatientMerge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMerge", merge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMerge indicates an expected call of SaveMerge.
+func (mr *MockRepositoryMockRecorder) SaveMerge(merge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMerge", reflect.TypeOf((*MockRepository)(nil).SaveMerge), merge)
+}
+
+// Search mocks base method.
+func (m *MockRepository) Search(q models.PatientSearchQuery) ([]models.PatientSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", q)
+	ret0, _ := ret[0].([]models.PatientSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockRepositoryMockRecorder) Search(q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockRepository)(nil).Search), q)
+}
+
+// Update mocks base method.
+func (m *MockRepository) Update(id int, patient *models.PatientUpdateDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", id, patient)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRepositoryMockRecorder) Update(id, patient interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), id, patient)
+}
+
+// WithTx mocks base method.
+func (m *MockRepository) WithTx(tx database.DBTX) patient.Repository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(patient.Repository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRepositoryMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepository)(nil).WithTx), tx)
+}
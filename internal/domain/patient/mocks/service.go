@@ -0,0 +1,288 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	query "github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	database "github.com/tonitomc/healthcare-crm-api/internal/database"
+	patient "github.com/tonitomc/healthcare-crm-api/internal/domain/patient"
+	models "github.com/tonitomc/healthcare-crm-api/internal/domain/patient/models"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// Archive mocks base method.
+func (m *MockService) Archive(id, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockServiceMockRecorder) Archive(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockService)(nil).Archive), id, userID)
+}
+
+// Create mocks base method.
+func (m *MockService) Create(patient *models.PatientCreateDTO) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", patient)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockServiceMockRecorder) Create(patient interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), patient)
+}
+
+// FindDuplicates mocks base method.
+func (m *MockService) FindDuplicates(q models.DuplicateQuery) ([]models.DuplicateCandidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDuplicates", q)
+	ret0, _ := ret[0].([]models.DuplicateCandidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDuplicates indicates an expected call of FindDuplicates.
+func (mr *MockServiceMockRecorder) FindDuplicates(q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDuplicates", reflect.TypeOf((*MockService)(nil).FindDuplicates), q)
+}
+
+// GetAll mocks base method.
+func (m *MockService) GetAll(opts query.Options, archived bool) ([]models.Patient, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", opts, archived)
+	ret0, _ := ret[0].([]models.Patient)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockServiceMockRecorder) GetAll(opts, archived interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockService)(nil).GetAll), opts, archived)
+}
+
+// GetByID mocks base method.
+func (m *MockService) GetByID(id int) (*models.Patient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Patient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockServiceMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockService)(nil).GetByID), id)
+}
+
+// GetMerges mocks base method.
+func (m *MockService) GetMerges(patientID int) ([]models.PatientMerge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerges", patientID)
+	ret0, _ := ret[0].([]models.PatientMerge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerges indicates an expected call of GetMerges.
+func (mr *MockServiceMockRecorder) GetMerges(patientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerges", reflect.TypeOf((*MockService)(nil).GetMerges), patientID)
+}
+
+// Merge mocks base method.
+func (m *MockService) Merge(survivorID, mergedID, userID int) (*models.PatientMerge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", survivorID, mergedID, userID)
+	ret0, _ := ret[0].(*models.PatientMerge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockServiceMockRecorder) Merge(survivorID, mergedID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockService)(nil).Merge), survivorID, mergedID, userID)
+}
+
+// Purge mocks base method.
+func (m *MockService) Purge(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockServiceMockRecorder) Purge(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockService)(nil).Purge), id)
+}
+
+// Restore mocks base method.
+func (m *MockService) Restore(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockServiceMockRecorder) Restore(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockService)(nil).Restore), id)
+}
+
+// Search mocks base method.
+func (m *MockService) Search(term string, limit int, archived bool) ([]models.PatientSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", term, limit, archived)
+	ret0, _ := ret[0].([]models.PatientSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockServiceMockRecorder) Search(term, limit, archived interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockService)(nil).Search), term, limit, archived)
+}
+
+// Update mocks base method.
+func (m *MockService) Update(id int, patient *models.PatientUpdateDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", id, patient)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockServiceMockRecorder) Update(id, patient interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), id, patient)
+}
+
+// WithTx mocks base method.
+func (m *MockService) WithTx(tx database.DBTX) patient.Service {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(patient.Service)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockServiceMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockService)(nil).WithTx), tx)
+}
+
+// MockDependentCleaner is a mock of DependentCleaner interface.
+type MockDependentCleaner struct {
+	ctrl     *gomock.Controller
+	recorder *MockDependentCleanerMockRecorder
+}
+
+// MockDependentCleanerMockRecorder is the mock recorder for MockDependentCleaner.
+type MockDependentCleanerMockRecorder struct {
+	mock *MockDependentCleaner
+}
+
+// NewMockDependentCleaner creates a new mock instance.
+func NewMockDependentCleaner(ctrl *gomock.Controller) *MockDependentCleaner {
+	mock := &MockDependentCleaner{ctrl: ctrl}
+	mock.recorder = &MockDependentCleanerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDependentCleaner) EXPECT() *MockDependentCleanerMockRecorder {
+	return m.recorder
+}
+
+// CleanupPatient mocks base method.
+func (m *MockDependentCleaner) CleanupPatient(tx database.DBTX, patientID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CleanupPatient", tx, patientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CleanupPatient indicates an expected call of CleanupPatient.
+func (mr *MockDependentCleanerMockRecorder) CleanupPatient(tx, patientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanupPatient", reflect.TypeOf((*MockDependentCleaner)(nil).CleanupPatient), tx, patientID)
+}
+
+// MockDependentMerger is a mock of DependentMerger interface.
+type MockDependentMerger struct {
+	ctrl     *gomock.Controller
+	recorder *MockDependentMergerMockRecorder
+}
+
+// MockDependentMergerMockRecorder is the mock recorder for MockDependentMerger.
+type MockDependentMergerMockRecorder struct {
+	mock *MockDependentMerger
+}
+
+// NewMockDependentMerger creates a new mock instance.
+func NewMockDependentMerger(ctrl *gomock.Controller) *MockDependentMerger {
+	mock := &MockDependentMerger{ctrl: ctrl}
+	mock.recorder = &MockDependentMergerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDependentMerger) EXPECT() *MockDependentMergerMockRecorder {
+	return m.recorder
+}
+
+// MergePatient mocks base method.
+func (m *MockDependentMerger) MergePatient(tx database.DBTX, fromID, toID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergePatient", tx, fromID, toID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergePatient indicates an expected call of MergePatient.
+func (mr *MockDependentMergerMockRecorder) MergePatient(tx, fromID, toID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergePatient", reflect.TypeOf((*MockDependentMerger)(nil).MergePatient), tx, fromID, toID)
+}
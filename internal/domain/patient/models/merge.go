@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// DuplicateQuery filtra el reporte de posibles duplicados
+type DuplicateQuery struct {
+	PacienteID int     // 0 = todos los pares; si no, solo los pares de este paciente
+	MinScore   float64 // puntaje mínimo del par
+	Limit      int
+}
+
+// DuplicateCandidate es un par de pacientes que podrían ser la misma persona. El
+// puntaje va de 0 a 1: 0.5 por la similitud del nombre, 0.3 si coincide la fecha de
+// nacimiento y 0.2 si coincide el teléfono.
+type DuplicateCandidate struct {
+	Paciente        Patient `json:"paciente"`
+	Duplicado       Patient `json:"duplicado"`
+	Puntaje         float64 `json:"puntaje"`
+	SimilitudNombre float64 `json:"similitud_nombre"`
+	MismaFecha      bool    `json:"misma_fecha_nacimiento"`
+	MismoTelefono   bool    `json:"mismo_telefono"`
+}
+
+// PatientMergeDTO indica el paciente que se fusiona en el de la ruta
+type PatientMergeDTO struct {
+	FusionadoID int `json:"fusionado_id" validate:"required"`
+}
+
+// PatientMerge es el registro de una fusión: el paciente fusionado (ya eliminado)
+// se conserva como copia de sus datos.
+type PatientMerge struct {
+	ID                  int        `json:"id"`
+	PacienteID          *int       `json:"paciente_id,omitempty"`
+	FusionadoID         int        `json:"fusionado_id"`
+	FusionadoNombre     string     `json:"fusionado_nombre"`
+	FusionadoNacimiento *time.Time `json:"fusionado_fecha_nacimiento,omitempty"`
+	FusionadoTelefono   *string    `json:"fusionado_telefono,omitempty"`
+	FusionadoSexo       *string    `json:"fusionado_sexo,omitempty"`
+	UsuarioID           *int       `json:"usuario_id,omitempty"`
+	Fecha               time.Time  `json:"fecha"`
+}
@@ -2,6 +2,30 @@ package models
 
 import "time"
 
+// Sexo biológico y género se registran por separado
+const (
+	SexMale         = "M"
+	SexFemale       = "F"
+	SexIntersex     = "I"
+	SexNotSpecified = "X"
+)
+
+var Sexes = []string{SexMale, SexFemale, SexIntersex, SexNotSpecified}
+
+var Genders = []string{"masculino", "femenino", "no-binario", "otro", "prefiere-no-decir"}
+
+// Canales de contacto preferidos
+const (
+	ChannelPhone    = "telefono"
+	ChannelWhatsApp = "whatsapp"
+	ChannelSMS      = "sms"
+	ChannelEmail    = "correo"
+)
+
+var ContactChannels = []string{ChannelPhone, ChannelWhatsApp, ChannelSMS, ChannelEmail}
+
+var PhoneTypes = []string{"movil", "casa", "trabajo", "otro"}
+
 // Patient representa un paciente en el sistema
 type Patient struct {
 	ID              int       `json:"id"`
@@ -9,28 +33,97 @@ type Patient struct {
 	FechaNacimiento time.Time `json:"fecha_nacimiento"`
 	Telefono        *string   `json:"telefono,omitempty"`
 	Sexo            string    `json:"sexo"`
+	PatientDemographics
+	// Archivo: un paciente archivado se oculta de listados y búsquedas
+	ArchivadoEn  *time.Time `json:"archivado_en,omitempty"`
+	ArchivadoPor *int       `json:"archivado_por,omitempty"`
+}
+
+// PatientDemographics son los datos de identificación y contacto adicionales. Telefono
+// del paciente es el número principal (el que se usa en búsquedas); Telefonos guarda
+// los demás.
+type PatientDemographics struct {
+	DPI                *string        `json:"dpi,omitempty" validate:"omitempty,dpi"`
+	Correo             *string        `json:"correo,omitempty" validate:"omitempty,email"`
+	Direccion          *string        `json:"direccion,omitempty"`
+	Ocupacion          *string        `json:"ocupacion,omitempty"`
+	Genero             *string        `json:"genero,omitempty" validate:"omitempty,oneof=masculino femenino no-binario otro prefiere-no-decir"`
+	CanalContacto      *string        `json:"canal_contacto,omitempty" validate:"omitempty,oneof=telefono whatsapp sms correo"`
+	Telefonos          []PatientPhone `json:"telefonos,omitempty" validate:"dive"`
+	ContactoEmergencia *Contact       `json:"contacto_emergencia,omitempty"`
+	Tutor              *Contact       `json:"tutor,omitempty"` // obligatorio para menores de edad
+	Seguro             *Insurance     `json:"seguro,omitempty"`
+}
+
+// PatientPhone es un número adicional del paciente
+type PatientPhone struct {
+	Numero string `json:"numero" validate:"required,phone"`
+	Tipo   string `json:"tipo" validate:"required,oneof=movil casa trabajo otro"`
+}
+
+// Contact es un contacto de emergencia o el tutor de un menor
+type Contact struct {
+	Nombre     string  `json:"nombre" validate:"required"`
+	Telefono   string  `json:"telefono" validate:"required,phone"`
+	Parentesco string  `json:"parentesco,omitempty"`
+	DPI        *string `json:"dpi,omitempty" validate:"omitempty,dpi"`
+}
+
+// Insurance es el seguro médico del paciente
+type Insurance struct {
+	Aseguradora string  `json:"aseguradora" validate:"required"`
+	Poliza      string  `json:"poliza" validate:"required"`
+	Titular     *string `json:"titular,omitempty"` // si el paciente es beneficiario
 }
 
 // PatientCreateDTO para crear un paciente
 type PatientCreateDTO struct {
 	Nombre          string  `json:"nombre" validate:"required"`
-	FechaNacimiento string  `json:"fecha_nacimiento" validate:"required"` // format: YYYY-MM-DD
-	Telefono        *string `json:"telefono,omitempty"`
-	Sexo            string  `json:"sexo" validate:"required,oneof=M F"`
+	FechaNacimiento string  `json:"fecha_nacimiento" validate:"required,datetime=2006-01-02"`
+	Telefono        *string `json:"telefono,omitempty" validate:"omitempty,phone"`
+	Sexo            string  `json:"sexo" validate:"required,oneof=M F I X"`
+	PatientDemographics
 }
 
-// PatientUpdateDTO para actualizar un paciente
+// PatientUpdateDTO para actualizar un paciente; reemplaza todos los datos
 type PatientUpdateDTO struct {
 	Nombre          string  `json:"nombre" validate:"required"`
-	FechaNacimiento string  `json:"fecha_nacimiento" validate:"required"`
-	Telefono        *string `json:"telefono,omitempty"`
-	Sexo            string  `json:"sexo" validate:"required,oneof=M F"`
+	FechaNacimiento string  `json:"fecha_nacimiento" validate:"required,datetime=2006-01-02"`
+	Telefono        *string `json:"telefono,omitempty" validate:"omitempty,phone"`
+	Sexo            string  `json:"sexo" validate:"required,oneof=M F I X"`
+	PatientDemographics
 }
 
-// PatientSearchResult para resultados de búsqueda
+// PatientSearchResult para resultados de búsqueda, ordenados por relevancia
 type PatientSearchResult struct {
-	ID              int     `json:"id"`
-	Nombre          string  `json:"nombre"`
-	Telefono        *string `json:"telefono,omitempty"`
-	FechaNacimiento string  `json:"fecha_nacimiento"`
+	ID              int              `json:"id"`
+	Nombre          string           `json:"nombre"`
+	Telefono        *string          `json:"telefono,omitempty"`
+	FechaNacimiento string           `json:"fecha_nacimiento"`
+	ArchivadoEn     *time.Time       `json:"archivado_en,omitempty"`
+	Puntaje         float64          `json:"puntaje"`
+	ProximaCita     *NextAppointment `json:"proxima_cita,omitempty"`
+}
+
+// NextAppointment es la próxima cita activa (agendada o confirmada) del paciente
+type NextAppointment struct {
+	ID    int       `json:"id"`
+	Fecha time.Time `json:"fecha"`
+}
+
+// PatientSearchQuery es el texto de búsqueda ya interpretado. Un paciente coincide si
+// cumple cualquiera de los criterios; el puntaje suma los que cumple.
+type PatientSearchQuery struct {
+	Terminos        []string   // palabras del nombre, con tolerancia a errores
+	Telefono        string     // solo dígitos; coincide en cualquier parte del teléfono
+	FechaNacimiento *time.Time // fecha completa
+	Anio, Mes, Dia  int        // fecha parcial; 0 = sin filtrar por esa parte
+	Archivados      bool       // incluir pacientes archivados
+	Limit           int
+}
+
+// Empty indica que el texto no produjo ningún criterio de búsqueda
+func (q PatientSearchQuery) Empty() bool {
+	return len(q.Terminos) == 0 && q.Telefono == "" && q.FechaNacimiento == nil &&
+		q.Anio == 0 && q.Mes == 0 && q.Dia == 0
 }
@@ -4,8 +4,13 @@ package patient
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
 	"github.com/tonitomc/healthcare-crm-api/internal/database"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/patient/models"
 	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
@@ -13,59 +18,162 @@ import (
 
 type Repository interface {
 	GetByID(id int) (*models.Patient, error)
-	GetAll() ([]models.Patient, error)
+	// GetAll omite los pacientes archivados salvo que archived sea true
+	GetAll(opts query.Options, archived bool) ([]models.Patient, int, error)
 	Create(patient *models.PatientCreateDTO) (int, error)
 	Update(id int, patient *models.PatientUpdateDTO) error
 	Delete(id int) error
-	SearchByName(name string) ([]models.PatientSearchResult, error)
+
+	// Archivo: Archive falla con ErrNotFound si el paciente no existe o ya está archivado
+	Archive(id, userID int) error
+	Restore(id int) error
+	// LastActivity es la fecha más reciente de sus consultas, exámenes y citas; nil si no tiene
+	LastActivity(id int) (*time.Time, error)
+	Search(q models.PatientSearchQuery) ([]models.PatientSearchResult, error)
+
+	// Duplicados y fusión
+	FindDuplicates(q models.DuplicateQuery) ([]models.DuplicateCandidate, error)
+	// LockForUpdate bloquea las filas de los pacientes hasta el fin de la transacción;
+	// falla con ErrNotFound si alguno no existe
+	LockForUpdate(ids []int) error
+	SaveMerge(merge *models.PatientMerge) error
+	GetMerges(patientID int) ([]models.PatientMerge, error)
+
+	// WithTx devuelve una copia del repositorio que ejecuta sus consultas en tx
+	WithTx(tx database.DBTX) Repository
 }
 
 type repository struct {
-	db *sql.DB
+	db database.DBTX
 }
 
 func NewRepository(db *sql.DB) Repository {
 	return &repository{db: db}
 }
 
+func (r *repository) WithTx(tx database.DBTX) Repository {
+	return &repository{db: tx}
+}
+
+const selectPatient = `
+	SELECT id, nombre, fecha_nacimiento, telefono, sexo,
+	       dpi, correo, direccion, ocupacion, genero, canal_contacto,
+	       telefonos, contacto_emergencia, tutor, seguro,
+	       archivado_en, archivado_por
+	FROM pacientes`
+
+func scanPatient(row interface{ Scan(...interface{}) error }, p *models.Patient) error {
+	return row.Scan(&p.ID, &p.Nombre, &p.FechaNacimiento, &p.Telefono, &p.Sexo,
+		&p.DPI, &p.Correo, &p.Direccion, &p.Ocupacion, &p.Genero, &p.CanalContacto,
+		jsonColumn{&p.Telefonos}, jsonColumn{&p.ContactoEmergencia}, jsonColumn{&p.Tutor}, jsonColumn{&p.Seguro},
+		&p.ArchivadoEn, &p.ArchivadoPor)
+}
+
+// jsonColumn lee una columna JSONB en el valor apuntado; NULL deja el valor en cero
+type jsonColumn struct{ dest interface{} }
+
+func (j jsonColumn) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, j.dest)
+	case string:
+		return json.Unmarshal([]byte(v), j.dest)
+	default:
+		return fmt.Errorf("jsonColumn: unsupported type %T", src)
+	}
+}
+
+// jsonArg serializa un objeto opcional para una columna JSONB; nil se guarda como NULL
+func jsonArg[T any](v *T) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// demographicArgs devuelve, en orden, los valores de dpi, correo, direccion, ocupacion,
+// genero, canal_contacto, telefonos, contacto_emergencia, tutor y seguro
+func demographicArgs(d *models.PatientDemographics) ([]interface{}, error) {
+	phones := d.Telefonos
+	if phones == nil {
+		phones = []models.PatientPhone{}
+	}
+	telefonos, err := json.Marshal(phones)
+	if err != nil {
+		return nil, err
+	}
+	emergencia, err := jsonArg(d.ContactoEmergencia)
+	if err != nil {
+		return nil, err
+	}
+	tutor, err := jsonArg(d.Tutor)
+	if err != nil {
+		return nil, err
+	}
+	seguro, err := jsonArg(d.Seguro)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{d.DPI, d.Correo, d.Direccion, d.Ocupacion, d.Genero, d.CanalContacto,
+		string(telefonos), emergencia, tutor, seguro}, nil
+}
+
 func (r *repository) GetByID(id int) (*models.Patient, error) {
 	var p models.Patient
-	err := r.db.QueryRow(`
-		SELECT id, nombre, fecha_nacimiento, telefono, sexo
-		FROM pacientes
-		WHERE id = $1
-	`, id).Scan(&p.ID, &p.Nombre, &p.FechaNacimiento, &p.Telefono, &p.Sexo)
-	if err != nil {
+	if err := scanPatient(r.db.QueryRow(selectPatient+` WHERE id = $1`, id), &p); err != nil {
 		return nil, database.MapSQLError(err, "PatientRepository.GetByID")
 	}
 	return &p, nil
 }
 
-func (r *repository) GetAll() ([]models.Patient, error) {
-	rows, err := r.db.Query(`
-		SELECT id, nombre, fecha_nacimiento, telefono, sexo
-		FROM pacientes
-		ORDER BY nombre
-	`)
+// patientColumns traduce los campos de orden y filtro del listado a columnas
+var patientColumns = query.Columns{
+	"id":               "id",
+	"nombre":           "nombre",
+	"fecha_nacimiento": "fecha_nacimiento",
+	"telefono":         "telefono",
+	"sexo":             "sexo",
+	"nacido_desde":     "fecha_nacimiento",
+	"nacido_hasta":     "fecha_nacimiento",
+}
+
+// GetAll devuelve una página de pacientes y el total que cumple los filtros
+func (r *repository) GetAll(opts query.Options, archived bool) ([]models.Patient, int, error) {
+	conds, args := opts.Conditions(patientColumns, nil)
+	if !archived {
+		conds = append(conds, "archivado_en IS NULL")
+	}
+	where := query.Where(conds)
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM pacientes`+where, args...).Scan(&total); err != nil {
+		return nil, 0, database.MapSQLError(err, "PatientRepository.GetAll(count)")
+	}
+
+	conds, args = opts.Seek(patientColumns, "id", conds, args)
+	page, args := opts.LimitOffset(args)
+	rows, err := r.db.Query(selectPatient+query.Where(conds)+opts.OrderBy(patientColumns, "id")+page, args...)
 	if err != nil {
-		return nil, database.MapSQLError(err, "PatientRepository.GetAll")
+		return nil, 0, database.MapSQLError(err, "PatientRepository.GetAll")
 	}
 	defer rows.Close()
 
 	var patients []models.Patient
 	for rows.Next() {
 		var p models.Patient
-		if err := rows.Scan(&p.ID, &p.Nombre, &p.FechaNacimiento, &p.Telefono, &p.Sexo); err != nil {
-			return nil, appErr.Wrap("PatientRepository.GetAll(scan)", appErr.ErrInternal, err)
+		if err := scanPatient(rows, &p); err != nil {
+			return nil, 0, appErr.Wrap("PatientRepository.GetAll(scan)", appErr.ErrInternal, err)
 		}
 		patients = append(patients, p)
 	}
 
-	if len(patients) == 0 {
-		return nil, appErr.Wrap("PatientRepository.GetAll", appErr.ErrNotFound, nil)
-	}
-
-	return patients, nil
+	return patients, total, nil
 }
 
 func (r *repository) Create(patient *models.PatientCreateDTO) (int, error) {
@@ -74,12 +182,19 @@ func (r *repository) Create(patient *models.PatientCreateDTO) (int, error) {
 		return 0, appErr.Wrap("PatientRepository.Create(parse_date)", appErr.ErrInvalidInput, err)
 	}
 
+	demographics, err := demographicArgs(&patient.PatientDemographics)
+	if err != nil {
+		return 0, appErr.Wrap("PatientRepository.Create(marshal)", appErr.ErrInvalidInput, err)
+	}
+
 	var id int
 	err = r.db.QueryRow(`
-		INSERT INTO pacientes (nombre, fecha_nacimiento, telefono, sexo)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO pacientes (nombre, fecha_nacimiento, telefono, sexo,
+		                       dpi, correo, direccion, ocupacion, genero, canal_contacto,
+		                       telefonos, contacto_emergencia, tutor, seguro)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id
-	`, patient.Nombre, fecha, patient.Telefono, patient.Sexo).Scan(&id)
+	`, append([]interface{}{patient.Nombre, fecha, patient.Telefono, patient.Sexo}, demographics...)...).Scan(&id)
 	if err != nil {
 		return 0, database.MapSQLError(err, "PatientRepository.Create")
 	}
@@ -92,11 +207,18 @@ func (r *repository) Update(id int, patient *models.PatientUpdateDTO) error {
 		return appErr.Wrap("PatientRepository.Update(parse_date)", appErr.ErrInvalidInput, err)
 	}
 
+	demographics, err := demographicArgs(&patient.PatientDemographics)
+	if err != nil {
+		return appErr.Wrap("PatientRepository.Update(marshal)", appErr.ErrInvalidInput, err)
+	}
+
 	res, err := r.db.Exec(`
 		UPDATE pacientes
-		SET nombre = $1, fecha_nacimiento = $2, telefono = $3, sexo = $4
-		WHERE id = $5
-	`, patient.Nombre, fecha, patient.Telefono, patient.Sexo, id)
+		SET nombre = $1, fecha_nacimiento = $2, telefono = $3, sexo = $4,
+		    dpi = $5, correo = $6, direccion = $7, ocupacion = $8, genero = $9, canal_contacto = $10,
+		    telefonos = $11, contacto_emergencia = $12, tutor = $13, seguro = $14
+		WHERE id = $15
+	`, append([]interface{}{patient.Nombre, fecha, patient.Telefono, patient.Sexo}, append(demographics, id)...)...)
 	if err != nil {
 		return database.MapSQLError(err, "PatientRepository.Update")
 	}
@@ -123,27 +245,289 @@ func (r *repository) Delete(id int) error {
 	return nil
 }
 
-func (r *repository) SearchByName(name string) ([]models.PatientSearchResult, error) {
+func (r *repository) Archive(id, userID int) error {
+	var archivadoPor *int
+	if userID > 0 {
+		archivadoPor = &userID
+	}
+
+	res, err := r.db.Exec(`
+		UPDATE pacientes
+		SET archivado_en = NOW(), archivado_por = $2
+		WHERE id = $1 AND archivado_en IS NULL
+	`, id, archivadoPor)
+	if err != nil {
+		return database.MapSQLError(err, "PatientRepository.Archive")
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return appErr.Wrap("PatientRepository.Archive", appErr.ErrNotFound, nil)
+	}
+
+	return nil
+}
+
+func (r *repository) Restore(id int) error {
+	res, err := r.db.Exec(`
+		UPDATE pacientes
+		SET archivado_en = NULL, archivado_por = NULL
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return database.MapSQLError(err, "PatientRepository.Restore")
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return appErr.Wrap("PatientRepository.Restore", appErr.ErrNotFound, nil)
+	}
+
+	return nil
+}
+
+func (r *repository) LastActivity(id int) (*time.Time, error) {
+	var last *time.Time
+	err := r.db.QueryRow(`
+		SELECT GREATEST(
+			(SELECT MAX(fecha)::timestamptz FROM consultas WHERE paciente_id = $1),
+			(SELECT MAX(fecha)::timestamptz FROM examenes WHERE paciente_id = $1),
+			(SELECT MAX(fecha)::timestamptz FROM citas WHERE paciente_id = $1)
+		)
+	`, id).Scan(&last)
+	if err != nil {
+		return nil, database.MapSQLError(err, "PatientRepository.LastActivity")
+	}
+	return last, nil
+}
+
+// Search busca pacientes por nombre, teléfono y fecha de nacimiento. Cada criterio
+// aporta al puntaje: hasta 1 por el nombre (promedio de las palabras; 1 si la palabra
+// es el inicio de un nombre, si no su similitud por trigramas), 1 por el teléfono si
+// empieza con los dígitos (0.8 si solo los contiene), 1 por la fecha exacta y 0.5 por
+// la fecha parcial.
+func (r *repository) Search(q models.PatientSearchQuery) ([]models.PatientSearchResult, error) {
+	var (
+		args    []interface{}
+		matches []string
+		scores  []string
+	)
+	param := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	const nombre = "paciente_nombre_busqueda(p.nombre)"
+	if len(q.Terminos) > 0 {
+		tokenScores := make([]string, 0, len(q.Terminos))
+		for _, t := range q.Terminos {
+			tok := "paciente_nombre_busqueda(" + param(t) + ")"
+			matches = append(matches, nombre+" LIKE '%' || "+tok+" || '%'", tok+" <% "+nombre)
+			tokenScores = append(tokenScores, "GREATEST(CASE WHEN ' ' || "+nombre+" LIKE '% ' || "+tok+" || '%' THEN 1 ELSE 0 END, word_similarity("+tok+", "+nombre+"))")
+		}
+		scores = append(scores, "("+strings.Join(tokenScores, " + ")+") / "+strconv.Itoa(len(q.Terminos)))
+	}
+
+	if q.Telefono != "" {
+		const digitos = "paciente_telefono_digitos(p.telefono)"
+		tel := param(q.Telefono)
+		matches = append(matches, digitos+" LIKE '%' || "+tel+" || '%'")
+		scores = append(scores, "CASE WHEN "+digitos+" LIKE "+tel+" || '%' THEN 1 WHEN "+digitos+" LIKE '%' || "+tel+" || '%' THEN 0.8 ELSE 0 END")
+	}
+
+	if q.FechaNacimiento != nil {
+		cond := "p.fecha_nacimiento = " + param(q.FechaNacimiento.Format("2006-01-02")) + "::date"
+		matches = append(matches, cond)
+		scores = append(scores, "CASE WHEN "+cond+" THEN 1 ELSE 0 END")
+	}
+
+	var parts []string
+	if q.Anio > 0 {
+		parts = append(parts, "EXTRACT(YEAR FROM p.fecha_nacimiento) = "+param(q.Anio))
+	}
+	if q.Mes > 0 {
+		parts = append(parts, "EXTRACT(MONTH FROM p.fecha_nacimiento) = "+param(q.Mes))
+	}
+	if q.Dia > 0 {
+		parts = append(parts, "EXTRACT(DAY FROM p.fecha_nacimiento) = "+param(q.Dia))
+	}
+	if len(parts) > 0 {
+		cond := "(" + strings.Join(parts, " AND ") + ")"
+		matches = append(matches, cond)
+		scores = append(scores, "CASE WHEN "+cond+" THEN 0.5 ELSE 0 END")
+	}
+
+	if len(matches) == 0 {
+		return []models.PatientSearchResult{}, nil
+	}
+	where := "(" + strings.Join(matches, " OR ") + ")"
+	if !q.Archivados {
+		where += " AND p.archivado_en IS NULL"
+	}
+
 	rows, err := r.db.Query(`
-		SELECT id, nombre, telefono, fecha_nacimiento
-		FROM pacientes
-		WHERE unaccent(nombre) ILIKE '%' || unaccent($1) || '%'
-		ORDER BY nombre
-		LIMIT 20
-	`, name)
+		SELECT p.id, p.nombre, p.telefono, p.fecha_nacimiento, p.archivado_en, m.puntaje, c.id, c.fecha
+		FROM pacientes p
+		CROSS JOIN LATERAL (SELECT (`+strings.Join(scores, " + ")+`)::float8 AS puntaje) m
+		LEFT JOIN LATERAL (
+			SELECT id, fecha
+			FROM citas
+			WHERE paciente_id = p.id AND fecha >= NOW() AND estado IN ('scheduled', 'confirmed')
+			ORDER BY fecha
+			LIMIT 1
+		) c ON true
+		WHERE `+where+`
+		ORDER BY m.puntaje DESC, p.nombre, p.id
+		LIMIT `+param(q.Limit), args...)
 	if err != nil {
-		return nil, database.MapSQLError(err, "PatientRepository.SearchByName")
+		return nil, database.MapSQLError(err, "PatientRepository.Search")
 	}
 	defer rows.Close()
 
-	var results []models.PatientSearchResult
+	results := []models.PatientSearchResult{}
 	for rows.Next() {
-		var r models.PatientSearchResult
-		if err := rows.Scan(&r.ID, &r.Nombre, &r.Telefono, &r.FechaNacimiento); err != nil {
-			return nil, appErr.Wrap("PatientRepository.SearchByName(scan)", appErr.ErrInternal, err)
+		var (
+			res       models.PatientSearchResult
+			citaID    sql.NullInt64
+			citaFecha sql.NullTime
+		)
+		if err := rows.Scan(&res.ID, &res.Nombre, &res.Telefono, &res.FechaNacimiento, &res.ArchivadoEn, &res.Puntaje, &citaID, &citaFecha); err != nil {
+			return nil, appErr.Wrap("PatientRepository.Search(scan)", appErr.ErrInternal, err)
+		}
+		if citaID.Valid {
+			res.ProximaCita = &models.NextAppointment{ID: int(citaID.Int64), Fecha: citaFecha.Time}
 		}
-		results = append(results, r)
+		results = append(results, res)
 	}
 
 	return results, nil
 }
+
+// FindDuplicates arma los pares candidatos por nombre parecido (trigramas), misma
+// fecha de nacimiento o mismo teléfono y los ordena por puntaje. El teléfono se
+// compara por sus últimos 8 dígitos para ignorar el código de país.
+func (r *repository) FindDuplicates(q models.DuplicateQuery) ([]models.DuplicateCandidate, error) {
+	rows, err := r.db.Query(`
+		WITH pares AS (
+			SELECT a.id AS a_id, b.id AS b_id
+			FROM pacientes a
+			JOIN pacientes b ON a.id < b.id
+				AND paciente_nombre_busqueda(a.nombre) % paciente_nombre_busqueda(b.nombre)
+			WHERE $2 = 0 OR $2 IN (a.id, b.id)
+			UNION
+			SELECT a.id, b.id
+			FROM pacientes a
+			JOIN pacientes b ON a.id < b.id AND a.fecha_nacimiento = b.fecha_nacimiento
+			WHERE $2 = 0 OR $2 IN (a.id, b.id)
+			UNION
+			SELECT a.id, b.id
+			FROM pacientes a
+			JOIN pacientes b ON a.id < b.id
+				AND right(paciente_telefono_digitos(a.telefono), 8) = right(paciente_telefono_digitos(b.telefono), 8)
+			WHERE length(paciente_telefono_digitos(a.telefono)) >= 7 AND ($2 = 0 OR $2 IN (a.id, b.id))
+		)
+		SELECT a.id, a.nombre, a.fecha_nacimiento, a.telefono, a.sexo,
+		       b.id, b.nombre, b.fecha_nacimiento, b.telefono, b.sexo,
+		       m.puntaje, s.similitud, s.misma_fecha, s.mismo_telefono
+		FROM pares
+		JOIN pacientes a ON a.id = pares.a_id
+		JOIN pacientes b ON b.id = pares.b_id
+		CROSS JOIN LATERAL (
+			SELECT similarity(paciente_nombre_busqueda(a.nombre), paciente_nombre_busqueda(b.nombre))::float8 AS similitud,
+			       a.fecha_nacimiento = b.fecha_nacimiento AS misma_fecha,
+			       length(paciente_telefono_digitos(a.telefono)) >= 7
+			           AND right(paciente_telefono_digitos(a.telefono), 8) = right(paciente_telefono_digitos(b.telefono), 8) AS mismo_telefono
+		) s
+		CROSS JOIN LATERAL (
+			SELECT (0.5 * s.similitud
+			        + CASE WHEN s.misma_fecha THEN 0.3 ELSE 0 END
+			        + CASE WHEN s.mismo_telefono THEN 0.2 ELSE 0 END)::float8 AS puntaje
+		) m
+		WHERE m.puntaje >= $1
+		ORDER BY m.puntaje DESC, a.id, b.id
+		LIMIT $3
+	`, q.MinScore, q.PacienteID, q.Limit)
+	if err != nil {
+		return nil, database.MapSQLError(err, "PatientRepository.FindDuplicates")
+	}
+	defer rows.Close()
+
+	candidates := []models.DuplicateCandidate{}
+	for rows.Next() {
+		var d models.DuplicateCandidate
+		a, b := &d.Paciente, &d.Duplicado
+		if err := rows.Scan(
+			&a.ID, &a.Nombre, &a.FechaNacimiento, &a.Telefono, &a.Sexo,
+			&b.ID, &b.Nombre, &b.FechaNacimiento, &b.Telefono, &b.Sexo,
+			&d.Puntaje, &d.SimilitudNombre, &d.MismaFecha, &d.MismoTelefono,
+		); err != nil {
+			return nil, appErr.Wrap("PatientRepository.FindDuplicates(scan)", appErr.ErrInternal, err)
+		}
+		candidates = append(candidates, d)
+	}
+
+	return candidates, nil
+}
+
+// LockForUpdate bloquea en orden de id para que dos fusiones cruzadas no se bloqueen
+// entre sí
+func (r *repository) LockForUpdate(ids []int) error {
+	rows, err := r.db.Query(`SELECT id FROM pacientes WHERE id = ANY($1) ORDER BY id FOR UPDATE`, ids)
+	if err != nil {
+		return database.MapSQLError(err, "PatientRepository.LockForUpdate")
+	}
+	defer rows.Close()
+
+	locked := 0
+	for rows.Next() {
+		locked++
+	}
+	if err := rows.Err(); err != nil {
+		return database.MapSQLError(err, "PatientRepository.LockForUpdate")
+	}
+	if locked != len(ids) {
+		return appErr.Wrap("PatientRepository.LockForUpdate", appErr.ErrNotFound, nil)
+	}
+	return nil
+}
+
+func (r *repository) SaveMerge(m *models.PatientMerge) error {
+	err := r.db.QueryRow(`
+		INSERT INTO pacientes_fusiones (paciente_id, fusionado_id, fusionado_nombre, fusionado_nacimiento,
+		                                fusionado_telefono, fusionado_sexo, usuario_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, fecha
+	`, m.PacienteID, m.FusionadoID, m.FusionadoNombre, m.FusionadoNacimiento,
+		m.FusionadoTelefono, m.FusionadoSexo, m.UsuarioID).Scan(&m.ID, &m.Fecha)
+	if err != nil {
+		return database.MapSQLError(err, "PatientRepository.SaveMerge")
+	}
+	return nil
+}
+
+// GetMerges devuelve las fusiones en las que participó el paciente, conservado o fusionado
+func (r *repository) GetMerges(patientID int) ([]models.PatientMerge, error) {
+	rows, err := r.db.Query(`
+		SELECT id, paciente_id, fusionado_id, fusionado_nombre, fusionado_nacimiento,
+		       fusionado_telefono, fusionado_sexo, usuario_id, fecha
+		FROM pacientes_fusiones
+		WHERE paciente_id = $1 OR fusionado_id = $1
+		ORDER BY fecha DESC, id DESC
+	`, patientID)
+	if err != nil {
+		return nil, database.MapSQLError(err, "PatientRepository.GetMerges")
+	}
+	defer rows.Close()
+
+	merges := []models.PatientMerge{}
+	for rows.Next() {
+		var m models.PatientMerge
+		if err := rows.Scan(&m.ID, &m.PacienteID, &m.FusionadoID, &m.FusionadoNombre, &m.FusionadoNacimiento,
+			&m.FusionadoTelefono, &m.FusionadoSexo, &m.UsuarioID, &m.Fecha); err != nil {
+			return nil, appErr.Wrap("PatientRepository.GetMerges(scan)", appErr.ErrInternal, err)
+		}
+		merges = append(merges, m)
+	}
+
+	return merges, nil
+}
@@ -3,25 +3,79 @@
 package patient
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
 	"github.com/tonitomc/healthcare-crm-api/internal/domain/patient/models"
+	"github.com/tonitomc/healthcare-crm-api/pkg/dpi"
 	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/validator"
 )
 
 type Service interface {
 	GetByID(id int) (*models.Patient, error)
-	GetAll() ([]models.Patient, error)
+	GetAll(opts query.Options, archived bool) ([]models.Patient, int, error)
 	Create(patient *models.PatientCreateDTO) (int, error)
 	Update(id int, patient *models.PatientUpdateDTO) error
-	Delete(id int) error
-	SearchByName(name string) ([]models.PatientSearchResult, error)
+	Search(term string, limit int, archived bool) ([]models.PatientSearchResult, error)
+
+	// Archivo: Archive oculta al paciente conservando su historial; Purge lo borra
+	// definitivamente, solo si está archivado y pasó el periodo de retención
+	Archive(id, userID int) error
+	Restore(id int) error
+	Purge(id int) error
+
+	// Duplicados: FindDuplicates reporta pares candidatos; Merge fusiona mergedID en
+	// survivorID y elimina a mergedID, dejando registro en GetMerges
+	FindDuplicates(q models.DuplicateQuery) ([]models.DuplicateCandidate, error)
+	Merge(survivorID, mergedID, userID int) (*models.PatientMerge, error)
+	GetMerges(patientID int) ([]models.PatientMerge, error)
+
+	// WithTx devuelve una copia del servicio que ejecuta sus operaciones en tx
+	WithTx(tx database.DBTX) Service
+}
+
+// DependentCleaner elimina o desvincula los datos de otros dominios (citas, consultas,
+// exámenes, antecedentes) que referencian a un paciente. Se ejecuta dentro de la misma
+// transacción que el borrado del paciente.
+type DependentCleaner interface {
+	CleanupPatient(tx database.DBTX, patientID int) error
+}
+
+// DependentMerger pasa los datos de otros dominios (citas, consultas con sus respuestas,
+// exámenes, antecedentes, lista de espera) de un paciente a otro al fusionar duplicados.
+// Se ejecuta dentro de la misma transacción que la fusión.
+type DependentMerger interface {
+	MergePatient(tx database.DBTX, fromID, toID int) error
+}
+
+// Config agrupa los parámetros configurables del servicio
+type Config struct {
+	// RetentionYears es el tiempo que un paciente archivado se conserva, contado desde
+	// el archivo y desde su última actividad clínica, antes de poder purgarlo
+	RetentionYears int
 }
 
 type service struct {
-	repo Repository
+	repo    Repository
+	uow     database.UnitOfWork
+	cleaner DependentCleaner
+	merger  DependentMerger
+	cfg     Config
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(repo Repository, uow database.UnitOfWork, cleaner DependentCleaner, merger DependentMerger, cfg Config) Service {
+	return &service{repo: repo, uow: uow, cleaner: cleaner, merger: merger, cfg: cfg}
+}
+
+func (s *service) WithTx(tx database.DBTX) Service {
+	return &service{repo: s.repo.WithTx(tx), uow: database.JoinUnitOfWork(tx), cleaner: s.cleaner, merger: s.merger, cfg: s.cfg}
 }
 
 func (s *service) GetByID(id int) (*models.Patient, error) {
@@ -31,14 +85,17 @@ func (s *service) GetByID(id int) (*models.Patient, error) {
 	return s.repo.GetByID(id)
 }
 
-func (s *service) GetAll() ([]models.Patient, error) {
-	return s.repo.GetAll()
+func (s *service) GetAll(opts query.Options, archived bool) ([]models.Patient, int, error) {
+	return s.repo.GetAll(opts, archived)
 }
 
 func (s *service) Create(patient *models.PatientCreateDTO) (int, error) {
-	if patient == nil || patient.Nombre == "" || patient.Sexo == "" {
+	if patient == nil {
 		return 0, appErr.Wrap("PatientService.Create", appErr.ErrInvalidInput, nil)
 	}
+	if err := validatePatient(patient, patient.FechaNacimiento, patient.Telefono, &patient.PatientDemographics, time.Now()); err != nil {
+		return 0, err
+	}
 	return s.repo.Create(patient)
 }
 
@@ -46,21 +103,370 @@ func (s *service) Update(id int, patient *models.PatientUpdateDTO) error {
 	if id <= 0 || patient == nil {
 		return appErr.Wrap("PatientService.Update", appErr.ErrInvalidInput, nil)
 	}
+	if err := validatePatient(patient, patient.FechaNacimiento, patient.Telefono, &patient.PatientDemographics, time.Now()); err != nil {
+		return err
+	}
+
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if current.ArchivadoEn != nil {
+		return appErr.NewDomainError(appErr.ErrConflict, "El paciente está archivado; restáurelo antes de modificarlo")
+	}
 	return s.repo.Update(id, patient)
 }
 
-func (s *service) Delete(id int) error {
+const adultAge = 18
+
+// validatePatient valida el DTO contra sus etiquetas y las reglas que combinan varios
+// campos, y normaliza los opcionales: los textos vacíos se guardan como nulos y los
+// DPI sin espacios ni guiones.
+func validatePatient(dto interface{}, fechaNacimiento string, telefono *string, d *models.PatientDemographics, now time.Time) error {
+	for _, field := range []**string{&d.DPI, &d.Correo, &d.Direccion, &d.Ocupacion, &d.Genero, &d.CanalContacto} {
+		if *field != nil && strings.TrimSpace(**field) == "" {
+			*field = nil
+		}
+	}
+
+	if err := validator.Struct(dto); err != nil {
+		return err
+	}
+
+	birth, _ := time.Parse("2006-01-02", fechaNacimiento) // formato validado por la etiqueta
+	if birth.After(now) {
+		return appErr.NewValidationError("fecha_nacimiento", "past", "La fecha de nacimiento no puede ser futura")
+	}
+
+	switch {
+	case d.CanalContacto == nil:
+	case *d.CanalContacto == models.ChannelEmail && d.Correo == nil:
+		return appErr.NewValidationError("correo", "required_if", "El canal de contacto por correo requiere un correo")
+	case *d.CanalContacto != models.ChannelEmail && telefono == nil && len(d.Telefonos) == 0:
+		return appErr.NewValidationError("telefono", "required_if", "El canal de contacto elegido requiere un teléfono")
+	}
+
+	if birth.AddDate(adultAge, 0, 0).After(now) && d.Tutor == nil {
+		return appErr.NewValidationError("tutor", "required_if", "Los pacientes menores de edad requieren un tutor")
+	}
+
+	if d.DPI != nil {
+		*d.DPI = dpi.Normalize(*d.DPI)
+	}
+	if d.Tutor != nil && d.Tutor.DPI != nil {
+		*d.Tutor.DPI = dpi.Normalize(*d.Tutor.DPI)
+	}
+	return nil
+}
+
+func (s *service) Archive(id, userID int) error {
+	if id <= 0 {
+		return appErr.Wrap("PatientService.Archive", appErr.ErrInvalidInput, nil)
+	}
+
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if current.ArchivadoEn != nil {
+		return appErr.NewDomainError(appErr.ErrConflict, "El paciente ya está archivado")
+	}
+	return s.repo.Archive(id, userID)
+}
+
+func (s *service) Restore(id int) error {
 	if id <= 0 {
-		return appErr.Wrap("PatientService.Delete", appErr.ErrInvalidInput, nil)
+		return appErr.Wrap("PatientService.Restore", appErr.ErrInvalidInput, nil)
 	}
-	return s.repo.Delete(id)
+
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if current.ArchivadoEn == nil {
+		return appErr.NewDomainError(appErr.ErrConflict, "El paciente no está archivado")
+	}
+	return s.repo.Restore(id)
 }
 
-func (s *service) SearchByName(name string) ([]models.PatientSearchResult, error) {
-	if name == "" {
-		return nil, appErr.Wrap("PatientService.SearchByName", appErr.ErrInvalidInput, nil)
+// Purge borra definitivamente un paciente archivado y sus datos dependientes. El
+// periodo de retención se cuenta desde el archivo y desde la última actividad clínica.
+func (s *service) Purge(id int) error {
+	if id <= 0 {
+		return appErr.Wrap("PatientService.Purge", appErr.ErrInvalidInput, nil)
+	}
+
+	// Los datos dependientes y el paciente se borran juntos o no se borra nada
+	return s.uow.Do(func(tx database.DBTX) error {
+		repo := s.repo.WithTx(tx)
+		if err := repo.LockForUpdate([]int{id}); err != nil {
+			return err
+		}
+
+		current, err := repo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if current.ArchivadoEn == nil {
+			return appErr.NewDomainError(appErr.ErrConflict, "Solo se pueden purgar pacientes archivados")
+		}
+
+		last, err := repo.LastActivity(id)
+		if err != nil {
+			return err
+		}
+		retainedSince := *current.ArchivadoEn
+		if last != nil && last.After(retainedSince) {
+			retainedSince = *last
+		}
+		if until := retainedSince.AddDate(s.cfg.RetentionYears, 0, 0); time.Now().Before(until) {
+			return appErr.NewDomainError(appErr.ErrConflict, fmt.Sprintf(
+				"El paciente debe conservarse hasta el %s por el periodo de retención de %d años",
+				until.Format("2006-01-02"), s.cfg.RetentionYears))
+		}
+
+		if s.cleaner != nil {
+			if err := s.cleaner.CleanupPatient(tx, id); err != nil {
+				return err
+			}
+		}
+		return repo.Delete(id)
+	})
+}
+
+const (
+	searchMinLength    = 2
+	searchDefaultLimit = 10
+	searchMaxLimit     = 50
+	phoneMinDigits     = 3
+)
+
+// Search interpreta el texto libre de búsqueda (nombre, teléfono o fecha de
+// nacimiento) y devuelve los pacientes ordenados por relevancia.
+func (s *service) Search(term string, limit int, archived bool) ([]models.PatientSearchResult, error) {
+	term = strings.TrimSpace(term)
+	if utf8.RuneCountInString(term) < searchMinLength {
+		return nil, appErr.Wrap("PatientService.Search", appErr.ErrInvalidInput, nil)
+	}
+
+	switch {
+	case limit <= 0:
+		limit = searchDefaultLimit
+	case limit > searchMaxLimit:
+		limit = searchMaxLimit
+	}
+
+	q := parseSearchTerm(term, time.Now())
+	if q.Empty() {
+		return nil, appErr.Wrap("PatientService.Search", appErr.ErrInvalidInput, nil)
+	}
+	q.Limit = limit
+	q.Archivados = archived
+	return s.repo.Search(q)
+}
+
+// Fechas completas aceptadas en la búsqueda; "2" y "1" aceptan uno o dos dígitos
+var searchDateLayouts = []string{"2006-1-2", "2/1/2006", "2-1-2006", "2.1.2006"}
+
+// parseSearchTerm reparte las palabras del texto entre los criterios: fechas
+// (completas, día/mes o un año), teléfonos (dígitos con guiones, espacios o
+// paréntesis; las partes se unen, "5555 1234") y el resto como palabras del nombre.
+// Un número de cuatro dígitos que puede ser un año se busca como año, y también como
+// teléfono si es el único número del texto.
+func parseSearchTerm(term string, now time.Time) models.PatientSearchQuery {
+	var (
+		q     models.PatientSearchQuery
+		phone []string
+		year  string
+	)
+	for _, field := range strings.Fields(term) {
+		if d, ok := parseSearchDate(field, now); ok {
+			if q.FechaNacimiento == nil {
+				q.FechaNacimiento = &d
+			}
+			continue
+		}
+		if d, err := time.Parse("2/1", field); err == nil {
+			q.Dia, q.Mes = d.Day(), int(d.Month())
+			continue
+		}
+		if digits, ok := phoneDigits(field); ok {
+			if n, _ := strconv.Atoi(digits); len(digits) == 4 && n >= 1900 && n <= now.Year() {
+				q.Anio, year = n, digits
+				continue
+			}
+			phone = append(phone, digits)
+			continue
+		}
+		q.Terminos = append(q.Terminos, strings.FieldsFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})...)
+	}
+
+	if len(phone) == 0 {
+		phone = append(phone, year)
+	}
+	if digits := strings.Join(phone, ""); len(digits) >= phoneMinDigits {
+		q.Telefono = digits
+	}
+	return q
+}
+
+func parseSearchDate(field string, now time.Time) (time.Time, bool) {
+	for _, layout := range searchDateLayouts {
+		if d, err := time.Parse(layout, field); err == nil && d.Year() >= 1900 && !d.After(now) {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// phoneDigits devuelve los dígitos de una palabra formada solo por dígitos y
+// separadores de teléfono
+func phoneDigits(field string) (string, bool) {
+	var b strings.Builder
+	for _, r := range field {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case strings.ContainsRune("-.()+", r):
+		default:
+			return "", false
+		}
+	}
+	return b.String(), b.Len() > 0
+}
+
+const (
+	duplicateDefaultScore = 0.5
+	duplicateDefaultLimit = 50
+	duplicateMaxLimit     = 200
+)
+
+func (s *service) FindDuplicates(q models.DuplicateQuery) ([]models.DuplicateCandidate, error) {
+	if q.PacienteID < 0 || q.MinScore < 0 || q.MinScore > 1 {
+		return nil, appErr.Wrap("PatientService.FindDuplicates", appErr.ErrInvalidInput, nil)
+	}
+	if q.MinScore == 0 {
+		q.MinScore = duplicateDefaultScore
+	}
+	switch {
+	case q.Limit <= 0:
+		q.Limit = duplicateDefaultLimit
+	case q.Limit > duplicateMaxLimit:
+		q.Limit = duplicateMaxLimit
+	}
+	return s.repo.FindDuplicates(q)
+}
+
+// Merge fusiona mergedID en survivorID: los datos de los demás dominios pasan al
+// conservado, su teléfono completa al conservado si este no tiene, se guarda una
+// copia del fusionado y se elimina. Todo ocurre en una sola transacción.
+func (s *service) Merge(survivorID, mergedID, userID int) (*models.PatientMerge, error) {
+	if survivorID <= 0 || mergedID <= 0 {
+		return nil, appErr.Wrap("PatientService.Merge", appErr.ErrInvalidInput, nil)
+	}
+	if survivorID == mergedID {
+		return nil, appErr.NewDomainError(appErr.ErrInvalidInput, "Un paciente no se puede fusionar consigo mismo")
+	}
+
+	var merge *models.PatientMerge
+	err := s.uow.Do(func(tx database.DBTX) error {
+		repo := s.repo.WithTx(tx)
+		if err := repo.LockForUpdate([]int{survivorID, mergedID}); err != nil {
+			return err
+		}
+
+		survivor, err := repo.GetByID(survivorID)
+		if err != nil {
+			return err
+		}
+		merged, err := repo.GetByID(mergedID)
+		if err != nil {
+			return err
+		}
+
+		if s.merger != nil {
+			if err := s.merger.MergePatient(tx, mergedID, survivorID); err != nil {
+				return err
+			}
+		}
+
+		merge = &models.PatientMerge{
+			PacienteID:          &survivorID,
+			FusionadoID:         mergedID,
+			FusionadoNombre:     merged.Nombre,
+			FusionadoNacimiento: &merged.FechaNacimiento,
+			FusionadoTelefono:   merged.Telefono,
+			FusionadoSexo:       &merged.Sexo,
+		}
+		if userID > 0 {
+			merge.UsuarioID = &userID
+		}
+		if err := repo.SaveMerge(merge); err != nil {
+			return err
+		}
+		if err := repo.Delete(mergedID); err != nil {
+			return err
+		}
+
+		// Después de borrar al fusionado, para que su DPI pueda pasar al conservado
+		if update, changed := fillMissing(survivor, merged); changed {
+			return repo.Update(survivorID, update)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merge, nil
+}
+
+// fillMissing completa los datos vacíos del conservado con los del fusionado
+func fillMissing(survivor, merged *models.Patient) (*models.PatientUpdateDTO, bool) {
+	d, m := survivor.PatientDemographics, merged.PatientDemographics
+	changed := false
+	fillString := func(dst **string, src *string) {
+		if *dst == nil && src != nil {
+			*dst, changed = src, true
+		}
+	}
+
+	telefono := survivor.Telefono
+	fillString(&telefono, merged.Telefono)
+	fillString(&d.DPI, m.DPI)
+	fillString(&d.Correo, m.Correo)
+	fillString(&d.Direccion, m.Direccion)
+	fillString(&d.Ocupacion, m.Ocupacion)
+	fillString(&d.Genero, m.Genero)
+	fillString(&d.CanalContacto, m.CanalContacto)
+	if len(d.Telefonos) == 0 && len(m.Telefonos) > 0 {
+		d.Telefonos, changed = m.Telefonos, true
+	}
+	if d.ContactoEmergencia == nil && m.ContactoEmergencia != nil {
+		d.ContactoEmergencia, changed = m.ContactoEmergencia, true
+	}
+	if d.Tutor == nil && m.Tutor != nil {
+		d.Tutor, changed = m.Tutor, true
+	}
+	if d.Seguro == nil && m.Seguro != nil {
+		d.Seguro, changed = m.Seguro, true
+	}
+
+	return &models.PatientUpdateDTO{
+		Nombre:              survivor.Nombre,
+		FechaNacimiento:     survivor.FechaNacimiento.Format("2006-01-02"),
+		Telefono:            telefono,
+		Sexo:                survivor.Sexo,
+		PatientDemographics: d,
+	}, changed
+}
+
+func (s *service) GetMerges(patientID int) ([]models.PatientMerge, error) {
+	if patientID <= 0 {
+		return nil, appErr.Wrap("PatientService.GetMerges", appErr.ErrInvalidInput, nil)
 	}
-	return s.repo.SearchByName(name)
+	return s.repo.GetMerges(patientID)
 }
 
 func (s *service) GetNameByID(patientID int) (string, error) {
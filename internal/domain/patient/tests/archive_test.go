@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/patient/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+func timePtr(v time.Time) *time.Time { return &v }
+
+// -----------------------------------------------------------------------------
+// Archive / Restore
+// -----------------------------------------------------------------------------
+
+func TestService_Archive(t *testing.T) {
+	t.Parallel()
+
+	t.Run("active patient is archived by the user", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(1).Return(&models.Patient{ID: 1}, nil)
+		f.repo.EXPECT().Archive(1, 7).Return(nil)
+
+		require.NoError(t, f.svc.Archive(1, 7))
+	})
+
+	t.Run("already archived", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(1).Return(&models.Patient{ID: 1, ArchivadoEn: timePtr(time.Now())}, nil)
+
+		require.True(t, appErr.IsDomainError(f.svc.Archive(1, 7)))
+	})
+
+	t.Run("archived patients cannot be edited", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(1).Return(&models.Patient{ID: 1, ArchivadoEn: timePtr(time.Now())}, nil)
+
+		err := f.svc.Update(1, &models.PatientUpdateDTO{Nombre: "Ana", FechaNacimiento: "1985-03-12", Sexo: "F"})
+		require.True(t, appErr.IsDomainError(err))
+	})
+
+	t.Run("restore only archived patients", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetByID(1).Return(&models.Patient{ID: 1}, nil)
+
+		require.True(t, appErr.IsDomainError(f.svc.Restore(1)))
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Purge
+// -----------------------------------------------------------------------------
+
+func TestService_Purge(t *testing.T) {
+	t.Parallel()
+
+	longAgo := time.Now().AddDate(-11, 0, 0)
+
+	t.Run("past retention the patient and its data are deleted", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().LockForUpdate([]int{1}).Return(nil)
+		f.repo.EXPECT().GetByID(1).Return(&models.Patient{ID: 1, ArchivadoEn: &longAgo}, nil)
+		f.repo.EXPECT().LastActivity(1).Return(timePtr(longAgo.AddDate(-1, 0, 0)), nil)
+		f.cleaner.EXPECT().CleanupPatient(gomock.Any(), 1).Return(nil)
+		f.repo.EXPECT().Delete(1).Return(nil)
+
+		require.NoError(t, f.svc.Purge(1))
+		require.True(t, f.uow.committed)
+	})
+
+	t.Run("active patients cannot be purged", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().LockForUpdate([]int{1}).Return(nil)
+		f.repo.EXPECT().GetByID(1).Return(&models.Patient{ID: 1}, nil)
+
+		require.True(t, appErr.IsDomainError(f.svc.Purge(1)))
+	})
+
+	t.Run("recently archived", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().LockForUpdate([]int{1}).Return(nil)
+		f.repo.EXPECT().GetByID(1).Return(&models.Patient{ID: 1, ArchivadoEn: timePtr(time.Now().AddDate(-1, 0, 0))}, nil)
+		f.repo.EXPECT().LastActivity(1).Return(nil, nil)
+
+		require.True(t, appErr.IsDomainError(f.svc.Purge(1)))
+		require.True(t, f.uow.rolledBack)
+	})
+
+	t.Run("recent clinical activity extends retention", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().LockForUpdate([]int{1}).Return(nil)
+		f.repo.EXPECT().GetByID(1).Return(&models.Patient{ID: 1, ArchivadoEn: &longAgo}, nil)
+		f.repo.EXPECT().LastActivity(1).Return(timePtr(time.Now().AddDate(-2, 0, 0)), nil)
+
+		require.True(t, appErr.IsDomainError(f.svc.Purge(1)))
+	})
+}
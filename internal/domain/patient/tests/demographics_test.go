@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/patient/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+func adult() models.PatientCreateDTO {
+	return models.PatientCreateDTO{Nombre: "Ana López", FechaNacimiento: "1985-03-12", Sexo: "F"}
+}
+
+// -----------------------------------------------------------------------------
+// Create / Update validation
+// -----------------------------------------------------------------------------
+
+func TestService_CreateDemographics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("full demographics are normalized and saved", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		dto := adult()
+		dto.Sexo = "I"
+		dto.Telefono = strPtr("+502 5555-1234")
+		dto.DPI = strPtr("1234 56789 0101")
+		dto.Correo = strPtr("ana@example.com")
+		dto.Direccion = strPtr("  ")
+		dto.Genero = strPtr("no-binario")
+		dto.CanalContacto = strPtr(models.ChannelWhatsApp)
+		dto.Telefonos = []models.PatientPhone{{Numero: "2222-3333", Tipo: "trabajo"}}
+		dto.ContactoEmergencia = &models.Contact{Nombre: "Luis López", Telefono: "4444-5555", Parentesco: "hermano"}
+		dto.Seguro = &models.Insurance{Aseguradora: "Seguros GT", Poliza: "P-123"}
+
+		f.repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(p *models.PatientCreateDTO) (int, error) {
+			require.Equal(t, "1234567890101", *p.DPI)
+			require.Nil(t, p.Direccion, "blank optional text is stored as null")
+			return 5, nil
+		})
+
+		id, err := f.svc.Create(&dto)
+		require.NoError(t, err)
+		require.Equal(t, 5, id)
+	})
+
+	t.Run("minor needs a guardian", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		dto := adult()
+		dto.FechaNacimiento = time.Now().AddDate(-10, 0, 0).Format("2006-01-02")
+
+		_, err := f.svc.Create(&dto)
+		fields, _ := appErr.ValidationDetails(err)
+		require.Len(t, fields, 1)
+		require.Equal(t, "tutor", fields[0].Campo)
+
+		dto.Tutor = &models.Contact{Nombre: "María López", Telefono: "5555-1234", Parentesco: "madre"}
+		f.repo.EXPECT().Create(gomock.Any()).Return(6, nil)
+
+		_, err = f.svc.Create(&dto)
+		require.NoError(t, err)
+	})
+
+	invalid := []struct {
+		name, field string
+		mutate      func(d *models.PatientCreateDTO)
+	}{
+		{"old sex options only", "sexo", func(d *models.PatientCreateDTO) { d.Sexo = "otro" }},
+		{"malformed birth date", "fecha_nacimiento", func(d *models.PatientCreateDTO) { d.FechaNacimiento = "03/04/1990" }},
+		{"future birth date", "fecha_nacimiento", func(d *models.PatientCreateDTO) { d.FechaNacimiento = time.Now().AddDate(1, 0, 0).Format("2006-01-02") }},
+		{"bad DPI check digit", "dpi", func(d *models.PatientCreateDTO) { d.DPI = strPtr("1234567880101") }},
+		{"bad email", "correo", func(d *models.PatientCreateDTO) { d.Correo = strPtr("ana@") }},
+		{"unknown gender", "genero", func(d *models.PatientCreateDTO) { d.Genero = strPtr("x") }},
+		{"email channel no email", "correo", func(d *models.PatientCreateDTO) { d.CanalContacto = strPtr(models.ChannelEmail) }},
+		{"sms channel no phone", "telefono", func(d *models.PatientCreateDTO) { d.CanalContacto = strPtr(models.ChannelSMS) }},
+		{"short extra phone", "telefonos[0].numero", func(d *models.PatientCreateDTO) {
+			d.Telefonos = []models.PatientPhone{{Numero: "123", Tipo: "casa"}}
+		}},
+		{"emergency without phone", "contacto_emergencia.telefono", func(d *models.PatientCreateDTO) {
+			d.ContactoEmergencia = &models.Contact{Nombre: "Luis"}
+		}},
+		{"insurance without policy", "seguro.poliza", func(d *models.PatientCreateDTO) {
+			d.Seguro = &models.Insurance{Aseguradora: "Seguros GT"}
+		}},
+	}
+	for _, tc := range invalid {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			f := setupFixture(t)
+			defer f.ctrl.Finish()
+
+			dto := adult()
+			tc.mutate(&dto)
+
+			_, err := f.svc.Create(&dto)
+			require.ErrorIs(t, err, appErr.ErrInvalidInput)
+			fields, ok := appErr.ValidationDetails(err)
+			require.True(t, ok, "got %v", err)
+			require.Equal(t, tc.field, fields[0].Campo)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/patient"
+	patientMocks "github.com/tonitomc/healthcare-crm-api/internal/domain/patient/mocks"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/patient/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+type fixture struct {
+	ctrl    *gomock.Controller
+	repo    *patientMocks.MockRepository
+	cleaner *patientMocks.MockDependentCleaner
+	merger  *patientMocks.MockDependentMerger
+	uow     *fakeUnitOfWork
+	svc     patient.Service
+}
+
+func setupFixture(t *testing.T) *fixture {
+	ctrl := gomock.NewController(t)
+	f := &fixture{
+		ctrl:    ctrl,
+		repo:    patientMocks.NewMockRepository(ctrl),
+		cleaner: patientMocks.NewMockDependentCleaner(ctrl),
+		merger:  patientMocks.NewMockDependentMerger(ctrl),
+		uow:     &fakeUnitOfWork{},
+	}
+	f.svc = patient.NewService(f.repo, f.uow, f.cleaner, f.merger, patient.Config{RetentionYears: 10})
+	f.repo.EXPECT().WithTx(gomock.Any()).Return(f.repo).AnyTimes()
+	return f
+}
+
+// fakeUnitOfWork runs fn without a database and records how it ended.
+type fakeUnitOfWork struct {
+	committed  bool
+	rolledBack bool
+}
+
+func (u *fakeUnitOfWork) Do(fn func(tx database.DBTX) error) error {
+	if err := fn(nil); err != nil {
+		u.rolledBack = true
+		return err
+	}
+	u.committed = true
+	return nil
+}
+
+func strPtr(v string) *string { return &v }
+
+var birth = time.Date(1985, 3, 12, 0, 0, 0, 0, time.UTC)
+
+// -----------------------------------------------------------------------------
+// Merge
+// -----------------------------------------------------------------------------
+
+func TestService_Merge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("moves dependents, records the merge and fills missing data", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		gomock.InOrder(
+			f.repo.EXPECT().LockForUpdate([]int{1, 2}).Return(nil),
+			f.repo.EXPECT().GetByID(1).Return(&models.Patient{
+				ID: 1, Nombre: "Ana López", FechaNacimiento: birth, Sexo: "F",
+				PatientDemographics: models.PatientDemographics{Correo: strPtr("ana@example.com")},
+			}, nil),
+			f.repo.EXPECT().GetByID(2).Return(&models.Patient{
+				ID: 2, Nombre: "Ana Lopes", FechaNacimiento: birth, Sexo: "F", Telefono: strPtr("5555-1234"),
+				PatientDemographics: models.PatientDemographics{DPI: strPtr("1234567890101"), Correo: strPtr("otra@example.com")},
+			}, nil),
+			f.merger.EXPECT().MergePatient(gomock.Any(), 2, 1).Return(nil),
+			f.repo.EXPECT().SaveMerge(gomock.Any()).DoAndReturn(func(m *models.PatientMerge) error {
+				require.Equal(t, 1, *m.PacienteID)
+				require.Equal(t, 2, m.FusionadoID)
+				require.Equal(t, "Ana Lopes", m.FusionadoNombre)
+				require.Equal(t, 7, *m.UsuarioID)
+				m.ID = 40
+				return nil
+			}),
+			f.repo.EXPECT().Delete(2).Return(nil),
+			f.repo.EXPECT().Update(1, gomock.Any()).DoAndReturn(func(_ int, dto *models.PatientUpdateDTO) error {
+				require.Equal(t, "Ana López", dto.Nombre)
+				require.Equal(t, "1985-03-12", dto.FechaNacimiento)
+				require.Equal(t, "5555-1234", *dto.Telefono)
+				require.Equal(t, "1234567890101", *dto.DPI)
+				require.Equal(t, "ana@example.com", *dto.Correo, "survivor data is kept")
+				return nil
+			}),
+		)
+
+		merge, err := f.svc.Merge(1, 2, 7)
+		require.NoError(t, err)
+		require.Equal(t, 40, merge.ID)
+		require.True(t, f.uow.committed)
+	})
+
+	t.Run("a failing dependent rolls everything back", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().LockForUpdate(gomock.Any()).Return(nil)
+		f.repo.EXPECT().GetByID(1).Return(&models.Patient{ID: 1, Telefono: strPtr("1")}, nil)
+		f.repo.EXPECT().GetByID(2).Return(&models.Patient{ID: 2}, nil)
+		f.merger.EXPECT().MergePatient(gomock.Any(), 2, 1).Return(errors.New("boom"))
+
+		_, err := f.svc.Merge(1, 2, 7)
+		require.Error(t, err)
+		require.True(t, f.uow.rolledBack)
+	})
+
+	t.Run("missing patient", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().LockForUpdate(gomock.Any()).Return(appErr.Wrap("lock", appErr.ErrNotFound, nil))
+
+		_, err := f.svc.Merge(1, 2, 7)
+		require.ErrorIs(t, err, appErr.ErrNotFound)
+	})
+
+	t.Run("cannot merge a patient into itself", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		_, err := f.svc.Merge(3, 3, 7)
+		require.True(t, appErr.IsDomainError(err))
+	})
+}
+
+// -----------------------------------------------------------------------------
+// FindDuplicates
+// -----------------------------------------------------------------------------
+
+func TestService_FindDuplicates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("applies defaults", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().FindDuplicates(models.DuplicateQuery{MinScore: 0.5, Limit: 50}).Return([]models.DuplicateCandidate{}, nil)
+
+		_, err := f.svc.FindDuplicates(models.DuplicateQuery{})
+		require.NoError(t, err)
+	})
+
+	t.Run("score out of range", func(t *testing.T) {
+		f := setupFixture(t)
+		defer f.ctrl.Finish()
+
+		_, err := f.svc.FindDuplicates(models.DuplicateQuery{MinScore: 1.5})
+		require.ErrorIs(t, err, appErr.ErrInvalidInput)
+	})
+}
@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/patient"
+	patientMocks "github.com/tonitomc/healthcare-crm-api/internal/domain/patient/mocks"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/patient/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+func setup(t *testing.T) (*patientMocks.MockRepository, patient.Service, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	mockRepo := patientMocks.NewMockRepository(ctrl)
+	svc := patient.NewService(mockRepo, nil, nil, nil, patient.Config{})
+	return mockRepo, svc, ctrl
+}
+
+// expectQuery verifica el criterio que el servicio envía al repositorio
+func expectQuery(t *testing.T, repo *patientMocks.MockRepository, check func(q models.PatientSearchQuery)) {
+	repo.EXPECT().Search(gomock.Any()).DoAndReturn(func(q models.PatientSearchQuery) ([]models.PatientSearchResult, error) {
+		check(q)
+		return []models.PatientSearchResult{}, nil
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Search
+// -----------------------------------------------------------------------------
+
+func TestService_Search(t *testing.T) {
+	t.Parallel()
+
+	t.Run("too short for type-ahead", func(t *testing.T) {
+		_, svc, ctrl := setup(t)
+		defer ctrl.Finish()
+
+		_, err := svc.Search(" a ", 0, false)
+		require.ErrorIs(t, err, appErr.ErrInvalidInput)
+	})
+
+	t.Run("name tokens with default limit", func(t *testing.T) {
+		repo, svc, ctrl := setup(t)
+		defer ctrl.Finish()
+
+		expectQuery(t, repo, func(q models.PatientSearchQuery) {
+			require.Equal(t, []string{"María", "José", "Lopez"}, q.Terminos)
+			require.Empty(t, q.Telefono)
+			require.Equal(t, 10, q.Limit)
+		})
+
+		_, err := svc.Search("María José-Lopez", 0, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("phone with dashes and spaces", func(t *testing.T) {
+		repo, svc, ctrl := setup(t)
+		defer ctrl.Finish()
+
+		expectQuery(t, repo, func(q models.PatientSearchQuery) {
+			require.Equal(t, "50255551234", q.Telefono)
+			require.Empty(t, q.Terminos)
+			require.Equal(t, 50, q.Limit)
+		})
+
+		_, err := svc.Search("(502) 5555-1234", 500, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("exact birth date with name", func(t *testing.T) {
+		repo, svc, ctrl := setup(t)
+		defer ctrl.Finish()
+
+		expectQuery(t, repo, func(q models.PatientSearchQuery) {
+			require.Equal(t, []string{"ana"}, q.Terminos)
+			require.NotNil(t, q.FechaNacimiento)
+			require.Equal(t, time.Date(1985, 3, 12, 0, 0, 0, 0, time.UTC), *q.FechaNacimiento)
+		})
+
+		_, err := svc.Search("ana 12/03/1985", 0, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("partial birth date", func(t *testing.T) {
+		repo, svc, ctrl := setup(t)
+		defer ctrl.Finish()
+
+		expectQuery(t, repo, func(q models.PatientSearchQuery) {
+			require.Equal(t, 12, q.Dia)
+			require.Equal(t, 3, q.Mes)
+			require.Equal(t, 1985, q.Anio)
+			require.Equal(t, "1985", q.Telefono, "a lone year is also tried as a phone fragment")
+		})
+
+		_, err := svc.Search("12/3 1985", 0, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("year next to a phone is not merged into it", func(t *testing.T) {
+		repo, svc, ctrl := setup(t)
+		defer ctrl.Finish()
+
+		expectQuery(t, repo, func(q models.PatientSearchQuery) {
+			require.Equal(t, 1990, q.Anio)
+			require.Equal(t, "5555", q.Telefono)
+		})
+
+		_, err := svc.Search("1990 5555", 0, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("nothing searchable", func(t *testing.T) {
+		_, svc, ctrl := setup(t)
+		defer ctrl.Finish()
+
+		_, err := svc.Search("-- 12", 0, false)
+		require.ErrorIs(t, err, appErr.ErrInvalidInput)
+	})
+}
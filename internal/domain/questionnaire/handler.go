@@ -22,7 +22,7 @@ func NewHandler(s Service) *Handler {
 // ===================== ROUTES =====================
 
 func (h *Handler) RegisterRoutes(g *echo.Group) {
-	q := g.Group("/questionnaires", ErrorMiddleware())
+	q := g.Group("/questionnaires", ErrorMiddleware(), middleware.Audit("cuestionario", "id", h.auditedQuestionnaire))
 
 	q.GET("", h.GetAll, middleware.RequirePermission("ver-cuestionarios"))
 	q.GET("/:id", h.GetByID, middleware.RequirePermission("ver-cuestionarios"))
@@ -37,7 +37,12 @@ func (h *Handler) RegisterRoutes(g *echo.Group) {
 	q.PUT("/:id/deactivate", h.SetInactive, middleware.RequirePermission("manejar-cuestionarios"))
 
 	// optional: validate answers externally (for testing)
-	q.POST("/:id/validate", h.ValidateAnswers, middleware.RequirePermission("ver-cuestionarios"))
+	q.POST("/:id/validate", h.ValidateAnswers, middleware.RequirePermission("ver-cuestionarios"), middleware.NoAudit())
+}
+
+// auditedQuestionnaire is the state middleware.Audit records before and after a change
+func (h *Handler) auditedQuestionnaire(id int) (interface{}, error) {
+	return h.service.GetByID(id)
 }
 
 // ===================== HANDLERS =====================
@@ -80,7 +85,7 @@ func (h *Handler) GetActiveByName(c echo.Context) error {
 		return err
 	}
 	if q == nil {
-		return c.JSON(http.StatusNotFound, echo.Map{"message": "No se encontró un cuestionario activo con ese nombre"})
+		return appErr.NewDomainError(appErr.ErrNotFound, "No se encontró un cuestionario activo con ese nombre")
 	}
 	return c.JSON(http.StatusOK, q)
 }
@@ -1,3 +1,5 @@
+//go:generate mockgen -source=service.go -destination=./mocks/service.go -package=mocks
+
 // internal/domain/rbac/service.go
 package rbac
 
@@ -20,7 +20,7 @@ func NewHandler(s Service) *Handler {
 }
 
 func (h *Handler) RegisterRoutes(g *echo.Group) {
-	r := g.Group("/reminders", ErrorMiddleware(), middleware.RequireAuth())
+	r := g.Group("/reminders", ErrorMiddleware(), middleware.RequireAuth(), middleware.Audit("recordatorio", "id", nil))
 
 	r.GET("", h.GetMyReminders)
 	r.POST("", h.CreateReminder)
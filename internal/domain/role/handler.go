@@ -22,7 +22,7 @@ func NewHandler(s Service) *Handler {
 
 // RegisterRoutes mounts /role routes under the provided Echo group.
 func (h *Handler) RegisterRoutes(g *echo.Group) {
-	roleGroup := g.Group("/role", ErrorMiddleware())
+	roleGroup := g.Group("/role", ErrorMiddleware(), middleware.Audit("rol", "id", h.auditedRole))
 
 	roleGroup.GET("/all/permissions", h.GetAllPermissions, middleware.RequirePermission("manejar-roles"))
 
@@ -40,6 +40,16 @@ func (h *Handler) RegisterRoutes(g *echo.Group) {
 	roleGroup.PUT("/:id/permissions", h.UpdateRolePermissions, middleware.RequirePermission("manejar-roles"))
 }
 
+// auditedRole is the state middleware.Audit records before and after a change:
+// the role with its permissions.
+func (h *Handler) auditedRole(id int) (interface{}, error) {
+	role, perms, err := h.service.GetRoleByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return echo.Map{"rol": role, "permisos": perms}, nil
+}
+
 // -----------------------------------------------------------------------------
 // Role CRUD
 // -----------------------------------------------------------------------------
@@ -11,6 +11,41 @@ import (
 	models "github.com/tonitomc/healthcare-crm-api/internal/domain/role/models"
 )
 
+// MockAccessListener is a mock of AccessListener interface.
+type MockAccessListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessListenerMockRecorder
+}
+
+// MockAccessListenerMockRecorder is the mock recorder for MockAccessListener.
+type MockAccessListenerMockRecorder struct {
+	mock *MockAccessListener
+}
+
+// NewMockAccessListener creates a new mock instance.
+func NewMockAccessListener(ctrl *gomock.Controller) *MockAccessListener {
+	mock := &MockAccessListener{ctrl: ctrl}
+	mock.recorder = &MockAccessListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessListener) EXPECT() *MockAccessListenerMockRecorder {
+	return m.recorder
+}
+
+// RoleAccessChanged mocks base method.
+func (m *MockAccessListener) RoleAccessChanged(roleID int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RoleAccessChanged", roleID)
+}
+
+// RoleAccessChanged indicates an expected call of RoleAccessChanged.
+func (mr *MockAccessListenerMockRecorder) RoleAccessChanged(roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoleAccessChanged", reflect.TypeOf((*MockAccessListener)(nil).RoleAccessChanged), roleID)
+}
+
 // MockService is a mock of Service interface.
 type MockService struct {
 	ctrl     *gomock.Controller
@@ -4,4 +4,5 @@ type Role struct {
 	ID          int    `json:"id"`
 	Name        string `json:"nombre"`
 	Description string `json:"descripcion"`
+	RequiresMFA bool   `json:"requiere_mfa"` // its users must use two-step verification
 }
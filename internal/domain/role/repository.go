@@ -43,7 +43,7 @@ func NewRepository(db *sql.DB) Repository {
 
 // GetAll retrieves all roles from the database.
 func (r *repository) GetAll() ([]models.Role, error) {
-	rows, err := r.db.Query(`SELECT id, nombre, descripcion FROM roles ORDER BY id`)
+	rows, err := r.db.Query(`SELECT id, nombre, descripcion, requiere_mfa FROM roles ORDER BY id`)
 	if err != nil {
 		return nil, database.MapSQLError(err, "RoleRepository.GetAll")
 	}
@@ -52,7 +52,7 @@ func (r *repository) GetAll() ([]models.Role, error) {
 	var roles []models.Role
 	for rows.Next() {
 		var role models.Role
-		if err := rows.Scan(&role.ID, &role.Name, &role.Description); err != nil {
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.RequiresMFA); err != nil {
 			return nil, appErr.Wrap("RoleRepository.GetAll(scan)", appErr.ErrInternal, err)
 		}
 		roles = append(roles, role)
@@ -69,9 +69,9 @@ func (r *repository) GetAll() ([]models.Role, error) {
 func (r *repository) GetByID(id int) (*models.Role, error) {
 	var role models.Role
 	err := r.db.QueryRow(
-		`SELECT id, nombre, descripcion FROM roles WHERE id = $1`,
+		`SELECT id, nombre, descripcion, requiere_mfa FROM roles WHERE id = $1`,
 		id,
-	).Scan(&role.ID, &role.Name, &role.Description)
+	).Scan(&role.ID, &role.Name, &role.Description, &role.RequiresMFA)
 	if err != nil {
 		return nil, database.MapSQLError(err, "RoleRepository.GetByID")
 	}
@@ -84,8 +84,8 @@ func (r *repository) Create(role *models.Role) error {
 		return appErr.Wrap("RoleRepository.Create", appErr.ErrInvalidInput, nil)
 	}
 	_, err := r.db.Exec(
-		`INSERT INTO roles (nombre, descripcion) VALUES ($1, $2)`,
-		role.Name, role.Description,
+		`INSERT INTO roles (nombre, descripcion, requiere_mfa) VALUES ($1, $2, $3)`,
+		role.Name, role.Description, role.RequiresMFA,
 	)
 	if err != nil {
 		return database.MapSQLError(err, "RoleRepository.Create")
@@ -100,8 +100,8 @@ func (r *repository) Update(role *models.Role) error {
 	}
 
 	res, err := r.db.Exec(
-		`UPDATE roles SET nombre = $1, descripcion = $2 WHERE id = $3`,
-		role.Name, role.Description, role.ID,
+		`UPDATE roles SET nombre = $1, descripcion = $2, requiere_mfa = $3 WHERE id = $4`,
+		role.Name, role.Description, role.RequiresMFA, role.ID,
 	)
 	if err != nil {
 		return database.MapSQLError(err, "RoleRepository.Update")
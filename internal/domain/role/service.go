@@ -7,6 +7,12 @@ import (
 	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
 )
 
+// AccessListener is told when the permissions of a role change, e.g. to drop the
+// cached permissions of its users.
+type AccessListener interface {
+	RoleAccessChanged(roleID int)
+}
+
 type Service interface {
 	GetAllRoles() ([]models.Role, error)
 	GetRoleByID(id int) (*models.Role, []models.Permission, error)
@@ -22,11 +28,19 @@ type Service interface {
 }
 
 type service struct {
-	repo Repository
+	repo     Repository
+	listener AccessListener
+}
+
+// NewService constructs a new Role Service. listener may be nil.
+func NewService(repo Repository, listener AccessListener) Service {
+	return &service{repo: repo, listener: listener}
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func (s *service) accessChanged(roleID int) {
+	if s.listener != nil {
+		s.listener.RoleAccessChanged(roleID)
+	}
 }
 
 // -----------------------------------------------------------------------------
@@ -85,6 +99,7 @@ func (s *service) DeleteRole(id int) error {
 	if err := s.repo.Delete(id); err != nil {
 		return err
 	}
+	s.accessChanged(id)
 	return nil
 }
 
@@ -110,6 +125,7 @@ func (s *service) AddPermission(roleID, permissionID int) error {
 	if err := s.repo.AddPermission(roleID, permissionID); err != nil {
 		return err
 	}
+	s.accessChanged(roleID)
 	return nil
 }
 
@@ -120,6 +136,7 @@ func (s *service) RemovePermission(roleID, permissionID int) error {
 	if err := s.repo.RemovePermission(roleID, permissionID); err != nil {
 		return err
 	}
+	s.accessChanged(roleID)
 	return nil
 }
 
@@ -128,6 +145,9 @@ func (s *service) UpdateRolePermissions(roleID int, permissionIDs []int) error {
 		return appErr.Wrap("roleService.UpdateRolePermissions", appErr.ErrInvalidInput, nil)
 	}
 
+	// Users of the role may have lost permissions even if adding them back fails
+	defer s.accessChanged(roleID)
+
 	// Clear existing ones first
 	if err := s.repo.ClearPermissions(roleID); err != nil {
 		return err
@@ -17,7 +17,7 @@ import (
 func setup(t *testing.T) (*mocks.MockRepository, role.Service, *gomock.Controller) {
 	ctrl := gomock.NewController(t)
 	mockRepo := mocks.NewMockRepository(ctrl)
-	svc := role.NewService(mockRepo)
+	svc := role.NewService(mockRepo, nil)
 	return mockRepo, svc, ctrl
 }
 
@@ -160,4 +160,19 @@ func TestService_UpdateRolePermissions(t *testing.T) {
 		err := svc.UpdateRolePermissions(1, []int{1, 2})
 		require.NoError(t, err)
 	})
+
+	t.Run("notifies the access listener even if adding back fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRepo := mocks.NewMockRepository(ctrl)
+		listener := mocks.NewMockAccessListener(ctrl)
+		svc := role.NewService(mockRepo, listener)
+
+		mockRepo.EXPECT().ClearPermissions(1).Return(nil)
+		mockRepo.EXPECT().AddPermission(1, 1).Return(errors.New("fk violation"))
+		listener.EXPECT().RoleAccessChanged(1)
+
+		err := svc.UpdateRolePermissions(1, []int{1, 2})
+		require.True(t, errors.Is(err, appErr.ErrConflict))
+	})
 }
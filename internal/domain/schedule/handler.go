@@ -2,6 +2,7 @@ package schedule
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -22,7 +23,7 @@ func NewHandler(s Service) *Handler {
 
 // RegisterRoutes mounts /schedule routes under the provided Echo group.
 func (h *Handler) RegisterRoutes(g *echo.Group) {
-	scheduleGroup := g.Group("/schedule", ErrorMiddleware())
+	scheduleGroup := g.Group("/schedule", ErrorMiddleware(), middleware.Audit("horario", "", nil))
 
 	// Read operations
 	scheduleGroup.GET("/working-hours", h.GetWorkingHours, middleware.RequirePermission("ver-horarios"))
@@ -36,16 +37,20 @@ func (h *Handler) RegisterRoutes(g *echo.Group) {
 	scheduleGroup.DELETE("/special-hours/:date", h.DeleteSpecialDay, middleware.RequirePermission("editar-horarios"))
 }
 
-// GET /schedule/working-hours
+// GET /schedule/working-hours?doctor_id=N
 func (h *Handler) GetWorkingHours(c echo.Context) error {
-	data, err := h.service.GetWorkingHours()
+	doctorID, err := doctorParam(c)
+	if err != nil {
+		return err
+	}
+	data, err := h.service.GetWorkingHours(doctorID)
 	if err != nil {
 		return err
 	}
 	return c.JSON(http.StatusOK, data)
 }
 
-// GET /schedule/special-hours?start=YYYY-MM-DD&end=YYYY-MM-DD
+// GET /schedule/special-hours?start=YYYY-MM-DD&end=YYYY-MM-DD&doctor_id=N
 func (h *Handler) GetSpecialHoursBetween(c echo.Context) error {
 	startStr := c.QueryParam("start")
 	endStr := c.QueryParam("end")
@@ -59,14 +64,19 @@ func (h *Handler) GetSpecialHoursBetween(c echo.Context) error {
 		return appErr.Wrap("Schedule.GetSpecialHoursBetween.ParseEnd", appErr.ErrInvalidInput, err)
 	}
 
-	data, err := h.service.GetSpecialHoursBetween(start, end)
+	doctorID, err := doctorParam(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.service.GetSpecialHoursBetween(start, end, doctorID)
 	if err != nil {
 		return err
 	}
 	return c.JSON(http.StatusOK, data)
 }
 
-// GET /schedule/effective/day/:date
+// GET /schedule/effective/day/:date?doctor_id=N
 func (h *Handler) GetEffectiveDay(c echo.Context) error {
 	dateStr := c.Param("date")
 	date, err := time.Parse("2006-01-02", dateStr)
@@ -74,14 +84,19 @@ func (h *Handler) GetEffectiveDay(c echo.Context) error {
 		return appErr.Wrap("Schedule.GetEffectiveDay.Parse", appErr.ErrInvalidInput, err)
 	}
 
-	eff, err := h.service.GetEffectiveDay(date)
+	doctorID, err := doctorParam(c)
+	if err != nil {
+		return err
+	}
+
+	eff, err := h.service.GetEffectiveDay(date, doctorID)
 	if err != nil {
 		return err
 	}
 	return c.JSON(http.StatusOK, eff)
 }
 
-// GET /schedule/effective/range?start=YYYY-MM-DD&end=YYYY-MM-DD
+// GET /schedule/effective/range?start=YYYY-MM-DD&end=YYYY-MM-DD&doctor_id=N
 func (h *Handler) GetEffectiveRange(c echo.Context) error {
 	startStr := c.QueryParam("start")
 	endStr := c.QueryParam("end")
@@ -95,7 +110,12 @@ func (h *Handler) GetEffectiveRange(c echo.Context) error {
 		return appErr.Wrap("Schedule.GetEffectiveRange.ParseEnd", appErr.ErrInvalidInput, err)
 	}
 
-	data, err := h.service.GetEffectiveRange(start, end)
+	doctorID, err := doctorParam(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.service.GetEffectiveRange(start, end, doctorID)
 	if err != nil {
 		return err
 	}
@@ -114,6 +134,7 @@ func (h *Handler) UpdateWorkDay(c echo.Context) error {
 	workDay := models.WorkDay{
 		DayOfWeek: req.DayOfWeek,
 		Active:    active,
+		DoctorID:  req.DoctorID,
 	}
 
 	for _, r := range req.Ranges {
@@ -145,7 +166,7 @@ func (h *Handler) AddSpecialDay(c echo.Context) error {
 
 	active := len(req.Ranges) > 0
 
-	day := models.SpecialDay{Date: date, Active: active}
+	day := models.SpecialDay{Date: date, Active: active, DoctorID: req.DoctorID}
 	for _, r := range req.Ranges {
 		start, err1 := time.Parse("15:04", r.Start)
 		end, err2 := time.Parse("15:04", r.End)
@@ -162,7 +183,7 @@ func (h *Handler) AddSpecialDay(c echo.Context) error {
 	return c.JSON(http.StatusCreated, echo.Map{"message": "Horario especial agregado correctamente"})
 }
 
-// DELETE /schedule/special-hours/:date
+// DELETE /schedule/special-hours/:date?doctor_id=N
 func (h *Handler) DeleteSpecialDay(c echo.Context) error {
 	dateStr := c.Param("date")
 	if dateStr == "" {
@@ -174,7 +195,12 @@ func (h *Handler) DeleteSpecialDay(c echo.Context) error {
 		return appErr.Wrap("Schedule.DeleteSpecialDay.ParseDate", appErr.ErrInvalidInput, err)
 	}
 
-	if err := h.service.DeleteSpecialDay(date); err != nil {
+	doctorID, err := doctorParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteSpecialDay(date, doctorID); err != nil {
 		return err
 	}
 
@@ -182,3 +208,16 @@ func (h *Handler) DeleteSpecialDay(c echo.Context) error {
 		"message": "Horario especial eliminado correctamente",
 	})
 }
+
+// doctorParam reads the optional ?doctor_id= query param (omitted = clinic-wide schedule).
+func doctorParam(c echo.Context) (*int, error) {
+	raw := c.QueryParam("doctor_id")
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		return nil, appErr.Wrap("Schedule.doctorParam", appErr.ErrInvalidInput, err)
+	}
+	return &id, nil
+}
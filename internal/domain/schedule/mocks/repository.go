@@ -36,77 +36,92 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // DeleteSpecialHour mocks base method.
-func (m *MockRepository) DeleteSpecialHour(date time.Time) error {
+func (m *MockRepository) DeleteSpecialHour(date time.Time, doctorID *int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSpecialHour", date)
+	ret := m.ctrl.Call(m, "DeleteSpecialHour", date, doctorID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSpecialHour indicates an expected call of DeleteSpecialHour.
-func (mr *MockRepositoryMockRecorder) DeleteSpecialHour(date interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) DeleteSpecialHour(date, doctorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSpecialHour", reflect.TypeOf((*MockRepository)(nil).DeleteSpecialHour), date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSpecialHour", reflect.TypeOf((*MockRepository)(nil).DeleteSpecialHour), date, doctorID)
 }
 
 // GetAllSpecialHours mocks base method.
-func (m *MockRepository) GetAllSpecialHours() ([]models.SpecialDay, error) {
+func (m *MockRepository) GetAllSpecialHours(doctorID *int) ([]models.SpecialDay, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllSpecialHours")
+	ret := m.ctrl.Call(m, "GetAllSpecialHours", doctorID)
 	ret0, _ := ret[0].([]models.SpecialDay)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllSpecialHours indicates an expected call of GetAllSpecialHours.
-func (mr *MockRepositoryMockRecorder) GetAllSpecialHours() *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetAllSpecialHours(doctorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSpecialHours", reflect.TypeOf((*MockRepository)(nil).GetAllSpecialHours))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSpecialHours", reflect.TypeOf((*MockRepository)(nil).GetAllSpecialHours), doctorID)
 }
 
 // GetAllWorkingHours mocks base method.
-func (m *MockRepository) GetAllWorkingHours() ([]models.WorkDay, error) {
+func (m *MockRepository) GetAllWorkingHours(doctorID *int) ([]models.WorkDay, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllWorkingHours")
+	ret := m.ctrl.Call(m, "GetAllWorkingHours", doctorID)
 	ret0, _ := ret[0].([]models.WorkDay)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllWorkingHours indicates an expected call of GetAllWorkingHours.
-func (mr *MockRepositoryMockRecorder) GetAllWorkingHours() *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetAllWorkingHours(doctorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWorkingHours", reflect.TypeOf((*MockRepository)(nil).GetAllWorkingHours))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWorkingHours", reflect.TypeOf((*MockRepository)(nil).GetAllWorkingHours), doctorID)
 }
 
 // GetSpecialHoursBetween mocks base method.
-func (m *MockRepository) GetSpecialHoursBetween(start, end time.Time) ([]models.SpecialDay, error) {
+func (m *MockRepository) GetSpecialHoursBetween(start, end time.Time, doctorID *int) ([]models.SpecialDay, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSpecialHoursBetween", start, end)
+	ret := m.ctrl.Call(m, "GetSpecialHoursBetween", start, end, doctorID)
 	ret0, _ := ret[0].([]models.SpecialDay)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSpecialHoursBetween indicates an expected call of GetSpecialHoursBetween.
-func (mr *MockRepositoryMockRecorder) GetSpecialHoursBetween(start, end interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetSpecialHoursBetween(start, end, doctorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpecialHoursBetween", reflect.TypeOf((*MockRepository)(nil).GetSpecialHoursBetween), start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpecialHoursBetween", reflect.TypeOf((*MockRepository)(nil).GetSpecialHoursBetween), start, end, doctorID)
 }
 
 // GetSpecialHoursByDate mocks base method.
-func (m *MockRepository) GetSpecialHoursByDate(date time.Time) ([]models.SpecialDay, error) {
+func (m *MockRepository) GetSpecialHoursByDate(date time.Time, doctorID *int) ([]models.SpecialDay, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSpecialHoursByDate", date)
+	ret := m.ctrl.Call(m, "GetSpecialHoursByDate", date, doctorID)
 	ret0, _ := ret[0].([]models.SpecialDay)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSpecialHoursByDate indicates an expected call of GetSpecialHoursByDate.
-func (mr *MockRepositoryMockRecorder) GetSpecialHoursByDate(date interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetSpecialHoursByDate(date, doctorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpecialHoursByDate", reflect.TypeOf((*MockRepository)(nil).GetSpecialHoursByDate), date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpecialHoursByDate", reflect.TypeOf((*MockRepository)(nil).GetSpecialHoursByDate), date, doctorID)
+}
+
+// GetWorkingHoursForDate mocks base method.
+func (m *MockRepository) GetWorkingHoursForDate(date time.Time, doctorID *int) ([]models.WorkDay, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkingHoursForDate", date, doctorID)
+	ret0, _ := ret[0].([]models.WorkDay)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkingHoursForDate indicates an expected call of GetWorkingHoursForDate.
+func (mr *MockRepositoryMockRecorder) GetWorkingHoursForDate(date, doctorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkingHoursForDate", reflect.TypeOf((*MockRepository)(nil).GetWorkingHoursForDate), date, doctorID)
 }
 
 // UpdateSpecialHour mocks base method.
@@ -50,92 +50,92 @@ func (mr *MockServiceMockRecorder) AddSpecialDay(day interface{}) *gomock.Call {
 }
 
 // DeleteSpecialDay mocks base method.
-func (m *MockService) DeleteSpecialDay(date time.Time) error {
+func (m *MockService) DeleteSpecialDay(date time.Time, doctorID *int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSpecialDay", date)
+	ret := m.ctrl.Call(m, "DeleteSpecialDay", date, doctorID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSpecialDay indicates an expected call of DeleteSpecialDay.
-func (mr *MockServiceMockRecorder) DeleteSpecialDay(date interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) DeleteSpecialDay(date, doctorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSpecialDay", reflect.TypeOf((*MockService)(nil).DeleteSpecialDay), date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSpecialDay", reflect.TypeOf((*MockService)(nil).DeleteSpecialDay), date, doctorID)
 }
 
 // GetEffectiveDay mocks base method.
-func (m *MockService) GetEffectiveDay(date time.Time) (*models.EffectiveDay, error) {
+func (m *MockService) GetEffectiveDay(date time.Time, doctorID *int) (*models.EffectiveDay, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEffectiveDay", date)
+	ret := m.ctrl.Call(m, "GetEffectiveDay", date, doctorID)
 	ret0, _ := ret[0].(*models.EffectiveDay)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEffectiveDay indicates an expected call of GetEffectiveDay.
-func (mr *MockServiceMockRecorder) GetEffectiveDay(date interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetEffectiveDay(date, doctorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEffectiveDay", reflect.TypeOf((*MockService)(nil).GetEffectiveDay), date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEffectiveDay", reflect.TypeOf((*MockService)(nil).GetEffectiveDay), date, doctorID)
 }
 
 // GetEffectiveRange mocks base method.
-func (m *MockService) GetEffectiveRange(start, end time.Time) ([]models.EffectiveDay, error) {
+func (m *MockService) GetEffectiveRange(start, end time.Time, doctorID *int) ([]models.EffectiveDay, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEffectiveRange", start, end)
+	ret := m.ctrl.Call(m, "GetEffectiveRange", start, end, doctorID)
 	ret0, _ := ret[0].([]models.EffectiveDay)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEffectiveRange indicates an expected call of GetEffectiveRange.
-func (mr *MockServiceMockRecorder) GetEffectiveRange(start, end interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetEffectiveRange(start, end, doctorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEffectiveRange", reflect.TypeOf((*MockService)(nil).GetEffectiveRange), start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEffectiveRange", reflect.TypeOf((*MockService)(nil).GetEffectiveRange), start, end, doctorID)
 }
 
 // GetSpecialHoursBetween mocks base method.
-func (m *MockService) GetSpecialHoursBetween(start, end time.Time) ([]models.SpecialDay, error) {
+func (m *MockService) GetSpecialHoursBetween(start, end time.Time, doctorID *int) ([]models.SpecialDay, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSpecialHoursBetween", start, end)
+	ret := m.ctrl.Call(m, "GetSpecialHoursBetween", start, end, doctorID)
 	ret0, _ := ret[0].([]models.SpecialDay)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSpecialHoursBetween indicates an expected call of GetSpecialHoursBetween.
-func (mr *MockServiceMockRecorder) GetSpecialHoursBetween(start, end interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetSpecialHoursBetween(start, end, doctorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpecialHoursBetween", reflect.TypeOf((*MockService)(nil).GetSpecialHoursBetween), start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpecialHoursBetween", reflect.TypeOf((*MockService)(nil).GetSpecialHoursBetween), start, end, doctorID)
 }
 
 // GetWorkingHours mocks base method.
-func (m *MockService) GetWorkingHours() ([]models.WorkDay, error) {
+func (m *MockService) GetWorkingHours(doctorID *int) ([]models.WorkDay, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWorkingHours")
+	ret := m.ctrl.Call(m, "GetWorkingHours", doctorID)
 	ret0, _ := ret[0].([]models.WorkDay)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetWorkingHours indicates an expected call of GetWorkingHours.
-func (mr *MockServiceMockRecorder) GetWorkingHours() *gomock.Call {
+func (mr *MockServiceMockRecorder) GetWorkingHours(doctorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkingHours", reflect.TypeOf((*MockService)(nil).GetWorkingHours))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkingHours", reflect.TypeOf((*MockService)(nil).GetWorkingHours), doctorID)
 }
 
 // IsTimeRangeWithinWorkingHours mocks base method.
-func (m *MockService) IsTimeRangeWithinWorkingHours(date, start, end time.Time) (bool, error) {
+func (m *MockService) IsTimeRangeWithinWorkingHours(date, start, end time.Time, doctorID *int) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsTimeRangeWithinWorkingHours", date, start, end)
+	ret := m.ctrl.Call(m, "IsTimeRangeWithinWorkingHours", date, start, end, doctorID)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // IsTimeRangeWithinWorkingHours indicates an expected call of IsTimeRangeWithinWorkingHours.
-func (mr *MockServiceMockRecorder) IsTimeRangeWithinWorkingHours(date, start, end interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) IsTimeRangeWithinWorkingHours(date, start, end, doctorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTimeRangeWithinWorkingHours", reflect.TypeOf((*MockService)(nil).IsTimeRangeWithinWorkingHours), date, start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTimeRangeWithinWorkingHours", reflect.TypeOf((*MockService)(nil).IsTimeRangeWithinWorkingHours), date, start, end, doctorID)
 }
 
 // UpdateWorkDay mocks base method.
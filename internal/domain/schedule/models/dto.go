@@ -3,17 +3,19 @@ package models
 // CreateWorkDayRequest represents POST body for creating new working hours.
 type CreateWorkDayRequest struct {
 	DayOfWeek int           `json:"day_of_week" validate:"required,min=1,max=7"`
-	Ranges    []TimeRangeIn `json:"ranges" validate:"required,dive"`
+	Ranges    []TimeRangeIn `json:"ranges" validate:"dive"` // vacío = día cerrado
+	DoctorID  *int          `json:"doctor_id,omitempty"`    // omitido = horario general de la clínica
 }
 
 // TimeRangeIn uses string-based time input for JSON convenience.
 type TimeRangeIn struct {
-	Start string `json:"start"` // e.g. "09:00"
-	End   string `json:"end"`   // e.g. "17:00"
+	Start string `json:"start" validate:"required,datetime=15:04"` // e.g. "09:00"
+	End   string `json:"end" validate:"required,datetime=15:04"`   // e.g. "17:00"
 }
 
 // CreateSpecialDayRequest represents POST body for creating a special schedule override.
 type CreateSpecialDayRequest struct {
-	Date   string        `json:"date"` // YYYY-MM-DD
-	Ranges []TimeRangeIn `json:"ranges"`
+	Date     string        `json:"date" validate:"required,datetime=2006-01-02"`
+	Ranges   []TimeRangeIn `json:"ranges" validate:"dive"`
+	DoctorID *int          `json:"doctor_id,omitempty"` // omitido = aplica a toda la clínica
 }
@@ -9,4 +9,5 @@ type EffectiveDay struct {
 	Ranges     []TimeRange `json:"ranges"`
 	IsOverride bool        `json:"is_override"` // true if came from SpecialDay
 	Active     bool        `json:"active"`      // false if closed
+	DoctorID   *int        `json:"doctor_id,omitempty"`
 }
@@ -9,4 +9,6 @@ type SpecialDay struct {
 	Date   time.Time   `json:"date"` // YYYY-MM-DD
 	Ranges []TimeRange `json:"ranges"`
 	Active bool        `json:"active"`
+	// nil = aplica a toda la clínica (p. ej. feriados)
+	DoctorID *int `json:"doctor_id,omitempty"`
 }
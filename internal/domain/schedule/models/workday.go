@@ -7,4 +7,5 @@ type WorkDay struct {
 	DayOfWeek int         `json:"day_of_week"`
 	Ranges    []TimeRange `json:"ranges"`
 	Active    bool        `json:"active"`
+	DoctorID  *int        `json:"doctor_id,omitempty"` // nil = horario general de la clínica
 }
@@ -13,18 +13,20 @@ import (
 )
 
 // Repository defines the data access contract for working hours and special days.
+// Every query is scoped by doctorID: nil targets the clinic-wide schedule,
+// a value targets that provider's own rows only (no fallback at this layer).
 type Repository interface {
 	// Reads
-	GetAllWorkingHours() ([]models.WorkDay, error)
-	GetAllSpecialHours() ([]models.SpecialDay, error)
-	GetSpecialHoursBetween(start, end time.Time) ([]models.SpecialDay, error)
-	GetSpecialHoursByDate(date time.Time) ([]models.SpecialDay, error)
-	GetWorkingHoursForDate(date time.Time) ([]models.WorkDay, error)
+	GetAllWorkingHours(doctorID *int) ([]models.WorkDay, error)
+	GetAllSpecialHours(doctorID *int) ([]models.SpecialDay, error)
+	GetSpecialHoursBetween(start, end time.Time, doctorID *int) ([]models.SpecialDay, error)
+	GetSpecialHoursByDate(date time.Time, doctorID *int) ([]models.SpecialDay, error)
+	GetWorkingHoursForDate(date time.Time, doctorID *int) ([]models.WorkDay, error)
 
 	// Writes
 	UpdateWorkingHour(day models.WorkDay) error
 	UpdateSpecialHour(day models.SpecialDay) error
-	DeleteSpecialHour(date time.Time) error
+	DeleteSpecialHour(date time.Time, doctorID *int) error
 }
 
 // -----------------------------------------------------------------------------
@@ -44,14 +46,15 @@ func NewRepository(db *sql.DB) Repository {
 // Working Hours
 // -----------------------------------------------------------------------------
 
-func (r *repository) GetAllWorkingHours() ([]models.WorkDay, error) {
+func (r *repository) GetAllWorkingHours(doctorID *int) ([]models.WorkDay, error) {
 	rows, err := r.db.Query(`
 		SELECT id, dia_semana, hora_apertura, hora_cierre, abierto
 		FROM horarios_laborales
     WHERE valid_from <= NOW()
       AND valid_to   >  NOW()
+      AND doctor_id IS NOT DISTINCT FROM $1
 		ORDER BY dia_semana, hora_apertura;
-	`)
+	`, doctorID)
 	if err != nil {
 		return nil, dbErr.MapSQLError(err, "ScheduleRepo.GetAllWorkingHours")
 	}
@@ -74,6 +77,7 @@ func (r *repository) GetAllWorkingHours() ([]models.WorkDay, error) {
 			ID:        id,
 			DayOfWeek: dayOfWeek,
 			Active:    active,
+			DoctorID:  doctorID,
 		}
 
 		if active && openStr.Valid && closeStr.Valid {
@@ -117,9 +121,10 @@ func (r *repository) UpdateWorkingHour(day models.WorkDay) error {
 		UPDATE horarios_laborales
 		SET valid_to = $1
 		WHERE dia_semana = $2
+		  AND doctor_id IS NOT DISTINCT FROM $3
 		  AND valid_from <= $1
 		  AND valid_to   >  $1;
-	`, now, day.DayOfWeek); err != nil {
+	`, now, day.DayOfWeek, day.DoctorID); err != nil {
 		return dbErr.MapSQLError(err, "ScheduleRepo.UpdateWorkingHour(close previous)")
 	}
 
@@ -132,9 +137,10 @@ func (r *repository) UpdateWorkingHour(day models.WorkDay) error {
 				hora_cierre,
 				abierto,
 				valid_from,
-				valid_to
+				valid_to,
+				doctor_id
 			)
-			VALUES ($1, $2, $3, TRUE, $4, 'infinity'::timestamptz);
+			VALUES ($1, $2, $3, TRUE, $4, 'infinity'::timestamptz, $5);
 		`)
 		if err != nil {
 			return dbErr.MapSQLError(err, "ScheduleRepo.UpdateWorkingHour(prepare insert open)")
@@ -148,7 +154,7 @@ func (r *repository) UpdateWorkingHour(day models.WorkDay) error {
 					"Rango horario inválido en UpdateWorkingHour",
 				)
 			}
-			if _, err := stmt.Exec(day.DayOfWeek, tr.Start, tr.End, now); err != nil {
+			if _, err := stmt.Exec(day.DayOfWeek, tr.Start, tr.End, now, day.DoctorID); err != nil {
 				return dbErr.MapSQLError(err, "ScheduleRepo.UpdateWorkingHour(insert range)")
 			}
 		}
@@ -161,10 +167,11 @@ func (r *repository) UpdateWorkingHour(day models.WorkDay) error {
 				hora_cierre,
 				abierto,
 				valid_from,
-				valid_to
+				valid_to,
+				doctor_id
 			)
-			VALUES ($1, NULL, NULL, FALSE, $2, 'infinity'::timestamptz);
-		`, day.DayOfWeek, now); err != nil {
+			VALUES ($1, NULL, NULL, FALSE, $2, 'infinity'::timestamptz, $3);
+		`, day.DayOfWeek, now, day.DoctorID); err != nil {
 			return dbErr.MapSQLError(err, "ScheduleRepo.UpdateWorkingHour(insert closed)")
 		}
 	}
@@ -180,12 +187,13 @@ func (r *repository) UpdateWorkingHour(day models.WorkDay) error {
 // Special Hours
 // -----------------------------------------------------------------------------
 
-func (r *repository) GetAllSpecialHours() ([]models.SpecialDay, error) {
+func (r *repository) GetAllSpecialHours(doctorID *int) ([]models.SpecialDay, error) {
 	rows, err := r.db.Query(`
 		SELECT id, fecha, hora_apertura, hora_cierre, abierto
 		FROM horarios_especiales
+		WHERE doctor_id IS NOT DISTINCT FROM $1
 		ORDER BY fecha;
-	`)
+	`, doctorID)
 	if err != nil {
 		return nil, dbErr.MapSQLError(err, "ScheduleRepo.GetAllSpecialHours")
 	}
@@ -205,9 +213,10 @@ func (r *repository) GetAllSpecialHours() ([]models.SpecialDay, error) {
 		}
 
 		sd := models.SpecialDay{
-			ID:     id,
-			Date:   date,
-			Active: active,
+			ID:       id,
+			Date:     date,
+			Active:   active,
+			DoctorID: doctorID,
 		}
 		if active && openStr.Valid && closeStr.Valid {
 			start, err1 := time.Parse("15:04:05", openStr.String)
@@ -229,13 +238,14 @@ func (r *repository) GetAllSpecialHours() ([]models.SpecialDay, error) {
 	return result, nil
 }
 
-func (r *repository) GetSpecialHoursBetween(start, end time.Time) ([]models.SpecialDay, error) {
+func (r *repository) GetSpecialHoursBetween(start, end time.Time, doctorID *int) ([]models.SpecialDay, error) {
 	rows, err := r.db.Query(`
 		SELECT id, fecha, hora_apertura, hora_cierre, abierto
 		FROM horarios_especiales
 		WHERE fecha BETWEEN $1 AND $2
+		  AND doctor_id IS NOT DISTINCT FROM $3
 		ORDER BY fecha;
-	`, start, end)
+	`, start, end, doctorID)
 	if err != nil {
 		return nil, dbErr.MapSQLError(err, "ScheduleRepo.GetSpecialHoursBetween")
 	}
@@ -255,9 +265,10 @@ func (r *repository) GetSpecialHoursBetween(start, end time.Time) ([]models.Spec
 		}
 
 		sd := models.SpecialDay{
-			ID:     id,
-			Date:   date,
-			Active: active,
+			ID:       id,
+			Date:     date,
+			Active:   active,
+			DoctorID: doctorID,
 		}
 		if active && openStr.Valid && closeStr.Valid {
 			start, err1 := time.Parse("15:04:05", openStr.String)
@@ -275,13 +286,14 @@ func (r *repository) GetSpecialHoursBetween(start, end time.Time) ([]models.Spec
 
 // GetSpecialHoursByDate returns all special hour entries for a specific date.
 // Multiple rows can exist (e.g., morning + afternoon shifts).
-func (r *repository) GetSpecialHoursByDate(date time.Time) ([]models.SpecialDay, error) {
+func (r *repository) GetSpecialHoursByDate(date time.Time, doctorID *int) ([]models.SpecialDay, error) {
 	rows, err := r.db.Query(`
 		SELECT id, fecha, hora_apertura, hora_cierre, abierto
 		FROM horarios_especiales
 		WHERE fecha = $1
+		  AND doctor_id IS NOT DISTINCT FROM $2
 		ORDER BY hora_apertura;
-	`, date)
+	`, date, doctorID)
 	if err != nil {
 		return nil, dbErr.MapSQLError(err, "ScheduleRepo.GetSpecialHoursByDate")
 	}
@@ -300,9 +312,10 @@ func (r *repository) GetSpecialHoursByDate(date time.Time) ([]models.SpecialDay,
 		}
 
 		sd := models.SpecialDay{
-			ID:     id,
-			Date:   d,
-			Active: active,
+			ID:       id,
+			Date:     d,
+			Active:   active,
+			DoctorID: doctorID,
 		}
 
 		// Only build a range if active AND both times present
@@ -347,16 +360,19 @@ func (r *repository) UpdateSpecialHour(day models.SpecialDay) error {
 		_ = tx.Rollback() // safe rollback if commit not called
 	}()
 
-	// Delete all existing entries for this date
-	if _, err := tx.Exec(`DELETE FROM horarios_especiales WHERE fecha = $1;`, day.Date); err != nil {
+	// Delete all existing entries for this date (same provider scope)
+	if _, err := tx.Exec(`
+		DELETE FROM horarios_especiales
+		WHERE fecha = $1 AND doctor_id IS NOT DISTINCT FROM $2;
+	`, day.Date, day.DoctorID); err != nil {
 		return dbErr.MapSQLError(err, "ScheduleRepo.UpdateSpecialHour(delete)")
 	}
 
 	// Reinsert all new ranges for that date
 	if day.Active && len(day.Ranges) > 0 {
 		stmt, err := tx.Prepare(`
-			INSERT INTO horarios_especiales (fecha, hora_apertura, hora_cierre, abierto)
-			VALUES ($1, $2, $3, $4);
+			INSERT INTO horarios_especiales (fecha, hora_apertura, hora_cierre, abierto, doctor_id)
+			VALUES ($1, $2, $3, $4, $5);
 		`)
 		if err != nil {
 			return dbErr.MapSQLError(err, "ScheduleRepo.UpdateSpecialHour(prepare)")
@@ -367,16 +383,16 @@ func (r *repository) UpdateSpecialHour(day models.SpecialDay) error {
 			if !tr.IsValid() {
 				return appErr.NewDomainError(appErr.ErrInvalidInput, "Rango horario inválido en UpdateSpecialHour")
 			}
-			if _, err := stmt.Exec(day.Date, tr.Start, tr.End, true); err != nil {
+			if _, err := stmt.Exec(day.Date, tr.Start, tr.End, true, day.DoctorID); err != nil {
 				return dbErr.MapSQLError(err, "ScheduleRepo.UpdateSpecialHour(insert)")
 			}
 		}
 	} else {
 		// if no ranges provided, insert a closed (inactive) row
 		if _, err := tx.Exec(`
-			INSERT INTO horarios_especiales (fecha, hora_apertura, hora_cierre, abierto)
-			VALUES ($1, NULL, NULL, FALSE);
-		`, day.Date); err != nil {
+			INSERT INTO horarios_especiales (fecha, hora_apertura, hora_cierre, abierto, doctor_id)
+			VALUES ($1, NULL, NULL, FALSE, $2);
+		`, day.Date, day.DoctorID); err != nil {
 			return dbErr.MapSQLError(err, "ScheduleRepo.UpdateSpecialHour(insert closed)")
 		}
 	}
@@ -388,12 +404,15 @@ func (r *repository) UpdateSpecialHour(day models.SpecialDay) error {
 	return nil
 }
 
-func (r *repository) DeleteSpecialHour(date time.Time) error {
+func (r *repository) DeleteSpecialHour(date time.Time, doctorID *int) error {
 	if date.IsZero() {
 		return appErr.Wrap("ScheduleRepo.DeleteSpecialHourByDate", appErr.ErrInvalidInput, nil)
 	}
 
-	_, err := r.db.Exec(`DELETE FROM horarios_especiales WHERE fecha = $1;`, date)
+	_, err := r.db.Exec(`
+		DELETE FROM horarios_especiales
+		WHERE fecha = $1 AND doctor_id IS NOT DISTINCT FROM $2;
+	`, date, doctorID)
 	if err != nil {
 		return dbErr.MapSQLError(err, "ScheduleRepo.DeleteSpecialHourByDate")
 	}
@@ -433,14 +452,15 @@ func getEnd(d any) *time.Time {
 	return nil
 }
 
-func (r *repository) GetWorkingHoursForDate(d time.Time) ([]models.WorkDay, error) {
+func (r *repository) GetWorkingHoursForDate(d time.Time, doctorID *int) ([]models.WorkDay, error) {
 	rows, err := r.db.Query(`
 		SELECT id, dia_semana, hora_apertura, hora_cierre, abierto
 		FROM horarios_laborales
 		WHERE valid_from <= $1
 		  AND valid_to   >  $1
+		  AND doctor_id IS NOT DISTINCT FROM $2
 		ORDER BY dia_semana, hora_apertura;
-	`, d)
+	`, d, doctorID)
 	if err != nil {
 		return nil, dbErr.MapSQLError(err, "ScheduleRepo.GetWorkingHoursForDate")
 	}
@@ -463,6 +483,7 @@ func (r *repository) GetWorkingHoursForDate(d time.Time) ([]models.WorkDay, erro
 			ID:        id,
 			DayOfWeek: dayOfWeek,
 			Active:    active,
+			DoctorID:  doctorID,
 		}
 
 		if active && openStr.Valid && closeStr.Valid {
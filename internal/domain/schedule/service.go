@@ -12,20 +12,24 @@ import (
 )
 
 // Service Interface
+//
+// doctorID selects a provider's schedule; nil means the clinic-wide schedule.
+// Effective schedules for a provider fall back to the clinic-wide rows when the
+// provider has nothing configured for that date or weekday.
 type Service interface {
 	// Reads
-	GetWorkingHours() ([]models.WorkDay, error)
-	GetSpecialHoursBetween(start, end time.Time) ([]models.SpecialDay, error)
-	GetEffectiveDay(date time.Time) (*models.EffectiveDay, error)
-	GetEffectiveRange(start, end time.Time) ([]models.EffectiveDay, error)
+	GetWorkingHours(doctorID *int) ([]models.WorkDay, error)
+	GetSpecialHoursBetween(start, end time.Time, doctorID *int) ([]models.SpecialDay, error)
+	GetEffectiveDay(date time.Time, doctorID *int) (*models.EffectiveDay, error)
+	GetEffectiveRange(start, end time.Time, doctorID *int) ([]models.EffectiveDay, error)
 
 	// Writes
 	UpdateWorkDay(day models.WorkDay) error
 	AddSpecialDay(day models.SpecialDay) error
-	DeleteSpecialDay(date time.Time) error
+	DeleteSpecialDay(date time.Time, doctorID *int) error
 
 	// Validations (Internal)
-	IsTimeRangeWithinWorkingHours(date, start, end time.Time) (bool, error)
+	IsTimeRangeWithinWorkingHours(date, start, end time.Time, doctorID *int) (bool, error)
 }
 
 // Implementation
@@ -43,8 +47,8 @@ func NewService(repo Repository) Service {
 
 // GetWorkingHours returns all weekly recurring working days (Mon–Sun),
 // grouping all time ranges belonging to the same weekday.
-func (s *service) GetWorkingHours() ([]models.WorkDay, error) {
-	raw, err := s.repo.GetAllWorkingHours()
+func (s *service) GetWorkingHours(doctorID *int) ([]models.WorkDay, error) {
+	raw, err := s.repo.GetAllWorkingHours(doctorID)
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +64,7 @@ func (s *service) GetWorkingHours() ([]models.WorkDay, error) {
 				DayOfWeek: wd.DayOfWeek,
 				Ranges:    wd.Ranges,
 				Active:    wd.Active,
+				DoctorID:  wd.DoctorID,
 			}
 		}
 	}
@@ -81,8 +86,8 @@ func (s *service) GetWorkingHours() ([]models.WorkDay, error) {
 
 // GetSpecialHoursBetween returns all special overrides in a date range,
 // grouping all ranges for the same date.
-func (s *service) GetSpecialHoursBetween(start, end time.Time) ([]models.SpecialDay, error) {
-	raw, err := s.repo.GetSpecialHoursBetween(start, end)
+func (s *service) GetSpecialHoursBetween(start, end time.Time, doctorID *int) ([]models.SpecialDay, error) {
+	raw, err := s.repo.GetSpecialHoursBetween(start, end, doctorID)
 	if err != nil {
 		return nil, err
 	}
@@ -95,10 +100,11 @@ func (s *service) GetSpecialHoursBetween(start, end time.Time) ([]models.Special
 			existing.Active = existing.Active || sd.Active
 		} else {
 			grouped[key] = &models.SpecialDay{
-				ID:     sd.ID,
-				Date:   sd.Date,
-				Ranges: sd.Ranges,
-				Active: sd.Active,
+				ID:       sd.ID,
+				Date:     sd.Date,
+				Ranges:   sd.Ranges,
+				Active:   sd.Active,
+				DoctorID: sd.DoctorID,
 			}
 		}
 	}
@@ -119,35 +125,29 @@ func (s *service) GetSpecialHoursBetween(start, end time.Time) ([]models.Special
 }
 
 // GetEffectiveDay merges recurring + special schedules for a specific date.
-func (s *service) GetEffectiveDay(date time.Time) (*models.EffectiveDay, error) {
-	// --- 1. Check for special day overrides ---
-	specials, err := s.repo.GetSpecialHoursByDate(date)
-	if err != nil {
-		return nil, err
+//
+// Precedence (first match wins):
+//  1. provider special day
+//  2. clinic-wide special day (e.g. holidays)
+//  3. provider weekly hours for that weekday
+//  4. clinic-wide weekly hours
+func (s *service) GetEffectiveDay(date time.Time, doctorID *int) (*models.EffectiveDay, error) {
+	// --- 1. Check for special day overrides (provider first, then clinic) ---
+	scopes := []*int{nil}
+	if doctorID != nil {
+		scopes = []*int{doctorID, nil}
 	}
 
-	if len(specials) > 0 {
-		// Merge all special day entries for the same date (can have multiple ranges)
-		var mergedRanges []models.TimeRange
-		active := false
-
-		for _, sd := range specials {
-			if sd.Active {
-				mergedRanges = append(mergedRanges, sd.Ranges...)
-				active = true
-			}
+	for _, scope := range scopes {
+		specials, err := s.repo.GetSpecialHoursByDate(date, scope)
+		if err != nil {
+			return nil, err
+		}
+		if len(specials) > 0 {
+			eff := mergeSpecialDays(specials)
+			eff.DoctorID = doctorID
+			return eff, nil
 		}
-
-		sort.Slice(mergedRanges, func(i, j int) bool {
-			return mergedRanges[i].Start.Before(mergedRanges[j].Start)
-		})
-
-		return &models.EffectiveDay{
-			Date:       specials[0].Date,
-			Ranges:     mergedRanges,
-			IsOverride: true,
-			Active:     active,
-		}, nil
 	}
 
 	// --- 2. Fallback: use recurring working hours if no special override exists ---
@@ -156,16 +156,62 @@ func (s *service) GetEffectiveDay(date time.Time) (*models.EffectiveDay, error)
 		weekday = 7
 	}
 
-	raw, err := s.repo.GetWorkingHoursForDate(date)
-	if err != nil {
-		return nil, err
+	for _, scope := range scopes {
+		raw, err := s.repo.GetWorkingHoursForDate(date, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		var forDay []models.WorkDay
+		for _, wd := range raw {
+			if wd.DayOfWeek == weekday {
+				forDay = append(forDay, wd)
+			}
+		}
+		// A provider with no rows for this weekday inherits the clinic schedule
+		if len(forDay) == 0 && scope != nil {
+			continue
+		}
+
+		eff := mergeWorkDays(date, forDay)
+		eff.DoctorID = doctorID
+		return eff, nil
 	}
 
+	return &models.EffectiveDay{Date: date, DoctorID: doctorID}, nil
+}
+
+// mergeSpecialDays merges all special day entries for the same date (can have multiple ranges).
+func mergeSpecialDays(specials []models.SpecialDay) *models.EffectiveDay {
 	var mergedRanges []models.TimeRange
 	active := false
 
-	for _, wd := range raw {
-		if wd.DayOfWeek == weekday && wd.Active {
+	for _, sd := range specials {
+		if sd.Active {
+			mergedRanges = append(mergedRanges, sd.Ranges...)
+			active = true
+		}
+	}
+
+	sort.Slice(mergedRanges, func(i, j int) bool {
+		return mergedRanges[i].Start.Before(mergedRanges[j].Start)
+	})
+
+	return &models.EffectiveDay{
+		Date:       specials[0].Date,
+		Ranges:     mergedRanges,
+		IsOverride: true,
+		Active:     active,
+	}
+}
+
+// mergeWorkDays merges the recurring rows of a single weekday.
+func mergeWorkDays(date time.Time, days []models.WorkDay) *models.EffectiveDay {
+	var mergedRanges []models.TimeRange
+	active := false
+
+	for _, wd := range days {
+		if wd.Active {
 			mergedRanges = append(mergedRanges, wd.Ranges...)
 			active = true
 		}
@@ -180,15 +226,15 @@ func (s *service) GetEffectiveDay(date time.Time) (*models.EffectiveDay, error)
 		Ranges:     mergedRanges,
 		IsOverride: false,
 		Active:     active,
-	}, nil
+	}
 }
 
 // GetEffectiveRange returns merged schedules for each date in a period,
 // calling GetEffectiveDay for each date and aggregating results.
-func (s *service) GetEffectiveRange(start, end time.Time) ([]models.EffectiveDay, error) {
+func (s *service) GetEffectiveRange(start, end time.Time, doctorID *int) ([]models.EffectiveDay, error) {
 	var days []models.EffectiveDay
 	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
-		eff, err := s.GetEffectiveDay(d)
+		eff, err := s.GetEffectiveDay(d, doctorID)
 		if err != nil {
 			return nil, err
 		}
@@ -202,8 +248,8 @@ func (s *service) GetEffectiveRange(start, end time.Time) ([]models.EffectiveDay
 // ============================================================================
 
 // IsTimeRangeWithinWorkingHours ensures an appointment fits within open slots.
-func (s *service) IsTimeRangeWithinWorkingHours(date, start, end time.Time) (bool, error) {
-	eff, err := s.GetEffectiveDay(date)
+func (s *service) IsTimeRangeWithinWorkingHours(date, start, end time.Time, doctorID *int) (bool, error) {
+	eff, err := s.GetEffectiveDay(date, doctorID)
 	if err != nil {
 		return false, err
 	}
@@ -261,6 +307,6 @@ func (s *service) UpdateSpecialDay(day models.SpecialDay) error {
 	return s.repo.UpdateSpecialHour(day)
 }
 
-func (s *service) DeleteSpecialDay(date time.Time) error {
-	return s.repo.DeleteSpecialHour(date)
+func (s *service) DeleteSpecialDay(date time.Time, doctorID *int) error {
+	return s.repo.DeleteSpecialHour(date, doctorID)
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
 	userModels "github.com/tonitomc/healthcare-crm-api/internal/domain/user/models"
 	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
 )
@@ -22,7 +23,7 @@ func NewHandler(s Service) *Handler {
 
 // RegisterRoutes mounts /user routes under the provided Echo group.
 func (h *Handler) RegisterRoutes(g *echo.Group) {
-	userGroup := g.Group("/user", ErrorMiddleware())
+	userGroup := g.Group("/user", ErrorMiddleware(), middleware.Audit("usuario", "id", h.auditedUser))
 
 	// Read operations
 	userGroup.GET("", h.GetAll, middleware.RequirePermission("manejar-usuarios"))
@@ -41,25 +42,58 @@ func (h *Handler) RegisterRoutes(g *echo.Group) {
 	userGroup.GET("/enriched", h.GetAllWithRoles, middleware.RequirePermission("manejar-usuarios"))
 }
 
+// auditedUser is the state middleware.Audit records before and after a change:
+// the user with their roles.
+func (h *Handler) auditedUser(id int) (interface{}, error) {
+	u, err := h.service.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := h.service.GetUserRoles(id)
+	if err != nil {
+		return nil, err
+	}
+	return echo.Map{"usuario": u, "roles": roles}, nil
+}
+
 // -----------------------------------------------------------------------------
 // Handlers
 // -----------------------------------------------------------------------------
 
+// listSpec declares the sort fields and filters accepted by GET /user
+var listSpec = query.Spec{
+	Sortable: []string{"id", "username", "email"},
+	Filters: map[string]query.FilterType{
+		"username": query.Text,
+		"email":    query.Text,
+	},
+	DefaultSort: []query.Sort{{Field: "id"}},
+}
+
+// listKey returns a user's sort values for the next page cursor
+func listKey(u userModels.User, field string) interface{} {
+	switch field {
+	case "username":
+		return u.Username
+	case "email":
+		return u.Email
+	}
+	return u.ID
+}
+
 // GET /user
 func (h *Handler) GetAll(c echo.Context) error {
-	users, err := h.service.GetAllUsers()
+	opts, err := query.Parse(c, listSpec)
 	if err != nil {
 		return err
 	}
 
-	if len(users) == 0 {
-		return c.JSON(http.StatusOK, echo.Map{
-			"message": "No hay usuarios registrados",
-			"data":    []userModels.User{},
-		})
+	users, total, err := h.service.ListUsers(opts)
+	if err != nil {
+		return err
 	}
 
-	return c.JSON(http.StatusOK, users)
+	return c.JSON(http.StatusOK, query.NewPage(users, total, opts, listKey))
 }
 
 // GET /user/:id
@@ -8,7 +8,10 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	query "github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	database "github.com/tonitomc/healthcare-crm-api/internal/database"
 	models "github.com/tonitomc/healthcare-crm-api/internal/domain/role/models"
+	user "github.com/tonitomc/healthcare-crm-api/internal/domain/user"
 	models0 "github.com/tonitomc/healthcare-crm-api/internal/domain/user/models"
 )
 
@@ -151,6 +154,22 @@ func (mr *MockRepositoryMockRecorder) GetUserRoles(userID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoles", reflect.TypeOf((*MockRepository)(nil).GetUserRoles), userID)
 }
 
+// List mocks base method.
+func (m *MockRepository) List(opts query.Options) ([]models0.User, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", opts)
+	ret0, _ := ret[0].([]models0.User)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockRepositoryMockRecorder) List(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepository)(nil).List), opts)
+}
+
 // RemoveRole mocks base method.
 func (m *MockRepository) RemoveRole(userID, roleID int) error {
 	m.ctrl.T.Helper()
@@ -178,3 +197,17 @@ func (mr *MockRepositoryMockRecorder) Update(u interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), u)
 }
+
+// WithTx mocks base method.
+func (m *MockRepository) WithTx(tx database.DBTX) user.Repository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(user.Repository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRepositoryMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepository)(nil).WithTx), tx)
+}
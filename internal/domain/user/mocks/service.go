@@ -8,10 +8,48 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	query "github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	database "github.com/tonitomc/healthcare-crm-api/internal/database"
 	models "github.com/tonitomc/healthcare-crm-api/internal/domain/role/models"
+	user "github.com/tonitomc/healthcare-crm-api/internal/domain/user"
 	models0 "github.com/tonitomc/healthcare-crm-api/internal/domain/user/models"
 )
 
+// MockAccessListener is a mock of AccessListener interface.
+type MockAccessListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessListenerMockRecorder
+}
+
+// MockAccessListenerMockRecorder is the mock recorder for MockAccessListener.
+type MockAccessListenerMockRecorder struct {
+	mock *MockAccessListener
+}
+
+// NewMockAccessListener creates a new mock instance.
+func NewMockAccessListener(ctrl *gomock.Controller) *MockAccessListener {
+	mock := &MockAccessListener{ctrl: ctrl}
+	mock.recorder = &MockAccessListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessListener) EXPECT() *MockAccessListenerMockRecorder {
+	return m.recorder
+}
+
+// UserAccessChanged mocks base method.
+func (m *MockAccessListener) UserAccessChanged(userID int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UserAccessChanged", userID)
+}
+
+// UserAccessChanged indicates an expected call of UserAccessChanged.
+func (mr *MockAccessListenerMockRecorder) UserAccessChanged(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserAccessChanged", reflect.TypeOf((*MockAccessListener)(nil).UserAccessChanged), userID)
+}
+
 // MockService is a mock of Service interface.
 type MockService struct {
 	ctrl     *gomock.Controller
@@ -167,6 +205,22 @@ func (mr *MockServiceMockRecorder) GetUserRoles(userID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoles", reflect.TypeOf((*MockService)(nil).GetUserRoles), userID)
 }
 
+// ListUsers mocks base method.
+func (m *MockService) ListUsers(opts query.Options) ([]models0.User, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", opts)
+	ret0, _ := ret[0].([]models0.User)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockServiceMockRecorder) ListUsers(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockService)(nil).ListUsers), opts)
+}
+
 // RemoveRole mocks base method.
 func (m *MockService) RemoveRole(userID, roleID int) error {
 	m.ctrl.T.Helper()
@@ -194,3 +248,17 @@ func (mr *MockServiceMockRecorder) UpdateUser(u interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockService)(nil).UpdateUser), u)
 }
+
+// WithTx mocks base method.
+func (m *MockService) WithTx(tx database.DBTX) user.Service {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(user.Service)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockServiceMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockService)(nil).WithTx), tx)
+}
@@ -5,6 +5,7 @@ package user
 import (
 	"database/sql"
 
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
 	"github.com/tonitomc/healthcare-crm-api/internal/database"
 	roleModels "github.com/tonitomc/healthcare-crm-api/internal/domain/role/models"
 	userModels "github.com/tonitomc/healthcare-crm-api/internal/domain/user/models"
@@ -15,6 +16,7 @@ import (
 type Repository interface {
 	// --- User CRUD ---
 	GetAll() ([]userModels.User, error)
+	List(opts query.Options) ([]userModels.User, int, error)
 	GetByID(id int) (*userModels.User, error)
 	GetByUsernameOrEmail(identifier string) (*userModels.User, error)
 	Create(u *userModels.User) error
@@ -26,17 +28,24 @@ type Repository interface {
 	AddRole(userID, roleID int) error
 	RemoveRole(userID, roleID int) error
 	ClearRoles(userID int) error
+
+	// WithTx returns a copy of the repository that runs its queries in tx
+	WithTx(tx database.DBTX) Repository
 }
 
 // Concrete implementation backed by PostgreSQL.
 type repository struct {
-	db *sql.DB
+	db database.DBTX
 }
 
 func NewRepository(db *sql.DB) Repository {
 	return &repository{db: db}
 }
 
+func (r *repository) WithTx(tx database.DBTX) Repository {
+	return &repository{db: tx}
+}
+
 // -----------------------------------------------------------------------------
 // User CRUD
 // -----------------------------------------------------------------------------
@@ -64,6 +73,43 @@ func (r *repository) GetAll() ([]userModels.User, error) {
 	return users, nil
 }
 
+// userColumns traduce los campos de orden y filtro del listado a columnas
+var userColumns = query.Columns{
+	"id":       "id",
+	"username": "username",
+	"email":    "correo",
+}
+
+// List devuelve una página de usuarios y el total que cumple los filtros
+func (r *repository) List(opts query.Options) ([]userModels.User, int, error) {
+	conds, args := opts.Conditions(userColumns, nil)
+	where := query.Where(conds)
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM usuarios`+where, args...).Scan(&total); err != nil {
+		return nil, 0, database.MapSQLError(err, "UserRepository.List(count)")
+	}
+
+	conds, args = opts.Seek(userColumns, "id", conds, args)
+	page, args := opts.LimitOffset(args)
+	rows, err := r.db.Query(`SELECT id, username, correo, password_hash FROM usuarios`+query.Where(conds)+opts.OrderBy(userColumns, "id")+page, args...)
+	if err != nil {
+		return nil, 0, database.MapSQLError(err, "UserRepository.List")
+	}
+	defer rows.Close()
+
+	var users []userModels.User
+	for rows.Next() {
+		var u userModels.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.Email, &u.PasswordHash); err != nil {
+			return nil, 0, appErr.Wrap("UserRepository.List(scan)", appErr.ErrInternal, err)
+		}
+		users = append(users, u)
+	}
+
+	return users, total, nil
+}
+
 func (r *repository) GetByID(id int) (*userModels.User, error) {
 	if id <= 0 {
 		return nil, appErr.Wrap("UserRepository.GetByID", appErr.ErrInvalidInput, nil)
@@ -160,7 +206,7 @@ func (r *repository) Delete(id int) error {
 
 func (r *repository) GetUserRoles(userID int) ([]roleModels.Role, error) {
 	rows, err := r.db.Query(`
-		SELECT r.id, r.nombre, r.descripcion
+		SELECT r.id, r.nombre, r.descripcion, r.requiere_mfa
 		FROM roles r
 		JOIN usuarios_roles ur ON ur.rol_id = r.id
 		WHERE ur.usuario_id = $1
@@ -173,7 +219,7 @@ func (r *repository) GetUserRoles(userID int) ([]roleModels.Role, error) {
 	var roles []roleModels.Role
 	for rows.Next() {
 		var role roleModels.Role
-		if err := rows.Scan(&role.ID, &role.Name, &role.Description); err != nil {
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.RequiresMFA); err != nil {
 			return nil, appErr.Wrap("UserRepository.GetUserRoles(scan)", appErr.ErrInternal, err)
 		}
 		roles = append(roles, role)
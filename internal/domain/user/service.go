@@ -3,6 +3,8 @@
 package user
 
 import (
+	"github.com/tonitomc/healthcare-crm-api/internal/api/query"
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
 	roleDomain "github.com/tonitomc/healthcare-crm-api/internal/domain/role"
 	roleModels "github.com/tonitomc/healthcare-crm-api/internal/domain/role/models"
 	userModels "github.com/tonitomc/healthcare-crm-api/internal/domain/user/models"
@@ -13,11 +15,18 @@ import (
 // Service Interface
 // -----------------------------------------------------------------------------
 
+// AccessListener is told when a user's roles change, e.g. to drop their cached
+// permissions.
+type AccessListener interface {
+	UserAccessChanged(userID int)
+}
+
 // Service defines the business logic for managing users and their roles.
 // Authentication (password hashing/comparison) is handled separately in auth/.
 type Service interface {
 	// User CRUD
 	GetAllUsers() ([]userModels.User, error)
+	ListUsers(opts query.Options) ([]userModels.User, int, error)
 	CreateUser(username, email, passwordHash string) error
 	GetByID(id int) (*userModels.User, error)
 	GetByUsernameOrEmail(identifier string) (*userModels.User, error)
@@ -30,6 +39,9 @@ type Service interface {
 	RemoveRole(userID, roleID int) error
 	ClearRoles(userID int) error
 	GetRolesAndPermissions(userID int) ([]roleModels.Role, []roleModels.Permission, error)
+
+	// WithTx returns a copy of the service whose queries run in tx
+	WithTx(tx database.DBTX) Service
 }
 
 // -----------------------------------------------------------------------------
@@ -39,11 +51,22 @@ type Service interface {
 type service struct {
 	repo        Repository
 	roleService roleDomain.Service
+	listener    AccessListener
+}
+
+// NewService constructs a new User Service. listener may be nil.
+func NewService(repo Repository, roleService roleDomain.Service, listener AccessListener) Service {
+	return &service{repo: repo, roleService: roleService, listener: listener}
 }
 
-// NewService constructs a new User Service.
-func NewService(repo Repository, roleService roleDomain.Service) Service {
-	return &service{repo: repo, roleService: roleService}
+func (s *service) WithTx(tx database.DBTX) Service {
+	return &service{repo: s.repo.WithTx(tx), roleService: s.roleService, listener: s.listener}
+}
+
+func (s *service) accessChanged(userID int) {
+	if s.listener != nil {
+		s.listener.UserAccessChanged(userID)
+	}
 }
 
 // -----------------------------------------------------------------------------
@@ -58,6 +81,11 @@ func (s *service) GetAllUsers() ([]userModels.User, error) {
 	return users, nil
 }
 
+// ListUsers returns one page of users and the total matching the list options.
+func (s *service) ListUsers(opts query.Options) ([]userModels.User, int, error) {
+	return s.repo.List(opts)
+}
+
 func (s *service) CreateUser(username, email, passwordHash string) error {
 	if username == "" || email == "" || passwordHash == "" {
 		return appErr.Wrap("UserService.CreateUser", appErr.ErrInvalidInput, nil)
@@ -120,6 +148,7 @@ func (s *service) DeleteUser(id int) error {
 	if err := s.repo.Delete(id); err != nil {
 		return err
 	}
+	s.accessChanged(id)
 	return nil
 }
 
@@ -169,6 +198,7 @@ func (s *service) AddRole(userID, roleID int) error {
 	if err := s.repo.AddRole(userID, roleID); err != nil {
 		return err
 	}
+	s.accessChanged(userID)
 	return nil
 }
 
@@ -184,6 +214,7 @@ func (s *service) RemoveRole(userID, roleID int) error {
 	if err := s.repo.RemoveRole(userID, roleID); err != nil {
 		return err
 	}
+	s.accessChanged(userID)
 	return nil
 }
 
@@ -199,6 +230,7 @@ func (s *service) ClearRoles(userID int) error {
 	if err := s.repo.ClearRoles(userID); err != nil {
 		return err
 	}
+	s.accessChanged(userID)
 	return nil
 }
 
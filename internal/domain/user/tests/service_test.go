@@ -23,7 +23,7 @@ func setup(t *testing.T) (*userMocks.MockRepository, *roleMocks.MockService, use
 	ctrl := gomock.NewController(t)
 	mockRepo := userMocks.NewMockRepository(ctrl)
 	mockRoleSvc := roleMocks.NewMockService(ctrl)
-	svc := user.NewService(mockRepo, mockRoleSvc)
+	svc := user.NewService(mockRepo, mockRoleSvc, nil)
 	return mockRepo, mockRoleSvc, svc, ctrl
 }
 
@@ -306,6 +306,22 @@ func TestService_AddRole_RemoveRole_ClearRoles(t *testing.T) {
 		mockRepo.EXPECT().ClearRoles(1).Return(nil)
 		require.NoError(t, svc.ClearRoles(1))
 	})
+
+	t.Run("role changes notify the access listener", func(t *testing.T) {
+		mockRepo, mockRoleSvc, _, ctrl := setup(t)
+		defer ctrl.Finish()
+		listener := userMocks.NewMockAccessListener(ctrl)
+		svc := user.NewService(mockRepo, mockRoleSvc, listener)
+
+		mockRepo.EXPECT().GetByID(1).Return(&userModels.User{ID: 1}, nil).Times(2)
+		mockRepo.EXPECT().GetUserRoles(1).Return([]roleModels.Role{}, nil)
+		mockRepo.EXPECT().AddRole(1, 3).Return(nil)
+		mockRepo.EXPECT().RemoveRole(1, 3).Return(appErr.Wrap("repo.RemoveRole", appErr.ErrInternal, errors.New("db error")))
+		listener.EXPECT().UserAccessChanged(1).Times(1)
+
+		require.NoError(t, svc.AddRole(1, 3))
+		require.Error(t, svc.RemoveRole(1, 3)) // nothing changed, nothing notified
+	})
 }
 
 // -----------------------------------------------------------------------------
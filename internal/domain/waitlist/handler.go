@@ -0,0 +1,136 @@
+package waitlist
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tonitomc/healthcare-crm-api/internal/api/middleware"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(e *echo.Group) {
+	waitlist := e.Group("/waitlist", ErrorMiddleware(), middleware.Audit("lista_espera", "id", h.auditedEntry))
+
+	waitlist.GET("", h.List, middleware.RequirePermission("ver-citas"))
+	waitlist.GET("/matches", h.FindMatches, middleware.RequirePermission("ver-citas"))
+	waitlist.GET("/:id", h.GetByID, middleware.RequirePermission("ver-citas"))
+	waitlist.POST("", h.Create, middleware.RequirePermission("manejar-citas"))
+	waitlist.DELETE("/:id", h.Remove, middleware.RequirePermission("manejar-citas"))
+	waitlist.POST("/:id/decline", h.Decline, middleware.RequirePermission("manejar-citas"))
+	waitlist.POST("/:id/promote", h.Promote, middleware.RequirePermission("manejar-citas"))
+}
+
+// auditedEntry is the state middleware.Audit records before and after a change
+func (h *Handler) auditedEntry(id int) (interface{}, error) {
+	return h.service.GetByID(id)
+}
+
+func (h *Handler) List(c echo.Context) error {
+	entries, err := h.service.List(c.QueryParam("estado"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, entries)
+}
+
+func (h *Handler) GetByID(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	entry, err := h.service.GetByID(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, entry)
+}
+
+func (h *Handler) Create(c echo.Context) error {
+	var req models.EntryCreateDTO
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Waitlist.Create.Bind", appErr.ErrInvalidInput, err)
+	}
+	id, err := h.service.Create(&req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, echo.Map{"id": id, "message": "Agregado a la lista de espera"})
+}
+
+func (h *Handler) Remove(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	if err := h.service.Remove(id); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": "Retirado de la lista de espera"})
+}
+
+// FindMatches lista quién podría tomar un horario:
+// ?fecha=2025-03-03T09:00:00-06:00&duracion=1800&doctor_id=7
+func (h *Handler) FindMatches(c echo.Context) error {
+	fecha, err := time.Parse(time.RFC3339, c.QueryParam("fecha"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "El parámetro 'fecha' debe tener formato RFC3339")
+	}
+	duracion, err := strconv.ParseInt(c.QueryParam("duracion"), 10, 64)
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "El parámetro 'duracion' debe ser numérico")
+	}
+	slot := models.Slot{Fecha: fecha, Duracion: duracion}
+	if raw := c.QueryParam("doctor_id"); raw != "" {
+		doctorID, err := strconv.Atoi(raw)
+		if err != nil {
+			return appErr.NewDomainError(appErr.ErrInvalidInput, "El parámetro 'doctor_id' debe ser numérico")
+		}
+		slot.DoctorID = &doctorID
+	}
+
+	entries, err := h.service.FindMatches(slot)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, entries)
+}
+
+func (h *Handler) Decline(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	next, err := h.service.Decline(id)
+	if err != nil {
+		return err
+	}
+	// next es la entrada a la que se ofreció ahora el horario (null si nadie más califica)
+	return c.JSON(http.StatusOK, echo.Map{"message": "Oferta rechazada", "siguiente": next})
+}
+
+func (h *Handler) Promote(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return appErr.NewDomainError(appErr.ErrInvalidInput, "ID inválido")
+	}
+	var req models.PromoteDTO
+	if err := c.Bind(&req); err != nil {
+		return appErr.Wrap("Waitlist.Promote.Bind", appErr.ErrInvalidInput, err)
+	}
+	citaID, err := h.service.Promote(id, &req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, echo.Map{"cita_id": citaID, "message": "Cita creada desde la lista de espera"})
+}
@@ -0,0 +1,238 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	database "github.com/tonitomc/healthcare-crm-api/internal/database"
+	waitlist "github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist"
+	models "github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist/models"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(entry *models.EntryCreateDTO) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", entry)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), entry)
+}
+
+// GetByID mocks base method.
+func (m *MockRepository) GetByID(id int) (*models.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRepository)(nil).GetByID), id)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockRepository) GetForUpdate(id int) (*models.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", id)
+	ret0, _ := ret[0].(*models.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockRepositoryMockRecorder) GetForUpdate(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockRepository)(nil).GetForUpdate), id)
+}
+
+// GetWaitingForDay mocks base method.
+func (m *MockRepository) GetWaitingForDay(day time.Time) ([]models.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWaitingForDay", day)
+	ret0, _ := ret[0].([]models.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWaitingForDay indicates an expected call of GetWaitingForDay.
+func (mr *MockRepositoryMockRecorder) GetWaitingForDay(day interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWaitingForDay", reflect.TypeOf((*MockRepository)(nil).GetWaitingForDay), day)
+}
+
+// List mocks base method.
+func (m *MockRepository) List(estado string) ([]models.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", estado)
+	ret0, _ := ret[0].([]models.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRepositoryMockRecorder) List(estado interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepository)(nil).List), estado)
+}
+
+// MarkBooked mocks base method.
+func (m *MockRepository) MarkBooked(id, citaID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkBooked", id, citaID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkBooked indicates an expected call of MarkBooked.
+func (mr *MockRepositoryMockRecorder) MarkBooked(id, citaID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkBooked", reflect.TypeOf((*MockRepository)(nil).MarkBooked), id, citaID)
+}
+
+// MarkOffered mocks base method.
+func (m *MockRepository) MarkOffered(id int, slot models.Slot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOffered", id, slot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOffered indicates an expected call of MarkOffered.
+func (mr *MockRepositoryMockRecorder) MarkOffered(id, slot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOffered", reflect.TypeOf((*MockRepository)(nil).MarkOffered), id, slot)
+}
+
+// MarkRemoved mocks base method.
+func (m *MockRepository) MarkRemoved(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRemoved", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRemoved indicates an expected call of MarkRemoved.
+func (mr *MockRepositoryMockRecorder) MarkRemoved(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRemoved", reflect.TypeOf((*MockRepository)(nil).MarkRemoved), id)
+}
+
+// MarkWaiting mocks base method.
+func (m *MockRepository) MarkWaiting(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWaiting", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWaiting indicates an expected call of MarkWaiting.
+func (mr *MockRepositoryMockRecorder) MarkWaiting(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWaiting", reflect.TypeOf((*MockRepository)(nil).MarkWaiting), id)
+}
+
+// ReassignPatient mocks base method.
+func (m *MockRepository) ReassignPatient(fromID, toID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignPatient", fromID, toID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReassignPatient indicates an expected call of ReassignPatient.
+func (mr *MockRepositoryMockRecorder) ReassignPatient(fromID, toID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignPatient", reflect.TypeOf((*MockRepository)(nil).ReassignPatient), fromID, toID)
+}
+
+// WithTx mocks base method.
+func (m *MockRepository) WithTx(tx database.DBTX) waitlist.Repository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(waitlist.Repository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRepositoryMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepository)(nil).WithTx), tx)
+}
+
+// Mockscanner is a mock of scanner interface.
+type Mockscanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockscannerMockRecorder
+}
+
+// MockscannerMockRecorder is the mock recorder for Mockscanner.
+type MockscannerMockRecorder struct {
+	mock *Mockscanner
+}
+
+// NewMockscanner creates a new mock instance.
+func NewMockscanner(ctrl *gomock.Controller) *Mockscanner {
+	mock := &Mockscanner{ctrl: ctrl}
+	mock.recorder = &MockscannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockscanner) EXPECT() *MockscannerMockRecorder {
+	return m.recorder
+}
+
+// Scan mocks base method.
+func (m *Mockscanner) Scan(dest ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range dest {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Scan", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockscannerMockRecorder) Scan(dest ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*Mockscanner)(nil).Scan), dest...)
+}
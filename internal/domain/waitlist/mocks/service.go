@@ -0,0 +1,246 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	database "github.com/tonitomc/healthcare-crm-api/internal/database"
+	waitlist "github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist"
+	models "github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist/models"
+)
+
+// MockAppointmentBooker is a mock of AppointmentBooker interface.
+type MockAppointmentBooker struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppointmentBookerMockRecorder
+}
+
+// MockAppointmentBookerMockRecorder is the mock recorder for MockAppointmentBooker.
+type MockAppointmentBookerMockRecorder struct {
+	mock *MockAppointmentBooker
+}
+
+// NewMockAppointmentBooker creates a new mock instance.
+func NewMockAppointmentBooker(ctrl *gomock.Controller) *MockAppointmentBooker {
+	mock := &MockAppointmentBooker{ctrl: ctrl}
+	mock.recorder = &MockAppointmentBookerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppointmentBooker) EXPECT() *MockAppointmentBookerMockRecorder {
+	return m.recorder
+}
+
+// Book mocks base method.
+func (m *MockAppointmentBooker) Book(pacienteID *int, nombre *string, fecha time.Time, duracion int64, doctorID *int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Book", pacienteID, nombre, fecha, duracion, doctorID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Book indicates an expected call of Book.
+func (mr *MockAppointmentBookerMockRecorder) Book(pacienteID, nombre, fecha, duracion, doctorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Book", reflect.TypeOf((*MockAppointmentBooker)(nil).Book), pacienteID, nombre, fecha, duracion, doctorID)
+}
+
+// WithTx mocks base method.
+func (m *MockAppointmentBooker) WithTx(tx database.DBTX) waitlist.AppointmentBooker {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(waitlist.AppointmentBooker)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockAppointmentBookerMockRecorder) WithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockAppointmentBooker)(nil).WithTx), tx)
+}
+
+// MockReminderCreator is a mock of ReminderCreator interface.
+type MockReminderCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockReminderCreatorMockRecorder
+}
+
+// MockReminderCreatorMockRecorder is the mock recorder for MockReminderCreator.
+type MockReminderCreatorMockRecorder struct {
+	mock *MockReminderCreator
+}
+
+// NewMockReminderCreator creates a new mock instance.
+func NewMockReminderCreator(ctrl *gomock.Controller) *MockReminderCreator {
+	mock := &MockReminderCreator{ctrl: ctrl}
+	mock.recorder = &MockReminderCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReminderCreator) EXPECT() *MockReminderCreatorMockRecorder {
+	return m.recorder
+}
+
+// CreateGlobal mocks base method.
+func (m *MockReminderCreator) CreateGlobal(description string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGlobal", description)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGlobal indicates an expected call of CreateGlobal.
+func (mr *MockReminderCreatorMockRecorder) CreateGlobal(description interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGlobal", reflect.TypeOf((*MockReminderCreator)(nil).CreateGlobal), description)
+}
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockService) Create(dto *models.EntryCreateDTO) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", dto)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockServiceMockRecorder) Create(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), dto)
+}
+
+// Decline mocks base method.
+func (m *MockService) Decline(id int) (*models.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decline", id)
+	ret0, _ := ret[0].(*models.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decline indicates an expected call of Decline.
+func (mr *MockServiceMockRecorder) Decline(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decline", reflect.TypeOf((*MockService)(nil).Decline), id)
+}
+
+// FindMatches mocks base method.
+func (m *MockService) FindMatches(slot models.Slot) ([]models.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMatches", slot)
+	ret0, _ := ret[0].([]models.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMatches indicates an expected call of FindMatches.
+func (mr *MockServiceMockRecorder) FindMatches(slot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMatches", reflect.TypeOf((*MockService)(nil).FindMatches), slot)
+}
+
+// GetByID mocks base method.
+func (m *MockService) GetByID(id int) (*models.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockServiceMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockService)(nil).GetByID), id)
+}
+
+// List mocks base method.
+func (m *MockService) List(estado string) ([]models.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", estado)
+	ret0, _ := ret[0].([]models.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockServiceMockRecorder) List(estado interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockService)(nil).List), estado)
+}
+
+// OfferSlot mocks base method.
+func (m *MockService) OfferSlot(slot models.Slot) (*models.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OfferSlot", slot)
+	ret0, _ := ret[0].(*models.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OfferSlot indicates an expected call of OfferSlot.
+func (mr *MockServiceMockRecorder) OfferSlot(slot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OfferSlot", reflect.TypeOf((*MockService)(nil).OfferSlot), slot)
+}
+
+// Promote mocks base method.
+func (m *MockService) Promote(id int, dto *models.PromoteDTO) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Promote", id, dto)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Promote indicates an expected call of Promote.
+func (mr *MockServiceMockRecorder) Promote(id, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Promote", reflect.TypeOf((*MockService)(nil).Promote), id, dto)
+}
+
+// Remove mocks base method.
+func (m *MockService) Remove(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockServiceMockRecorder) Remove(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockService)(nil).Remove), id)
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Estados de una entrada de la lista de espera
+const (
+	StatusWaiting = "waiting" // en cola
+	StatusOffered = "offered" // se le ofreció un horario liberado, pendiente de respuesta
+	StatusBooked  = "booked"  // promovida a una cita real
+	StatusRemoved = "removed" // retirada de la lista
+)
+
+// IsValidStatus indica si el estado es uno de los estados conocidos
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusWaiting, StatusOffered, StatusBooked, StatusRemoved:
+		return true
+	}
+	return false
+}
+
+// IsActive indica si la entrada sigue esperando un horario
+func IsActive(status string) bool {
+	return status == StatusWaiting || status == StatusOffered
+}
+
+// Entry es una persona en la lista de espera.
+// Igual que en las citas, puede ser un paciente registrado o solo un nombre (walk-in).
+type Entry struct {
+	ID               int        `json:"id"`
+	PacienteID       *int       `json:"paciente_id,omitempty"`
+	Nombre           *string    `json:"nombre,omitempty"`
+	NombrePaciente   *string    `json:"nombre_paciente,omitempty"`
+	TelefonoPaciente *string    `json:"telefono_paciente,omitempty"`
+	DoctorID         *int       `json:"doctor_id,omitempty"`  // nil = cualquier doctor
+	Desde            time.Time  `json:"desde"`                // primer día aceptable
+	Hasta            time.Time  `json:"hasta"`                // último día aceptable (inclusive)
+	HoraDesde        *string    `json:"hora_desde,omitempty"` // "HH:MM", preferencia de horario
+	HoraHasta        *string    `json:"hora_hasta,omitempty"` // "HH:MM"
+	Duracion         int64      `json:"duracion"`             // segundos
+	Notas            *string    `json:"notas,omitempty"`
+	Estado           string     `json:"estado"`
+	OfertaFecha      *time.Time `json:"oferta_fecha,omitempty"` // horario ofrecido
+	OfertaDoctorID   *int       `json:"oferta_doctor_id,omitempty"`
+	CitaID           *int       `json:"cita_id,omitempty"` // cita creada al promover
+	FechaCreacion    time.Time  `json:"fecha_creacion"`
+}
+
+// DisplayName devuelve el nombre a mostrar: el del paciente o el del walk-in
+func (e *Entry) DisplayName() string {
+	if e.NombrePaciente != nil {
+		return *e.NombrePaciente
+	}
+	if e.Nombre != nil {
+		return *e.Nombre
+	}
+	return fmt.Sprintf("entrada #%d", e.ID)
+}
+
+// Matches indica si el horario liberado sirve para esta entrada: día dentro del rango,
+// duración suficiente, doctor compatible y dentro de la preferencia de horario.
+func (e *Entry) Matches(slot Slot) bool {
+	if e.Estado != StatusWaiting || slot.Duracion < e.Duracion {
+		return false
+	}
+	if e.DoctorID != nil && (slot.DoctorID == nil || *slot.DoctorID != *e.DoctorID) {
+		return false
+	}
+
+	day := time.Date(slot.Fecha.Year(), slot.Fecha.Month(), slot.Fecha.Day(), 0, 0, 0, 0, time.UTC)
+	desde := time.Date(e.Desde.Year(), e.Desde.Month(), e.Desde.Day(), 0, 0, 0, 0, time.UTC)
+	hasta := time.Date(e.Hasta.Year(), e.Hasta.Month(), e.Hasta.Day(), 0, 0, 0, 0, time.UTC)
+	if day.Before(desde) || day.After(hasta) {
+		return false
+	}
+
+	start := slot.Fecha.Hour()*60 + slot.Fecha.Minute()
+	end := start + int(e.Duracion/60)
+	if e.HoraDesde != nil {
+		if from, err := ParseClock(*e.HoraDesde); err == nil && start < from {
+			return false
+		}
+	}
+	if e.HoraHasta != nil {
+		if to, err := ParseClock(*e.HoraHasta); err == nil && end > to {
+			return false
+		}
+	}
+	return true
+}
+
+// Slot es un horario que quedó libre (cita cancelada o movida), en hora de la clínica
+type Slot struct {
+	Fecha    time.Time `json:"fecha"`
+	Duracion int64     `json:"duracion"`
+	DoctorID *int      `json:"doctor_id,omitempty"`
+}
+
+// EntryCreateDTO para agregar a alguien a la lista de espera
+type EntryCreateDTO struct {
+	PacienteID *int      `json:"paciente_id,omitempty"`
+	Nombre     *string   `json:"nombre,omitempty"`
+	DoctorID   *int      `json:"doctor_id,omitempty"`
+	Desde      time.Time `json:"desde" validate:"required"`
+	Hasta      time.Time `json:"hasta" validate:"required"`
+	HoraDesde  *string   `json:"hora_desde,omitempty"`
+	HoraHasta  *string   `json:"hora_hasta,omitempty"`
+	Duracion   int64     `json:"duracion" validate:"required,min=1"`
+	Notas      *string   `json:"notas,omitempty"`
+}
+
+// PromoteDTO convierte una entrada en una cita real.
+// Sin Fecha se usa el horario ofrecido; sin Duracion, la duración solicitada.
+type PromoteDTO struct {
+	Fecha    *time.Time `json:"fecha,omitempty"`
+	Duracion *int64     `json:"duracion,omitempty"`
+	DoctorID *int       `json:"doctor_id,omitempty"`
+}
+
+// ParseClock convierte "HH:MM" en minutos desde la medianoche
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// FormatClock convierte minutos desde la medianoche en "HH:MM"
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,214 @@
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
+package waitlist
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/timeutil"
+)
+
+type Repository interface {
+	GetByID(id int) (*models.Entry, error)
+	// GetForUpdate es GetByID bloqueando la fila de la entrada hasta el fin de la transacción
+	GetForUpdate(id int) (*models.Entry, error)
+	// List devuelve las entradas en el estado indicado; estado vacío devuelve las activas
+	List(estado string) ([]models.Entry, error)
+	// GetWaitingForDay devuelve las entradas en espera cuyo rango incluye el día, por orden de llegada
+	GetWaitingForDay(day time.Time) ([]models.Entry, error)
+	Create(entry *models.EntryCreateDTO) (int, error)
+
+	// Transiciones: fallan con conflicto si la entrada ya no está en el estado esperado
+	MarkOffered(id int, slot models.Slot) error
+	MarkWaiting(id int) error
+	MarkBooked(id int, citaID int) error
+	MarkRemoved(id int) error
+
+	// ReassignPatient pasa las entradas de un paciente a otro (fusión de duplicados)
+	ReassignPatient(fromID, toID int) error
+
+	// WithTx devuelve una copia del repositorio que ejecuta sus consultas en tx
+	WithTx(tx database.DBTX) Repository
+}
+
+type repository struct {
+	db database.DBTX
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) WithTx(tx database.DBTX) Repository {
+	return &repository{db: tx}
+}
+
+const selectEntry = `
+	SELECT w.id, w.paciente_id, w.nombre, p.nombre, p.telefono, w.doctor_id, w.desde, w.hasta,
+		   w.minuto_desde, w.minuto_hasta, w.duracion, w.notas, w.estado,
+		   w.oferta_fecha, w.oferta_doctor_id, w.cita_id, w.fecha_creacion
+	FROM lista_espera w
+	LEFT JOIN pacientes p ON w.paciente_id = p.id
+`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEntry(row scanner) (*models.Entry, error) {
+	var e models.Entry
+	var minDesde, minHasta sql.NullInt64
+	if err := row.Scan(
+		&e.ID, &e.PacienteID, &e.Nombre, &e.NombrePaciente, &e.TelefonoPaciente, &e.DoctorID, &e.Desde, &e.Hasta,
+		&minDesde, &minHasta, &e.Duracion, &e.Notas, &e.Estado,
+		&e.OfertaFecha, &e.OfertaDoctorID, &e.CitaID, &e.FechaCreacion,
+	); err != nil {
+		return nil, err
+	}
+	if minDesde.Valid {
+		h := models.FormatClock(int(minDesde.Int64))
+		e.HoraDesde = &h
+	}
+	if minHasta.Valid {
+		h := models.FormatClock(int(minHasta.Int64))
+		e.HoraHasta = &h
+	}
+	if e.OfertaFecha != nil {
+		f := timeutil.NormalizeToClinic(*e.OfertaFecha)
+		e.OfertaFecha = &f
+	}
+	return &e, nil
+}
+
+func (r *repository) GetByID(id int) (*models.Entry, error) {
+	e, err := scanEntry(r.db.QueryRow(selectEntry+` WHERE w.id = $1`, id))
+	if err != nil {
+		return nil, database.MapSQLError(err, "WaitlistRepository.GetByID")
+	}
+	return e, nil
+}
+
+func (r *repository) GetForUpdate(id int) (*models.Entry, error) {
+	e, err := scanEntry(r.db.QueryRow(selectEntry+` WHERE w.id = $1 FOR UPDATE OF w`, id))
+	if err != nil {
+		return nil, database.MapSQLError(err, "WaitlistRepository.GetForUpdate")
+	}
+	return e, nil
+}
+
+func (r *repository) List(estado string) ([]models.Entry, error) {
+	query := selectEntry + ` WHERE w.estado IN ('waiting', 'offered') ORDER BY w.fecha_creacion, w.id`
+	args := []interface{}{}
+	if estado != "" {
+		query = selectEntry + ` WHERE w.estado = $1 ORDER BY w.fecha_creacion, w.id`
+		args = append(args, estado)
+	}
+	return r.query("WaitlistRepository.List", query, args...)
+}
+
+func (r *repository) GetWaitingForDay(day time.Time) ([]models.Entry, error) {
+	return r.query("WaitlistRepository.GetWaitingForDay", selectEntry+`
+		WHERE w.estado = 'waiting' AND w.desde <= $1::date AND w.hasta >= $1::date
+		ORDER BY w.fecha_creacion, w.id
+	`, timeutil.NormalizeToClinic(day).Format("2006-01-02"))
+}
+
+func (r *repository) query(context, query string, args ...interface{}) ([]models.Entry, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, database.MapSQLError(err, context)
+	}
+	defer rows.Close()
+
+	entries := []models.Entry{}
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, appErr.Wrap(context+"(scan)", appErr.ErrInternal, err)
+		}
+		entries = append(entries, *e)
+	}
+	return entries, nil
+}
+
+func (r *repository) Create(entry *models.EntryCreateDTO) (int, error) {
+	var minDesde, minHasta *int
+	if entry.HoraDesde != nil {
+		m, err := models.ParseClock(*entry.HoraDesde)
+		if err != nil {
+			return 0, appErr.Wrap("WaitlistRepository.Create(hora_desde)", appErr.ErrInvalidInput, err)
+		}
+		minDesde = &m
+	}
+	if entry.HoraHasta != nil {
+		m, err := models.ParseClock(*entry.HoraHasta)
+		if err != nil {
+			return 0, appErr.Wrap("WaitlistRepository.Create(hora_hasta)", appErr.ErrInvalidInput, err)
+		}
+		minHasta = &m
+	}
+
+	var id int
+	err := r.db.QueryRow(`
+		INSERT INTO lista_espera (paciente_id, nombre, doctor_id, desde, hasta, minuto_desde, minuto_hasta, duracion, notas)
+		VALUES ($1, $2, $3, $4::date, $5::date, $6, $7, $8, $9)
+		RETURNING id
+	`, entry.PacienteID, entry.Nombre, entry.DoctorID,
+		entry.Desde.Format("2006-01-02"), entry.Hasta.Format("2006-01-02"),
+		minDesde, minHasta, entry.Duracion, entry.Notas).Scan(&id)
+	if err != nil {
+		return 0, database.MapSQLError(err, "WaitlistRepository.Create")
+	}
+	return id, nil
+}
+
+func (r *repository) MarkOffered(id int, slot models.Slot) error {
+	return r.transition("WaitlistRepository.MarkOffered", `
+		UPDATE lista_espera SET estado = 'offered', oferta_fecha = $2, oferta_doctor_id = $3
+		WHERE id = $1 AND estado = 'waiting'
+	`, id, slot.Fecha, slot.DoctorID)
+}
+
+func (r *repository) MarkWaiting(id int) error {
+	return r.transition("WaitlistRepository.MarkWaiting", `
+		UPDATE lista_espera SET estado = 'waiting', oferta_fecha = NULL, oferta_doctor_id = NULL
+		WHERE id = $1 AND estado = 'offered'
+	`, id)
+}
+
+func (r *repository) MarkBooked(id int, citaID int) error {
+	return r.transition("WaitlistRepository.MarkBooked", `
+		UPDATE lista_espera SET estado = 'booked', cita_id = $2
+		WHERE id = $1 AND estado IN ('waiting', 'offered')
+	`, id, citaID)
+}
+
+func (r *repository) MarkRemoved(id int) error {
+	return r.transition("WaitlistRepository.MarkRemoved", `
+		UPDATE lista_espera SET estado = 'removed'
+		WHERE id = $1 AND estado IN ('waiting', 'offered')
+	`, id)
+}
+
+func (r *repository) ReassignPatient(fromID, toID int) error {
+	if _, err := r.db.Exec(`UPDATE lista_espera SET paciente_id = $2 WHERE paciente_id = $1`, fromID, toID); err != nil {
+		return database.MapSQLError(err, "WaitlistRepository.ReassignPatient")
+	}
+	return nil
+}
+
+func (r *repository) transition(context, query string, args ...interface{}) error {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return database.MapSQLError(err, context)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return appErr.NewDomainError(appErr.ErrConflict, "La entrada de la lista de espera fue modificada por otro usuario, intente de nuevo")
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+//go:generate mockgen -source=service.go -destination=mocks/service.go -package=mocks
+
+package waitlist
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/timeutil"
+	"github.com/tonitomc/healthcare-crm-api/pkg/validator"
+)
+
+// AppointmentBooker crea la cita real al promover una entrada (vía el servicio de citas,
+// con todas sus validaciones de horario y traslapes)
+type AppointmentBooker interface {
+	Book(pacienteID *int, nombre *string, fecha time.Time, duracion int64, doctorID *int) (int, error)
+	// WithTx devuelve un agendador cuyas escrituras participan en la transacción tx
+	WithTx(tx database.DBTX) AppointmentBooker
+}
+
+// ReminderCreator avisa a recepción que hay un horario para ofrecer
+type ReminderCreator interface {
+	CreateGlobal(description string) error
+}
+
+type Service interface {
+	GetByID(id int) (*models.Entry, error)
+	List(estado string) ([]models.Entry, error)
+	Create(dto *models.EntryCreateDTO) (int, error)
+	Remove(id int) error
+
+	// FindMatches lista las entradas en espera a las que les sirve el horario, por orden de llegada
+	FindMatches(slot models.Slot) ([]models.Entry, error)
+	// OfferSlot ofrece el horario a la primera entrada compatible y crea un recordatorio.
+	// Devuelve nil si nadie en la lista puede tomarlo.
+	OfferSlot(slot models.Slot) (*models.Entry, error)
+	// Decline devuelve la entrada a la cola y ofrece su horario a la siguiente
+	Decline(id int) (*models.Entry, error)
+	// Promote crea la cita y marca la entrada como agendada, en una sola transacción
+	Promote(id int, dto *models.PromoteDTO) (int, error)
+}
+
+type service struct {
+	repo      Repository
+	booker    AppointmentBooker
+	reminders ReminderCreator
+	uow       database.UnitOfWork
+}
+
+func NewService(repo Repository, booker AppointmentBooker, reminders ReminderCreator, uow database.UnitOfWork) Service {
+	return &service{repo: repo, booker: booker, reminders: reminders, uow: uow}
+}
+
+func (s *service) GetByID(id int) (*models.Entry, error) {
+	if id <= 0 {
+		return nil, appErr.Wrap("WaitlistService.GetByID", appErr.ErrInvalidInput, nil)
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *service) List(estado string) ([]models.Entry, error) {
+	if estado != "" && !models.IsValidStatus(estado) {
+		return nil, appErr.Wrap("WaitlistService.List(invalid estado)", appErr.ErrInvalidInput, nil)
+	}
+	return s.repo.List(estado)
+}
+
+func (s *service) Create(dto *models.EntryCreateDTO) (int, error) {
+	if dto.PacienteID == nil && dto.Nombre == nil {
+		return 0, appErr.Wrap("WaitlistService.Create(must provide paciente_id or nombre)", appErr.ErrInvalidInput, nil)
+	}
+	if err := validator.Struct(dto); err != nil {
+		return 0, err
+	}
+
+	dto.Desde = timeutil.StartOfClinicDay(dto.Desde)
+	dto.Hasta = timeutil.StartOfClinicDay(dto.Hasta)
+	if dto.Hasta.Before(dto.Desde) {
+		return 0, appErr.NewDomainError(appErr.ErrInvalidInput, "El rango de fechas es inválido")
+	}
+
+	from, to := 0, 24*60
+	var err error
+	if dto.HoraDesde != nil {
+		if from, err = models.ParseClock(*dto.HoraDesde); err != nil {
+			return 0, appErr.NewDomainError(appErr.ErrInvalidInput, "La hora preferida debe tener formato HH:MM")
+		}
+	}
+	if dto.HoraHasta != nil {
+		if to, err = models.ParseClock(*dto.HoraHasta); err != nil {
+			return 0, appErr.NewDomainError(appErr.ErrInvalidInput, "La hora preferida debe tener formato HH:MM")
+		}
+	}
+	if to <= from {
+		return 0, appErr.NewDomainError(appErr.ErrInvalidInput, "El rango de horas preferido es inválido")
+	}
+
+	return s.repo.Create(dto)
+}
+
+func (s *service) Remove(id int) error {
+	if id <= 0 {
+		return appErr.Wrap("WaitlistService.Remove", appErr.ErrInvalidInput, nil)
+	}
+	if _, err := s.repo.GetByID(id); err != nil {
+		return err
+	}
+	return s.repo.MarkRemoved(id)
+}
+
+func (s *service) FindMatches(slot models.Slot) ([]models.Entry, error) {
+	if slot.Duracion <= 0 {
+		return nil, appErr.Wrap("WaitlistService.FindMatches(duracion must be > 0)", appErr.ErrInvalidInput, nil)
+	}
+	slot.Fecha = timeutil.NormalizeToClinic(slot.Fecha)
+
+	candidates, err := s.repo.GetWaitingForDay(slot.Fecha)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []models.Entry{}
+	for _, e := range candidates {
+		if e.Matches(slot) {
+			matches = append(matches, e)
+		}
+	}
+	return matches, nil
+}
+
+func (s *service) OfferSlot(slot models.Slot) (*models.Entry, error) {
+	return s.offer(slot, 0)
+}
+
+// offer ofrece el horario a la primera entrada compatible distinta de skipID.
+func (s *service) offer(slot models.Slot, skipID int) (*models.Entry, error) {
+	slot.Fecha = timeutil.NormalizeToClinic(slot.Fecha)
+	if !slot.Fecha.After(time.Now()) {
+		return nil, nil // un horario que ya pasó no se ofrece
+	}
+
+	matches, err := s.FindMatches(slot)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range matches {
+		e := &matches[i]
+		if e.ID == skipID {
+			continue
+		}
+		if err := s.repo.MarkOffered(e.ID, slot); err != nil {
+			if appErr.IsDomainError(err) {
+				continue // otra oferta la tomó primero; probar con la siguiente
+			}
+			return nil, err
+		}
+
+		desc := fmt.Sprintf("Lista de espera: horario libre el %s para %s (entrada #%d). Ofrecer y confirmar.",
+			slot.Fecha.Format("02/01/2006 15:04"), e.DisplayName(), e.ID)
+		if e.TelefonoPaciente != nil {
+			desc += fmt.Sprintf(" Tel: %s.", *e.TelefonoPaciente)
+		}
+		if err := s.reminders.CreateGlobal(desc); err != nil {
+			return nil, err
+		}
+
+		e.Estado = models.StatusOffered
+		e.OfertaFecha = &slot.Fecha
+		e.OfertaDoctorID = slot.DoctorID
+		return e, nil
+	}
+	return nil, nil
+}
+
+func (s *service) Decline(id int) (*models.Entry, error) {
+	if id <= 0 {
+		return nil, appErr.Wrap("WaitlistService.Decline", appErr.ErrInvalidInput, nil)
+	}
+	entry, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Estado != models.StatusOffered || entry.OfertaFecha == nil {
+		return nil, appErr.NewDomainError(appErr.ErrConflict, "La entrada no tiene un horario ofrecido")
+	}
+
+	if err := s.repo.MarkWaiting(id); err != nil {
+		return nil, err
+	}
+
+	return s.offer(models.Slot{
+		Fecha:    *entry.OfertaFecha,
+		Duracion: entry.Duracion,
+		DoctorID: entry.OfertaDoctorID,
+	}, id)
+}
+
+func (s *service) Promote(id int, dto *models.PromoteDTO) (int, error) {
+	if id <= 0 {
+		return 0, appErr.Wrap("WaitlistService.Promote", appErr.ErrInvalidInput, nil)
+	}
+
+	// La entrada queda bloqueada mientras se agenda: un segundo Promote espera y la ve
+	// ya agendada, y si algo falla no queda una cita sin su entrada
+	var citaID int
+	err := s.uow.Do(func(tx database.DBTX) error {
+		repo := s.repo.WithTx(tx)
+		entry, err := repo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if citaID, err = s.book(tx, entry, dto); err != nil {
+			return err
+		}
+		return repo.MarkBooked(id, citaID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return citaID, nil
+}
+
+// book crea en tx la cita de una entrada bloqueada, con los datos del DTO o los de la oferta
+func (s *service) book(tx database.DBTX, entry *models.Entry, dto *models.PromoteDTO) (int, error) {
+	if !models.IsActive(entry.Estado) {
+		return 0, appErr.NewDomainError(appErr.ErrConflict, "La entrada ya no está en la lista de espera")
+	}
+
+	fecha := entry.OfertaFecha
+	if dto.Fecha != nil {
+		fecha = dto.Fecha
+	}
+	if fecha == nil {
+		return 0, appErr.NewDomainError(appErr.ErrInvalidInput, "Debe indicar la fecha de la cita")
+	}
+	duracion := entry.Duracion
+	if dto.Duracion != nil {
+		duracion = *dto.Duracion
+	}
+	doctorID := entry.DoctorID
+	if entry.OfertaDoctorID != nil && dto.Fecha == nil {
+		doctorID = entry.OfertaDoctorID
+	}
+	if dto.DoctorID != nil {
+		doctorID = dto.DoctorID
+	}
+
+	return s.booker.WithTx(tx).Book(entry.PacienteID, entry.Nombre, *fecha, duracion, doctorID)
+}
@@ -0,0 +1,237 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tonitomc/healthcare-crm-api/internal/database"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist/mocks"
+	"github.com/tonitomc/healthcare-crm-api/internal/domain/waitlist/models"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+	"github.com/tonitomc/healthcare-crm-api/pkg/timeutil"
+)
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+type fixture struct {
+	repo      *mocks.MockRepository
+	booker    *mocks.MockAppointmentBooker
+	reminders *mocks.MockReminderCreator
+	uow       *fakeUnitOfWork
+	svc       waitlist.Service
+	ctrl      *gomock.Controller
+}
+
+func setup(t *testing.T) fixture {
+	ctrl := gomock.NewController(t)
+	f := fixture{
+		repo:      mocks.NewMockRepository(ctrl),
+		booker:    mocks.NewMockAppointmentBooker(ctrl),
+		reminders: mocks.NewMockReminderCreator(ctrl),
+		uow:       &fakeUnitOfWork{},
+		ctrl:      ctrl,
+	}
+	f.svc = waitlist.NewService(f.repo, f.booker, f.reminders, f.uow)
+	f.repo.EXPECT().WithTx(gomock.Any()).Return(f.repo).AnyTimes()
+	f.booker.EXPECT().WithTx(gomock.Any()).Return(f.booker).AnyTimes()
+	return f
+}
+
+// fakeUnitOfWork runs fn without a database and records how it ended.
+type fakeUnitOfWork struct {
+	committed  bool
+	rolledBack bool
+}
+
+func (u *fakeUnitOfWork) Do(fn func(tx database.DBTX) error) error {
+	if err := fn(nil); err != nil {
+		u.rolledBack = true
+		return err
+	}
+	u.committed = true
+	return nil
+}
+
+func intPtr(v int) *int { return &v }
+
+func strPtr(v string) *string { return &v }
+
+// futureSlot returns a 09:00 clinic-time slot a few days ahead.
+func futureSlot(duracion int64) models.Slot {
+	day := timeutil.StartOfClinicDay(time.Now().AddDate(0, 0, 3))
+	return models.Slot{Fecha: day.Add(9 * time.Hour), Duracion: duracion}
+}
+
+func entry(id int, slot models.Slot) models.Entry {
+	day := timeutil.StartOfClinicDay(slot.Fecha)
+	return models.Entry{
+		ID:       id,
+		Nombre:   strPtr("Walk-in"),
+		Desde:    day.AddDate(0, 0, -1),
+		Hasta:    day.AddDate(0, 0, 1),
+		Duracion: 1800,
+		Estado:   models.StatusWaiting,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Matching
+// -----------------------------------------------------------------------------
+
+func TestEntry_Matches(t *testing.T) {
+	t.Parallel()
+
+	slot := futureSlot(1800)
+
+	e := entry(1, slot)
+	require.True(t, e.Matches(slot))
+
+	e.Duracion = 3600
+	require.False(t, e.Matches(slot), "slot too short")
+
+	e = entry(1, slot)
+	e.HoraDesde = strPtr("10:00")
+	require.False(t, e.Matches(slot), "before preferred window")
+
+	e = entry(1, slot)
+	e.HoraHasta = strPtr("09:15")
+	require.False(t, e.Matches(slot), "ends after preferred window")
+
+	e = entry(1, slot)
+	e.DoctorID = intPtr(7)
+	require.False(t, e.Matches(slot), "wants a specific doctor")
+	slot.DoctorID = intPtr(7)
+	require.True(t, e.Matches(slot))
+
+	e = entry(1, slot)
+	e.Hasta = e.Desde
+	require.False(t, e.Matches(slot), "outside date range")
+}
+
+// -----------------------------------------------------------------------------
+// OfferSlot
+// -----------------------------------------------------------------------------
+
+func TestService_OfferSlot(t *testing.T) {
+	t.Parallel()
+
+	t.Run("offers to the first matching entry and reminds the front desk", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		slot := futureSlot(1800)
+		picky := entry(1, slot)
+		picky.HoraDesde = strPtr("14:00")
+		first := entry(2, slot)
+		second := entry(3, slot)
+
+		f.repo.EXPECT().GetWaitingForDay(gomock.Any()).Return([]models.Entry{picky, first, second}, nil)
+		f.repo.EXPECT().MarkOffered(2, gomock.Any()).Return(nil)
+		f.reminders.EXPECT().CreateGlobal(gomock.Any()).DoAndReturn(func(desc string) error {
+			require.Contains(t, desc, "Walk-in")
+			require.Contains(t, desc, "#2")
+			return nil
+		})
+
+		offered, err := f.svc.OfferSlot(slot)
+		require.NoError(t, err)
+		require.Equal(t, 2, offered.ID)
+		require.Equal(t, models.StatusOffered, offered.Estado)
+	})
+
+	t.Run("past slots are not offered", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		offered, err := f.svc.OfferSlot(models.Slot{Fecha: time.Now().Add(-time.Hour), Duracion: 1800})
+		require.NoError(t, err)
+		require.Nil(t, offered)
+	})
+
+	t.Run("nobody matches", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		f.repo.EXPECT().GetWaitingForDay(gomock.Any()).Return(nil, nil)
+
+		offered, err := f.svc.OfferSlot(futureSlot(1800))
+		require.NoError(t, err)
+		require.Nil(t, offered)
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Promote
+// -----------------------------------------------------------------------------
+
+func TestService_Promote(t *testing.T) {
+	t.Parallel()
+
+	t.Run("books the offered slot", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		slot := futureSlot(1800)
+		e := entry(2, slot)
+		e.Estado = models.StatusOffered
+		e.OfertaFecha = &slot.Fecha
+		e.OfertaDoctorID = intPtr(7)
+
+		f.repo.EXPECT().GetForUpdate(2).Return(&e, nil)
+		f.booker.EXPECT().Book(nil, e.Nombre, slot.Fecha, int64(1800), intPtr(7)).Return(40, nil)
+		f.repo.EXPECT().MarkBooked(2, 40).Return(nil)
+
+		citaID, err := f.svc.Promote(2, &models.PromoteDTO{})
+		require.NoError(t, err)
+		require.Equal(t, 40, citaID)
+		require.True(t, f.uow.committed)
+	})
+
+	t.Run("a failed mark rolls back the appointment", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		slot := futureSlot(1800)
+		e := entry(2, slot)
+		e.Estado = models.StatusOffered
+		e.OfertaFecha = &slot.Fecha
+
+		f.repo.EXPECT().GetForUpdate(2).Return(&e, nil)
+		f.booker.EXPECT().Book(nil, e.Nombre, slot.Fecha, int64(1800), nil).Return(40, nil)
+		f.repo.EXPECT().MarkBooked(2, 40).Return(appErr.NewDomainError(appErr.ErrConflict, "La entrada ya no está en la lista de espera"))
+
+		_, err := f.svc.Promote(2, &models.PromoteDTO{})
+		require.True(t, appErr.IsDomainError(err))
+		require.True(t, f.uow.rolledBack)
+		require.False(t, f.uow.committed)
+	})
+
+	t.Run("waiting entry needs a date", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		e := entry(2, futureSlot(1800))
+		f.repo.EXPECT().GetForUpdate(2).Return(&e, nil)
+
+		_, err := f.svc.Promote(2, &models.PromoteDTO{})
+		require.True(t, appErr.IsDomainError(err))
+	})
+
+	t.Run("booked entries cannot be promoted again", func(t *testing.T) {
+		f := setup(t)
+		defer f.ctrl.Finish()
+
+		e := entry(2, futureSlot(1800))
+		e.Estado = models.StatusBooked
+		f.repo.EXPECT().GetForUpdate(2).Return(&e, nil)
+
+		_, err := f.svc.Promote(2, &models.PromoteDTO{})
+		require.True(t, appErr.IsDomainError(err))
+	})
+}
@@ -0,0 +1,18 @@
+-- Series de citas recurrentes (semanal, cada N semanas o mensual)
+CREATE TABLE IF NOT EXISTS series_citas (
+    id           SERIAL PRIMARY KEY,
+    paciente_id  INT REFERENCES pacientes(id) ON DELETE CASCADE,
+    nombre       TEXT,
+    frecuencia   TEXT        NOT NULL CHECK (frecuencia IN ('weekly', 'monthly')),
+    intervalo    INT         NOT NULL DEFAULT 1 CHECK (intervalo > 0),
+    fecha_inicio TIMESTAMPTZ NOT NULL,
+    duracion     BIGINT      NOT NULL CHECK (duracion > 0),
+    hasta        TIMESTAMPTZ,
+    ocurrencias  INT,
+    CHECK (hasta IS NOT NULL OR ocurrencias IS NOT NULL)
+);
+
+ALTER TABLE citas
+    ADD COLUMN IF NOT EXISTS serie_id INT REFERENCES series_citas(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_citas_serie_id ON citas (serie_id);
@@ -0,0 +1,19 @@
+-- Ciclo de vida de las citas: las citas ya no se eliminan, cambian de estado
+ALTER TABLE citas
+    ADD COLUMN IF NOT EXISTS estado TEXT NOT NULL DEFAULT 'scheduled'
+        CHECK (estado IN ('scheduled', 'confirmed', 'checked-in', 'in-progress', 'completed', 'cancelled', 'no-show'));
+
+CREATE INDEX IF NOT EXISTS idx_citas_fecha_estado ON citas (fecha, estado);
+
+-- Historial de cambios de estado (quién, cuándo y por qué)
+CREATE TABLE IF NOT EXISTS citas_historial_estados (
+    id              SERIAL PRIMARY KEY,
+    cita_id         INT         NOT NULL REFERENCES citas(id) ON DELETE CASCADE,
+    estado_anterior TEXT        NOT NULL,
+    estado_nuevo    TEXT        NOT NULL,
+    usuario_id      INT         REFERENCES usuarios(id) ON DELETE SET NULL,
+    motivo          TEXT,
+    fecha           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_citas_historial_cita ON citas_historial_estados (cita_id, fecha);
@@ -0,0 +1,18 @@
+-- Agenda por proveedor: las citas y los horarios pueden pertenecer a un doctor (usuario con rol doctor).
+-- doctor_id NULL conserva el comportamiento anterior (agenda general de la clínica).
+ALTER TABLE citas
+    ADD COLUMN IF NOT EXISTS doctor_id INT REFERENCES usuarios(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_citas_doctor_fecha ON citas (doctor_id, fecha);
+
+ALTER TABLE series_citas
+    ADD COLUMN IF NOT EXISTS doctor_id INT REFERENCES usuarios(id) ON DELETE SET NULL;
+
+ALTER TABLE horarios_laborales
+    ADD COLUMN IF NOT EXISTS doctor_id INT REFERENCES usuarios(id) ON DELETE CASCADE;
+
+ALTER TABLE horarios_especiales
+    ADD COLUMN IF NOT EXISTS doctor_id INT REFERENCES usuarios(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_horarios_laborales_doctor ON horarios_laborales (doctor_id, dia_semana);
+CREATE INDEX IF NOT EXISTS idx_horarios_especiales_doctor ON horarios_especiales (doctor_id, fecha);
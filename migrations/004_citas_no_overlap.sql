@@ -0,0 +1,25 @@
+-- Evita reservas dobles a nivel de base de datos: dos citas activas del mismo doctor
+-- (o de la agenda general, doctor_id NULL) no pueden traslaparse en el tiempo.
+-- El servicio ya serializa las reservas con un advisory lock por día y doctor; esta
+-- restricción es la última barrera si otra ruta de escritura se salta ese candado.
+--
+-- Antes de aplicar, resolver cualquier traslape existente entre citas activas, o la
+-- creación de la restricción fallará.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Rango [fecha, fecha + duracion) de una cita. Sumar segundos a un TIMESTAMPTZ no
+-- depende de la zona horaria, así que la función es segura como IMMUTABLE.
+CREATE OR REPLACE FUNCTION cita_rango(inicio TIMESTAMPTZ, duracion BIGINT)
+RETURNS TSTZRANGE
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$
+    SELECT tstzrange(inicio, inicio + duracion * INTERVAL '1 second', '[)')
+$$;
+
+ALTER TABLE citas DROP CONSTRAINT IF EXISTS citas_sin_traslape;
+ALTER TABLE citas
+    ADD CONSTRAINT citas_sin_traslape
+    EXCLUDE USING gist (
+        COALESCE(doctor_id, 0) WITH =,
+        cita_rango(fecha, duracion) WITH &&
+    ) WHERE (estado NOT IN ('cancelled', 'no-show'));
@@ -0,0 +1,25 @@
+-- Lista de espera para días sin horarios disponibles.
+-- Cuando una cita se cancela o se mueve, el horario liberado se ofrece a la primera
+-- entrada compatible (orden de llegada) y se crea un recordatorio para recepción.
+CREATE TABLE IF NOT EXISTS lista_espera (
+    id               SERIAL PRIMARY KEY,
+    paciente_id      INT REFERENCES pacientes(id) ON DELETE CASCADE,
+    nombre           TEXT,
+    doctor_id        INT REFERENCES usuarios(id) ON DELETE SET NULL,
+    desde            DATE        NOT NULL,
+    hasta            DATE        NOT NULL,
+    minuto_desde     INT CHECK (minuto_desde BETWEEN 0 AND 1440), -- preferencia de horario, minutos desde medianoche
+    minuto_hasta     INT CHECK (minuto_hasta BETWEEN 0 AND 1440),
+    duracion         BIGINT      NOT NULL CHECK (duracion > 0),
+    notas            TEXT,
+    estado           TEXT        NOT NULL DEFAULT 'waiting'
+        CHECK (estado IN ('waiting', 'offered', 'booked', 'removed')),
+    oferta_fecha     TIMESTAMPTZ,
+    oferta_doctor_id INT REFERENCES usuarios(id) ON DELETE SET NULL,
+    cita_id          INT REFERENCES citas(id) ON DELETE SET NULL,
+    fecha_creacion   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (paciente_id IS NOT NULL OR nombre IS NOT NULL),
+    CHECK (hasta >= desde)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lista_espera_activa ON lista_espera (estado, desde, hasta);
@@ -0,0 +1,41 @@
+-- Catálogo de tipos de cita (primera visita, seguimiento, solo examen, ...) y de los
+-- recursos (cuartos y equipos) que cada tipo ocupa. Una cita sin tipo conserva el
+-- comportamiento anterior: duración libre y sin recursos.
+CREATE TABLE IF NOT EXISTS recursos (
+    id     SERIAL PRIMARY KEY,
+    nombre TEXT    NOT NULL UNIQUE,
+    tipo   TEXT    NOT NULL CHECK (tipo IN ('room', 'equipment')),
+    activo BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE IF NOT EXISTS tipos_cita (
+    id              SERIAL PRIMARY KEY,
+    nombre          TEXT       NOT NULL UNIQUE,
+    duracion        BIGINT     NOT NULL CHECK (duracion > 0), -- segundos
+    color           VARCHAR(7) NOT NULL DEFAULT '#3B82F6',
+    cuestionario_id INT REFERENCES cuestionarios(id) ON DELETE SET NULL,
+    activo          BOOLEAN    NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE IF NOT EXISTS tipos_cita_recursos (
+    tipo_id    INT NOT NULL REFERENCES tipos_cita(id) ON DELETE CASCADE,
+    recurso_id INT NOT NULL REFERENCES recursos(id) ON DELETE CASCADE,
+    PRIMARY KEY (tipo_id, recurso_id)
+);
+
+ALTER TABLE citas
+    ADD COLUMN IF NOT EXISTS tipo_id INT REFERENCES tipos_cita(id) ON DELETE SET NULL;
+
+ALTER TABLE series_citas
+    ADD COLUMN IF NOT EXISTS tipo_id INT REFERENCES tipos_cita(id) ON DELETE SET NULL;
+
+-- Recursos ocupados por cada cita. Se copian del tipo al agendar, así cambiar el
+-- catálogo no altera las citas ya reservadas. El servicio serializa las reservas de
+-- un mismo recurso con un advisory lock por día y recurso.
+CREATE TABLE IF NOT EXISTS citas_recursos (
+    cita_id    INT NOT NULL REFERENCES citas(id) ON DELETE CASCADE,
+    recurso_id INT NOT NULL REFERENCES recursos(id) ON DELETE RESTRICT,
+    PRIMARY KEY (cita_id, recurso_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_citas_recursos_recurso ON citas_recursos (recurso_id);
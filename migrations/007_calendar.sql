@@ -0,0 +1,43 @@
+-- Calendario iCalendar: feed por usuario con token revocable e importación de citas.
+
+-- secuencia y fecha_modificacion alimentan SEQUENCE y LAST-MODIFIED de cada VEVENT para
+-- que los clientes de calendario detecten reprogramaciones y cancelaciones. Un trigger
+-- las mantiene sin importar qué ruta de escritura modifique la cita.
+ALTER TABLE citas
+    ADD COLUMN IF NOT EXISTS secuencia          INT         NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fecha_modificacion TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS uid_externo        TEXT; -- UID del evento importado
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_citas_uid_externo ON citas (uid_externo) WHERE uid_externo IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION citas_marcar_modificacion()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF NEW.fecha IS DISTINCT FROM OLD.fecha
+        OR NEW.duracion IS DISTINCT FROM OLD.duracion
+        OR NEW.estado IS DISTINCT FROM OLD.estado
+        OR NEW.doctor_id IS DISTINCT FROM OLD.doctor_id
+        OR NEW.tipo_id IS DISTINCT FROM OLD.tipo_id
+        OR NEW.nombre IS DISTINCT FROM OLD.nombre
+        OR NEW.paciente_id IS DISTINCT FROM OLD.paciente_id THEN
+        NEW.secuencia := OLD.secuencia + 1;
+        NEW.fecha_modificacion := NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS trg_citas_modificacion ON citas;
+CREATE TRIGGER trg_citas_modificacion
+    BEFORE UPDATE ON citas
+    FOR EACH ROW EXECUTE FUNCTION citas_marcar_modificacion();
+
+-- Un token de feed por usuario. Solo se guarda el hash SHA-256; generar uno nuevo
+-- reemplaza (revoca) el anterior.
+CREATE TABLE IF NOT EXISTS calendario_tokens (
+    usuario_id     INT PRIMARY KEY REFERENCES usuarios(id) ON DELETE CASCADE,
+    token_hash     TEXT        NOT NULL UNIQUE,
+    fecha_creacion TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,6 @@
+-- Check-in de citas: la consulta creada al registrar la llegada del paciente guarda
+-- la cita de origen. Una cita genera a lo sumo una consulta.
+ALTER TABLE consultas
+    ADD COLUMN IF NOT EXISTS cita_id INT REFERENCES citas(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_consultas_cita ON consultas (cita_id) WHERE cita_id IS NOT NULL;
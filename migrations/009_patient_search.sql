@@ -0,0 +1,29 @@
+-- Búsqueda de pacientes: nombre con tolerancia a errores de escritura (trigramas),
+-- teléfono por dígitos sin importar guiones o espacios y fecha de nacimiento.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent() es STABLE porque depende del diccionario configurado; fijar el
+-- diccionario permite declararla IMMUTABLE y usarla en índices.
+CREATE OR REPLACE FUNCTION paciente_nombre_busqueda(nombre TEXT)
+RETURNS TEXT
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$
+    SELECT lower(public.unaccent('public.unaccent'::regdictionary, nombre))
+$$;
+
+-- Solo los dígitos del teléfono: "5555-1234" y "5555 1234" se buscan igual.
+CREATE OR REPLACE FUNCTION paciente_telefono_digitos(telefono TEXT)
+RETURNS TEXT
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$
+    SELECT regexp_replace(COALESCE(telefono, ''), '\D', '', 'g')
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_pacientes_nombre_trgm
+    ON pacientes USING gin (paciente_nombre_busqueda(nombre) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_pacientes_telefono_trgm
+    ON pacientes USING gin (paciente_telefono_digitos(telefono) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_pacientes_fecha_nacimiento ON pacientes (fecha_nacimiento);
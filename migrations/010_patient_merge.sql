@@ -0,0 +1,17 @@
+-- Fusión de pacientes duplicados: los datos del paciente fusionado pasan al que se
+-- conserva y el fusionado se elimina. Cada fusión queda registrada con una copia de
+-- los datos del paciente eliminado.
+CREATE TABLE IF NOT EXISTS pacientes_fusiones (
+    id                      SERIAL PRIMARY KEY,
+    paciente_id             INT REFERENCES pacientes(id) ON DELETE SET NULL, -- el que se conserva
+    fusionado_id            INT         NOT NULL,                            -- ya no existe en pacientes
+    fusionado_nombre        TEXT        NOT NULL,
+    fusionado_nacimiento    DATE,
+    fusionado_telefono      TEXT,
+    fusionado_sexo          TEXT,
+    usuario_id              INT REFERENCES usuarios(id) ON DELETE SET NULL,
+    fecha                   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pacientes_fusiones_paciente ON pacientes_fusiones (paciente_id);
+CREATE INDEX IF NOT EXISTS idx_pacientes_fusiones_fusionado ON pacientes_fusiones (fusionado_id);
@@ -0,0 +1,12 @@
+-- Archivo de pacientes: borrar un paciente lo archiva y conserva su historial clínico.
+-- El borrado definitivo (purga) requiere el permiso purgar-pacientes y que haya pasado
+-- el periodo de retención desde el archivo y desde la última actividad clínica.
+ALTER TABLE pacientes
+    ADD COLUMN IF NOT EXISTS archivado_en  TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS archivado_por INT REFERENCES usuarios(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pacientes_activos ON pacientes (nombre) WHERE archivado_en IS NULL;
+
+INSERT INTO permisos (nombre, descripcion)
+SELECT 'purgar-pacientes', 'Eliminar definitivamente pacientes archivados'
+WHERE NOT EXISTS (SELECT 1 FROM permisos WHERE nombre = 'purgar-pacientes');
@@ -0,0 +1,22 @@
+-- Datos demográficos y de contacto del paciente. telefono sigue siendo el número
+-- principal; telefonos guarda los adicionales. Contacto de emergencia, tutor (para
+-- menores de edad) y seguro se guardan como objetos JSON.
+ALTER TABLE pacientes
+    ADD COLUMN IF NOT EXISTS dpi                 TEXT,
+    ADD COLUMN IF NOT EXISTS correo              TEXT,
+    ADD COLUMN IF NOT EXISTS direccion           TEXT,
+    ADD COLUMN IF NOT EXISTS ocupacion           TEXT,
+    ADD COLUMN IF NOT EXISTS genero              TEXT,
+    ADD COLUMN IF NOT EXISTS canal_contacto      TEXT,
+    ADD COLUMN IF NOT EXISTS telefonos           JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS contacto_emergencia JSONB,
+    ADD COLUMN IF NOT EXISTS tutor               JSONB,
+    ADD COLUMN IF NOT EXISTS seguro              JSONB;
+
+ALTER TABLE pacientes DROP CONSTRAINT IF EXISTS pacientes_canal_contacto_check;
+ALTER TABLE pacientes
+    ADD CONSTRAINT pacientes_canal_contacto_check
+    CHECK (canal_contacto IN ('telefono', 'whatsapp', 'sms', 'correo'));
+
+-- Un DPI identifica a una sola persona
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pacientes_dpi ON pacientes (dpi) WHERE dpi IS NOT NULL;
@@ -0,0 +1,46 @@
+-- Bitácora de auditoría: cada cambio hecho a través de la API queda registrado con
+-- quién lo hizo, desde qué IP y el estado de la entidad antes y después. La tabla es
+-- de solo inserción; no tiene llaves foráneas para sobrevivir a la eliminación de
+-- usuarios y a la purga de pacientes.
+CREATE TABLE IF NOT EXISTS auditoria (
+    id          BIGSERIAL PRIMARY KEY,
+    usuario_id  INT,                                -- actor (del token); NULL = sin sesión
+    accion      TEXT        NOT NULL CHECK (accion IN ('crear', 'actualizar', 'eliminar')),
+    entidad     TEXT        NOT NULL,               -- paciente, consulta, diagnostico...
+    entidad_id  INT,
+    paciente_id INT,                                -- paciente afectado, si aplica
+    ruta        TEXT        NOT NULL,               -- p. ej. "PUT /api/consultations/:id"
+    antes       JSONB,
+    despues     JSONB,
+    cambios     JSONB       NOT NULL DEFAULT '[]',  -- [{campo, antes, despues}]
+    ip          TEXT,
+    fecha       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auditoria_fecha ON auditoria (fecha);
+CREATE INDEX IF NOT EXISTS idx_auditoria_paciente ON auditoria (paciente_id, fecha) WHERE paciente_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_auditoria_usuario ON auditoria (usuario_id, fecha);
+CREATE INDEX IF NOT EXISTS idx_auditoria_entidad ON auditoria (entidad, entidad_id);
+
+CREATE OR REPLACE FUNCTION auditoria_solo_insercion()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RAISE EXCEPTION 'La bitácora de auditoría es de solo inserción';
+END
+$$;
+
+DROP TRIGGER IF EXISTS trg_auditoria_solo_insercion ON auditoria;
+CREATE TRIGGER trg_auditoria_solo_insercion
+    BEFORE UPDATE OR DELETE ON auditoria
+    FOR EACH ROW EXECUTE FUNCTION auditoria_solo_insercion();
+
+DROP TRIGGER IF EXISTS trg_auditoria_sin_truncate ON auditoria;
+CREATE TRIGGER trg_auditoria_sin_truncate
+    BEFORE TRUNCATE ON auditoria
+    FOR EACH STATEMENT EXECUTE FUNCTION auditoria_solo_insercion();
+
+INSERT INTO permisos (nombre, descripcion)
+SELECT 'ver-auditoria', 'Consultar la bitácora de auditoría'
+WHERE NOT EXISTS (SELECT 1 FROM permisos WHERE nombre = 'ver-auditoria');
@@ -0,0 +1,31 @@
+-- Registro de accesos a expedientes: quién consultó qué parte del expediente de un
+-- paciente y con qué motivo. Igual que la bitácora de auditoría, es de solo inserción
+-- y no tiene llaves foráneas.
+CREATE TABLE IF NOT EXISTS accesos (
+    id          BIGSERIAL PRIMARY KEY,
+    usuario_id  INT,
+    paciente_id INT         NOT NULL,
+    recurso     TEXT        NOT NULL,  -- detalles_paciente, archivo_examen, expediente, detalles_consulta
+    recurso_id  INT,
+    motivo      TEXT,                  -- declarado por el cliente; NULL = no declarado
+    ruta        TEXT        NOT NULL,
+    ip          TEXT,
+    fecha       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_accesos_paciente ON accesos (paciente_id, fecha);
+CREATE INDEX IF NOT EXISTS idx_accesos_usuario ON accesos (usuario_id, fecha);
+CREATE INDEX IF NOT EXISTS idx_accesos_fecha ON accesos (fecha);
+
+-- El reporte de accesos inusuales busca citas por paciente y doctor
+CREATE INDEX IF NOT EXISTS idx_citas_paciente_doctor ON citas (paciente_id, doctor_id, fecha);
+
+DROP TRIGGER IF EXISTS trg_accesos_solo_insercion ON accesos;
+CREATE TRIGGER trg_accesos_solo_insercion
+    BEFORE UPDATE OR DELETE ON accesos
+    FOR EACH ROW EXECUTE FUNCTION auditoria_solo_insercion();
+
+DROP TRIGGER IF EXISTS trg_accesos_sin_truncate ON accesos;
+CREATE TRIGGER trg_accesos_sin_truncate
+    BEFORE TRUNCATE ON accesos
+    FOR EACH STATEMENT EXECUTE FUNCTION auditoria_solo_insercion();
@@ -0,0 +1,29 @@
+-- Sesiones de usuario. Cada inicio de sesión abre una sesión (la "familia" de sus
+-- refresh tokens); cerrar o revocar la sesión invalida tanto sus refresh tokens como
+-- los access tokens emitidos con ella.
+CREATE TABLE IF NOT EXISTS sesiones (
+    id                TEXT        PRIMARY KEY,  -- aleatorio; va en el claim "sid" del access token
+    usuario_id        INT         NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    creada_en         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ultimo_uso        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expira_en         TIMESTAMPTZ NOT NULL,      -- se extiende en cada renovación
+    revocada_en       TIMESTAMPTZ,
+    motivo_revocacion TEXT,                      -- logout, admin, reuso, cambio_contrasena
+    ip                TEXT,
+    user_agent        TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_sesiones_usuario ON sesiones (usuario_id, creada_en);
+
+-- Refresh tokens rotativos: solo se guarda el hash SHA-256. Un token ya usado que se
+-- vuelve a presentar indica que fue robado y revoca toda su sesión.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id         SERIAL      PRIMARY KEY,
+    sesion_id  TEXT        NOT NULL REFERENCES sesiones(id) ON DELETE CASCADE,
+    token_hash TEXT        NOT NULL UNIQUE,
+    creado_en  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expira_en  TIMESTAMPTZ NOT NULL,
+    usado_en   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_sesion ON refresh_tokens (sesion_id);
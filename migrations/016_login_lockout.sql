@@ -0,0 +1,25 @@
+-- Protección contra fuerza bruta en el login. Los contadores llevan los intentos
+-- fallidos recientes por cuenta ("usuario:<id>", o "identificador:<texto>" cuando la
+-- cuenta no existe) y por IP ("ip:<dirección>").
+CREATE TABLE IF NOT EXISTS contadores_login (
+    clave           TEXT        PRIMARY KEY,
+    fallos          INT         NOT NULL DEFAULT 0,
+    ultimo_fallo    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    bloqueado_hasta TIMESTAMPTZ
+);
+
+-- Historial de bloqueos: cada vez que una cuenta o una IP queda bloqueada
+CREATE TABLE IF NOT EXISTS bloqueos_login (
+    id               SERIAL      PRIMARY KEY,
+    clave            TEXT        NOT NULL,
+    usuario_id       INT         REFERENCES usuarios(id) ON DELETE SET NULL,
+    ip               TEXT,
+    fallos           INT         NOT NULL,
+    desde            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    hasta            TIMESTAMPTZ NOT NULL,
+    desbloqueado_en  TIMESTAMPTZ,
+    desbloqueado_por INT         REFERENCES usuarios(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_bloqueos_login_usuario ON bloqueos_login (usuario_id, desde);
+CREATE INDEX IF NOT EXISTS idx_bloqueos_login_hasta ON bloqueos_login (hasta);
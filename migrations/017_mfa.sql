@@ -0,0 +1,23 @@
+-- Verificación en dos pasos (TOTP, RFC 6238). El secreto se guarda al iniciar el
+-- enrolamiento y queda activo cuando el usuario confirma un primer código.
+CREATE TABLE IF NOT EXISTS mfa_usuarios (
+    usuario_id    INT         PRIMARY KEY REFERENCES usuarios(id) ON DELETE CASCADE,
+    secreto       TEXT        NOT NULL,            -- base32
+    creado_en     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    confirmado_en TIMESTAMPTZ,                     -- NULL = enrolamiento pendiente
+    ultimo_paso   BIGINT      NOT NULL DEFAULT 0   -- último paso de 30 s aceptado; evita reusar un código
+);
+
+-- Códigos de recuperación de un solo uso; solo se guarda su hash SHA-256
+CREATE TABLE IF NOT EXISTS codigos_recuperacion (
+    id          SERIAL      PRIMARY KEY,
+    usuario_id  INT         NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    codigo_hash TEXT        NOT NULL,
+    usado_en    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_codigos_recuperacion_usuario ON codigos_recuperacion (usuario_id);
+
+-- Los usuarios con al menos un rol que lo requiere deben usar verificación en dos
+-- pasos; si no la tienen, se les pide enrolarse al iniciar sesión.
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS requiere_mfa BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,24 @@
+-- Tokens de un solo uso para restablecer la contraseña y para aceptar invitaciones.
+-- Solo se guarda su hash SHA-256; el token viaja en el enlace enviado al usuario.
+CREATE TABLE IF NOT EXISTS tokens_usuario (
+    id         SERIAL      PRIMARY KEY,
+    usuario_id INT         NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    tipo       TEXT        NOT NULL CHECK (tipo IN ('restablecer', 'invitacion')),
+    token_hash TEXT        NOT NULL UNIQUE,
+    creado_en  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expira_en  TIMESTAMPTZ NOT NULL,
+    usado_en   TIMESTAMPTZ,                                           -- también al quedar reemplazado
+    creado_por INT         REFERENCES usuarios(id) ON DELETE SET NULL -- administrador que invitó
+);
+
+CREATE INDEX IF NOT EXISTS idx_tokens_usuario_usuario ON tokens_usuario (usuario_id, tipo, creado_en);
+
+-- Hashes de las últimas contraseñas de cada usuario, para impedir que las reutilice
+CREATE TABLE IF NOT EXISTS historial_contrasenas (
+    id            SERIAL      PRIMARY KEY,
+    usuario_id    INT         NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    password_hash TEXT        NOT NULL,
+    creado_en     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_historial_contrasenas_usuario ON historial_contrasenas (usuario_id, creado_en);
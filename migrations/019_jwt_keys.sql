@@ -0,0 +1,15 @@
+-- Claves de firma de los access tokens (RS256 o EdDSA). Cada clave firma durante su
+-- periodo [firma_desde, firma_hasta) y verifica hasta expira_en, para que los tokens
+-- ya emitidos sigan siendo válidos tras la rotación. La siguiente clave se crea antes
+-- de empezar a firmar, para que quien lea /.well-known/jwks.json la conozca a tiempo.
+CREATE TABLE IF NOT EXISTS claves_jwt (
+    kid           TEXT        PRIMARY KEY,
+    algoritmo     TEXT        NOT NULL CHECK (algoritmo IN ('RS256', 'EdDSA')),
+    clave_privada TEXT        NOT NULL,   -- PKCS#8, cifrada con AES-GCM (clave derivada de JWT_SECRET)
+    creada_en     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    firma_desde   TIMESTAMPTZ NOT NULL,
+    firma_hasta   TIMESTAMPTZ NOT NULL,
+    expira_en     TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_claves_jwt_expira ON claves_jwt (expira_en);
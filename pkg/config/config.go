@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,9 +14,34 @@ type Config struct {
 	DatabaseURL string
 
 	// JWT Config
-	JWTSecret string        // secret key for signing tokens
-	JWTTTL    time.Duration // token time-to-live (default 24h)
-	JWTIssuer string        // issuer name in JWT claims
+	JWTSecret      string        // secret key for signing tokens
+	JWTTTL         time.Duration // access token time-to-live (default 15m)
+	RefreshTTL     time.Duration // refresh token time-to-live; idle sessions expire after it (default 14 days)
+	JWTIssuer      string        // issuer name in JWT claims
+	JWTAlgorithm   string        // HS256 (default), RS256 or EdDSA
+	JWTKeyRotation time.Duration // how long each RS256/EdDSA key signs (default 30 days)
+
+	// Permissions
+	PermissionSource   string        // "database" (default) or "token": what RequirePermission trusts
+	PermissionCacheTTL time.Duration // how long looked up permissions are cached; 0 disables the cache (default 1m)
+
+	// Proxies
+	TrustedProxies []string // CIDR ranges whose X-Forwarded-For is believed; empty uses the connection's address
+
+	// Login Config
+	LoginMaxAttempts int           // failed logins before an account is locked (default 10)
+	LoginLockout     time.Duration // first lockout; repeated failures double it (default 15m)
+
+	// Password Config
+	PasswordMinLength  int           // default 12
+	PasswordHistory    int           // last passwords that cannot be reused (default 5)
+	PasswordBreachList string        // file of breached or common passwords; empty disables the check
+	PasswordResetTTL   time.Duration // reset links (default 60m)
+	InvitationTTL      time.Duration // invitation links (default 72h)
+
+	// Notifications Config
+	AppURL            string // frontend that reset and invitation links point to
+	NotificationsFile string // dev notifier: file the messages are appended to; empty logs them
 
 	// Superuser Config
 	SuperuserName     string
@@ -36,6 +62,12 @@ type Config struct {
 
 	// Timezone Config
 	ClinicTimezone string // IANA tz name, e.g., America/Guatemala
+
+	// Appointments Config
+	AppointmentBufferMinutes int // minutes kept free between appointments (default 0)
+
+	// Patients Config
+	PatientRetentionYears int // years an archived patient is kept before it can be purged (default 10)
 }
 
 // Load reads environment variables into a Config struct.
@@ -55,16 +87,27 @@ func Load() *Config {
 		log.Fatal("JWT_SECRET not set")
 	}
 
-	// Token expiration (in hours)
-	if ttlStr := os.Getenv("JWT_TTL_HOURS"); ttlStr != "" {
+	// Access token expiration (in minutes); sessions are kept alive with refresh tokens
+	cfg.JWTTTL = 15 * time.Minute
+	if ttlStr := os.Getenv("JWT_ACCESS_TTL_MINUTES"); ttlStr != "" {
 		if ttl, err := strconv.Atoi(ttlStr); err == nil && ttl > 0 {
-			cfg.JWTTTL = time.Duration(ttl) * time.Hour
+			cfg.JWTTTL = time.Duration(ttl) * time.Minute
 		} else {
-			log.Printf("Invalid JWT_TTL_HOURS value, defaulting to 24h")
-			cfg.JWTTTL = 24 * time.Hour
+			log.Printf("Invalid JWT_ACCESS_TTL_MINUTES value, defaulting to 15m")
+		}
+	}
+	if os.Getenv("JWT_TTL_HOURS") != "" {
+		log.Println("⚠️ JWT_TTL_HOURS is no longer used — set JWT_ACCESS_TTL_MINUTES and JWT_REFRESH_TTL_DAYS")
+	}
+
+	// Refresh token expiration (in days)
+	cfg.RefreshTTL = 14 * 24 * time.Hour
+	if ttlStr := os.Getenv("JWT_REFRESH_TTL_DAYS"); ttlStr != "" {
+		if ttl, err := strconv.Atoi(ttlStr); err == nil && ttl > 0 {
+			cfg.RefreshTTL = time.Duration(ttl) * 24 * time.Hour
+		} else {
+			log.Printf("Invalid JWT_REFRESH_TTL_DAYS value, defaulting to 14 days")
 		}
-	} else {
-		cfg.JWTTTL = 24 * time.Hour
 	}
 
 	// JWT Issuer
@@ -73,6 +116,128 @@ func Load() *Config {
 		log.Fatal("JWT_ISSUER not set")
 	}
 
+	// Signing algorithm; HS256 signs with JWT_SECRET, the others with rotated key pairs.
+	// Switching from HS256 rejects every token it signed, so it stays the default.
+	cfg.JWTAlgorithm = "HS256"
+	if v := os.Getenv("JWT_ALGORITHM"); v != "" {
+		switch v {
+		case "HS256", "RS256", "EdDSA":
+			cfg.JWTAlgorithm = v
+		default:
+			log.Fatalf("Invalid JWT_ALGORITHM %q: use HS256, RS256 or EdDSA", v)
+		}
+	}
+
+	// Signing key rotation (in days)
+	cfg.JWTKeyRotation = 30 * 24 * time.Hour
+	if v := os.Getenv("JWT_KEY_ROTATION_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.JWTKeyRotation = time.Duration(n) * 24 * time.Hour
+		} else {
+			log.Printf("Invalid JWT_KEY_ROTATION_DAYS value, defaulting to 30 days")
+		}
+	}
+
+	// Permission source: the database (through the cache) or the access token
+	cfg.PermissionSource = "database"
+	if v := os.Getenv("PERMISSION_SOURCE"); v != "" {
+		switch v {
+		case "database", "token":
+			cfg.PermissionSource = v
+		default:
+			log.Fatalf("Invalid PERMISSION_SOURCE %q: use database or token", v)
+		}
+	}
+
+	// Permission cache (in seconds)
+	cfg.PermissionCacheTTL = time.Minute
+	if v := os.Getenv("PERMISSION_CACHE_TTL_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.PermissionCacheTTL = time.Duration(n) * time.Second
+		} else {
+			log.Printf("Invalid PERMISSION_CACHE_TTL_SECONDS value, defaulting to 1m")
+		}
+	}
+
+	// Reverse proxies in front of the API (comma-separated CIDR ranges)
+	for _, v := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			cfg.TrustedProxies = append(cfg.TrustedProxies, v)
+		}
+	}
+
+	// Failed logins before an account is locked
+	cfg.LoginMaxAttempts = 10
+	if v := os.Getenv("LOGIN_MAX_ATTEMPTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.LoginMaxAttempts = n
+		} else {
+			log.Printf("Invalid LOGIN_MAX_ATTEMPTS value, defaulting to 10")
+		}
+	}
+
+	// First lockout (in minutes)
+	cfg.LoginLockout = 15 * time.Minute
+	if v := os.Getenv("LOGIN_LOCKOUT_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.LoginLockout = time.Duration(n) * time.Minute
+		} else {
+			log.Printf("Invalid LOGIN_LOCKOUT_MINUTES value, defaulting to 15m")
+		}
+	}
+
+	// Password policy
+	cfg.PasswordMinLength = 12
+	if v := os.Getenv("PASSWORD_MIN_LENGTH"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.PasswordMinLength = n
+		} else {
+			log.Printf("Invalid PASSWORD_MIN_LENGTH value, defaulting to 12")
+		}
+	}
+
+	cfg.PasswordHistory = 5
+	if v := os.Getenv("PASSWORD_HISTORY"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.PasswordHistory = n
+		} else {
+			log.Printf("Invalid PASSWORD_HISTORY value, defaulting to 5")
+		}
+	}
+
+	cfg.PasswordBreachList = os.Getenv("PASSWORD_BREACH_LIST")
+	if cfg.PasswordBreachList == "" {
+		log.Println("⚠️ PASSWORD_BREACH_LIST not set — breached passwords will not be rejected")
+	}
+
+	// Password reset links (in minutes)
+	cfg.PasswordResetTTL = time.Hour
+	if v := os.Getenv("PASSWORD_RESET_TTL_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.PasswordResetTTL = time.Duration(n) * time.Minute
+		} else {
+			log.Printf("Invalid PASSWORD_RESET_TTL_MINUTES value, defaulting to 60m")
+		}
+	}
+
+	// Invitation links (in hours)
+	cfg.InvitationTTL = 72 * time.Hour
+	if v := os.Getenv("INVITATION_TTL_HOURS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.InvitationTTL = time.Duration(n) * time.Hour
+		} else {
+			log.Printf("Invalid INVITATION_TTL_HOURS value, defaulting to 72h")
+		}
+	}
+
+	// Notifications
+	cfg.AppURL = os.Getenv("APP_URL")
+	if cfg.AppURL == "" {
+		cfg.AppURL = "http://localhost:3000"
+		log.Println("ℹ️ APP_URL not set — defaulting to http://localhost:3000")
+	}
+	cfg.NotificationsFile = os.Getenv("NOTIFICATIONS_FILE")
+
 	// Superuser
 	cfg.SuperuserName = os.Getenv("SUPERUSER_NAME")
 	cfg.SuperuserEmail = os.Getenv("SUPERUSER_EMAIL")
@@ -121,5 +286,22 @@ func Load() *Config {
 		log.Println("ℹ️ CLINIC_TZ not set — defaulting to America/Guatemala")
 	}
 
+	if bufStr := os.Getenv("APPOINTMENT_BUFFER_MINUTES"); bufStr != "" {
+		if buf, err := strconv.Atoi(bufStr); err == nil && buf >= 0 {
+			cfg.AppointmentBufferMinutes = buf
+		} else {
+			log.Printf("Invalid APPOINTMENT_BUFFER_MINUTES value, defaulting to 0")
+		}
+	}
+
+	cfg.PatientRetentionYears = 10
+	if yearsStr := os.Getenv("PATIENT_RETENTION_YEARS"); yearsStr != "" {
+		if years, err := strconv.Atoi(yearsStr); err == nil && years >= 0 {
+			cfg.PatientRetentionYears = years
+		} else {
+			log.Printf("Invalid PATIENT_RETENTION_YEARS value, defaulting to 10")
+		}
+	}
+
 	return cfg
 }
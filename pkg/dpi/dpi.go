@@ -0,0 +1,42 @@
+// Package dpi validates the CUI (Código Único de Identificación) printed on the
+// Guatemalan DPI.
+package dpi
+
+import "strings"
+
+// municipalities holds how many municipalities each department (01-22) has
+var municipalities = []int{17, 8, 16, 16, 13, 14, 19, 33, 24, 21, 14, 29, 32, 21, 8, 17, 14, 5, 11, 11, 7, 17}
+
+// Normalize drops the spaces and dashes the CUI is usually written with
+// ("1234 56789 0101" -> "1234567890101").
+func Normalize(cui string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(cui))
+}
+
+// Valid reports whether cui is a well-formed CUI: 8 digits, a check digit, and the
+// department and municipality codes where it was issued.
+func Valid(cui string) bool {
+	cui = Normalize(cui)
+	if len(cui) != 13 {
+		return false
+	}
+	d := make([]int, len(cui))
+	for i, r := range cui {
+		if r < '0' || r > '9' {
+			return false
+		}
+		d[i] = int(r - '0')
+	}
+
+	dept := d[9]*10 + d[10]
+	muni := d[11]*10 + d[12]
+	if dept < 1 || dept > len(municipalities) || muni < 1 || muni > municipalities[dept-1] {
+		return false
+	}
+
+	total := 0
+	for i := 0; i < 8; i++ {
+		total += d[i] * (i + 2)
+	}
+	return total%11 == d[8]
+}
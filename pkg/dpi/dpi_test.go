@@ -0,0 +1,27 @@
+package dpi
+
+import "testing"
+
+func TestValid(t *testing.T) {
+	valid := []string{"1234567890101", "1234 56789 0101", "1234-56789-2217"}
+	for _, cui := range valid {
+		if !Valid(cui) {
+			t.Errorf("expected %q to be valid", cui)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"123456789010",  // too short
+		"1234567880101", // wrong check digit
+		"1234567892301", // department 23 does not exist
+		"1234567891818", // department 18 has 5 municipalities
+		"12345678a0101", // not a number
+		"1234567890100", // municipality 00
+	}
+	for _, cui := range invalid {
+		if Valid(cui) {
+			t.Errorf("expected %q to be invalid", cui)
+		}
+	}
+}
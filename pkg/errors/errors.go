@@ -3,6 +3,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Core sentinel errors (global, domain-agnostic)
@@ -23,12 +24,14 @@ var (
 	ErrAlreadyExists  = errors.New("el recurso ya existe")
 	ErrConflict       = errors.New("conflicto de datos")
 	ErrInternal       = errors.New("error interno del servidor")
+	ErrTooLarge       = errors.New("contenido demasiado grande")
 
 	// Authentication / authorization errors
 	ErrUnauthorized       = errors.New("no autorizado")
 	ErrForbidden          = errors.New("acceso denegado")
 	ErrInvalidToken       = errors.New("token inválido o expirado")
 	ErrInvalidCredentials = errors.New("credenciales inválidas")
+	ErrTooManyRequests    = errors.New("demasiados intentos")
 
 	// Operational / rule violations
 	ErrOperationNotAllowed = errors.New("operación no permitida")
@@ -40,6 +43,11 @@ func Wrap(context string, public, internal error) error {
 	if internal == nil {
 		return fmt.Errorf("%s: %w", context, public)
 	}
+	// Validation errors stay in the chain so their field details reach the response.
+	var v *ValidationError
+	if errors.As(internal, &v) {
+		return fmt.Errorf("%s: %w: %w", context, public, internal)
+	}
 	return fmt.Errorf("%s: %w: %v", context, public, internal)
 }
 
@@ -70,3 +78,42 @@ func IsDomainError(err error) bool {
 	var d *DomainError
 	return errors.As(err, &d)
 }
+
+// FieldError describes one request field that failed validation.
+type FieldError struct {
+	Campo   string `json:"campo"`   // JSON path of the field, e.g. "telefonos[0].numero"
+	Regla   string `json:"regla"`   // rule that failed, e.g. "required"
+	Mensaje string `json:"mensaje"` // human-readable message in Spanish
+}
+
+// ValidationError lists every invalid field of a request. It unwraps to
+// ErrInvalidInput, so it maps to a 400 like any other invalid input.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Mensaje
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidInput.Error(), strings.Join(msgs, "; "))
+}
+
+// Unwrap exposes ErrInvalidInput to errors.Is.
+func (e *ValidationError) Unwrap() error { return ErrInvalidInput }
+
+// NewValidationError builds a ValidationError for a single field.
+func NewValidationError(field, rule, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Campo: field, Regla: rule, Mensaje: message}}}
+}
+
+// ValidationDetails returns the field errors carried by err, if any.
+func ValidationDetails(err error) ([]FieldError, bool) {
+	var v *ValidationError
+	if errors.As(err, &v) {
+		return v.Fields, true
+	}
+	return nil, false
+}
@@ -0,0 +1,400 @@
+// Package ical reads and writes the subset of iCalendar (RFC 5545) needed to
+// publish appointments as a calendar feed and to import externally scheduled events.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event statuses (RFC 5545 §3.8.1.11)
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+const (
+	utcLayout      = "20060102T150405Z"
+	localLayout    = "20060102T150405"
+	dateLayout     = "20060102"
+	maxLineOctets  = 75
+	defaultProduct = "-//healthcare-crm//agenda//ES"
+)
+
+// Event is a VEVENT. Start and End are absolute instants; AllDay marks events
+// parsed from a DATE value, which have no time of day.
+type Event struct {
+	UID          string
+	Sequence     int
+	Stamp        time.Time // DTSTAMP
+	LastModified time.Time // zero = omitted
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Summary      string
+	Description  string
+	Location     string
+	Status       string // TENTATIVE | CONFIRMED | CANCELLED, empty = omitted
+	Color        string // RFC 7986 COLOR, empty = omitted
+}
+
+// Calendar is a VCALENDAR with its events.
+type Calendar struct {
+	ProdID string
+	Name   string // X-WR-CALNAME, shown by most clients as the calendar title
+	Events []Event
+}
+
+// ---------------------------------------------------------------------------
+// Encoding
+// ---------------------------------------------------------------------------
+
+// Encode writes the calendar as an iCalendar stream with CRLF line endings and
+// lines folded at 75 octets. Times are written in UTC.
+func Encode(w io.Writer, cal *Calendar) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	prodID := cal.ProdID
+	if prodID == "" {
+		prodID = defaultProduct
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", prodID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", EscapeText(cal.Name))
+	}
+
+	for _, ev := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", EscapeText(ev.UID))
+		line("SEQUENCE", strconv.Itoa(ev.Sequence))
+		line("DTSTAMP", ev.Stamp.UTC().Format(utcLayout))
+		if !ev.LastModified.IsZero() {
+			line("LAST-MODIFIED", ev.LastModified.UTC().Format(utcLayout))
+		}
+		line("DTSTART", ev.Start.UTC().Format(utcLayout))
+		line("DTEND", ev.End.UTC().Format(utcLayout))
+		line("SUMMARY", EscapeText(ev.Summary))
+		if ev.Description != "" {
+			line("DESCRIPTION", EscapeText(ev.Description))
+		}
+		if ev.Location != "" {
+			line("LOCATION", EscapeText(ev.Location))
+		}
+		if ev.Status != "" {
+			line("STATUS", ev.Status)
+		}
+		if ev.Color != "" {
+			line("COLOR", ev.Color)
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// writeFolded writes a content line, folding it so no physical line exceeds 75
+// octets. Folds never split a UTF-8 sequence.
+func writeFolded(w *bufio.Writer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1 // continuation lines start with a space
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// EscapeText escapes a TEXT value (RFC 5545 §3.3.11).
+func EscapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// UnescapeText reverses EscapeText.
+func UnescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i]) // \\ \; \,
+		}
+	}
+	return b.String()
+}
+
+// ---------------------------------------------------------------------------
+// Parsing
+// ---------------------------------------------------------------------------
+
+// ErrNoCalendar is returned when the input has no VCALENDAR component.
+var ErrNoCalendar = errors.New("ical: no VCALENDAR found")
+
+// property is one unfolded content line: NAME;PARAM=VALUE:value
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the VEVENTs of an iCalendar stream. Floating times (no Z suffix and
+// no TZID) are interpreted in defaultLoc. Unknown properties and components are ignored.
+func Parse(r io.Reader, defaultLoc *time.Location) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &Calendar{}
+	var current *Event
+	var hasDuration bool
+	var duration time.Duration
+	found := false
+
+	for n, raw := range lines {
+		if raw == "" {
+			continue
+		}
+		p, err := parseProperty(raw)
+		if err != nil {
+			return nil, fmt.Errorf("ical: line %d: %w", n+1, err)
+		}
+
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VCALENDAR"):
+			found = true
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VEVENT"):
+			current = &Event{}
+			hasDuration, duration = false, 0
+		case p.name == "END" && strings.EqualFold(p.value, "VEVENT"):
+			if current == nil {
+				return nil, fmt.Errorf("ical: line %d: END:VEVENT without BEGIN", n+1)
+			}
+			if current.End.IsZero() {
+				switch {
+				case hasDuration:
+					current.End = current.Start.Add(duration)
+				case current.AllDay:
+					current.End = current.Start.AddDate(0, 0, 1)
+				default:
+					current.End = current.Start
+				}
+			}
+			cal.Events = append(cal.Events, *current)
+			current = nil
+		case current != nil:
+			if err := applyProperty(current, p, defaultLoc, &hasDuration, &duration); err != nil {
+				return nil, fmt.Errorf("ical: line %d: %w", n+1, err)
+			}
+		case p.name == "PRODID":
+			cal.ProdID = p.value
+		case p.name == "X-WR-CALNAME":
+			cal.Name = UnescapeText(p.value)
+		}
+	}
+
+	if !found {
+		return nil, ErrNoCalendar
+	}
+	return cal, nil
+}
+
+func applyProperty(ev *Event, p property, defaultLoc *time.Location, hasDuration *bool, duration *time.Duration) error {
+	var err error
+	switch p.name {
+	case "UID":
+		ev.UID = UnescapeText(p.value)
+	case "SEQUENCE":
+		ev.Sequence, _ = strconv.Atoi(p.value)
+	case "SUMMARY":
+		ev.Summary = UnescapeText(p.value)
+	case "DESCRIPTION":
+		ev.Description = UnescapeText(p.value)
+	case "LOCATION":
+		ev.Location = UnescapeText(p.value)
+	case "STATUS":
+		ev.Status = strings.ToUpper(p.value)
+	case "DTSTART":
+		ev.Start, ev.AllDay, err = parseDateTime(p, defaultLoc)
+	case "DTEND":
+		ev.End, _, err = parseDateTime(p, defaultLoc)
+	case "DTSTAMP":
+		ev.Stamp, _, err = parseDateTime(p, defaultLoc)
+	case "DURATION":
+		*duration, err = ParseDuration(p.value)
+		*hasDuration = err == nil
+	}
+	return err
+}
+
+// unfold splits the stream into logical lines, joining continuation lines
+// (those starting with a space or tab) to the previous one.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if len(text) > 0 && (text[0] == ' ' || text[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += text[1:]
+			continue
+		}
+		lines = append(lines, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ical: %w", err)
+	}
+	return lines, nil
+}
+
+// parseProperty splits NAME;PARAM=VALUE;PARAM="QUOTED":value. The first colon
+// outside a quoted parameter value separates the value.
+func parseProperty(line string) (property, error) {
+	inQuotes := false
+	colon := -1
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ':':
+			if !inQuotes {
+				colon = i
+			}
+		}
+		if colon >= 0 {
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("malformed content line %q", line)
+	}
+
+	head := strings.Split(line[:colon], ";")
+	p := property{name: strings.ToUpper(head[0]), params: map[string]string{}, value: line[colon+1:]}
+	for _, param := range head[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return p, nil
+}
+
+// parseDateTime parses DATE-TIME (UTC, TZID or floating) and DATE values.
+func parseDateTime(p property, defaultLoc *time.Location) (time.Time, bool, error) {
+	loc := defaultLoc
+	if tzid, ok := p.params["TZID"]; ok {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	if p.params["VALUE"] == "DATE" || len(p.value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, p.value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", p.value)
+		}
+		return t, true, nil
+	}
+	if strings.HasSuffix(p.value, "Z") {
+		t, err := time.Parse(utcLayout, p.value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date-time %q", p.value)
+		}
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation(localLayout, p.value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q", p.value)
+	}
+	return t, false, nil
+}
+
+// ParseDuration parses an RFC 5545 duration such as PT30M, PT1H30M, P1D or P1W.
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+		if num == "" {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		n, _ := strconv.Atoi(num)
+		num = ""
+
+		switch {
+		case c == 'W' && !inTime:
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			d += time.Duration(n) * 24 * time.Hour
+		case c == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case c == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case c == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
@@ -0,0 +1,152 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode_RoundTrip(t *testing.T) {
+	start := time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC)
+	cal := &Calendar{
+		Name: "Agenda",
+		Events: []Event{{
+			UID:         "cita-1@test",
+			Sequence:    2,
+			Stamp:       start,
+			Start:       start,
+			End:         start.Add(30 * time.Minute),
+			Summary:     "Pérez, Juan; seguimiento",
+			Description: "Línea 1\nLínea 2",
+			Status:      StatusCancelled,
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, cal); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "SUMMARY:Pérez\\, Juan\\; seguimiento\r\n") {
+		t.Fatalf("summary not escaped:\n%s", out)
+	}
+
+	parsed, err := Parse(strings.NewReader(out), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(parsed.Events))
+	}
+	ev := parsed.Events[0]
+	if ev.UID != "cita-1@test" || ev.Sequence != 2 || ev.Status != StatusCancelled {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+	if ev.Summary != cal.Events[0].Summary || ev.Description != cal.Events[0].Description {
+		t.Fatalf("text did not round-trip: %q / %q", ev.Summary, ev.Description)
+	}
+	if !ev.Start.Equal(start) || !ev.End.Equal(start.Add(30*time.Minute)) {
+		t.Fatalf("unexpected times %v - %v", ev.Start, ev.End)
+	}
+}
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	cal := &Calendar{Events: []Event{{UID: "x", Summary: strings.Repeat("ñ", 100)}}}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, cal); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("line exceeds 75 octets: %d", len(line))
+		}
+	}
+
+	parsed, err := Parse(&buf, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Events[0].Summary != strings.Repeat("ñ", 100) {
+		t.Fatal("folded summary did not unfold back")
+	}
+}
+
+func TestParse_TimeForms(t *testing.T) {
+	guatemala, err := time.LoadLocation("America/Guatemala")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:tzid",
+		"DTSTART;TZID=America/Guatemala:20250303T090000",
+		"DURATION:PT45M",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:floating",
+		"DTSTART:20250303T100000",
+		"DTEND:20250303T103000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:allday",
+		"DTSTART;VALUE=DATE:20250304",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	cal, err := Parse(strings.NewReader(input), guatemala)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cal.Events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(cal.Events))
+	}
+
+	tzid := cal.Events[0]
+	if want := time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC); !tzid.Start.Equal(want) {
+		t.Fatalf("TZID start: got %v want %v", tzid.Start.UTC(), want)
+	}
+	if tzid.End.Sub(tzid.Start) != 45*time.Minute {
+		t.Fatalf("DURATION not applied: %v", tzid.End.Sub(tzid.Start))
+	}
+
+	floating := cal.Events[1]
+	if floating.Start.Location() != guatemala || floating.Start.Hour() != 10 {
+		t.Fatalf("floating time not read in default location: %v", floating.Start)
+	}
+
+	if !cal.Events[2].AllDay {
+		t.Fatal("DATE value should be all-day")
+	}
+}
+
+func TestParse_RequiresCalendar(t *testing.T) {
+	if _, err := Parse(strings.NewReader("hello"), time.UTC); err == nil {
+		t.Fatal("expected error for non-calendar input")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"PT30M":    30 * time.Minute,
+		"PT1H30M":  90 * time.Minute,
+		"P1D":      24 * time.Hour,
+		"P1W":      7 * 24 * time.Hour,
+		"-PT15M":   -15 * time.Minute,
+		"P1DT2H5S": 26*time.Hour + 5*time.Second,
+	}
+	for in, want := range cases {
+		got, err := ParseDuration(in)
+		if err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "30M", "PT", "PTM", "P1H"} {
+		if _, err := ParseDuration(bad); err == nil {
+			t.Errorf("ParseDuration(%q) should fail", bad)
+		}
+	}
+}
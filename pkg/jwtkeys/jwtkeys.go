@@ -0,0 +1,255 @@
+// Package jwtkeys holds the keys access tokens are signed and verified with. A Set
+// signs with one key and verifies with every key it holds, looked up by the "kid"
+// header, so keys can be rotated without invalidating the tokens already issued.
+// The public keys are published as a JWK Set (RFC 7517) for other services.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported algorithms
+const (
+	HS256 = "HS256" // shared secret; not published
+	RS256 = "RS256"
+	EdDSA = "EdDSA" // Ed25519
+)
+
+const rsaBits = 3072
+
+var (
+	ErrUnknownKey        = errors.New("jwtkeys: unknown key")
+	ErrUnsupported       = errors.New("jwtkeys: unsupported algorithm")
+	ErrAlgorithmMismatch = errors.New("jwtkeys: token algorithm does not match its key")
+	ErrNoSigningKey      = errors.New("jwtkeys: no signing key")
+)
+
+// Key is one signing key. Private is nil for keys that only verify.
+type Key struct {
+	ID        string
+	Algorithm string
+	Private   crypto.PrivateKey // []byte, *rsa.PrivateKey or ed25519.PrivateKey
+	Public    crypto.PublicKey  // []byte, *rsa.PublicKey or ed25519.PublicKey
+}
+
+// Method is the jwt signing method of the key.
+func (k *Key) Method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// Secret returns an HS256 key for a shared secret.
+func Secret(id string, secret []byte) *Key {
+	return &Key{ID: id, Algorithm: HS256, Private: secret, Public: secret}
+}
+
+// Generate returns a new random key for algorithm (RS256 or EdDSA).
+func Generate(id, algorithm string) (*Key, error) {
+	switch algorithm {
+	case RS256:
+		priv, err := rsa.GenerateKey(rand.Reader, rsaBits)
+		if err != nil {
+			return nil, err
+		}
+		return &Key{ID: id, Algorithm: algorithm, Private: priv, Public: &priv.PublicKey}, nil
+	case EdDSA:
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return &Key{ID: id, Algorithm: algorithm, Private: priv, Public: pub}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupported, algorithm)
+}
+
+// MarshalPrivate encodes the private key as PKCS#8 PEM.
+func (k *Key) MarshalPrivate() (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(k.Private)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// ParsePrivate decodes a key encoded with MarshalPrivate.
+func ParsePrivate(id, algorithm, privatePEM string) (*Key, error) {
+	block, _ := pem.Decode([]byte(privatePEM))
+	if block == nil {
+		return nil, fmt.Errorf("jwtkeys: key %s is not PEM", id)
+	}
+	priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p := priv.(type) {
+	case *rsa.PrivateKey:
+		if algorithm == RS256 {
+			return &Key{ID: id, Algorithm: algorithm, Private: p, Public: &p.PublicKey}, nil
+		}
+	case ed25519.PrivateKey:
+		if algorithm == EdDSA {
+			return &Key{ID: id, Algorithm: algorithm, Private: p, Public: p.Public()}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: key %s is not %s", ErrUnsupported, id, algorithm)
+}
+
+// -----------------------------------------------------------------------------
+// Set
+// -----------------------------------------------------------------------------
+
+// Set is the keys in use. It is safe for concurrent use.
+type Set struct {
+	mu      sync.RWMutex
+	signing *Key
+	keys    map[string]*Key
+	legacy  *Key // verifies tokens without "kid", issued before keys had IDs
+}
+
+// NewSet returns a Set that signs with signing and verifies with signing and others.
+func NewSet(signing *Key, others ...*Key) *Set {
+	s := &Set{}
+	s.Replace(signing, others...)
+	return s
+}
+
+// Replace swaps the keys of the set.
+func (s *Set) Replace(signing *Key, others ...*Key) {
+	keys := make(map[string]*Key, len(others)+1)
+	for _, k := range others {
+		keys[k.ID] = k
+	}
+	if signing != nil {
+		keys[signing.ID] = signing
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.signing, s.keys = signing, keys
+}
+
+// AcceptWithoutID makes tokens without "kid" verify with key. Only meant for an
+// HS256 secret that signed tokens before key IDs existed.
+func (s *Set) AcceptWithoutID(key *Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.legacy = key
+}
+
+// Signing returns the key new tokens are signed with.
+func (s *Set) Signing() *Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.signing
+}
+
+// Sign signs claims with the signing key and sets its "kid" header.
+func (s *Set) Sign(claims jwt.Claims) (string, error) {
+	key := s.Signing()
+	if key == nil || key.Private == nil {
+		return "", ErrNoSigningKey
+	}
+	token := jwt.NewWithClaims(key.Method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// Keyfunc is a jwt.Keyfunc that returns the key named by the token's "kid". The
+// token must use the key's algorithm, so a public key is never taken for an HMAC
+// secret.
+func (s *Set) Keyfunc(t *jwt.Token) (any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key := s.legacy
+	if kid, ok := t.Header["kid"].(string); ok {
+		key = s.keys[kid]
+	}
+	if key == nil {
+		return nil, ErrUnknownKey
+	}
+	if t.Method == nil || t.Method.Alg() != key.Algorithm {
+		return nil, ErrAlgorithmMismatch
+	}
+	return key.Public, nil
+}
+
+// Algorithms lists the algorithms of the keys in the set, for jwt.WithValidMethods.
+func (s *Set) Algorithms() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := map[string]bool{}
+	algs := []string{}
+	add := func(k *Key) {
+		if k != nil && !seen[k.Algorithm] {
+			seen[k.Algorithm] = true
+			algs = append(algs, k.Algorithm)
+		}
+	}
+	add(s.legacy)
+	for _, k := range s.keys {
+		add(k)
+	}
+	return algs
+}
+
+// -----------------------------------------------------------------------------
+// JWKS
+// -----------------------------------------------------------------------------
+
+// JWK is a public key in JSON Web Key format.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the set. HS256 secrets are never published.
+func (s *Set) JWKS() JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	set := JWKS{Keys: []JWK{}}
+	for _, k := range s.keys {
+		enc := base64.RawURLEncoding
+		switch pub := k.Public.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA", Kid: k.ID, Use: "sig", Alg: k.Algorithm,
+				N: enc.EncodeToString(pub.N.Bytes()),
+				E: enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP", Kid: k.ID, Use: "sig", Alg: k.Algorithm,
+				Crv: "Ed25519", X: enc.EncodeToString(pub),
+			})
+		}
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
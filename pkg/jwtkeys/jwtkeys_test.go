@@ -0,0 +1,142 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func claims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{Subject: "3", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+}
+
+func parse(s *Set, token string) error {
+	_, err := jwt.NewParser(jwt.WithValidMethods(s.Algorithms())).ParseWithClaims(token, &jwt.RegisteredClaims{}, s.Keyfunc)
+	return err
+}
+
+func TestSet_SignAndVerifyAfterRotation(t *testing.T) {
+	for _, alg := range []string{RS256, EdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			old, err := Generate("k1", alg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			set := NewSet(old)
+			token, err := set.Sign(claims())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Rotate: the old key keeps verifying what it signed
+			next, err := Generate("k2", alg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			set.Replace(next, old)
+			if err := parse(set, token); err != nil {
+				t.Fatalf("token of the previous key rejected: %v", err)
+			}
+
+			// Once retired, it does not
+			set.Replace(next)
+			if err := parse(set, token); !errors.Is(err, ErrUnknownKey) {
+				t.Fatalf("expected unknown key, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSet_RejectsAlgorithmConfusion(t *testing.T) {
+	key, err := Generate("k1", EdDSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := NewSet(key)
+
+	// An HS256 token that names the Ed25519 key, signed with its public key as secret
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+	forged.Header["kid"] = "k1"
+	token, err := forged.SignedString([]byte(key.Public.(ed25519.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parse(set, token); err == nil {
+		t.Fatal("forged token accepted")
+	}
+}
+
+func TestSet_LegacyTokensWithoutKid(t *testing.T) {
+	secret := Secret("hs", []byte("secret"))
+	set := NewSet(secret)
+	set.AcceptWithoutID(secret)
+
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims()).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parse(set, legacy); err != nil {
+		t.Fatalf("legacy token rejected: %v", err)
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	for _, alg := range []string{RS256, EdDSA} {
+		key, err := Generate("k1", alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded, err := key.MarshalPrivate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParsePrivate("k1", alg, encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		token, err := NewSet(key).Sign(claims())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := parse(NewSet(parsed), token); err != nil {
+			t.Fatalf("%s: parsed key does not verify: %v", alg, err)
+		}
+	}
+
+	if _, err := ParsePrivate("k1", RS256, mustMarshal(t, EdDSA)); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected algorithm mismatch, got %v", err)
+	}
+}
+
+func mustMarshal(t *testing.T, alg string) string {
+	key, err := Generate("x", alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := key.MarshalPrivate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, _ := Generate("a", RS256)
+	edKey, _ := Generate("b", EdDSA)
+	set := NewSet(edKey, rsaKey, Secret("hs", []byte("secret")))
+
+	jwks := set.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("expected the two public keys only, got %+v", jwks.Keys)
+	}
+	if k := jwks.Keys[0]; k.Kid != "a" || k.Kty != "RSA" || k.E != "AQAB" || k.N == "" {
+		t.Fatalf("unexpected RSA key %+v", k)
+	}
+	if k := jwks.Keys[1]; k.Kid != "b" || k.Kty != "OKP" || k.Crv != "Ed25519" || len(k.X) != 43 {
+		t.Fatalf("unexpected Ed25519 key %+v", k)
+	}
+}
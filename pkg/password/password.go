@@ -0,0 +1,95 @@
+// Package password checks new passwords against the password policy: a minimum
+// length and a list of breached or common passwords.
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// MaxBytes is the most bcrypt reads; anything longer would be silently truncated
+const MaxBytes = 72
+
+// List is a set of breached passwords, kept as uppercase SHA-1 hex.
+type List map[string]struct{}
+
+// LoadList reads a breached password list, one entry per line. An entry is either
+// a plain password or a SHA-1 hash in the "HASH:count" format of the Have I Been
+// Pwned downloads. Empty lines and lines starting with # are skipped.
+func LoadList(path string) (List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	list := List{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if hash, _, _ := strings.Cut(line, ":"); isSHA1(hash) {
+			list[strings.ToUpper(hash)] = struct{}{}
+			continue
+		}
+		list[hashOf(strings.ToLower(line))] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return list, nil
+}
+
+// Contains reports whether password is in the list. Plain entries match regardless
+// of case.
+func (l List) Contains(password string) bool {
+	if _, ok := l[hashOf(password)]; ok {
+		return true
+	}
+	_, ok := l[hashOf(strings.ToLower(password))]
+	return ok
+}
+
+// Policy is what a new password must meet.
+type Policy struct {
+	MinLength int  // in characters
+	Breached  List // optional
+}
+
+// Check returns a validation error for field if password does not meet the policy.
+func (p Policy) Check(field, password string) error {
+	switch {
+	case utf8.RuneCountInString(password) < p.MinLength:
+		return appErr.NewValidationError(field, "min",
+			fmt.Sprintf("La contraseña debe tener al menos %d caracteres.", p.MinLength))
+	case len(password) > MaxBytes:
+		return appErr.NewValidationError(field, "max",
+			fmt.Sprintf("La contraseña no puede superar los %d bytes.", MaxBytes))
+	case p.Breached.Contains(password):
+		return appErr.NewValidationError(field, "breached",
+			"La contraseña aparece en filtraciones conocidas o es muy común. Elija otra.")
+	}
+	return nil
+}
+
+func hashOf(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func isSHA1(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
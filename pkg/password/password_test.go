@@ -0,0 +1,56 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+func TestLoadList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	content := "# common passwords\nPassword123!\n\n" +
+		// SHA-1 of "correct horse battery staple", HIBP format
+		"ABF7AAD6438836DBE526AA231ABDE2D0EEF74D42:42\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := LoadList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pw := range []string{"Password123!", "password123!", "correct horse battery staple"} {
+		if !list.Contains(pw) {
+			t.Errorf("expected %q to be listed", pw)
+		}
+	}
+	if list.Contains("una frase larga y rara") {
+		t.Error("unexpected match")
+	}
+}
+
+func TestPolicy_Check(t *testing.T) {
+	p := Policy{MinLength: 12, Breached: List{hashOf("contraseña123"): {}}}
+
+	cases := []struct {
+		password string
+		rule     string
+	}{
+		{"corta", "min"},
+		{"contraseña123", "breached"},
+		{string(make([]byte, 80)), "max"},
+		{"una frase larga y rara", ""},
+	}
+	for _, tc := range cases {
+		err := p.Check("password", tc.password)
+		details, _ := appErr.ValidationDetails(err)
+		switch {
+		case tc.rule == "" && err != nil:
+			t.Errorf("%q: unexpected error %v", tc.password, err)
+		case tc.rule != "" && (len(details) != 1 || details[0].Regla != tc.rule):
+			t.Errorf("%q: got %v, want rule %s", tc.password, err, tc.rule)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test vectors of RFC 6238, appendix B (SHA1)
+func TestHOTP_RFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, tc := range cases {
+		if got := hotp(key, uint64(Step(time.Unix(tc.unix, 0))), 8); got != tc.code {
+			t.Errorf("t=%d: got %s, want %s", tc.unix, got, tc.code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+
+	code, err := Code(secret, now.Add(-Period*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	step, ok := Validate(secret, code, now, 1)
+	if !ok || step != Step(now)-1 {
+		t.Fatalf("previous step should be accepted: %d %v", step, ok)
+	}
+	if _, ok := Validate(secret, code, now.Add(Period*time.Second), 1); ok {
+		t.Fatal("a code two steps old should be rejected")
+	}
+	if _, ok := Validate(secret, "12345", now, 1); ok {
+		t.Fatal("a short code should be rejected")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("JBSWY3DPEHPK3PXP", "Clinica", "ana")
+	if !strings.HasPrefix(uri, "otpauth://totp/Clinica:ana?") || !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") {
+		t.Fatalf("unexpected uri %s", uri)
+	}
+}
@@ -0,0 +1,336 @@
+// Package validator enforces the `validate` struct tags on request DTOs and reports
+// every failing field with a Spanish message.
+//
+// It understands the subset of the go-playground/validator syntax used in this code
+// base: required, omitempty, oneof, min, max, email, datetime, dive and the custom
+// rules dpi, phone and hexcolor. Blank strings count as empty. Nested structs (including embedded ones and non-nil pointers) are
+// always validated; slice elements only with dive. Fields are named after their
+// JSON keys, e.g. "telefonos[0].numero".
+package validator
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tonitomc/healthcare-crm-api/pkg/dpi"
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+// Rule reports whether v satisfies the rule with the given parameter. v is never a
+// pointer: pointers are dereferenced (and nil ones skipped) before rules run.
+type Rule func(v reflect.Value, param string) bool
+
+type rule struct {
+	check   Rule
+	message func(v reflect.Value, param string) string
+}
+
+// Validator holds the registered rules. It implements echo.Validator.
+type Validator struct {
+	rules map[string]rule
+}
+
+// New returns a validator with the built-in rules
+func New() *Validator {
+	v := &Validator{rules: map[string]rule{}}
+	v.register("oneof", ruleOneOf, func(_ reflect.Value, p string) string {
+		return "debe ser uno de: " + strings.Join(strings.Fields(p), ", ")
+	})
+	v.register("min", ruleMin, func(val reflect.Value, p string) string { return sizeMessage(val, "al menos", p) })
+	v.register("max", ruleMax, func(val reflect.Value, p string) string { return sizeMessage(val, "como máximo", p) })
+	v.register("datetime", ruleDatetime, func(_ reflect.Value, p string) string {
+		return "debe ser una fecha con el formato " + layoutReplacer.Replace(p)
+	})
+	v.RegisterRule("email", ruleEmail, "debe ser un correo válido")
+	v.RegisterRule("dpi", ruleDPI, "debe ser un DPI válido")
+	v.RegisterRule("phone", rulePhone, "debe ser un teléfono válido (8 a 15 dígitos)")
+	v.RegisterRule("hexcolor", ruleHexColor, "debe tener formato #RRGGBB")
+	return v
+}
+
+// RegisterRule adds or replaces a rule; message completes "El campo 'x' ..."
+func (v *Validator) RegisterRule(name string, check Rule, message string) {
+	v.register(name, check, func(reflect.Value, string) string { return message })
+}
+
+func (v *Validator) register(name string, check Rule, message func(reflect.Value, string) string) {
+	v.rules[name] = rule{check: check, message: message}
+}
+
+// Validate implements echo.Validator; it is Struct under the name Echo expects
+func (v *Validator) Validate(i interface{}) error {
+	return v.Struct(i)
+}
+
+// Struct validates a struct or pointer to struct and returns an *appErr.ValidationError
+// listing every invalid field. Any other value is accepted as is, so binding into maps
+// or slices keeps working.
+func (v *Validator) Struct(s interface{}) error {
+	val := reflect.ValueOf(s)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs []appErr.FieldError
+	v.validateStruct(val, "", &errs)
+	if len(errs) > 0 {
+		return &appErr.ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+var defaultValidator = New()
+
+// Default is the validator shared by the HTTP binder and the services
+func Default() *Validator { return defaultValidator }
+
+// Struct validates s with the default validator
+func Struct(s interface{}) error { return defaultValidator.Struct(s) }
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (v *Validator) validateStruct(val reflect.Value, prefix string, errs *[]appErr.FieldError) {
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			// Embedded structs share the parent's namespace, as in encoding/json
+			if fv := deref(val.Field(i)); fv.IsValid() && fv.Kind() == reflect.Struct {
+				v.validateStruct(fv, prefix, errs)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		tags := field.Tag.Get("validate")
+		if tags == "-" {
+			continue
+		}
+		var rules []string
+		if tags != "" {
+			rules = strings.Split(tags, ",")
+		}
+		v.validateValue(val.Field(i), prefix+name, rules, errs)
+	}
+}
+
+// validateValue applies rules to fv and then descends into structs and, after dive,
+// into slice elements
+func (v *Validator) validateValue(fv reflect.Value, path string, rules []string, errs *[]appErr.FieldError) {
+	for len(rules) > 0 {
+		r := rules[0]
+		name, param, _ := strings.Cut(r, "=")
+
+		switch name {
+		case "omitempty":
+			if isEmpty(fv) {
+				return
+			}
+		case "required":
+			if isEmpty(fv) {
+				*errs = append(*errs, fieldError(path, name, "es obligatorio"))
+				return
+			}
+		case "dive":
+			elem := deref(fv)
+			if elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array {
+				for i := 0; i < elem.Len(); i++ {
+					v.validateValue(elem.Index(i), fmt.Sprintf("%s[%d]", path, i), rules[1:], errs)
+				}
+			}
+			return
+		default:
+			rl, ok := v.rules[name]
+			if !ok {
+				panic("validator: unknown rule " + name)
+			}
+			value := deref(fv)
+			if value.IsValid() && !rl.check(value, param) {
+				*errs = append(*errs, fieldError(path, name, rl.message(value, param)))
+				return
+			}
+		}
+		rules = rules[1:]
+	}
+
+	if value := deref(fv); value.IsValid() && value.Kind() == reflect.Struct && value.Type() != timeType {
+		prefix := path
+		if prefix != "" {
+			prefix += "."
+		}
+		v.validateStruct(value, prefix, errs)
+	}
+}
+
+func fieldError(path, rule, message string) appErr.FieldError {
+	return appErr.FieldError{Campo: path, Regla: rule, Mensaje: fmt.Sprintf("El campo '%s' %s", path, message)}
+}
+
+func jsonName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, false
+}
+
+// deref follows pointers and interfaces; the result is invalid for nil
+func deref(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// isEmpty: nil, the zero value, a blank string, or an empty slice or map. Structs
+// other than time.Time are never empty, so "required" on a struct only demands it
+// exists.
+func isEmpty(v reflect.Value) bool {
+	v = deref(v)
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Struct:
+		if v.Type() == timeType && v.CanInterface() {
+			return v.Interface().(time.Time).IsZero()
+		}
+		return false
+	default:
+		return v.IsZero()
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Built-in rules
+// -----------------------------------------------------------------------------
+
+func ruleOneOf(v reflect.Value, param string) bool {
+	s := fmt.Sprint(v)
+	for _, option := range strings.Fields(param) {
+		if s == option {
+			return true
+		}
+	}
+	return false
+}
+
+// size is the length of strings (in characters) and collections, or the number itself
+func size(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(len([]rune(v.String()))), true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+func ruleMin(v reflect.Value, param string) bool {
+	n, ok := size(v)
+	limit, err := strconv.ParseFloat(param, 64)
+	return ok && err == nil && n >= limit
+}
+
+func ruleMax(v reflect.Value, param string) bool {
+	n, ok := size(v)
+	limit, err := strconv.ParseFloat(param, 64)
+	return ok && err == nil && n <= limit
+}
+
+func sizeMessage(v reflect.Value, bound, param string) string {
+	switch v.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("debe tener %s %s caracteres", bound, param)
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return fmt.Sprintf("debe tener %s %s elementos", bound, param)
+	}
+	if bound == "al menos" {
+		return "debe ser mayor o igual a " + param
+	}
+	return "debe ser menor o igual a " + param
+}
+
+func ruleDatetime(v reflect.Value, layout string) bool {
+	if v.Kind() != reflect.String {
+		return false
+	}
+	_, err := time.Parse(layout, v.String())
+	return err == nil
+}
+
+// layoutReplacer shows Go time layouts the way users write them
+var layoutReplacer = strings.NewReplacer("2006", "AAAA", "01", "MM", "02", "DD", "15", "HH", "04", "mm")
+
+func ruleEmail(v reflect.Value, _ string) bool {
+	if v.Kind() != reflect.String {
+		return false
+	}
+	addr, err := mail.ParseAddress(v.String())
+	return err == nil && addr.Address == v.String()
+}
+
+func ruleDPI(v reflect.Value, _ string) bool {
+	return v.Kind() == reflect.String && dpi.Valid(v.String())
+}
+
+// rulePhone accepts 8 to 15 digits with an optional leading "+" and the usual
+// separators (spaces, dashes, dots, parentheses)
+func rulePhone(v reflect.Value, _ string) bool {
+	if v.Kind() != reflect.String {
+		return false
+	}
+	s := strings.TrimPrefix(strings.TrimSpace(v.String()), "+")
+	digits := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case strings.ContainsRune(" -.()", r):
+		default:
+			return false
+		}
+	}
+	return digits >= 8 && digits <= 15
+}
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+func ruleHexColor(v reflect.Value, _ string) bool {
+	return v.Kind() == reflect.String && hexColorPattern.MatchString(v.String())
+}
@@ -0,0 +1,157 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	appErr "github.com/tonitomc/healthcare-crm-api/pkg/errors"
+)
+
+type phone struct {
+	Numero string `json:"numero" validate:"required,phone"`
+	Tipo   string `json:"tipo" validate:"required,oneof=movil casa"`
+}
+
+type contact struct {
+	Nombre string `json:"nombre" validate:"required"`
+}
+
+type extra struct {
+	Correo *string `json:"correo,omitempty" validate:"omitempty,email"`
+}
+
+type request struct {
+	Nombre    string     `json:"nombre" validate:"required,max=10"`
+	Dia       int        `json:"dia" validate:"required,min=1,max=7"`
+	Fecha     time.Time  `json:"fecha" validate:"required"`
+	Nacido    string     `json:"nacido" validate:"omitempty,datetime=2006-01-02"`
+	DPI       *string    `json:"dpi,omitempty" validate:"omitempty,dpi"`
+	Color     string     `json:"color" validate:"omitempty,hexcolor"`
+	Telefonos []phone    `json:"telefonos" validate:"dive"`
+	Tutor     *contact   `json:"tutor,omitempty"`
+	Interno   string     `json:"-" validate:"required"`
+	Etiquetas []string   `json:"etiquetas" validate:"omitempty,dive,oneof=a b"`
+	Seguro    contact    `json:"seguro"`
+	Opcional  *time.Time `json:"opcional,omitempty"`
+	extra
+}
+
+func valid() request {
+	return request{
+		Nombre: "Ana",
+		Dia:    3,
+		Fecha:  time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC),
+		Seguro: contact{Nombre: "Seguros GT"},
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func fields(t *testing.T, err error) []string {
+	t.Helper()
+	var v *appErr.ValidationError
+	if !errors.As(err, &v) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if !errors.Is(err, appErr.ErrInvalidInput) {
+		t.Fatal("validation errors should unwrap to ErrInvalidInput")
+	}
+	names := make([]string, len(v.Fields))
+	for i, f := range v.Fields {
+		names[i] = f.Campo
+	}
+	return names
+}
+
+func TestStruct_Valid(t *testing.T) {
+	r := valid()
+	r.DPI = strPtr("1234567890101")
+	r.Color = "#3B82F6"
+	r.Nacido = "1990-04-03"
+	r.Telefonos = []phone{{Numero: "+502 5555-1234", Tipo: "movil"}}
+	r.Tutor = &contact{Nombre: "María"}
+	r.Etiquetas = []string{"a", "b"}
+
+	if err := Struct(&r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStruct_ReportsEveryField(t *testing.T) {
+	r := request{
+		Nombre:    "   ",
+		Dia:       9,
+		DPI:       strPtr("1234567880101"),
+		Color:     "azul",
+		Nacido:    "03/04/1990",
+		Telefonos: []phone{{Numero: "123", Tipo: "casa"}, {Numero: "55551234", Tipo: "fax"}},
+		Tutor:     &contact{},
+		Etiquetas: []string{"a", "c"},
+		extra:     extra{Correo: strPtr("ana@")},
+	}
+
+	got := fields(t, Struct(r))
+	want := []string{
+		"nombre", "dia", "fecha", "nacido", "dpi", "color",
+		"telefonos[0].numero", "telefonos[1].tipo", "tutor.nombre",
+		"etiquetas[1]", "seguro.nombre", "correo",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("fields\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestStruct_Messages(t *testing.T) {
+	r := valid()
+	r.Nombre = "Ana María López"
+	r.Dia = 0
+
+	var v *appErr.ValidationError
+	if !errors.As(Struct(r), &v) || len(v.Fields) != 2 {
+		t.Fatalf("expected two field errors, got %v", v)
+	}
+	if m := v.Fields[0].Mensaje; m != "El campo 'nombre' debe tener como máximo 10 caracteres" {
+		t.Errorf("unexpected message %q", m)
+	}
+	if f := v.Fields[1]; f.Regla != "required" || f.Mensaje != "El campo 'dia' es obligatorio" {
+		t.Errorf("unexpected error %+v", f)
+	}
+}
+
+func TestStruct_NonStructs(t *testing.T) {
+	var nilReq *request
+	for _, v := range []interface{}{nil, nilReq, map[string]string{}, []request{{}}} {
+		if err := Struct(v); err != nil {
+			t.Errorf("expected %T to be accepted, got %v", v, err)
+		}
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	v := New()
+	v.RegisterRule("even", func(val reflect.Value, _ string) bool { return val.Int()%2 == 0 }, "debe ser par")
+
+	type pair struct {
+		N int `json:"n" validate:"even"`
+	}
+	if err := v.Struct(pair{N: 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	var ve *appErr.ValidationError
+	if !errors.As(v.Struct(pair{N: 3}), &ve) || ve.Fields[0].Mensaje != "El campo 'n' debe ser par" {
+		t.Fatalf("custom rule not applied: %v", ve)
+	}
+}
+
+func TestWrapKeepsDetails(t *testing.T) {
+	err := appErr.Wrap("Handler.Create.Bind", appErr.ErrInvalidRequest, Struct(request{}))
+	if _, ok := appErr.ValidationDetails(err); !ok {
+		t.Fatal("wrapping should keep the field details")
+	}
+	if !errors.Is(err, appErr.ErrInvalidInput) {
+		t.Fatal("wrapped validation error should still be invalid input")
+	}
+}